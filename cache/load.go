@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/metrics"
+)
+
+// Load returns the value of key from c, or calls load and stores its result for ttl.
+// Errors of load are not cached. name labels the hit and miss metrics.
+func Load[T any](ctx context.Context, c datastore.Cache, name, key string, ttl time.Duration,
+	load func(context.Context) (T, error)) (T, error) {
+	if data, ok := c.Get(ctx, key); ok {
+		var value T
+
+		if err := json.Unmarshal(data, &value); err == nil {
+			metrics.CacheLookup(name, true)
+
+			return value, nil
+		}
+	}
+
+	metrics.CacheLookup(name, false)
+
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		slog.WarnContext(ctx, "could not cache value", "key", key, "error", err)
+
+		return value, nil
+	}
+
+	c.Set(ctx, key, data, ttl)
+
+	return value, nil
+}
@@ -0,0 +1,105 @@
+// Package cache has the stores behind the caching datastores
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// Memory is a least recently used cache with expiry, it is local to the process.
+// A shared store like redis can be plugged in through datastore.Cache.
+type Memory struct {
+	mu       sync.Mutex
+	capacity int
+	// order has most recently used entries at front
+	order   *list.List
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+// NewMemory returns a cache holding at most capacity entries
+func NewMemory(capacity int) *Memory {
+	return &Memory{capacity: capacity, order: list.New(), entries: make(map[string]*list.Element), now: time.Now}
+}
+
+// Get returns the value of key unless it is missing or expired
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*entry)
+	if !m.now().Before(e.expires) {
+		m.remove(el)
+
+		return nil, false
+	}
+
+	m.order.MoveToFront(el)
+
+	return e.value, true
+}
+
+// Set stores value for ttl, the least recently used entry is evicted when the cache is full
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expires := m.now().Add(ttl)
+
+	if el, ok := m.entries[key]; ok {
+		e := el.Value.(*entry)
+		e.value, e.expires = value, expires
+		m.order.MoveToFront(el)
+
+		return
+	}
+
+	m.entries[key] = m.order.PushFront(&entry{key: key, value: value, expires: expires})
+
+	for m.order.Len() > m.capacity {
+		m.remove(m.order.Back())
+	}
+}
+
+// Delete removes keys
+func (m *Memory) Delete(ctx context.Context, keys ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := m.entries[key]; ok {
+			m.remove(el)
+		}
+	}
+}
+
+// DeletePrefix removes every key starting with prefix
+func (m *Memory) DeletePrefix(ctx context.Context, prefix string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, el := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(el)
+		}
+	}
+}
+
+func (m *Memory) remove(el *list.Element) {
+	m.order.Remove(el)
+	delete(m.entries, el.Value.(*entry).key)
+}
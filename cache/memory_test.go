@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestMemory function is to test expiry, eviction of least recently used and deletes
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	m := NewMemory(2)
+	m.now = func() time.Time { return now }
+
+	m.Set(ctx, "book:1", []byte("1"), time.Minute)
+	m.Set(ctx, "book:2", []byte("2"), time.Hour)
+
+	// book:1 is used, so book:2 is the least recently used
+	m.Get(ctx, "book:1")
+	m.Set(ctx, "author:1", []byte("a"), time.Hour)
+
+	testcases := []struct {
+		desc  string
+		after time.Duration
+		key   string
+		found bool
+	}{
+		{desc: "kept", key: "book:1", found: true},
+		{desc: "evicted", key: "book:2", found: false},
+		{desc: "new", key: "author:1", found: true},
+		{desc: "expired", after: time.Minute, key: "book:1", found: false},
+	}
+
+	for i, v := range testcases {
+		now = now.Add(v.after)
+
+		if _, ok := m.Get(ctx, v.key); ok != v.found {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, ok, v.found)
+		}
+	}
+
+	m.Set(ctx, "book:3", []byte("3"), time.Hour)
+	m.DeletePrefix(ctx, "book:")
+
+	if _, ok := m.Get(ctx, "book:3"); ok {
+		t.Errorf("desc : delete prefix ,[TEST5]Failed. Got book:3\tExpected deleted\n")
+	}
+
+	m.Delete(ctx, "author:1")
+
+	if _, ok := m.Get(ctx, "author:1"); ok || len(m.entries) != 0 || m.order.Len() != 0 {
+		t.Errorf("desc : delete ,[TEST6]Failed. Got %v entries\tExpected none\n", len(m.entries))
+	}
+}
@@ -0,0 +1,71 @@
+// Package citation renders books with their author as bibliographic records and formatted citations
+package citation
+
+import (
+	"strconv"
+	"strings"
+
+	"Three-Layer-Architecture/models"
+)
+
+// Media types of citation formats
+const (
+	MediaBibTeX     = "application/x-bibtex"
+	MediaRIS        = "application/x-research-info-systems"
+	MediaCSLJSON    = "application/vnd.citationstyles.csl+json"
+	MediaDublinCore = "application/dc+xml"
+)
+
+// date splits published date dd/mm/yyyy, zero parts are missing
+func date(book models.Book) (year, month, day int) {
+	parts := strings.Split(book.PublishedDate, "/")
+	if len(parts) != 3 {
+		return 0, 0, 0
+	}
+
+	day, _ = strconv.Atoi(parts[0])
+	month, _ = strconv.Atoi(parts[1])
+	year, _ = strconv.Atoi(parts[2])
+
+	return year, month, day
+}
+
+// year is the published year, "n.d." when the date is unknown
+func year(book models.Book) string {
+	y, _, _ := date(book)
+	if y == 0 {
+		return "n.d."
+	}
+
+	return strconv.Itoa(y)
+}
+
+// inverted is the author as "last, first"
+func inverted(author models.Author) string {
+	if author.FirstName == "" {
+		return author.LastName
+	}
+
+	return author.LastName + ", " + author.FirstName
+}
+
+// initials gives "N. H." for "Nelle Harper"
+func initials(name string) string {
+	var parts []string
+
+	for _, n := range strings.Fields(name) {
+		parts = append(parts, string([]rune(n)[:1])+".")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// sentence ends s with a period unless it already ends with punctuation
+func sentence(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.ContainsAny(s[len(s)-1:], ".?!") {
+		return s
+	}
+
+	return s + "."
+}
@@ -0,0 +1,167 @@
+package citation
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"Three-Layer-Architecture/models"
+)
+
+// BibTeX returns a @book entry, its key is the author's last name, the year and the book id
+func BibTeX(book models.Book) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "@book{%s,\n", bibKey(book))
+	fmt.Fprintf(&b, "  author = {%s},\n", bibEscape(inverted(book.Auth)))
+	fmt.Fprintf(&b, "  title = {%s},\n", bibEscape(book.Title))
+	fmt.Fprintf(&b, "  publisher = {%s},\n", bibEscape(book.Publication))
+
+	if y, m, _ := date(book); y != 0 {
+		fmt.Fprintf(&b, "  year = {%d},\n", y)
+
+		if m != 0 {
+			fmt.Fprintf(&b, "  month = {%d},\n", m)
+		}
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+func bibKey(book models.Book) string {
+	key := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, book.Auth.LastName)
+
+	if y, _, _ := date(book); y != 0 {
+		key += strconv.Itoa(y)
+	}
+
+	return key + "_" + strconv.Itoa(book.BookID)
+}
+
+var bibReplacer = strings.NewReplacer(`\`, `\textbackslash{}`, "{", `\{`, "}", `\}`, "&", `\&`, "%", `\%`, "$", `\$`,
+	"#", `\#`, "_", `\_`)
+
+func bibEscape(s string) string {
+	return bibReplacer.Replace(s)
+}
+
+// RIS returns a BOOK reference, lines end with CR LF as the format asks
+func RIS(book models.Book) string {
+	var b strings.Builder
+
+	line := func(tag, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s  - %s\r\n", tag, strings.ReplaceAll(value, "\n", " "))
+		}
+	}
+
+	line("TY", "BOOK")
+	line("ID", strconv.Itoa(book.BookID))
+	line("AU", inverted(book.Auth))
+	line("TI", book.Title)
+	line("PB", book.Publication)
+
+	if y, m, d := date(book); y != 0 {
+		line("PY", strconv.Itoa(y))
+		line("DA", fmt.Sprintf("%04d/%02d/%02d", y, m, d))
+	}
+
+	b.WriteString("ER  - \r\n")
+
+	return b.String()
+}
+
+// CSL is a book as CSL-JSON item
+type CSL struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Author    []CSLName `json:"author"`
+	Publisher string    `json:"publisher,omitempty"`
+	Issued    *CSLDate  `json:"issued,omitempty"`
+}
+
+type CSLName struct {
+	Family string `json:"family"`
+	Given  string `json:"given,omitempty"`
+}
+
+type CSLDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// CSLJSON returns the CSL-JSON item of a book
+func CSLJSON(book models.Book) CSL {
+	item := CSL{ID: strconv.Itoa(book.BookID), Type: "book", Title: book.Title, Publisher: book.Publication,
+		Author: []CSLName{{Family: book.Auth.LastName, Given: book.Auth.FirstName}}}
+
+	if y, m, d := date(book); y != 0 {
+		item.Issued = &CSLDate{DateParts: [][]int{{y, m, d}}}
+	}
+
+	return item
+}
+
+// Dublin Core namespaces
+const (
+	NamespaceOAIDC = "http://www.openarchives.org/OAI/2.0/oai_dc/"
+	NamespaceDC    = "http://purl.org/dc/elements/1.1/"
+)
+
+// DC is a book as oai_dc record of simple Dublin Core
+type DC struct {
+	XMLName    xml.Name `xml:"oai_dc:dc"`
+	XmlnsOAIDC string   `xml:"xmlns:oai_dc,attr"`
+	XmlnsDC    string   `xml:"xmlns:dc,attr"`
+	Title      string   `xml:"dc:title"`
+	Creator    string   `xml:"dc:creator"`
+	Publisher  string   `xml:"dc:publisher,omitempty"`
+	Date       string   `xml:"dc:date,omitempty"`
+	Type       string   `xml:"dc:type"`
+	Identifier string   `xml:"dc:identifier"`
+}
+
+// DublinCore returns the Dublin Core record of a book, the date is written as yyyy-mm-dd
+func DublinCore(book models.Book) DC {
+	dc := DC{XmlnsOAIDC: NamespaceOAIDC, XmlnsDC: NamespaceDC, Title: book.Title, Creator: inverted(book.Auth),
+		Publisher: book.Publication, Type: "Text", Identifier: "book:" + strconv.Itoa(book.BookID)}
+
+	if y, m, d := date(book); y != 0 {
+		dc.Date = fmt.Sprintf("%04d-%02d-%02d", y, m, d)
+	}
+
+	return dc
+}
+
+// Encode renders a book in the format of a citation media type
+func Encode(book models.Book, media string) ([]byte, error) {
+	switch media {
+	case MediaBibTeX:
+		return []byte(BibTeX(book)), nil
+	case MediaRIS:
+		return []byte(RIS(book)), nil
+	case MediaCSLJSON:
+		// CSL-JSON documents are arrays of items
+		return json.Marshal([]CSL{CSLJSON(book)})
+	case MediaDublinCore:
+		body, err := xml.Marshal(DublinCore(book))
+		if err != nil {
+			return nil, err
+		}
+
+		return append([]byte(xml.Header), body...), nil
+	default:
+		return nil, fmt.Errorf("unsupported media type %v", media)
+	}
+}
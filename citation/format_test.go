@@ -0,0 +1,90 @@
+package citation
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"Three-Layer-Architecture/models"
+)
+
+var (
+	states = models.Book{BookID: 1, AuthorID: 1,
+		Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
+		Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}
+	mockingbird = models.Book{BookID: 2, AuthorID: 2,
+		Auth:  models.Author{AuthID: 2, FirstName: "Nelle Harper", LastName: "Lee", Dob: "28/04/1926", PenName: "Harper Lee"},
+		Title: "Who Killed the Mockingbird?", Publication: "Penguin & Sons", PublishedDate: "11/07/1960"}
+)
+
+// TestBibTeX function is to test entries, keys and escaping
+func TestBibTeX(t *testing.T) {
+	testcases := []struct {
+		desc string
+		book models.Book
+		resp string
+	}{
+		{desc: "book", book: states, resp: "@book{bhagat2016_1,\n  author = {Bhagat, Chetan},\n  title = {2 States},\n" +
+			"  publisher = {Scholastic},\n  year = {2016},\n  month = {3},\n}\n"},
+		{desc: "escaped publisher", book: mockingbird, resp: "@book{lee1960_2,\n  author = {Lee, Nelle Harper},\n" +
+			"  title = {Who Killed the Mockingbird?},\n  publisher = {Penguin \\& Sons},\n  year = {1960},\n  month = {7},\n}\n"},
+	}
+
+	for i, v := range testcases {
+		if resp := BibTeX(v.book); resp != v.resp {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+	}
+}
+
+// TestRIS function is to test references end with ER and CR LF lines
+func TestRIS(t *testing.T) {
+	expected := "TY  - BOOK\r\nID  - 1\r\nAU  - Bhagat, Chetan\r\nTI  - 2 States\r\nPB  - Scholastic\r\nPY  - 2016\r\n" +
+		"DA  - 2016/03/16\r\nER  - \r\n"
+
+	if resp := RIS(states); resp != expected {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %q\tExpected %q\n", "ris", 1, resp, expected)
+	}
+}
+
+// TestEncode function is to test every media type and an unsupported one
+func TestEncode(t *testing.T) {
+	body, err := Encode(states, MediaCSLJSON)
+	if err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "csl", 1, err, nil)
+	}
+
+	var items []CSL
+
+	expected := []CSL{{ID: "1", Type: "book", Title: "2 States", Author: []CSLName{{Family: "Bhagat", Given: "Chetan"}},
+		Publisher: "Scholastic", Issued: &CSLDate{DateParts: [][]int{{2016, 3, 16}}}}}
+
+	if err = json.Unmarshal(body, &items); err != nil || !reflect.DeepEqual(items, expected) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %s\tExpected %v\n", "csl", 1, body, expected)
+	}
+
+	body, err = Encode(states, MediaDublinCore)
+	if err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "dublin core", 2, err, nil)
+	}
+
+	dc := xml.Header + `<oai_dc:dc xmlns:oai_dc="http://www.openarchives.org/OAI/2.0/oai_dc/" ` +
+		`xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>2 States</dc:title><dc:creator>Bhagat, Chetan</dc:creator>` +
+		`<dc:publisher>Scholastic</dc:publisher><dc:date>2016-03-16</dc:date><dc:type>Text</dc:type>` +
+		`<dc:identifier>book:1</dc:identifier></oai_dc:dc>`
+
+	if string(body) != dc {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %s\tExpected %v\n", "dublin core", 2, body, dc)
+	}
+
+	for i, media := range []string{MediaBibTeX, MediaRIS} {
+		if _, err = Encode(states, media); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", media, i+3, err, nil)
+		}
+	}
+
+	if _, err = Encode(states, "text/html"); err == nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "unsupported", 5, err, "error")
+	}
+}
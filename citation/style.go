@@ -0,0 +1,39 @@
+package citation
+
+import (
+	"fmt"
+
+	"Three-Layer-Architecture/models"
+)
+
+// Citation styles
+const (
+	StyleAPA     = "apa"
+	StyleMLA     = "mla"
+	StyleChicago = "chicago"
+)
+
+// Text returns the reference list entry of a book in APA 7, MLA 9 or Chicago author-date style.
+// Titles are plain text, italics are left to the reader.
+func Text(book models.Book, style string) (string, error) {
+	author := book.Auth
+
+	switch style {
+	case StyleAPA:
+		name := author.LastName
+		if author.FirstName != "" {
+			name += ", " + initials(author.FirstName)
+		}
+
+		return fmt.Sprintf("%s (%s). %s %s", sentence(name), year(book), sentence(book.Title),
+			sentence(book.Publication)), nil
+	case StyleMLA:
+		return fmt.Sprintf("%s %s %s", sentence(inverted(author)), sentence(book.Title),
+			sentence(book.Publication+", "+year(book))), nil
+	case StyleChicago:
+		return fmt.Sprintf("%s %s %s %s", sentence(inverted(author)), sentence(year(book)), sentence(book.Title),
+			sentence(book.Publication)), nil
+	default:
+		return "", fmt.Errorf("unsupported style %q", style)
+	}
+}
@@ -0,0 +1,43 @@
+package citation
+
+import (
+	"errors"
+	"testing"
+
+	"Three-Layer-Architecture/models"
+)
+
+// TestText function is to test reference entries of every style
+func TestText(t *testing.T) {
+	undated := states
+	undated.PublishedDate = ""
+
+	testcases := []struct {
+		desc  string
+		book  models.Book
+		style string
+		resp  string
+		err   error
+	}{
+		{desc: "apa", book: states, style: StyleAPA, resp: "Bhagat, C. (2016). 2 States. Scholastic."},
+		{desc: "apa with two given names", book: mockingbird, style: StyleAPA,
+			resp: "Lee, N. H. (1960). Who Killed the Mockingbird? Penguin & Sons."},
+		{desc: "mla", book: states, style: StyleMLA, resp: "Bhagat, Chetan. 2 States. Scholastic, 2016."},
+		{desc: "mla without date", book: undated, style: StyleMLA, resp: "Bhagat, Chetan. 2 States. Scholastic, n.d."},
+		{desc: "chicago", book: mockingbird, style: StyleChicago,
+			resp: "Lee, Nelle Harper. 1960. Who Killed the Mockingbird? Penguin & Sons."},
+		{desc: "unsupported", book: states, style: "harvard", err: errors.New(`unsupported style "harvard"`)},
+	}
+
+	for i, v := range testcases {
+		resp, err := Text(v.book, v.style)
+
+		if resp != v.resp {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if (err == nil) != (v.err == nil) || (err != nil && err.Error() != v.err.Error()) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"Three-Layer-Architecture/models"
+)
+
+func (a app) users(ctx context.Context, args []string) error {
+	sub, args, err := subcommand("user", args)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "create":
+		if args, err = parse("user create", args, 2, nil); err != nil {
+			return err
+		}
+
+		// the password is read like a file so it is not in shell history
+		password, _ := bufio.NewReader(a.stdin).ReadString('\n')
+		if password = strings.TrimRight(password, "\r\n"); password == "" {
+			return errors.New("missing password on standard input")
+		}
+
+		u, err := a.user.Register(ctx, args[0], args[1], password)
+		if err != nil {
+			return err
+		}
+
+		return a.out.write(u, userTable(u))
+	case "get":
+		if args, err = parse("user get", args, 1, nil); err != nil {
+			return err
+		}
+
+		u, err := a.user.GetByUsername(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		return a.out.write(u, userTable(u))
+	case "revoke-sessions":
+		if args, err = parse("user revoke-sessions", args, 1, nil); err != nil {
+			return err
+		}
+
+		id, err := atoi("user id", args[0])
+		if err != nil {
+			return err
+		}
+
+		n, err := a.user.RevokeSessions(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return a.out.write(affected{Affected: n}, affectedTable(n))
+	default:
+		return fmt.Errorf("%w : unknown user subcommand %q", errUsage, sub)
+	}
+}
+
+func (a app) roles(ctx context.Context, args []string) error {
+	sub, args, err := subcommand("role", args)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "list":
+		if _, err = parse("role list", args, 0, nil); err != nil {
+			return err
+		}
+
+		roles, err := a.rbac.GetRoles(ctx)
+		if err != nil {
+			return err
+		}
+
+		return a.out.write(roles, roleTable(roles...))
+	case "get":
+		if args, err = parse("role get", args, 1, nil); err != nil {
+			return err
+		}
+
+		roles, err := a.rbac.GetAssignments(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		t := table{header: []string{"ROLE"}}
+
+		for _, r := range roles {
+			t.rows = append(t.rows, []string{r})
+		}
+
+		return a.out.write(roles, t)
+	case "assign", "unassign":
+		if args, err = parse("role "+sub, args, 2, nil); err != nil {
+			return err
+		}
+
+		change := a.rbac.Assign
+		if sub == "unassign" {
+			change = a.rbac.Unassign
+		}
+
+		if err = change(ctx, args[0], args[1]); err != nil {
+			return err
+		}
+
+		return a.out.write(affected{Affected: 1}, affectedTable(1))
+	default:
+		return fmt.Errorf("%w : unknown role subcommand %q", errUsage, sub)
+	}
+}
+
+// createdKey is the result of apikey create, the key is not shown again
+type createdKey struct {
+	Key string `json:"key"`
+	models.APIKey
+}
+
+func (a app) apiKeys(ctx context.Context, args []string) error {
+	sub, args, err := subcommand("apikey", args)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "create":
+		if args, err = parse("apikey create", args, 2, nil); err != nil {
+			return err
+		}
+
+		key, stored, err := a.apiKey.CreateAPIKey(ctx, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		return a.out.write(createdKey{Key: key, APIKey: stored}, table{
+			header: []string{"ID", "NAME", "PRINCIPAL", "KEY"},
+			rows:   [][]string{{strconv.Itoa(stored.ID), stored.Name, stored.Principal, key}},
+		})
+	case "revoke":
+		if args, err = parse("apikey revoke", args, 1, nil); err != nil {
+			return err
+		}
+
+		n, err := a.apiKey.RevokeAPIKey(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		return a.out.write(affected{Affected: n}, affectedTable(n))
+	default:
+		return fmt.Errorf("%w : unknown apikey subcommand %q", errUsage, sub)
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"Three-Layer-Architecture/models"
+)
+
+func (a app) books(ctx context.Context, args []string) error {
+	sub, args, err := subcommand("book", args)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "list":
+		if _, err = parse("book list", args, 0, nil); err != nil {
+			return err
+		}
+
+		books, err := a.book.GetAll(ctx)
+		if err != nil {
+			return err
+		}
+
+		return a.out.write(books, bookTable(books...))
+	case "get":
+		if args, err = parse("book get", args, 1, nil); err != nil {
+			return err
+		}
+
+		book, err := a.book.Getbyid(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		return a.out.write(book, bookTable(book))
+	case "create", "update":
+		var (
+			path string
+			book models.Book
+		)
+
+		n := 0
+		if sub == "update" {
+			n = 1
+		}
+
+		if args, err = parse("book "+sub, args, n, fileFlag(&path)); err != nil {
+			return err
+		}
+
+		if err = a.decode(path, &book); err != nil {
+			return err
+		}
+
+		if sub == "create" {
+			book, err = a.book.Post(ctx, &book)
+		} else {
+			book, err = a.book.Update(ctx, args[0], &book)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return a.out.write(book, bookTable(book))
+	case "delete", "restore":
+		if args, err = parse("book "+sub, args, 1, nil); err != nil {
+			return err
+		}
+
+		change := a.book.Delete
+		if sub == "restore" {
+			change = a.book.Restore
+		}
+
+		n, err := change(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		return a.out.write(affected{Affected: n}, affectedTable(n))
+	default:
+		return fmt.Errorf("%w : unknown book subcommand %q", errUsage, sub)
+	}
+}
+
+func (a app) authors(ctx context.Context, args []string) error {
+	sub, args, err := subcommand("author", args)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "get":
+		if args, err = parse("author get", args, 1, nil); err != nil {
+			return err
+		}
+
+		author, err := a.author.Getbyid(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		return a.out.write(author, authorTable(author))
+	case "create", "update":
+		var (
+			path   string
+			author models.Author
+		)
+
+		n := 0
+		if sub == "update" {
+			n = 1
+		}
+
+		if args, err = parse("author "+sub, args, n, fileFlag(&path)); err != nil {
+			return err
+		}
+
+		if err = a.decode(path, &author); err != nil {
+			return err
+		}
+
+		if sub == "create" {
+			author, err = a.author.Post(ctx, author)
+		} else {
+			author, err = a.author.Update(ctx, args[0], author)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return a.out.write(author, authorTable(author))
+	case "delete", "restore":
+		if args, err = parse("author "+sub, args, 1, nil); err != nil {
+			return err
+		}
+
+		change := a.author.Delete
+		if sub == "restore" {
+			change = a.author.Restore
+		}
+
+		n, err := change(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		return a.out.write(affected{Affected: n}, affectedTable(n))
+	default:
+		return fmt.Errorf("%w : unknown author subcommand %q", errUsage, sub)
+	}
+}
+
+func (a app) trashed(ctx context.Context, args []string) error {
+	sub, args, err := subcommand("trash", args)
+	if err != nil {
+		return err
+	}
+
+	if _, err = parse("trash "+sub, args, 0, nil); err != nil {
+		return err
+	}
+
+	switch sub {
+	case "list":
+		trash, err := a.trash.GetAll(ctx)
+		if err != nil {
+			return err
+		}
+
+		return a.out.write(trash, bookTable(trash.Books...), authorTable(trash.Authors...))
+	case "purge":
+		n, err := a.trash.Purge(ctx)
+		if err != nil {
+			return err
+		}
+
+		return a.out.write(affected{Affected: n}, affectedTable(n))
+	default:
+		return fmt.Errorf("%w : unknown trash subcommand %q", errUsage, sub)
+	}
+}
+
+// importBooks reads a file like POST /import, rows are checked by the book and author services
+func (a app) importBooks(ctx context.Context, args []string) error {
+	opts := models.ImportOptions{Format: models.ImportCSV}
+
+	args, err := parseFlags("import", args, func(flags *flag.FlagSet) {
+		flags.StringVar(&opts.Format, "format", opts.Format, "csv, ndjson, marc or marcxml")
+		flags.BoolVar(&opts.DryRun, "dry-run", false, "validate rows without storing them")
+		flags.IntVar(&opts.BatchSize, "batch-size", 0, "rows stored in one transaction")
+	})
+	if err != nil {
+		return err
+	}
+
+	// the file is optional, standard input is read without it
+	path := "-"
+
+	switch len(args) {
+	case 0:
+	case 1:
+		path = args[0]
+	default:
+		return fmt.Errorf("%w : import takes at most 1 argument", errUsage)
+	}
+
+	r, err := a.open(path)
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	result, err := a.importer.Import(ctx, r, opts)
+	if err != nil {
+		return err
+	}
+
+	return a.out.write(result, importTables(result)...)
+}
+
+// exportBooks writes the catalogue to standard output like GET /export, output format does not apply
+func (a app) exportBooks(ctx context.Context, args []string) error {
+	var (
+		opts   = models.ExportOptions{Format: models.ExportCSV}
+		fields string
+	)
+
+	_, err := parse("export", args, 0, func(flags *flag.FlagSet) {
+		flags.StringVar(&opts.Format, "format", opts.Format, "csv, ndjson, json, marc, marcxml, bibtex, ris, csljson or dc")
+		flags.StringVar(&fields, "fields", "", "comma separated columns")
+		flags.IntVar(&opts.Filter.AuthorID, "author-id", 0, "books of this author only")
+		flags.StringVar(&opts.Filter.Publication, "publication", "", "books of this publication only")
+		flags.StringVar(&opts.Filter.Title, "title", "", "books whose title contains it")
+	})
+	if err != nil {
+		return err
+	}
+
+	if fields != "" {
+		opts.Fields = strings.Split(fields, ",")
+	}
+
+	return a.export.Export(ctx, a.out.w, opts)
+}
+
+// check reports rows to fix, errInconsistent makes the process exit 1 when there are any
+func (a app) check(ctx context.Context, args []string) error {
+	if _, err := parse("check", args, 0, nil); err != nil {
+		return err
+	}
+
+	found, err := a.consistency.Check(ctx)
+	if err != nil {
+		return err
+	}
+
+	if found == nil {
+		found = []models.Inconsistency{}
+	}
+
+	if err = a.out.write(found, inconsistencyTable(found...)); err != nil {
+		return err
+	}
+
+	if len(found) > 0 {
+		return fmt.Errorf("%w : %d", errInconsistent, len(found))
+	}
+
+	return nil
+}
+
+// fileFlag defines -file, the JSON body of create and update
+func fileFlag(path *string) func(*flag.FlagSet) {
+	return func(flags *flag.FlagSet) {
+		flags.StringVar(path, "file", "-", "JSON file, - for standard input")
+	}
+}
+
+// decode reads the JSON body of create and update from path
+func (a app) decode(path string, v any) error {
+	r, err := a.open(path)
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	if err = json.NewDecoder(r).Decode(v); err != nil {
+		return fmt.Errorf("invalid json : %w", err)
+	}
+
+	return nil
+}
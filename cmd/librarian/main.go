@@ -0,0 +1,297 @@
+// Command librarian runs catalogue chores against the database through the same services as the api, so rows are
+// checked, audited and sent to outbox sinks as if they were changed over http. Changes are audited as cli:<os user>.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"os/user"
+	"strconv"
+	"time"
+
+	datastoreapikey "Three-Layer-Architecture/datastore/apikey"
+	datastoreauthor "Three-Layer-Architecture/datastore/author"
+	datastorebook "Three-Layer-Architecture/datastore/book"
+	datastoreconsistency "Three-Layer-Architecture/datastore/consistency"
+	datastoreexport "Three-Layer-Architecture/datastore/export"
+	datastoreimporter "Three-Layer-Architecture/datastore/importer"
+	datastorerbac "Three-Layer-Architecture/datastore/rbac"
+	datastorerevision "Three-Layer-Architecture/datastore/revision"
+	datastoresession "Three-Layer-Architecture/datastore/session"
+	datastoreuser "Three-Layer-Architecture/datastore/user"
+	"Three-Layer-Architecture/driver"
+	"Three-Layer-Architecture/logging"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/principal"
+	"Three-Layer-Architecture/service"
+	serviceauth "Three-Layer-Architecture/service/auth"
+	serviceauthor "Three-Layer-Architecture/service/author"
+	servicebook "Three-Layer-Architecture/service/book"
+	serviceconsistency "Three-Layer-Architecture/service/consistency"
+	serviceexport "Three-Layer-Architecture/service/export"
+	serviceimporter "Three-Layer-Architecture/service/importer"
+	servicerbac "Three-Layer-Architecture/service/rbac"
+	servicerevision "Three-Layer-Architecture/service/revision"
+	servicetrash "Three-Layer-Architecture/service/trash"
+	serviceuser "Three-Layer-Architecture/service/user"
+)
+
+const usage = `Usage: librarian [-output table|json] <command> [flags] [args]
+
+Catalogue
+  book list | get <id> | delete <id> | restore <id>
+  book create [-file book.json] | update [-file book.json] <id>    JSON as for the api, standard input by default
+  author get <id> | delete <id> | restore <id>
+  author create [-file author.json] | update [-file author.json] <id>
+  trash list | purge                                               purge removes rows older than TRASH_RETENTION
+  import [-format csv] [-dry-run] [-batch-size 100] [file]         standard input by default
+  export [-format csv] [-fields f,...] [-author-id id] [-publication p] [-title t]
+
+Access
+  user create <username> <email>                                   password on standard input
+  user get <username> | revoke-sessions <id>
+  role list | get <principal> | assign <principal> <role> | unassign <principal> <role>
+  apikey create <name> <principal> | revoke <id>
+
+Database
+  migrate [-file db.sql] [-reset -yes]                             creates missing tables, -reset drops every table
+  check                                                            reports inconsistent rows, exits 1 when found
+`
+
+// errUsage is returned for unknown commands and wrong arguments, the process exits 2
+var errUsage = errors.New("invalid usage")
+
+// errInconsistent is returned by check when it found rows to fix, the process exits 1 after printing them
+var errInconsistent = errors.New("inconsistent rows found")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run parses global flags, connects to the database and runs the command, it returns the exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// logs go to stderr so they are not mixed with json output
+	slog.SetDefault(logging.New(stderr, logging.ParseLevel(envOr("LOG_LEVEL", "warn"))))
+
+	flags := flag.NewFlagSet("librarian", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { fmt.Fprint(stderr, usage) }
+	format := flags.String("output", formatTable, "table or json")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 || (*format != formatTable && *format != formatJSON) {
+		flags.Usage()
+
+		return 2
+	}
+
+	db, err := driver.ConnectDB()
+	if err != nil {
+		fmt.Fprintln(stderr, "librarian: could not connect to sql:", err)
+
+		return 1
+	}
+
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	a := newApp(db, stdin, output{w: stdout, format: *format})
+
+	return exitCode(a.run(principal.NewContext(ctx, caller()), flags.Args()), stderr)
+}
+
+func exitCode(err error, stderr io.Writer) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(stderr, "librarian: %v\n\n%s", err, usage)
+
+		return 2
+	default:
+		fmt.Fprintln(stderr, "librarian:", err)
+
+		return 1
+	}
+}
+
+// caller is the principal changes are audited as
+func caller() models.Principal {
+	name := "unknown"
+
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+
+	return models.Principal{ID: models.PrincipalID(models.PrincipalCLI, name), Name: name, Kind: models.PrincipalCLI}
+}
+
+// app holds the services commands run with
+type app struct {
+	db          *sql.DB
+	book        service.Book
+	author      service.Author
+	importer    service.Import
+	export      service.Export
+	trash       service.Trash
+	user        service.User
+	rbac        service.RBAC
+	apiKey      service.APIKey
+	consistency service.Consistency
+	stdin       io.Reader
+	out         output
+}
+
+// newApp wires the services like the api does, without caches. The api replicas drop cached rows changed here when
+// they read the events of the changes from outbox.
+func newApp(db *sql.DB, stdin io.Reader, out output) app {
+	revisionService := servicerevision.New(datastorerevision.New(db))
+
+	bookService := servicebook.New(datastorebook.New(db), revisionService)
+	authorService := serviceauthor.New(datastoreauthor.New(db), revisionService)
+	userService := serviceuser.New(datastoreuser.New(db), datastoresession.New(db), nil, serviceuser.Config{})
+
+	return app{
+		db:       db,
+		book:     bookService,
+		author:   authorService,
+		importer: serviceimporter.New(bookService, authorService, datastoreimporter.New(db)),
+		export:   serviceexport.New(datastoreexport.New(db)),
+		trash: servicetrash.New(datastorebook.New(db), datastoreauthor.New(db),
+			durationFromEnv("TRASH_RETENTION", 30*24*time.Hour)),
+		user:        userService,
+		rbac:        servicerbac.New(datastorerbac.New(db)),
+		apiKey:      serviceauth.New(datastoreapikey.New(db), serviceauth.JWTConfig{}, userService),
+		consistency: serviceconsistency.New(datastoreconsistency.New(db), bookService),
+		stdin:       stdin,
+		out:         out,
+	}
+}
+
+// run dispatches args to the command they name
+func (a app) run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w : missing command", errUsage)
+	}
+
+	switch args[0] {
+	case "book":
+		return a.books(ctx, args[1:])
+	case "author":
+		return a.authors(ctx, args[1:])
+	case "trash":
+		return a.trashed(ctx, args[1:])
+	case "import":
+		return a.importBooks(ctx, args[1:])
+	case "export":
+		return a.exportBooks(ctx, args[1:])
+	case "user":
+		return a.users(ctx, args[1:])
+	case "role":
+		return a.roles(ctx, args[1:])
+	case "apikey":
+		return a.apiKeys(ctx, args[1:])
+	case "migrate":
+		return a.migrate(ctx, args[1:])
+	case "check":
+		return a.check(ctx, args[1:])
+	default:
+		return fmt.Errorf("%w : unknown command %q", errUsage, args[0])
+	}
+}
+
+// parse parses the flags of a subcommand and checks it got n arguments after them
+func parse(name string, args []string, n int, define func(*flag.FlagSet)) ([]string, error) {
+	args, err := parseFlags(name, args, define)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) != n {
+		return nil, fmt.Errorf("%w : %s takes %d arguments", errUsage, name, n)
+	}
+
+	return args, nil
+}
+
+// parseFlags parses the flags of a subcommand and returns the arguments after them
+func parseFlags(name string, args []string, define func(*flag.FlagSet)) ([]string, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	if define != nil {
+		define(flags)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, fmt.Errorf("%w : %s : %v", errUsage, name, err)
+	}
+
+	return flags.Args(), nil
+}
+
+// subcommand splits the subcommand name from its arguments
+func subcommand(command string, args []string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("%w : missing %s subcommand", errUsage, command)
+	}
+
+	return args[0], args[1:], nil
+}
+
+// open returns standard input for - and the file at path otherwise
+func (a app) open(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(a.stdin), nil
+	}
+
+	return os.Open(path)
+}
+
+// durationFromEnv reads a duration like "720h" from environment, def is used when it is unset or invalid
+func durationFromEnv(key string, def time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Warn("invalid duration, using default", "key", key, "error", err, "default", def)
+
+		return def
+	}
+
+	return d
+}
+
+// envOr returns the value of key, or def when it is not set
+func envOr(key, def string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+
+	return def
+}
+
+// atoi parses a numeric argument, a wrong one is a usage error
+func atoi(name, s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w : invalid %s %q", errUsage, name, s)
+	}
+
+	return n, nil
+}
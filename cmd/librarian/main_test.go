@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+var book = models.Book{BookID: 1, AuthorID: 1, Title: "2 States", Publication: "Penguin", PublishedDate: "16/03/2016",
+	Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}}
+
+// TestBookCommands function is to test book commands call the book service and print its result
+func TestBookCommands(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		args     []string
+		stdin    string
+		format   string
+		mock     func(m *service.MockBook)
+		expected string
+		err      error
+	}{
+		{desc: "get as table", args: []string{"book", "get", "1"}, format: formatTable,
+			mock: func(m *service.MockBook) { m.EXPECT().Getbyid(gomock.Any(), "1").Return(book, nil) },
+			expected: "ID  TITLE     AUTHOR           PUBLICATION  PUBLISHED\n" +
+				"1   2 States  1 Chetan Bhagat  Penguin      16/03/2016\n"},
+		{desc: "delete as json", args: []string{"book", "delete", "1"}, format: formatJSON,
+			mock:     func(m *service.MockBook) { m.EXPECT().Delete(gomock.Any(), "1").Return(1, nil) },
+			expected: "{\n  \"affected\": 1\n}\n"},
+		{desc: "create from standard input", args: []string{"book", "create"}, format: formatJSON,
+			stdin: `{"bookID":1,"title":"2 States"}`,
+			mock: func(m *service.MockBook) {
+				m.EXPECT().Post(gomock.Any(), &models.Book{BookID: 1, Title: "2 States"}).
+					Return(models.Book{BookID: 1, Title: "2 States"}, nil)
+			},
+			expected: "{\n  \"bookID\": 1,\n  \"authorID\": 0,\n  \"auth\": {\n    \"authID\": 0,\n    \"firstName\": \"\",\n" +
+				"    \"lastName\": \"\",\n    \"dob\": \"\",\n    \"penName\": \"\"\n  },\n  \"title\": \"2 States\",\n" +
+				"  \"publication\": \"\",\n  \"publishedDate\": \"\"\n}\n"},
+		{desc: "error from svc", args: []string{"book", "get", "0"}, format: formatTable,
+			mock: func(m *service.MockBook) {
+				m.EXPECT().Getbyid(gomock.Any(), "0").Return(models.Book{}, errors.New("invalid id"))
+			},
+			err: errors.New("invalid id")},
+		{desc: "missing id", args: []string{"book", "get"}, format: formatTable, err: errUsage},
+		{desc: "unknown subcommand", args: []string{"book", "shelve"}, format: formatTable, err: errUsage},
+		{desc: "unknown command", args: []string{"shelf"}, format: formatTable, err: errUsage},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockBook := service.NewMockBook(ctr)
+
+		if v.mock != nil {
+			v.mock(mockBook)
+		}
+
+		var out bytes.Buffer
+
+		a := app{book: mockBook, stdin: strings.NewReader(v.stdin), out: output{w: &out, format: v.format}}
+
+		err := a.run(context.Background(), v.args)
+		if (v.err == nil) != (err == nil) || (v.err != nil && !errors.Is(err, v.err) && err.Error() != v.err.Error()) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		if out.String() != v.expected {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %q\tExpected %q\n", v.desc, i+1, out.String(), v.expected)
+		}
+	}
+}
+
+// TestUserCreate function is to test the password is read from standard input
+func TestUserCreate(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockUser := service.NewMockUser(ctr)
+
+	mockUser.EXPECT().Register(gomock.Any(), "asha", "asha@example.com", "s3cret pass").
+		Return(models.User{ID: 4, Username: "asha", Email: "asha@example.com"}, nil)
+
+	a := app{user: mockUser, stdin: strings.NewReader("s3cret pass\n"), out: output{w: io.Discard, format: formatJSON}}
+
+	if err := a.run(context.Background(), []string{"user", "create", "asha", "asha@example.com"}); err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "password from stdin", 1, err, nil)
+	}
+
+	a.stdin = strings.NewReader("")
+
+	if err := a.run(context.Background(), []string{"user", "create", "asha", "asha@example.com"}); err == nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "missing password", 2, err, "error")
+	}
+}
+
+// TestCheck function is to test found inconsistencies are printed and fail the command
+func TestCheck(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockConsistency := service.NewMockConsistency(ctr)
+
+	gomock.InOrder(
+		mockConsistency.EXPECT().Check(gomock.Any()).Return(nil, nil),
+		mockConsistency.EXPECT().Check(gomock.Any()).Return([]models.Inconsistency{{Check: "book.trashed_author",
+			Entity: models.EntityBook, EntityID: 2, Detail: "author 1 is in trash"}}, nil),
+	)
+
+	var out bytes.Buffer
+
+	a := app{consistency: mockConsistency, out: output{w: &out, format: formatJSON}}
+
+	if err := a.run(context.Background(), []string{"check"}); err != nil || out.String() != "[]\n" {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %q\tExpected %v\n", "consistent", 1, err, out.String(), "[]")
+	}
+
+	out.Reset()
+
+	err := a.run(context.Background(), []string{"check"})
+	if !errors.Is(err, errInconsistent) || !strings.Contains(out.String(), "book.trashed_author") {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %q\tExpected %v\n", "inconsistent", 2, err, out.String(),
+			errInconsistent)
+	}
+}
+
+// TestExitCode function is to test usage errors exit 2 and others 1
+func TestExitCode(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		err      error
+		expected int
+	}{
+		{"success", nil, 0},
+		{"usage", errUsage, 2},
+		{"failure", errors.New("invalid id"), 1},
+		{"inconsistent", errInconsistent, 1},
+	}
+
+	for i, v := range testcases {
+		if got := exitCode(v.err, io.Discard); got != v.expected {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, got, v.expected)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// rewrites make statements of the schema file keep what exists, rows and objects already there are not touched
+var rewrites = []struct{ prefix, replacement string }{
+	{"CREATE TABLE ", "CREATE TABLE IF NOT EXISTS "},
+	{"CREATE TRIGGER ", "CREATE TRIGGER IF NOT EXISTS "},
+	{"INSERT INTO ", "INSERT IGNORE INTO "},
+}
+
+// columnsQuery lists the columns of every table in the database migrate runs against
+const columnsQuery = "SELECT TABLE_NAME,COLUMN_NAME FROM information_schema.COLUMNS WHERE TABLE_SCHEMA=DATABASE()"
+
+// definitions are the lines of CREATE TABLE which are not columns
+var definitions = []string{"PRIMARY", "INDEX", "KEY", "UNIQUE", "FOREIGN", "CONSTRAINT", "CHECK", "FULLTEXT", "SPATIAL"}
+
+// migrate applies the schema file, missing tables are created and seed rows added, existing tables are not altered.
+// It fails before changing anything when an existing table lacks a column of the schema file, such a table has to be
+// altered by hand. With -reset every table is dropped and created again, which loses every row, so it needs -yes.
+func (a app) migrate(ctx context.Context, args []string) error {
+	var (
+		path       string
+		reset, yes bool
+	)
+
+	_, err := parse("migrate", args, 0, func(flags *flag.FlagSet) {
+		flags.StringVar(&path, "file", "db.sql", "schema file")
+		flags.BoolVar(&reset, "reset", false, "drop and create every table")
+		flags.BoolVar(&yes, "yes", false, "confirm -reset")
+	})
+	if err != nil {
+		return err
+	}
+
+	if reset && !yes {
+		return fmt.Errorf("%w : migrate -reset drops every table and its rows, confirm with -yes", errUsage)
+	}
+
+	r, err := a.open(path)
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	schema, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	stmts := statements(string(schema))
+
+	if reset {
+		stmts = dropFirst(stmts)
+	} else if err = a.checkColumns(ctx, stmts); err != nil {
+		return err
+	}
+
+	applied := 0
+
+	for _, stmt := range stmts {
+		if !reset {
+			if stmt = keepExisting(stmt); stmt == "" {
+				continue
+			}
+		}
+
+		if _, err = a.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("statement %d : %w", applied+1, err)
+		}
+
+		applied++
+
+		slog.DebugContext(ctx, "applied statement", "statement", firstLine(stmt))
+	}
+
+	return a.out.write(struct {
+		Statements int `json:"statements"`
+	}{applied}, table{header: []string{"STATEMENTS"}, rows: [][]string{{strconv.Itoa(applied)}}})
+}
+
+// checkColumns returns an error naming the columns existing tables are missing, tables which do not exist are left
+// to be created
+func (a app) checkColumns(ctx context.Context, stmts []string) error {
+	rows, err := a.db.QueryContext(ctx, columnsQuery)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	existing := make(map[string]map[string]bool)
+
+	for rows.Next() {
+		var table, column string
+
+		if err = rows.Scan(&table, &column); err != nil {
+			return err
+		}
+
+		if existing[strings.ToLower(table)] == nil {
+			existing[strings.ToLower(table)] = make(map[string]bool)
+		}
+
+		existing[strings.ToLower(table)][strings.ToLower(column)] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, stmt := range stmts {
+		table, columns := tableColumns(stmt)
+
+		have, ok := existing[strings.ToLower(table)]
+		if table == "" || !ok {
+			continue
+		}
+
+		var missing []string
+
+		for _, column := range columns {
+			if !have[strings.ToLower(column)] {
+				missing = append(missing, column)
+			}
+		}
+
+		if len(missing) > 0 {
+			errs = append(errs, fmt.Errorf("table %s is missing columns %s", table, strings.Join(missing, ",")))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w, alter the tables or migrate with -reset", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// tableColumns returns the table and column names of a CREATE TABLE statement, table is empty for other statements
+func tableColumns(stmt string) (string, []string) {
+	lines := strings.Split(stmt, "\n")
+
+	fields := strings.Fields(strings.ReplaceAll(lines[0], "(", " "))
+	if len(fields) < 3 || !strings.EqualFold(fields[0], "CREATE") || !strings.EqualFold(fields[1], "TABLE") {
+		return "", nil
+	}
+
+	var columns []string
+
+	for _, line := range lines[1:] {
+		name, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if name == "" || name == ")" || strings.HasPrefix(name, "--") || isDefinition(name) {
+			continue
+		}
+
+		columns = append(columns, strings.TrimSuffix(name, ","))
+	}
+
+	return fields[2], columns
+}
+
+func isDefinition(name string) bool {
+	for _, d := range definitions {
+		if strings.EqualFold(name, d) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// statements splits the schema file, a statement ends with ; or a blank line and comment lines before it are dropped
+func statements(schema string) []string {
+	var (
+		stmts   []string
+		current []string
+	)
+
+	end := func() {
+		if stmt := strings.TrimSpace(strings.Join(current, "\n")); stmt != "" {
+			stmts = append(stmts, strings.TrimSuffix(stmt, ";"))
+		}
+
+		current = nil
+	}
+
+	for _, line := range strings.Split(schema, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			end()
+		case len(current) == 0 && strings.HasPrefix(trimmed, "--"):
+		default:
+			current = append(current, line)
+
+			if strings.HasSuffix(trimmed, ";") {
+				end()
+			}
+		}
+	}
+
+	end()
+
+	return stmts
+}
+
+// dropFirst replaces the drops of the schema file with drops of every table it creates, latest created first, so
+// tables go before the tables their foreign keys reference
+func dropFirst(stmts []string) []string {
+	var drops, rest []string
+
+	for _, stmt := range stmts {
+		if strings.HasPrefix(strings.ToUpper(stmt), "DROP ") {
+			continue
+		}
+
+		if table, _ := tableColumns(stmt); table != "" {
+			drops = append([]string{"DROP TABLE IF EXISTS " + table}, drops...)
+		}
+
+		rest = append(rest, stmt)
+	}
+
+	return append(drops, rest...)
+}
+
+// keepExisting rewrites stmt to leave existing tables and rows alone, drops are skipped
+func keepExisting(stmt string) string {
+	upper := strings.ToUpper(stmt)
+
+	if strings.HasPrefix(upper, "DROP ") {
+		return ""
+	}
+
+	for _, r := range rewrites {
+		if strings.HasPrefix(upper, r.prefix) {
+			return r.replacement + stmt[len(r.prefix):]
+		}
+	}
+
+	return stmt
+}
+
+func firstLine(stmt string) string {
+	line, _, _ := strings.Cut(stmt, "\n")
+
+	return line
+}
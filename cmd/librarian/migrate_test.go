@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+const schema = `DROP TABLE IF EXISTS Author;
+create table Author (
+    authorId INT,
+    -- pen name is optional
+    penName varchar(50)
+)
+
+-- Audit is append-only
+CREATE TRIGGER audit_no_update BEFORE UPDATE ON Audit FOR EACH ROW
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'Audit is append-only'
+
+INSERT INTO Role(name) VALUES ('admin')
+`
+
+// Test_Statements function is to test the schema file is split into statements
+func Test_Statements(t *testing.T) {
+	expected := []string{
+		"DROP TABLE IF EXISTS Author",
+		"create table Author (\n    authorId INT,\n    -- pen name is optional\n    penName varchar(50)\n)",
+		"CREATE TRIGGER audit_no_update BEFORE UPDATE ON Audit FOR EACH ROW\n" +
+			"    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'Audit is append-only'",
+		"INSERT INTO Role(name) VALUES ('admin')",
+	}
+
+	if got := statements(schema); !reflect.DeepEqual(got, expected) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %q\tExpected %q\n", "statements", 1, got, expected)
+	}
+}
+
+// Test_Migrate function is to test existing tables are kept unless reset
+func Test_Migrate(t *testing.T) {
+	stmts := statements(schema)
+
+	testcases := []struct {
+		desc     string
+		args     []string
+		expected []string
+		err      error
+	}{
+		{desc: "keep existing", args: nil, expected: []string{
+			"CREATE TABLE IF NOT EXISTS Author (\n    authorId INT,\n    -- pen name is optional\n    penName varchar(50)\n)",
+			"CREATE TRIGGER IF NOT EXISTS audit_no_update BEFORE UPDATE ON Audit FOR EACH ROW\n" +
+				"    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'Audit is append-only'",
+			"INSERT IGNORE INTO Role(name) VALUES ('admin')",
+		}},
+		{desc: "reset", args: []string{"-reset", "-yes"}, expected: stmts},
+		{desc: "reset not confirmed", args: []string{"-reset"}, err: errUsage},
+	}
+
+	for i, v := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		if v.args == nil {
+			mock.ExpectQuery(columnsQuery).WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_NAME"}).
+				AddRow("Author", "authorId").AddRow("Author", "penName"))
+		}
+
+		for _, stmt := range v.expected {
+			mock.ExpectExec(stmt).WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+
+		a := app{db: db, stdin: strings.NewReader(schema), out: output{w: io.Discard, format: formatJSON}}
+
+		if err = a.run(context.Background(), append([]string{"migrate", "-file", "-"}, v.args...)); !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, nil)
+		}
+
+		db.Close()
+	}
+}
+
+// Test_MigrateSchemaFile function is to test db.sql creates the tables the datastores use and refuses old tables
+func Test_MigrateSchemaFile(t *testing.T) {
+	file, err := os.ReadFile("../../db.sql")
+	if err != nil {
+		t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "read db.sql", 1, err, nil)
+	}
+
+	stmts := statements(string(file))
+
+	tables := make(map[string][]string)
+
+	for _, stmt := range stmts {
+		if table, columns := tableColumns(stmt); table != "" {
+			tables[table] = columns
+		}
+	}
+
+	// tables are dropped before the tables their foreign keys reference
+	dropped := make(map[string]int)
+
+	for i, stmt := range dropFirst(stmts) {
+		if table, ok := strings.CutPrefix(stmt, "DROP TABLE IF EXISTS "); ok {
+			dropped[table] = i
+		}
+	}
+
+	for _, v := range [][2]string{{"Book", "Author"}, {"Loan", "Book"}, {"Loan", "User"}, {"OutboxSent", "Outbox"}} {
+		if dropped[v[0]] >= dropped[v[1]] {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "drop order", 1, dropped, v[0]+" before "+v[1])
+		}
+	}
+
+	expected := []string{"bookId", "authorId", "title", "Publication", "PublishedDate", "isbn", "deleted_at", "updated_at"}
+	if !reflect.DeepEqual(tables["Book"], expected) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "book columns", 1, tables["Book"], expected)
+	}
+
+	var created []string
+
+	for _, stmt := range stmts {
+		if stmt = keepExisting(stmt); stmt != "" {
+			created = append(created, stmt)
+		}
+	}
+
+	testcases := []struct {
+		desc     string
+		existing [][2]string
+		expected []string
+		err      string
+	}{
+		{desc: "empty database", expected: created},
+		{desc: "book before trash", existing: [][2]string{{"Book", "bookId"}, {"Book", "authorId"}, {"Book", "title"},
+			{"Book", "Publication"}, {"Book", "PublishedDate"}},
+			err: "table Book is missing columns isbn,deleted_at,updated_at, alter the tables or migrate with -reset"},
+	}
+
+	for i, v := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		rows := sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_NAME"})
+		for _, c := range v.existing {
+			rows.AddRow(c[0], c[1])
+		}
+
+		mock.ExpectQuery(columnsQuery).WillReturnRows(rows)
+
+		// nothing is changed when a table is missing columns
+		for _, stmt := range v.expected {
+			mock.ExpectExec(stmt).WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+
+		a := app{db: db, stdin: bytes.NewReader(file), out: output{w: io.Discard, format: formatJSON}}
+
+		err = a.run(context.Background(), []string{"migrate", "-file", "-"})
+		if (err == nil && v.err != "") || (err != nil && err.Error() != v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+2, err, v.err)
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+2, err, nil)
+		}
+
+		db.Close()
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"Three-Layer-Architecture/models"
+)
+
+// Output formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// table is a header and its rows, printed aligned in columns
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// output prints results as indented JSON or as tables
+type output struct {
+	w      io.Writer
+	format string
+}
+
+// affected is the result of commands changing rows
+type affected struct {
+	Affected int `json:"affected"`
+}
+
+// write prints v as JSON, or tables with a blank line between them
+func (o output) write(v any, tables ...table) error {
+	if o.format == formatJSON {
+		enc := json.NewEncoder(o.w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+
+	for i, t := range tables {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+
+		fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+
+		for _, row := range t.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+	}
+
+	return tw.Flush()
+}
+
+func bookTable(books ...models.Book) table {
+	t := table{header: []string{"ID", "TITLE", "AUTHOR", "PUBLICATION", "PUBLISHED"}}
+
+	for _, b := range books {
+		t.rows = append(t.rows, []string{strconv.Itoa(b.BookID), b.Title,
+			strconv.Itoa(b.AuthorID) + " " + strings.TrimSpace(b.Auth.FirstName+" "+b.Auth.LastName), b.Publication,
+			b.PublishedDate})
+	}
+
+	return t
+}
+
+func authorTable(authors ...models.Author) table {
+	t := table{header: []string{"ID", "FIRST NAME", "LAST NAME", "DOB", "PEN NAME"}}
+
+	for _, a := range authors {
+		t.rows = append(t.rows, []string{strconv.Itoa(a.AuthID), a.FirstName, a.LastName, a.Dob, a.PenName})
+	}
+
+	return t
+}
+
+func affectedTable(n int) table {
+	return table{header: []string{"AFFECTED"}, rows: [][]string{{strconv.Itoa(n)}}}
+}
+
+func importTables(result models.ImportResult) []table {
+	summary := table{header: []string{"ROWS", "VALID", "IMPORTED", "FAILED", "DRY RUN"}, rows: [][]string{{
+		strconv.Itoa(result.Rows), strconv.Itoa(result.Valid), strconv.Itoa(result.Imported), strconv.Itoa(result.Failed),
+		strconv.FormatBool(result.DryRun)}}}
+
+	if len(result.Errors) == 0 {
+		return []table{summary}
+	}
+
+	errs := table{header: []string{"ROW", "BOOK", "ERROR"}}
+
+	for _, e := range result.Errors {
+		errs.rows = append(errs.rows, []string{strconv.Itoa(e.Row), strconv.Itoa(e.BookID), e.Error})
+	}
+
+	return []table{summary, errs}
+}
+
+func userTable(u models.User) table {
+	return table{header: []string{"ID", "USERNAME", "EMAIL", "CREATED"},
+		rows: [][]string{{strconv.Itoa(u.ID), u.Username, u.Email, u.CreatedAt.Format("2006-01-02 15:04:05")}}}
+}
+
+func roleTable(roles ...models.Role) table {
+	t := table{header: []string{"ROLE", "PERMISSIONS"}}
+
+	for _, r := range roles {
+		t.rows = append(t.rows, []string{r.Name, strings.Join(r.Permissions, ",")})
+	}
+
+	return t
+}
+
+func inconsistencyTable(found ...models.Inconsistency) table {
+	t := table{header: []string{"CHECK", "ENTITY", "ID", "DETAIL"}}
+
+	for _, f := range found {
+		t.rows = append(t.rows, []string{f.Check, f.Entity, strconv.Itoa(f.EntityID), f.Detail})
+	}
+
+	return t
+}
@@ -0,0 +1,37 @@
+// Package cql parses Contextual Query Language queries of SRU, prefix assignments and sortBy are not supported
+package cql
+
+// Node is a Clause or a Boolean
+type Node interface {
+	node()
+}
+
+// Modifier is /name or /name=value after a relation or boolean
+type Modifier struct {
+	Name       string
+	Comparison string
+	Value      string
+}
+
+// Clause is a search term on an index, a bare term has index cql.serverChoice and relation =.
+// Term is unquoted and keeps backslash escapes, so masking characters can be told from escaped ones.
+type Clause struct {
+	Index     string
+	Relation  string
+	Modifiers []Modifier
+	Term      string
+}
+
+// Boolean combines two nodes with and, or, not or prox, operators are lower case
+type Boolean struct {
+	Op        string
+	Modifiers []Modifier
+	Left      Node
+	Right     Node
+}
+
+func (Clause) node()  {}
+func (Boolean) node() {}
+
+// ServerChoice is the index of terms without one
+const ServerChoice = "cql.serverChoice"
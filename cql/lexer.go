@@ -0,0 +1,114 @@
+package cql
+
+import (
+	"fmt"
+	"strings"
+)
+
+type kind int
+
+const (
+	eof kind = iota
+	word
+	quoted
+	lparen
+	rparen
+	slash
+	comparitor
+)
+
+type token struct {
+	kind  kind
+	value string
+	pos   int
+}
+
+// SyntaxError is a query that is not valid CQL, Pos is the byte offset it was found at
+type SyntaxError struct {
+	Pos     int
+	Message string
+}
+
+func (e SyntaxError) Error() string {
+	return fmt.Sprintf("%s at %d", e.Message, e.Pos)
+}
+
+// symbols are comparitors, longest first
+var symbols = []string{"==", "<>", "<=", ">=", "=", "<", ">"}
+
+func lex(query string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(query); {
+		c := query[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: lparen, value: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: rparen, value: ")", pos: i})
+			i++
+		case c == '/':
+			tokens = append(tokens, token{kind: slash, value: "/", pos: i})
+			i++
+		case c == '"':
+			value, end, err := readQuoted(query, i)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: quoted, value: value, pos: i})
+			i = end
+		case strings.ContainsRune("=<>", rune(c)):
+			for _, s := range symbols {
+				if strings.HasPrefix(query[i:], s) {
+					tokens = append(tokens, token{kind: comparitor, value: s, pos: i})
+					i += len(s)
+
+					break
+				}
+			}
+		default:
+			start := i
+			for i < len(query) && !strings.ContainsRune(" \t\n\r()/\"=<>", rune(query[i])) {
+				// escaped characters belong to the word
+				if query[i] == '\\' && i+1 < len(query) {
+					i++
+				}
+
+				i++
+			}
+
+			tokens = append(tokens, token{kind: word, value: query[start:i], pos: start})
+		}
+	}
+
+	return append(tokens, token{kind: eof, pos: len(query)}), nil
+}
+
+// readQuoted returns the content of a quoted string starting at i and the offset after it, escapes are kept
+func readQuoted(query string, i int) (string, int, error) {
+	var b strings.Builder
+
+	for j := i + 1; j < len(query); j++ {
+		switch query[j] {
+		case '\\':
+			if j+1 >= len(query) {
+				return "", 0, SyntaxError{Pos: j, Message: "unfinished escape"}
+			}
+
+			b.WriteByte(query[j])
+			b.WriteByte(query[j+1])
+			j++
+		case '"':
+			return b.String(), j + 1, nil
+		default:
+			b.WriteByte(query[j])
+		}
+	}
+
+	return "", 0, SyntaxError{Pos: i, Message: "unterminated quoted string"}
+}
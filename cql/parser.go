@@ -0,0 +1,181 @@
+package cql
+
+import "strings"
+
+// named relations, they are words where a symbol could be
+var named = map[string]bool{"all": true, "any": true, "adj": true, "exact": true, "within": true, "encloses": true}
+
+var booleans = map[string]bool{"and": true, "or": true, "not": true, "prox": true}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse returns the tree of a CQL query, booleans bind left to right with equal precedence
+func Parse(query string) (Node, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	node, err := p.query()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(0); t.kind != eof {
+		return nil, SyntaxError{Pos: t.pos, Message: "unexpected " + t.value}
+	}
+
+	return node, nil
+}
+
+func (p *parser) peek(n int) token {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+
+	return p.tokens[p.pos+n]
+}
+
+func (p *parser) next() token {
+	t := p.peek(0)
+	if t.kind != eof {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) query() (Node, error) {
+	left, err := p.searchClause()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek(0)
+		if t.kind != word || !booleans[strings.ToLower(t.value)] {
+			return left, nil
+		}
+
+		p.next()
+
+		b := Boolean{Op: strings.ToLower(t.value), Left: left}
+
+		if b.Modifiers, err = p.modifiers(); err != nil {
+			return nil, err
+		}
+
+		if b.Right, err = p.searchClause(); err != nil {
+			return nil, err
+		}
+
+		left = b
+	}
+}
+
+func (p *parser) searchClause() (Node, error) {
+	t := p.peek(0)
+
+	switch {
+	case t.kind == lparen:
+		p.next()
+
+		node, err := p.query()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.next(); closing.kind != rparen {
+			return nil, SyntaxError{Pos: closing.pos, Message: "missing )"}
+		}
+
+		return node, nil
+	case t.kind != word && t.kind != quoted:
+		return nil, SyntaxError{Pos: t.pos, Message: "expected search term"}
+	}
+
+	if p.isRelation() {
+		index := p.next()
+
+		relation := p.next()
+		c := Clause{Index: index.value, Relation: relation.value}
+
+		if relation.kind == word {
+			c.Relation = strings.ToLower(relation.value)
+		}
+
+		var err error
+		if c.Modifiers, err = p.modifiers(); err != nil {
+			return nil, err
+		}
+
+		term := p.next()
+		if term.kind != word && term.kind != quoted {
+			return nil, SyntaxError{Pos: term.pos, Message: "expected search term"}
+		}
+
+		c.Term = term.value
+
+		return c, nil
+	}
+
+	p.next()
+
+	return Clause{Index: ServerChoice, Relation: "=", Term: t.value}, nil
+}
+
+// isRelation tells if the word at pos is an index followed by a relation
+func (p *parser) isRelation() bool {
+	if p.peek(0).kind != word {
+		return false
+	}
+
+	next := p.peek(1)
+	if next.kind == comparitor {
+		return true
+	}
+
+	if next.kind != word || !named[strings.ToLower(next.value)] {
+		return false
+	}
+
+	// "title all" alone is two terms short of a clause
+	after := p.peek(2)
+
+	return after.kind == word || after.kind == quoted || after.kind == slash
+}
+
+func (p *parser) modifiers() ([]Modifier, error) {
+	var mods []Modifier
+
+	for p.peek(0).kind == slash {
+		p.next()
+
+		name := p.next()
+		if name.kind != word {
+			return nil, SyntaxError{Pos: name.pos, Message: "expected modifier"}
+		}
+
+		m := Modifier{Name: name.value}
+
+		if p.peek(0).kind == comparitor {
+			m.Comparison = p.next().value
+
+			value := p.next()
+			if value.kind != word && value.kind != quoted {
+				return nil, SyntaxError{Pos: value.pos, Message: "expected modifier value"}
+			}
+
+			m.Value = value.value
+		}
+
+		mods = append(mods, m)
+	}
+
+	return mods, nil
+}
@@ -0,0 +1,71 @@
+package cql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestParse function is to test clauses, booleans, grouping and modifiers are parsed
+func TestParse(t *testing.T) {
+	title := Clause{Index: "dc.title", Relation: "=", Term: "2 States"}
+
+	testcases := []struct {
+		desc  string
+		query string
+		resp  Node
+	}{
+		{desc: "bare term", query: "bhagat", resp: Clause{Index: ServerChoice, Relation: "=", Term: "bhagat"}},
+		{desc: "quoted term with escapes", query: `"say \"hi\" \*"`,
+			resp: Clause{Index: ServerChoice, Relation: "=", Term: `say \"hi\" \*`}},
+		{desc: "index and relation", query: `dc.title = "2 States"`, resp: title},
+		{desc: "named relation", query: "title ALL \"indian girl\"",
+			resp: Clause{Index: "title", Relation: "all", Term: "indian girl"}},
+		{desc: "comparison", query: "date>=2016", resp: Clause{Index: "date", Relation: ">=", Term: "2016"}},
+		{desc: "relation modifier", query: "title =/relevant/locale=en girl",
+			resp: Clause{Index: "title", Relation: "=", Term: "girl",
+				Modifiers: []Modifier{{Name: "relevant"}, {Name: "locale", Comparison: "=", Value: "en"}}}},
+		{desc: "left to right", query: `dc.title = "2 States" or author = lee not date < 1970`,
+			resp: Boolean{Op: "not",
+				Left:  Boolean{Op: "or", Left: title, Right: Clause{Index: "author", Relation: "=", Term: "lee"}},
+				Right: Clause{Index: "date", Relation: "<", Term: "1970"}}},
+		{desc: "parentheses", query: `(a OR b) PROX/unit=word c`,
+			resp: Boolean{Op: "prox", Modifiers: []Modifier{{Name: "unit", Comparison: "=", Value: "word"}},
+				Left: Boolean{Op: "or", Left: Clause{Index: ServerChoice, Relation: "=", Term: "a"},
+					Right: Clause{Index: ServerChoice, Relation: "=", Term: "b"}},
+				Right: Clause{Index: ServerChoice, Relation: "=", Term: "c"}}},
+		{desc: "relation word as term", query: "any", resp: Clause{Index: ServerChoice, Relation: "=", Term: "any"}},
+	}
+
+	for i, v := range testcases {
+		resp, err := Parse(v.query)
+
+		if err != nil || !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", v.desc, i+1, resp, err, v.resp)
+		}
+	}
+}
+
+// TestParse_Errors function is to test invalid queries are syntax errors
+func TestParse_Errors(t *testing.T) {
+	testcases := []struct {
+		desc  string
+		query string
+	}{
+		{desc: "empty", query: ""},
+		{desc: "unterminated quote", query: `title = "girl`},
+		{desc: "missing term", query: "title ="},
+		{desc: "missing parenthesis", query: "(a or b"},
+		{desc: "dangling boolean", query: "a and"},
+		{desc: "extra parenthesis", query: "a)"},
+		{desc: "missing modifier", query: "title =/ girl"},
+	}
+
+	for i, v := range testcases {
+		var syntax SyntaxError
+
+		if _, err := Parse(v.query); !errors.As(err, &syntax) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, "syntax error")
+		}
+	}
+}
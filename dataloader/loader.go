@@ -0,0 +1,96 @@
+// Package dataloader batches the lookups of one request, keys asked for before the first value is read are loaded
+// by a single call and every key is loaded at most once
+package dataloader
+
+import (
+	"context"
+	"sync"
+)
+
+// Batch loads the values of keys, keys which are not found are missing from the map
+type Batch[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader must not be shared between requests, values are kept for its whole life
+type Loader[K comparable, V any] struct {
+	batch Batch[K, V]
+
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]bool
+	values  map[K]V
+	errs    map[K]error
+	batches int
+}
+
+func New[K comparable, V any](batch Batch[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{batch: batch, queued: map[K]bool{}, values: map[K]V{}, errs: map[K]error{}}
+}
+
+// Load queues key and returns a thunk of its value, ok is false when key was not found. The batch runs when the first
+// thunk of the pending keys is called, so callers queue every key they know of before reading any value.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) func() (value V, ok bool, err error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, bool, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.pending) > 0 && !l.loaded(key) {
+			l.flush(ctx)
+		}
+
+		if err := l.errs[key]; err != nil {
+			var zero V
+
+			return zero, false, err
+		}
+
+		value, ok := l.values[key]
+
+		return value, ok, nil
+	}
+}
+
+// Batches returns how many times the batch ran
+func (l *Loader[K, V]) Batches() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.batches
+}
+
+func (l *Loader[K, V]) loaded(key K) bool {
+	for _, k := range l.pending {
+		if k == key {
+			return false
+		}
+	}
+
+	return true
+}
+
+// flush loads every pending key, an error of the batch is the error of each of its keys
+func (l *Loader[K, V]) flush(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+	l.batches++
+
+	values, err := l.batch(ctx, keys)
+
+	for _, k := range keys {
+		if err != nil {
+			l.errs[k] = err
+
+			continue
+		}
+
+		if v, ok := values[k]; ok {
+			l.values[k] = v
+		}
+	}
+}
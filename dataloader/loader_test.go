@@ -0,0 +1,76 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestLoad function is to test keys queued before the first read are loaded by one batch
+func TestLoad(t *testing.T) {
+	var calls [][]int
+
+	loader := New(func(_ context.Context, keys []int) (map[int]string, error) {
+		calls = append(calls, keys)
+
+		values := map[int]string{}
+		for _, k := range keys {
+			if k != 9 {
+				values[k] = "v" + string(rune('0'+k))
+			}
+		}
+
+		return values, nil
+	})
+
+	ctx := context.Background()
+	first, second, again, missing := loader.Load(ctx, 1), loader.Load(ctx, 2), loader.Load(ctx, 1), loader.Load(ctx, 9)
+
+	testcases := []struct {
+		desc  string
+		thunk func() (string, bool, error)
+		value string
+		ok    bool
+	}{
+		{desc: "first", thunk: first, value: "v1", ok: true},
+		{desc: "second", thunk: second, value: "v2", ok: true},
+		{desc: "repeated key", thunk: again, value: "v1", ok: true},
+		{desc: "missing key", thunk: missing},
+	}
+
+	for i, v := range testcases {
+		value, ok, err := v.thunk()
+		if value != v.value || ok != v.ok || err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v %v\tExpected %v %v\n", v.desc, i+1, value, ok, err, v.value, v.ok)
+		}
+	}
+
+	// a key asked for after the batch runs in a batch of its own, loaded keys are not loaded again
+	if value, ok, err := loader.Load(ctx, 3)(); value != "v3" || !ok || err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v %v\tExpected %v\n", "key after the batch", 1, value, ok, err, "v3")
+	}
+
+	if value, _, _ := loader.Load(ctx, 2)(); value != "v2" {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "loaded key", 1, value, "v2")
+	}
+
+	if expected := [][]int{{1, 2, 9}, {3}}; !reflect.DeepEqual(calls, expected) || loader.Batches() != 2 {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "batches", 1, calls, expected)
+	}
+}
+
+// TestLoad_Error function is to test an error of the batch is returned for each of its keys
+func TestLoad_Error(t *testing.T) {
+	loader := New(func(_ context.Context, keys []int) (map[int]string, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	first, second := loader.Load(context.Background(), 1), loader.Load(context.Background(), 2)
+
+	for i, thunk := range []func() (string, bool, error){first, second} {
+		if _, ok, err := thunk(); ok || err == nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "error", i+1, ok, err, "connection refused")
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"Three-Layer-Architecture/models"
+)
+
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// Post method is to store a new api key
+func (d Datastore) Post(ctx context.Context, key models.APIKey) (models.APIKey, error) {
+	res, err := d.db.ExecContext(ctx, "insert into ApiKey(prefix,hash,name,principal,createdAt) values (?,?,?,?,?)",
+		key.Prefix, key.Hash, key.Name, key.Principal, key.CreatedAt)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.APIKey{}, err
+	}
+
+	key.ID = int(id)
+
+	return key, nil
+}
+
+// GetByPrefix method is to get the key which is not revoked by its public prefix
+func (d Datastore) GetByPrefix(ctx context.Context, prefix string) (models.APIKey, error) {
+	row := d.db.QueryRowContext(ctx, "SELECT id,prefix,hash,name,principal,createdAt FROM ApiKey WHERE prefix=? and revokedAt IS NULL", prefix)
+
+	var key models.APIKey
+
+	if err := row.Scan(&key.ID, &key.Prefix, &key.Hash, &key.Name, &key.Principal, &key.CreatedAt); err != nil {
+		return models.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+// Revoke method is to disable a key, it is kept for audit
+func (d Datastore) Revoke(ctx context.Context, iD string) (int, error) {
+	id, err := strconv.Atoi(iD)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := d.db.ExecContext(ctx, "UPDATE ApiKey SET revokedAt=NOW() WHERE id=? and revokedAt IS NULL", id)
+	if err != nil {
+		return 0, err
+	}
+
+	rowAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if rowAffected == 0 {
+		return 0, sql.ErrNoRows
+	}
+
+	return int(rowAffected), nil
+}
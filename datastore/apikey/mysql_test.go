@@ -0,0 +1,80 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"Three-Layer-Architecture/models"
+)
+
+// Test_GetByPrefix api key
+func Test_GetByPrefix(t *testing.T) {
+	at := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc   string
+		prefix string
+		rows   *sqlmock.Rows
+		resp   models.APIKey
+		err    error
+	}{
+		{desc: "valid", prefix: "ab12", rows: sqlmock.NewRows([]string{"id", "prefix", "hash", "name", "principal", "createdAt"}).
+			AddRow(1, "ab12", "f00d", "discovery", "svc-discovery", at), resp: models.APIKey{ID: 1, Prefix: "ab12",
+			Hash: "f00d", Name: "discovery", Principal: "svc-discovery", CreatedAt: at}},
+		{desc: "revoked or unknown", prefix: "cd34", rows: sqlmock.NewRows([]string{"id", "prefix", "hash", "name", "principal",
+			"createdAt"}), err: sql.ErrNoRows},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectQuery("SELECT id,prefix,hash,name,principal,createdAt FROM ApiKey WHERE prefix=? and revokedAt IS NULL").
+			WithArgs(v.prefix).WillReturnRows(v.rows)
+
+		d := New(db)
+
+		resp, err := d.GetByPrefix(context.Background(), v.prefix)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Test_Post api key
+func Test_Post(t *testing.T) {
+	at := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectExec("insert into ApiKey(prefix,hash,name,principal,createdAt) values (?,?,?,?,?)").
+		WithArgs("ab12", "f00d", "discovery", "svc-discovery", at).WillReturnResult(sqlmock.NewResult(3, 1))
+
+	d := New(db)
+
+	resp, err := d.Post(context.Background(), models.APIKey{Prefix: "ab12", Hash: "f00d", Name: "discovery", Principal: "svc-discovery", CreatedAt: at})
+	if resp.ID != 3 || err != nil {
+		t.Errorf("[TEST1]Failed. Got %v, %v\tExpected id 3, <nil>\n", resp, err)
+	}
+}
@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/principal"
+)
+
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// Record appends an entry in Audit table for a change done by the caller in ctx, in the transaction of the change so
+// the entry is kept exactly when the change is. before or after is nil when it does not exist, entries are never
+// updated or deleted
+func Record(ctx context.Context, tx *sql.Tx, operation, entity string, entityID int, before, after any) error {
+	var (
+		beforeData, afterData []byte
+		err                   error
+	)
+
+	if before != nil {
+		if beforeData, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+
+	if after != nil {
+		if afterData, err = json.Marshal(after); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, "insert into Audit(actor,occurredAt,operation,entity,entityId,beforeData,afterData) values (?,?,?,?,?,?,?)",
+		principal.FromContext(ctx).ID, time.Now().UTC(), operation, entity, entityID, nullJSON(beforeData), nullJSON(afterData))
+
+	return err
+}
+
+// Get method is to read Audit entries matching the filter, oldest first
+func (d Datastore) Get(ctx context.Context, filter models.AuditFilter) ([]models.Audit, error) {
+	var (
+		where []string
+		args  []any
+	)
+
+	if filter.Entity != "" {
+		where = append(where, "entity=?")
+		args = append(args, filter.Entity)
+	}
+
+	if filter.EntityID != 0 {
+		where = append(where, "entityId=?")
+		args = append(args, filter.EntityID)
+	}
+
+	if !filter.From.IsZero() {
+		where = append(where, "occurredAt>=?")
+		args = append(args, filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		where = append(where, "occurredAt<?")
+		args = append(args, filter.To)
+	}
+
+	query := "SELECT id,actor,occurredAt,operation,entity,entityId,beforeData,afterData FROM Audit"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " and ")
+	}
+
+	rows, err := d.db.QueryContext(ctx, query+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var audits []models.Audit
+
+	for rows.Next() {
+		var (
+			a             models.Audit
+			before, after []byte
+		)
+
+		if err = rows.Scan(&a.ID, &a.Actor, &a.Timestamp, &a.Operation, &a.Entity, &a.EntityID, &before, &after); err != nil {
+			return nil, err
+		}
+
+		a.Before, a.After = before, after
+
+		audits = append(audits, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return audits, nil
+}
+
+// nullJSON stores missing before/after state as NULL instead of empty string
+func nullJSON(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return string(data)
+}
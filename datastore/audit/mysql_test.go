@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/principal"
+)
+
+// Test_Record audit entry of the caller in the transaction of the change
+func Test_Record(t *testing.T) {
+	ctx := principal.NewContext(context.Background(), models.Principal{ID: "user:librarian-1", Name: "Asha"})
+	connErr := errors.New("connection refused")
+
+	testcases := []struct {
+		desc      string
+		ctx       context.Context
+		operation string
+		before    any
+		after     any
+		args      []driver.Value
+		err       error
+	}{
+		{desc: "create", ctx: ctx, operation: models.AuditCreate, after: models.Book{BookID: 1, Title: "2 States"},
+			args: []driver.Value{"user:librarian-1", models.AuditCreate, nil, `{"bookID":1,"authorID":0,"auth":{"authID":0,` +
+				`"firstName":"","lastName":"","dob":"","penName":""},"title":"2 States","publication":"","publishedDate":""}`}},
+		{desc: "delete without caller", ctx: context.Background(), operation: models.AuditDelete,
+			before: models.Author{AuthID: 1, FirstName: "Rajan"}, args: []driver.Value{principal.Anonymous.ID, models.AuditDelete,
+				`{"authID":1,"firstName":"Rajan","lastName":"","dob":"","penName":""}`, nil}},
+		{desc: "db error", ctx: ctx, operation: models.AuditDelete, before: models.Author{AuthID: 1},
+			args: []driver.Value{"user:librarian-1", models.AuditDelete, sqlmock.AnyArg(), nil}, err: connErr},
+	}
+
+	for i, v := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("insert into Audit(actor,occurredAt,operation,entity,entityId,beforeData,afterData) values (?,?,?,?,?,?,?)").
+			WithArgs(v.args[0], sqlmock.AnyArg(), v.args[1], models.EntityBook, 1, v.args[2], v.args[3]).
+			WillReturnResult(sqlmock.NewResult(7, 1)).WillReturnError(v.err)
+
+		tx, _ := db.Begin()
+
+		err = Record(v.ctx, tx, v.operation, models.EntityBook, 1, v.before, v.after)
+		if !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, nil)
+		}
+
+		db.Close()
+	}
+}
+
+// Test_Get audit entries by filter
+func Test_Get(t *testing.T) {
+	at := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	columns := []string{"id", "actor", "occurredAt", "operation", "entity", "entityId", "beforeData", "afterData"}
+
+	testcases := []struct {
+		desc   string
+		filter models.AuditFilter
+		query  string
+		args   []driver.Value
+		resp   []models.Audit
+	}{
+		{desc: "no filter", query: "SELECT id,actor,occurredAt,operation,entity,entityId,beforeData,afterData FROM Audit ORDER BY id",
+			resp: []models.Audit{{ID: 1, Actor: "anonymous", Timestamp: at, Operation: models.AuditCreate,
+				Entity: models.EntityBook, EntityID: 1, After: json.RawMessage(`{"bookID":1}`)}}},
+		{desc: "entity and range", filter: models.AuditFilter{Entity: models.EntityBook, EntityID: 1, From: at, To: at.Add(time.Hour)},
+			query: "SELECT id,actor,occurredAt,operation,entity,entityId,beforeData,afterData FROM Audit " +
+				"WHERE entity=? and entityId=? and occurredAt>=? and occurredAt<? ORDER BY id",
+			args: []driver.Value{models.EntityBook, 1, at, at.Add(time.Hour)},
+			resp: []models.Audit{{ID: 1, Actor: "anonymous", Timestamp: at, Operation: models.AuditCreate,
+				Entity: models.EntityBook, EntityID: 1, After: json.RawMessage(`{"bookID":1}`)}}},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectQuery(v.query).WithArgs(v.args...).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "anonymous", at, "create", "book", 1, nil, []byte(`{"bookID":1}`)))
+
+		d := New(db)
+
+		resp, err := d.Get(context.Background(), v.filter)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected <nil>\n", v.desc, i+1, err)
+		}
+	}
+}
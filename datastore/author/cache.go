@@ -0,0 +1,70 @@
+package author
+
+import (
+	"context"
+	"time"
+
+	"Three-Layer-Architecture/cache"
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/datastore/book"
+	"Three-Layer-Architecture/models"
+)
+
+const keyPrefix = "author:"
+
+// Cached serves Getbyid from cache, writes go to next and drop the entries they change
+type Cached struct {
+	next  datastore.Author
+	cache datastore.Cache
+	ttl   time.Duration
+}
+
+func NewCached(next datastore.Author, c datastore.Cache, ttl time.Duration) Cached {
+	return Cached{next: next, cache: c, ttl: ttl}
+}
+
+func (c Cached) Post(ctx context.Context, author models.Author) (models.Author, error) {
+	return c.next.Post(ctx, author)
+}
+
+func (c Cached) Getbyid(ctx context.Context, id string) (models.Author, error) {
+	return cache.Load(ctx, c.cache, "author", keyPrefix+id, c.ttl, func(ctx context.Context) (models.Author, error) {
+		return c.next.Getbyid(ctx, id)
+	})
+}
+
+func (c Cached) Update(ctx context.Context, id string, author models.Author) (models.Author, error) {
+	resp, err := c.next.Update(ctx, id, author)
+	c.Invalidate(ctx, id)
+
+	return resp, err
+}
+
+func (c Cached) Delete(ctx context.Context, id string) (int, error) {
+	resp, err := c.next.Delete(ctx, id)
+	c.Invalidate(ctx, id)
+
+	return resp, err
+}
+
+func (c Cached) GetDeleted(ctx context.Context) ([]models.Author, error) {
+	return c.next.GetDeleted(ctx)
+}
+
+func (c Cached) Restore(ctx context.Context, id string) (int, error) {
+	resp, err := c.next.Restore(ctx, id)
+	c.Invalidate(ctx, id)
+
+	return resp, err
+}
+
+func (c Cached) Purge(ctx context.Context, before time.Time) (int, error) {
+	return c.next.Purge(ctx, before)
+}
+
+// Invalidate drops the author and every book, books embed their author and deleting an author deletes its books.
+// Writes call it, changes made elsewhere are dropped when their outbox event comes through sink.Cache.
+func (c Cached) Invalidate(ctx context.Context, id string) {
+	c.cache.Delete(ctx, keyPrefix+id)
+	c.cache.DeletePrefix(ctx, book.KeyPrefix)
+}
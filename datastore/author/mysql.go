@@ -1,10 +1,15 @@
 package author
 
 import (
+	"Three-Layer-Architecture/datastore/audit"
+	"Three-Layer-Architecture/datastore/outbox"
+	"Three-Layer-Architecture/datastore/revision"
 	"Three-Layer-Architecture/models"
+	"context"
 	"database/sql"
 	"errors"
 	"strconv"
+	"time"
 )
 
 type Datastore struct {
@@ -15,62 +20,121 @@ func New(db *sql.DB) Datastore {
 	return Datastore{db}
 }
 
-// Post method is to post the data in Author table
-func (d Datastore) Post(auth models.Author) (models.Author, error) {
+// Post method is to post the data in Author table, author.created event, audit entry and revision are stored with it
+func (d Datastore) Post(ctx context.Context, auth models.Author) (models.Author, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Author{}, err
+	}
+
+	// rollback after commit is a no-op
+	defer func() { _ = tx.Rollback() }()
+
 	// inserting data into db
-	_, err := d.db.Exec("insert into Author(authorId,firstName,lastName,dob,penName) values (?,?,?,?,?)",
+	_, err = tx.ExecContext(ctx, "insert into Author(authorId,firstName,lastName,dob,penName) values (?,?,?,?,?)",
 		auth.AuthID, auth.FirstName, auth.LastName, auth.Dob, auth.PenName)
 	if err != nil {
 		return models.Author{}, err
 	}
 
+	if err = outbox.Record(ctx, tx, models.EventAuthorCreated, models.EntityAuthor, auth.AuthID, auth); err != nil {
+		return models.Author{}, err
+	}
+
+	if err = record(ctx, tx, models.AuditCreate, auth.AuthID, nil, auth); err != nil {
+		return models.Author{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.Author{}, err
+	}
+
 	return auth, nil
 }
 
-// Update method is to update the data in Author table
-func (d Datastore) Update(iD string, auth models.Author) (models.Author, error) {
+// Getbyid method is to get the Author by its ID
+func (d Datastore) Getbyid(ctx context.Context, iD string) (models.Author, error) {
+	id, err := strconv.Atoi(iD)
+	if err != nil {
+		return models.Author{}, err
+	}
+
+	return get(ctx, d.db, id)
+}
+
+// Update method is to update the data in Author table, author.updated event, audit entry and revision are stored
+// with it
+func (d Datastore) Update(ctx context.Context, iD string, auth models.Author) (models.Author, error) {
 	// conveting id string to integer
 	id, err := strconv.Atoi(iD)
 	if err != nil {
 		return models.Author{}, errors.New("strconv.Atoi: parsing a")
 	}
 
-	var author models.Author
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Author{}, err
+	}
 
-	row := d.db.QueryRow("select * from Author where authorId=?", id)
+	// rollback after commit is a no-op
+	defer func() { _ = tx.Rollback() }()
 
-	if err2 := row.Scan(&author.AuthID, &author.FirstName, &author.LastName, &author.Dob, &author.PenName); err2 != nil {
-		return models.Author{}, err2
+	before, err := get(ctx, tx, id)
+	if err != nil {
+		return models.Author{}, err
 	}
 
 	// now updating the table
-	_, err = d.db.Exec("UPDATE Author SET firstName=?, lastName=? , dob=? , penName=? WHERE authorId=?",
+	_, err = tx.ExecContext(ctx, "UPDATE Author SET firstName=?, lastName=? , dob=? , penName=? WHERE authorId=? and deleted_at IS NULL",
 		auth.FirstName, auth.LastName, auth.Dob, auth.PenName, id)
 	if err != nil {
 		return models.Author{}, err
 	}
 
-	return auth, nil
+	after := auth
+	after.AuthID = id
+
+	if err = outbox.Record(ctx, tx, models.EventAuthorUpdated, models.EntityAuthor, id, after); err != nil {
+		return models.Author{}, err
+	}
+
+	if err = record(ctx, tx, models.AuditUpdate, id, before, after); err != nil {
+		return models.Author{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.Author{}, err
+	}
+
+	return after, nil
 }
 
-// Delete method is to delete the data in Author
-func (d Datastore) Delete(iD string) (int, error) {
+// Delete method is to delete the data in Author, author.deleted event, audit entry and revision are stored with it
+func (d Datastore) Delete(ctx context.Context, iD string) (int, error) {
 	id, err := strconv.Atoi(iD)
 	if err != nil {
 		return 0, err
 	}
 
-	// Checking author is present or not
-	var author models.Author
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
 
-	result := d.db.QueryRow("select * from Author where authorId=?", id)
+	// rollback after commit is a no-op
+	defer func() { _ = tx.Rollback() }()
 
-	if err2 := result.Scan(&author.AuthID, &author.FirstName, &author.LastName, &author.Dob, &author.PenName); err2 != nil {
-		return 0, err2
+	// Checking author is present or not
+	author, err := get(ctx, tx, id)
+	if err != nil {
+		return 0, err
 	}
 
-	// Firstly deleting data from book table because book can't exist without author ( Foreign key )
-	res, err := d.db.Exec("delete from Book where bookId=?", id)
+	// books and author get the same time so restoring the author can tell the books trashed with it
+	at := time.Now().UTC().Truncate(time.Second)
+
+	// Firstly moving books of author to trash because book can't exist without author
+	res, err := tx.ExecContext(ctx, "UPDATE Book SET deleted_at=? where authorId=? and deleted_at IS NULL", at, id)
 	if err != nil {
 		return 0, err
 	}
@@ -81,11 +145,169 @@ func (d Datastore) Delete(iD string) (int, error) {
 		return 0, err2
 	}
 
-	// Now deleting data from Author
-	_, err = d.db.Exec("delete from Author where authorId=?", id)
+	// Now moving author to trash
+	_, err = tx.ExecContext(ctx, "UPDATE Author SET deleted_at=? where authorId=?", at, id)
 	if err != nil {
 		return 0, err
 	}
 
+	if err = outbox.Record(ctx, tx, models.EventAuthorDeleted, models.EntityAuthor, id, author); err != nil {
+		return 0, err
+	}
+
+	if err = record(ctx, tx, models.AuditDelete, id, author, nil); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
 	return int(rowaffected), nil
 }
+
+// GetDeleted method is to get all Authors which are in trash
+func (d Datastore) GetDeleted(ctx context.Context) ([]models.Author, error) {
+	rows, err := d.db.QueryContext(ctx, "SELECT authorId,firstName,lastName,dob,penName,deleted_at FROM Author WHERE deleted_at IS NOT NULL")
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var authors []models.Author
+
+	for rows.Next() {
+		var (
+			a         models.Author
+			deletedAt sql.NullTime
+		)
+
+		if err = rows.Scan(&a.AuthID, &a.FirstName, &a.LastName, &a.Dob, &a.PenName, &deletedAt); err != nil {
+			return nil, err
+		}
+
+		if deletedAt.Valid {
+			a.DeletedAt = &deletedAt.Time
+		}
+
+		authors = append(authors, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return authors, nil
+}
+
+// Restore method is to bring back an Author from trash with the books trashed with it, books trashed before are
+// restored separately. author.restored event, audit entry and revision are stored with it.
+func (d Datastore) Restore(ctx context.Context, iD string) (int, error) {
+	id, err := strconv.Atoi(iD)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	// rollback after commit is a no-op
+	defer func() { _ = tx.Rollback() }()
+
+	// nothing in trash with given id is sql.ErrNoRows
+	var deletedAt time.Time
+
+	err = tx.QueryRowContext(ctx, "SELECT deleted_at FROM Author WHERE authorId=? and deleted_at IS NOT NULL FOR UPDATE", id).
+		Scan(&deletedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.ExecContext(ctx, "UPDATE Author SET deleted_at=NULL where authorId=?", id)
+	if err != nil {
+		return 0, err
+	}
+
+	rowAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	// books trashed with the author come back with it, books trashed on their own before stay in trash
+	_, err = tx.ExecContext(ctx, "UPDATE Book SET deleted_at=NULL where authorId=? and deleted_at=?", id, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	author, err := get(ctx, tx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = outbox.Record(ctx, tx, models.EventAuthorRestored, models.EntityAuthor, id, author); err != nil {
+		return 0, err
+	}
+
+	if err = record(ctx, tx, models.AuditRestore, id, nil, author); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return int(rowAffected), nil
+}
+
+// Purge method is to permanently remove Authors which were deleted before given time.
+// Authors still referenced by a book ( live or in trash ) are kept because of foreign key.
+func (d Datastore) Purge(ctx context.Context, before time.Time) (int, error) {
+	res, err := d.db.ExecContext(ctx, "DELETE FROM Author where deleted_at IS NOT NULL and deleted_at < ? "+
+		"and authorId NOT IN (SELECT authorId FROM Book)", before)
+	if err != nil {
+		return 0, err
+	}
+
+	rowAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowAffected), nil
+}
+
+// querier is *sql.DB or *sql.Tx, an author is read the same way in and out of a transaction
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// get reads an author which is not in trash
+func get(ctx context.Context, q querier, id int) (models.Author, error) {
+	var author models.Author
+
+	row := q.QueryRowContext(ctx, "select authorId,firstName,lastName,dob,penName from Author where authorId=? and deleted_at IS NULL", id)
+
+	if err := row.Scan(&author.AuthID, &author.FirstName, &author.LastName, &author.Dob, &author.PenName); err != nil {
+		return models.Author{}, err
+	}
+
+	return author, nil
+}
+
+// record writes the change in audit log and revision history in the transaction of the change
+func record(ctx context.Context, tx *sql.Tx, operation string, id int, before, after any) error {
+	if err := audit.Record(ctx, tx, operation, models.EntityAuthor, id, before, after); err != nil {
+		return err
+	}
+
+	// deleted revision keeps the last state
+	snapshot := after
+	if snapshot == nil {
+		snapshot = before
+	}
+
+	return revision.Record(ctx, tx, operation, models.EntityAuthor, id, snapshot)
+}
@@ -1,18 +1,38 @@
 package author
 
 import (
+	"context"
+	"database/sql"
 	"database/sql/driver"
 	"errors"
 	"log"
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 
 	"Three-Layer-Architecture/models"
 )
 
+const (
+	outboxInsert   = "INSERT INTO Outbox(event,entity,entityId,payload,createdAt) VALUES (?,?,?,?,NOW())"
+	auditInsert    = "insert into Audit(actor,occurredAt,operation,entity,entityId,beforeData,afterData) values (?,?,?,?,?,?,?)"
+	latestRevision = "SELECT COALESCE(MAX(rev),0) FROM Revision WHERE entity=? and entityId=? FOR UPDATE"
+	revisionInsert = "insert into Revision(entity,entityId,rev,operation,actor,createdAt,data) values (?,?,?,?,?,?,?)"
+	authorQuery    = "select authorId,firstName,lastName,dob,penName from Author where authorId=? and deleted_at IS NULL"
+)
+
+// expectHistory expects the audit entry and next revision of a change in its transaction
+func expectHistory(mock sqlmock.Sqlmock, operation string, id int) {
+	mock.ExpectExec(auditInsert).WithArgs("anonymous", sqlmock.AnyArg(), operation, models.EntityAuthor, id, sqlmock.AnyArg(),
+		sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(latestRevision).WithArgs(models.EntityAuthor, id).WillReturnRows(sqlmock.NewRows([]string{"rev"}).AddRow(1))
+	mock.ExpectExec(revisionInsert).WithArgs(models.EntityAuthor, id, 2, operation, "anonymous", sqlmock.AnyArg(),
+		sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
 // Testing Post Author
 func TestAuthor_Post(t *testing.T) {
 	testcases := []struct {
@@ -41,13 +61,25 @@ func TestAuthor_Post(t *testing.T) {
 
 	for i, v := range testcases {
 		// mocking insert exec query
+		mock.ExpectBegin()
 		mock.ExpectExec("insert into Author(authorId,firstName,lastName,dob,penName) values (?,?,?,?,?)").
 			WithArgs(v.req.AuthID, v.req.FirstName, v.req.LastName, v.req.Dob, v.req.PenName).
 			WillReturnResult(sqlmock.NewResult(v.lastInsertID, v.rowAffected)).WillReturnError(v.err)
 
+		// event and history are stored in the same transaction, nothing is stored when insert fails
+		if v.err == nil {
+			mock.ExpectExec(outboxInsert).WithArgs(models.EventAuthorCreated, models.EntityAuthor, v.req.AuthID,
+				[]byte(`{"authID":1,"firstName":"Chetan","lastName":"Bhagat","dob":"06/04/2001","penName":"Chetan"}`)).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			expectHistory(mock, models.AuditCreate, v.req.AuthID)
+			mock.ExpectCommit()
+		} else {
+			mock.ExpectRollback()
+		}
+
 		d := New(db)
 
-		resp, err := d.Post(v.req)
+		resp, err := d.Post(context.Background(), v.req)
 
 		// Comparing body
 		if !reflect.DeepEqual(resp, v.resp) {
@@ -98,18 +130,32 @@ func TestAuthor_Put(t *testing.T) {
 		id, err := strconv.Atoi(v.id)
 		if err != nil {
 			log.Printf("%v", err)
+		} else {
+			// state before the change is read in its transaction
+			mock.ExpectBegin()
+			mock.ExpectQuery(authorQuery).WithArgs(id).WillReturnRows(v.rows)
 		}
 
-		mock.ExpectQuery("select * from Author where authorId=?").WithArgs(id).
-			WillReturnRows(v.rows)
-
-		// Mocking Exec for updating data
-		mock.ExpectExec("UPDATE Author SET firstName=?, lastName=? , dob=? , penName=? WHERE authorId=?").
-			WithArgs(v.resp.FirstName, v.resp.LastName, v.resp.Dob, v.resp.PenName, id).WillReturnResult(v.res).WillReturnError(v.err)
+		// Mocking Exec for updating data with its event and history
+		switch {
+		case v.err != nil:
+			mock.ExpectExec("UPDATE Author SET firstName=?, lastName=? , dob=? , penName=? WHERE authorId=? and deleted_at IS NULL").
+				WithArgs(v.resp.FirstName, v.resp.LastName, v.resp.Dob, v.resp.PenName, id).WillReturnError(v.err)
+			mock.ExpectRollback()
+		case v.res != nil:
+			mock.ExpectExec("UPDATE Author SET firstName=?, lastName=? , dob=? , penName=? WHERE authorId=? and deleted_at IS NULL").
+				WithArgs(v.resp.FirstName, v.resp.LastName, v.resp.Dob, v.resp.PenName, id).WillReturnResult(v.res)
+			mock.ExpectExec(outboxInsert).WithArgs(models.EventAuthorUpdated, models.EntityAuthor, id, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			expectHistory(mock, models.AuditUpdate, id)
+			mock.ExpectCommit()
+		case err == nil:
+			mock.ExpectRollback()
+		}
 
 		d := New(db)
 
-		resp, err := d.Update(v.id, v.resp)
+		resp, err := d.Update(context.Background(), v.id, v.resp)
 
 		// Comparing body
 		if !reflect.DeepEqual(resp, v.resp) {
@@ -156,23 +202,34 @@ func TestAuthor_Delete(t *testing.T) {
 		id, err := strconv.Atoi(v.ID)
 		if err != nil {
 			log.Printf("%v", err)
+		} else {
+			// Mocking Query for checking authorId
+			mock.ExpectBegin()
+			mock.ExpectQuery(authorQuery).WithArgs(id).WillReturnRows(v.rows)
 		}
 
-		// Mocking Query for checking authorId
-		mock.ExpectQuery("select * from Author where authorId=?").WithArgs(id).
-			WillReturnRows(v.rows)
-
-		// Mocking Exec for deleting book
-		mock.ExpectExec("delete from Book where bookId=?").WithArgs(id).
-			WillReturnResult(sqlmock.NewResult(v.lastInsertedID, v.rowAffected))
-
-		// Mocking Exec for deleting author
-		mock.ExpectExec("delete from Author where authorId=?").WithArgs(id).
-			WillReturnResult(sqlmock.NewResult(v.lastInsertedID, v.rowAffected))
+		// books and author are moved to trash with the event and history in one transaction
+		switch {
+		case v.rowAffected > 0:
+			// Mocking Exec for deleting book
+			mock.ExpectExec("UPDATE Book SET deleted_at=? where authorId=? and deleted_at IS NULL").
+				WithArgs(sqlmock.AnyArg(), id).WillReturnResult(sqlmock.NewResult(v.lastInsertedID, v.rowAffected))
+
+			// Mocking Exec for deleting author
+			mock.ExpectExec("UPDATE Author SET deleted_at=? where authorId=?").WithArgs(sqlmock.AnyArg(), id).
+				WillReturnResult(sqlmock.NewResult(v.lastInsertedID, v.rowAffected))
+
+			mock.ExpectExec(outboxInsert).WithArgs(models.EventAuthorDeleted, models.EntityAuthor, id, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			expectHistory(mock, models.AuditDelete, id)
+			mock.ExpectCommit()
+		case err == nil:
+			mock.ExpectRollback()
+		}
 
 		d := New(db)
 
-		resp, err := d.Delete(v.ID)
+		resp, err := d.Delete(context.Background(), v.ID)
 
 		if reflect.DeepEqual(resp, v.rowAffected) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.rowAffected)
@@ -183,3 +240,171 @@ func TestAuthor_Delete(t *testing.T) {
 		}
 	}
 }
+
+// Test_GetDeleted authors in trash
+func Test_GetDeleted(t *testing.T) {
+	deletedAt := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT authorId,firstName,lastName,dob,penName,deleted_at FROM Author WHERE deleted_at IS NOT NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"authorId", "firstName", "lastName", "dob", "penName", "deleted_at"}).
+			AddRow(1, "Rajan", "Sharma", "26/04/2001", "Rajan", deletedAt))
+
+	expected := []models.Author{{AuthID: 1, FirstName: "Rajan", LastName: "Sharma", Dob: "26/04/2001", PenName: "Rajan",
+		DeletedAt: &deletedAt}}
+
+	d := New(db)
+
+	resp, err := d.GetDeleted(context.Background())
+	if !reflect.DeepEqual(resp, expected) || err != nil {
+		t.Errorf("[TEST1]Failed. Got %v, %v\tExpected %v, <nil>\n", resp, err, expected)
+	}
+}
+
+// Test_Restore author from trash with its books
+func Test_Restore(t *testing.T) {
+	auditErr := errors.New("connection refused")
+	deletedAt := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc        string
+		id          string
+		rowAffected int64
+		auditErr    error
+		resp        int
+		err         error
+	}{
+		{desc: "valid", id: "1", rowAffected: 1, resp: 1},
+		{desc: "not in trash", id: "2", rowAffected: 0, err: sql.ErrNoRows},
+		{desc: "audit fails", id: "3", rowAffected: 1, auditErr: auditErr, err: auditErr},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		id, _ := strconv.Atoi(v.id)
+
+		mock.ExpectBegin()
+
+		// books trashed with the author have its time and come back with it
+		trashed := sqlmock.NewRows([]string{"deleted_at"})
+		if v.rowAffected > 0 {
+			trashed.AddRow(deletedAt)
+		}
+
+		mock.ExpectQuery("SELECT deleted_at FROM Author WHERE authorId=? and deleted_at IS NOT NULL FOR UPDATE").WithArgs(id).
+			WillReturnRows(trashed)
+
+		if v.rowAffected > 0 {
+			mock.ExpectExec("UPDATE Author SET deleted_at=NULL where authorId=?").WithArgs(id).
+				WillReturnResult(sqlmock.NewResult(0, v.rowAffected))
+			mock.ExpectExec("UPDATE Book SET deleted_at=NULL where authorId=? and deleted_at=?").WithArgs(id, deletedAt).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectQuery(authorQuery).WithArgs(id).
+				WillReturnRows(sqlmock.NewRows([]string{"authorId", "firstName", "lastName", "dob", "penName"}).
+					AddRow(id, "Rajan", "Sharma", "26/04/2001", "Rajan"))
+			mock.ExpectExec(outboxInsert).WithArgs(models.EventAuthorRestored, models.EntityAuthor, id, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+		}
+
+		switch {
+		case v.auditErr != nil:
+			// the restore is rolled back with its event when the audit entry can not be stored
+			mock.ExpectExec(auditInsert).WillReturnError(v.auditErr)
+			mock.ExpectRollback()
+		case v.err == nil:
+			expectHistory(mock, models.AuditRestore, id)
+			mock.ExpectCommit()
+		default:
+			mock.ExpectRollback()
+		}
+
+		d := New(db)
+
+		resp, err := d.Restore(context.Background(), v.id)
+
+		if resp != v.resp {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Test_Purge authors deleted before retention
+func Test_Purge(t *testing.T) {
+	before := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM Author where deleted_at IS NOT NULL and deleted_at < ? " +
+		"and authorId NOT IN (SELECT authorId FROM Book)").WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	d := New(db)
+
+	resp, err := d.Purge(context.Background(), before)
+	if resp != 2 || err != nil {
+		t.Errorf("[TEST1]Failed. Got %v, %v\tExpected 2, <nil>\n", resp, err)
+	}
+}
+
+// Test_Getbyid author
+func Test_Getbyid(t *testing.T) {
+	testcases := []struct {
+		desc string
+		id   string
+		rows *sqlmock.Rows
+		resp models.Author
+		err  error
+	}{
+		{desc: "valid", id: "1", rows: sqlmock.NewRows([]string{"authorId", "firstName", "lastName", "dob", "penName"}).
+			AddRow(1, "Rajan", "Sharma", "26/04/2001", "Rajan"), resp: models.Author{AuthID: 1, FirstName: "Rajan",
+			LastName: "Sharma", Dob: "26/04/2001", PenName: "Rajan"}},
+		{desc: "id not exist", id: "11", rows: sqlmock.NewRows([]string{"authorId", "firstName", "lastName", "dob", "penName"}),
+			err: sql.ErrNoRows},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		id, _ := strconv.Atoi(v.id)
+
+		mock.ExpectQuery("select authorId,firstName,lastName,dob,penName from Author where authorId=? and deleted_at IS NULL").
+			WithArgs(id).WillReturnRows(v.rows)
+
+		d := New(db)
+
+		resp, err := d.Getbyid(context.Background(), v.id)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package book
+
+import (
+	"context"
+	"time"
+
+	"Three-Layer-Architecture/cache"
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+// KeyPrefix is the prefix of every cached book key, author changes drop all of them as books embed their author
+const KeyPrefix = "book:"
+
+const listKey = KeyPrefix + "list"
+
+// Cached serves GetAll and Getbyid from cache, writes go to next and drop the entries they change
+type Cached struct {
+	next  datastore.Book
+	cache datastore.Cache
+	ttl   time.Duration
+}
+
+func NewCached(next datastore.Book, c datastore.Cache, ttl time.Duration) Cached {
+	return Cached{next: next, cache: c, ttl: ttl}
+}
+
+func (c Cached) Post(ctx context.Context, book *models.Book) (models.Book, error) {
+	resp, err := c.next.Post(ctx, book)
+	if err == nil {
+		c.cache.Delete(ctx, listKey)
+	}
+
+	return resp, err
+}
+
+func (c Cached) GetAll(ctx context.Context) ([]models.Book, error) {
+	return cache.Load(ctx, c.cache, "book", listKey, c.ttl, c.next.GetAll)
+}
+
+func (c Cached) Getbyid(ctx context.Context, id string) (models.Book, error) {
+	return cache.Load(ctx, c.cache, "book", KeyPrefix+id, c.ttl, func(ctx context.Context) (models.Book, error) {
+		return c.next.Getbyid(ctx, id)
+	})
+}
+
+func (c Cached) Update(ctx context.Context, id string, book *models.Book) (models.Book, error) {
+	resp, err := c.next.Update(ctx, id, book)
+	c.Invalidate(ctx, id)
+
+	return resp, err
+}
+
+func (c Cached) Delete(ctx context.Context, id string) (int, error) {
+	resp, err := c.next.Delete(ctx, id)
+	c.Invalidate(ctx, id)
+
+	return resp, err
+}
+
+func (c Cached) GetDeleted(ctx context.Context) ([]models.Book, error) {
+	return c.next.GetDeleted(ctx)
+}
+
+func (c Cached) Restore(ctx context.Context, id string) (int, error) {
+	resp, err := c.next.Restore(ctx, id)
+	c.Invalidate(ctx, id)
+
+	return resp, err
+}
+
+func (c Cached) Purge(ctx context.Context, before time.Time) (int, error) {
+	return c.next.Purge(ctx, before)
+}
+
+// Invalidate drops the book and the list, writes call it even when they failed as they may have been applied before
+// the error
+func (c Cached) Invalidate(ctx context.Context, id string) {
+	c.cache.Delete(ctx, KeyPrefix+id, listKey)
+}
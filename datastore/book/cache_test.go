@@ -0,0 +1,98 @@
+package book
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/cache"
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+// TestCached_Getbyid function is to test reads are served from cache until a write drops them
+func TestCached_Getbyid(t *testing.T) {
+	ctx := context.Background()
+	book := models.Book{BookID: 1, AuthorID: 1, Title: "Wings of Fire", Auth: models.Author{AuthID: 1, FirstName: "APJ"}}
+	updated := models.Book{BookID: 1, AuthorID: 1, Title: "Ignited Minds", Auth: models.Author{AuthID: 1, FirstName: "APJ"}}
+
+	ctr := gomock.NewController(t)
+	mockBook := datastore.NewMockBook(ctr)
+	cached := NewCached(mockBook, cache.NewMemory(10), time.Minute)
+
+	gomock.InOrder(
+		mockBook.EXPECT().Getbyid(gomock.Any(), "1").Return(book, nil),
+		mockBook.EXPECT().Update(gomock.Any(), "1", &updated).Return(updated, nil),
+		mockBook.EXPECT().Getbyid(gomock.Any(), "1").Return(updated, nil),
+	)
+
+	mockBook.EXPECT().Getbyid(gomock.Any(), "2").Return(models.Book{}, sql.ErrNoRows).Times(2)
+
+	testcases := []struct {
+		desc   string
+		id     string
+		update bool
+		resp   models.Book
+		err    error
+	}{
+		{desc: "miss", id: "1", resp: book},
+		{desc: "hit", id: "1", resp: book},
+		{desc: "after update", id: "1", update: true, resp: updated},
+		{desc: "hit after update", id: "1", resp: updated},
+		{desc: "not found", id: "2", err: sql.ErrNoRows},
+		{desc: "errors are not cached", id: "2", err: sql.ErrNoRows},
+	}
+
+	for i, v := range testcases {
+		if v.update {
+			if _, err := cached.Update(ctx, v.id, &updated); err != nil {
+				t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\n", v.desc, i+1, err)
+			}
+		}
+
+		resp, err := cached.Getbyid(ctx, v.id)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestCached_GetAll function is to test a new book drops the cached list
+func TestCached_GetAll(t *testing.T) {
+	ctx := context.Background()
+	book := models.Book{BookID: 2, AuthorID: 1, Title: "Ignited Minds"}
+
+	ctr := gomock.NewController(t)
+	mockBook := datastore.NewMockBook(ctr)
+	cached := NewCached(mockBook, cache.NewMemory(10), time.Minute)
+
+	gomock.InOrder(
+		mockBook.EXPECT().GetAll(gomock.Any()).Return([]models.Book{}, nil),
+		mockBook.EXPECT().Post(gomock.Any(), &book).Return(book, nil),
+		mockBook.EXPECT().GetAll(gomock.Any()).Return([]models.Book{book}, nil),
+	)
+
+	for i := 0; i < 2; i++ {
+		if resp, err := cached.GetAll(ctx); len(resp) != 0 || err != nil {
+			t.Errorf("[TEST%d]Failed. Got %v, %v\tExpected empty list\n", i+1, resp, err)
+		}
+	}
+
+	if _, err := cached.Post(ctx, &book); err != nil {
+		t.Fatalf("[TEST3]Failed. Got %v\n", err)
+	}
+
+	if resp, err := cached.GetAll(ctx); !reflect.DeepEqual(resp, []models.Book{book}) || err != nil {
+		t.Errorf("[TEST4]Failed. Got %v, %v\tExpected %v\n", resp, err, []models.Book{book})
+	}
+}
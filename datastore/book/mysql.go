@@ -1,10 +1,17 @@
 package book
 
 import (
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/datastore/audit"
+	"Three-Layer-Architecture/datastore/outbox"
+	"Three-Layer-Architecture/datastore/revision"
 	"Three-Layer-Architecture/models"
+	"context"
 	"database/sql"
-	"log"
+	"fmt"
+	"log/slog"
 	"strconv"
+	"time"
 )
 
 type Datastore struct {
@@ -15,28 +22,55 @@ func New(db *sql.DB) Datastore {
 	return Datastore{db: db}
 }
 
-// Post method is to Post data in Book
-func (d Datastore) Post(book *models.Book) (models.Book, error) {
+// Post method is to Post data in Book, book.created event, audit entry and revision are stored with it.
+// The stored book is returned with its author
+func (d Datastore) Post(ctx context.Context, book *models.Book) (models.Book, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Book{}, err
+	}
+
+	// rollback after commit is a no-op
+	defer func() { _ = tx.Rollback() }()
+
 	// inserting data into Db
-	_, err := d.db.Exec("insert into Book(bookId,title,authorId,Publication,PublishedDate) values (?,?,?,?,?)",
-		book.BookID, book.Title, book.AuthorID, book.Publication, book.PublishedDate)
+	_, err = tx.ExecContext(ctx, "insert into Book(bookId,title,authorId,Publication,PublishedDate,isbn) values (?,?,?,?,?,?)",
+		book.BookID, book.Title, book.AuthorID, book.Publication, book.PublishedDate, book.ISBN)
+	if err != nil {
+		return models.Book{}, err
+	}
+
+	if err = outbox.Record(ctx, tx, models.EventBookCreated, models.EntityBook, book.BookID, book); err != nil {
+		return models.Book{}, err
+	}
+
+	// the author of the request is not stored with the book, history keeps what was written
+	after, err := get(ctx, tx, book.BookID)
 	if err != nil {
 		return models.Book{}, err
 	}
 
-	return *book, nil
+	if err = record(ctx, tx, models.AuditCreate, book.BookID, nil, after); err != nil {
+		return models.Book{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.Book{}, err
+	}
+
+	return after, nil
 }
 
 // GetAll method is to get all Books with Author
-func (d Datastore) GetAll() ([]models.Book, error) {
+func (d Datastore) GetAll(ctx context.Context) ([]models.Book, error) {
 	// reading all books from Db
-	allRows, err := d.db.Query("SELECT * FROM Book")
+	allRows, err := d.db.QueryContext(ctx, "SELECT bookId,title,authorId,Publication,PublishedDate,isbn FROM Book WHERE deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
 
 	if allRows.Err() != nil {
-		log.Print(allRows.Err())
+		slog.ErrorContext(ctx, "could not read books", "error", allRows.Err())
 	}
 
 	// Closing db.query
@@ -49,13 +83,13 @@ func (d Datastore) GetAll() ([]models.Book, error) {
 	for allRows.Next() {
 		var b models.Book
 
-		err = allRows.Scan(&b.BookID, &b.Title, &b.AuthorID, &b.Publication, &b.PublishedDate)
+		err = allRows.Scan(&b.BookID, &b.Title, &b.AuthorID, &b.Publication, &b.PublishedDate, &b.ISBN)
 		if err != nil {
 			return []models.Book{}, err
 		}
 
 		// for storing author details
-		row := d.db.QueryRow("SELECT * FROM Author where authorId=?", b.AuthorID)
+		row := d.db.QueryRowContext(ctx, "SELECT authorId,firstName,lastName,dob,penName FROM Author where authorId=?", b.AuthorID)
 
 		var author models.Author
 
@@ -76,87 +110,216 @@ func (d Datastore) GetAll() ([]models.Book, error) {
 }
 
 // Getbyid method is to get book by its ID
-func (d Datastore) Getbyid(iD string) (models.Book, error) {
+func (d Datastore) Getbyid(ctx context.Context, iD string) (models.Book, error) {
 	// converting string to integer to check for invalid id
 	id, err := strconv.Atoi(iD)
 	if err != nil {
 		return models.Book{}, err
 	}
 
-	// reading all data of book with given id
-	row := d.db.QueryRow("select * from Book where bookId=?", id)
+	return get(ctx, d.db, id)
+}
 
-	// to store d book
-	var book models.Book
+// Update method is to change data of Particular book, book.updated event, audit entry and revision are stored with it.
+// The stored book is returned with its author
+func (d Datastore) Update(ctx context.Context, iD string, book *models.Book) (models.Book, error) {
+	// converting string to integer to check for invalid id
+	id, err := strconv.Atoi(iD)
+	if err != nil {
+		return models.Book{}, nil
+	}
 
-	// fetching data of book at given id and storing in book
-	if err := row.Scan(&book.BookID, &book.Title, &book.AuthorID, &book.Publication, &book.PublishedDate); err != nil {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
 		return models.Book{}, err
 	}
 
-	// for storing author details
-	result := d.db.QueryRow("SELECT * FROM Author where authorId=?", book.AuthorID)
+	// rollback after commit is a no-op
+	defer func() { _ = tx.Rollback() }()
 
-	// To store author
-	var author models.Author
+	before, err := get(ctx, tx, id)
+	if err != nil {
+		return models.Book{}, err
+	}
 
-	if err := result.Scan(&author.AuthID, &author.FirstName, &author.LastName, &author.Dob, &author.PenName); err != nil {
+	// Updating book data
+	_, err = tx.ExecContext(ctx, "UPDATE Book SET title=?, Publication=? , PublishedDate=? , isbn=? WHERE bookId=? and deleted_at IS NULL",
+		book.Title, book.Publication, book.PublishedDate, book.ISBN, id)
+	if err != nil {
 		return models.Book{}, err
 	}
 
-	book.Auth = author
+	// author of a book is not changed by update
+	after := before
+	after.Title, after.Publication, after.PublishedDate, after.ISBN = book.Title, book.Publication, book.PublishedDate, book.ISBN
 
-	return book, nil
+	if err = outbox.Record(ctx, tx, models.EventBookUpdated, models.EntityBook, id, after); err != nil {
+		return models.Book{}, err
+	}
+
+	if err = record(ctx, tx, models.AuditUpdate, id, before, after); err != nil {
+		return models.Book{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.Book{}, err
+	}
+
+	return after, nil
 }
 
-// Update method is to change data of Particular book
-func (d Datastore) Update(iD string, book *models.Book) (models.Book, error) {
+// Delete method is remove Book by its ID, book.deleted event, audit entry and revision are stored with it
+func (d Datastore) Delete(ctx context.Context, iD string) (int, error) {
 	// converting string to integer to check for invalid id
 	id, err := strconv.Atoi(iD)
 	if err != nil {
-		return models.Book{}, nil
+		return 0, err
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
 	}
 
-	var scanbook models.Book
+	// rollback after commit is a no-op
+	defer func() { _ = tx.Rollback() }()
 
-	row := d.db.QueryRow("select * from Book where bookId=?", id)
-	if err2 := row.Scan(&scanbook.BookID, &scanbook.Title, &scanbook.AuthorID, &scanbook.Publication, &scanbook.PublishedDate); err2 != nil {
-		return models.Book{}, err2
+	// Checking book exist or not
+	book, err := get(ctx, tx, id)
+	if err != nil {
+		return 0, err
 	}
 
-	// Updating book data
-	_, err = d.db.Exec("UPDATE Book SET title=?, Publication=? , PublishedDate=? WHERE bookId=?",
-		book.Title, book.Publication, book.PublishedDate, id)
+	// Now moving the book to trash, it is purged later
+	res, err := tx.ExecContext(ctx, "UPDATE Book SET deleted_at=NOW() where bookId=? and deleted_at IS NULL", id)
 	if err != nil {
-		return models.Book{}, err
+		return 0, err
+	}
+
+	rowAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if err = outbox.Record(ctx, tx, models.EventBookDeleted, models.EntityBook, id, book); err != nil {
+		return 0, err
+	}
+
+	if err = record(ctx, tx, models.AuditDelete, id, book, nil); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
 	}
 
-	return *book, nil
+	return int(rowAffected), nil
 }
 
-// Delete method is remove Book by its ID
-func (d Datastore) Delete(iD string) (int, error) {
-	// converting string to integer to check for invalid id
+// GetDeleted method is to get all Books which are in trash
+func (d Datastore) GetDeleted(ctx context.Context) ([]models.Book, error) {
+	rows, err := d.db.QueryContext(ctx, "SELECT bookId,title,authorId,Publication,PublishedDate,isbn,deleted_at FROM Book WHERE deleted_at IS NOT NULL")
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var books []models.Book
+
+	for rows.Next() {
+		var (
+			b         models.Book
+			deletedAt sql.NullTime
+		)
+
+		if err = rows.Scan(&b.BookID, &b.Title, &b.AuthorID, &b.Publication, &b.PublishedDate, &b.ISBN, &deletedAt); err != nil {
+			return nil, err
+		}
+
+		if deletedAt.Valid {
+			b.DeletedAt = &deletedAt.Time
+		}
+
+		books = append(books, b)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+// Restore method is to bring back a Book from trash, book.restored event, audit entry and revision are stored with it.
+// datastore.ErrAuthorTrashed is returned while its author is in trash.
+func (d Datastore) Restore(ctx context.Context, iD string) (int, error) {
 	id, err := strconv.Atoi(iD)
 	if err != nil {
 		return 0, err
 	}
 
-	// Checking book exist or not
-	var book models.Book
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
 
-	row := d.db.QueryRow("select * from Book where bookId=?", id)
+	// rollback after commit is a no-op
+	defer func() { _ = tx.Rollback() }()
 
-	if err2 := row.Scan(&book.BookID, &book.Title, &book.AuthorID, &book.Publication, &book.PublishedDate); err2 != nil {
-		return 0, err2
+	// a book can not be in the catalogue without its author, the author is locked so it is not trashed meanwhile
+	var authorTrashed bool
+
+	err = tx.QueryRowContext(ctx, "SELECT Author.deleted_at IS NOT NULL FROM Book "+
+		"JOIN Author ON Author.authorId=Book.authorId WHERE bookId=? FOR UPDATE", id).Scan(&authorTrashed)
+	if err != nil {
+		return 0, err
+	}
+
+	if authorTrashed {
+		return 0, fmt.Errorf("%w : restore the author of book %d", datastore.ErrAuthorTrashed, id)
 	}
 
+	res, err := tx.ExecContext(ctx, "UPDATE Book SET deleted_at=NULL where bookId=? and deleted_at IS NOT NULL", id)
+	if err != nil {
+		return 0, err
+	}
+
+	rowAffected, err := res.RowsAffected()
 	if err != nil {
 		return 0, err
 	}
 
-	// Now deleting data from table
-	res, err := d.db.Exec("DELETE FROM Book where bookId=?", id)
+	// nothing in trash with given id
+	if rowAffected == 0 {
+		return 0, sql.ErrNoRows
+	}
+
+	book, err := get(ctx, tx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = outbox.Record(ctx, tx, models.EventBookRestored, models.EntityBook, id, book); err != nil {
+		return 0, err
+	}
+
+	if err = record(ctx, tx, models.AuditRestore, id, nil, book); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return int(rowAffected), nil
+}
+
+// Purge method is to permanently remove Books which were deleted before given time.
+// Books with loans ( active or returned ) are kept because of foreign key.
+func (d Datastore) Purge(ctx context.Context, before time.Time) (int, error) {
+	res, err := d.db.ExecContext(ctx, "DELETE FROM Book where deleted_at IS NOT NULL and deleted_at < ? "+
+		"and bookId NOT IN (SELECT bookId FROM Loan)", before)
 	if err != nil {
 		return 0, err
 	}
@@ -168,3 +331,51 @@ func (d Datastore) Delete(iD string) (int, error) {
 
 	return int(rowAffected), nil
 }
+
+// querier is *sql.DB or *sql.Tx, a book is read the same way in and out of a transaction
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// get reads a book which is not in trash with its author
+func get(ctx context.Context, q querier, id int) (models.Book, error) {
+	// reading all data of book with given id
+	row := q.QueryRowContext(ctx, "select bookId,title,authorId,Publication,PublishedDate,isbn from Book where bookId=? and deleted_at IS NULL", id)
+
+	// to store d book
+	var book models.Book
+
+	// fetching data of book at given id and storing in book
+	if err := row.Scan(&book.BookID, &book.Title, &book.AuthorID, &book.Publication, &book.PublishedDate, &book.ISBN); err != nil {
+		return models.Book{}, err
+	}
+
+	// for storing author details
+	result := q.QueryRowContext(ctx, "SELECT authorId,firstName,lastName,dob,penName FROM Author where authorId=?", book.AuthorID)
+
+	// To store author
+	var author models.Author
+
+	if err := result.Scan(&author.AuthID, &author.FirstName, &author.LastName, &author.Dob, &author.PenName); err != nil {
+		return models.Book{}, err
+	}
+
+	book.Auth = author
+
+	return book, nil
+}
+
+// record writes the change in audit log and revision history in the transaction of the change
+func record(ctx context.Context, tx *sql.Tx, operation string, id int, before, after any) error {
+	if err := audit.Record(ctx, tx, operation, models.EntityBook, id, before, after); err != nil {
+		return err
+	}
+
+	// deleted revision keeps the last state
+	snapshot := after
+	if snapshot == nil {
+		snapshot = before
+	}
+
+	return revision.Record(ctx, tx, operation, models.EntityBook, id, snapshot)
+}
@@ -1,17 +1,51 @@
 package book
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"log"
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 
+	"Three-Layer-Architecture/datastore"
 	"Three-Layer-Architecture/models"
 )
 
+const (
+	outboxInsert   = "INSERT INTO Outbox(event,entity,entityId,payload,createdAt) VALUES (?,?,?,?,NOW())"
+	auditInsert    = "insert into Audit(actor,occurredAt,operation,entity,entityId,beforeData,afterData) values (?,?,?,?,?,?,?)"
+	latestRevision = "SELECT COALESCE(MAX(rev),0) FROM Revision WHERE entity=? and entityId=? FOR UPDATE"
+	revisionInsert = "insert into Revision(entity,entityId,rev,operation,actor,createdAt,data) values (?,?,?,?,?,?,?)"
+	bookQuery      = "select bookId,title,authorId,Publication,PublishedDate,isbn from Book where bookId=? and deleted_at IS NULL"
+	authorQuery    = "SELECT authorId,firstName,lastName,dob,penName FROM Author where authorId=?"
+)
+
+var (
+	bookColumns   = []string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn"}
+	authorColumns = []string{"authorId", "firstName", "lastName", "dob", "penName"}
+	chetan        = models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}
+)
+
+// expectAuthor expects the author of a book read with it
+func expectAuthor(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(authorQuery).WithArgs(chetan.AuthID).WillReturnRows(sqlmock.NewRows(authorColumns).
+		AddRow(chetan.AuthID, chetan.FirstName, chetan.LastName, chetan.Dob, chetan.PenName))
+}
+
+// expectHistory expects the audit entry and next revision of a change in its transaction
+func expectHistory(mock sqlmock.Sqlmock, operation string, id int) {
+	mock.ExpectExec(auditInsert).WithArgs("anonymous", sqlmock.AnyArg(), operation, models.EntityBook, id, sqlmock.AnyArg(),
+		sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(latestRevision).WithArgs(models.EntityBook, id).WillReturnRows(sqlmock.NewRows([]string{"rev"}).AddRow(1))
+	mock.ExpectExec(revisionInsert).WithArgs(models.EntityBook, id, 2, operation, "anonymous", sqlmock.AnyArg(),
+		sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
 // Test_Post Book
 func Test_Post(t *testing.T) {
 	testcases := []struct {
@@ -24,9 +58,10 @@ func Test_Post(t *testing.T) {
 	}{
 		{desc: "valid details", req: models.Book{BookID: 1, AuthorID: 1,
 			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}, response: models.Book{BookID: 1,
-			AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}, lastInsertID: 1, rowAffected: 1},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016", ISBN: "8129115301"}, response: models.Book{
+			BookID: 1, AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001",
+				PenName: "Chetan"}, Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016", ISBN: "8129115301"},
+			lastInsertID: 1, rowAffected: 1},
 		{desc: "duplicate id", req: models.Book{BookID: 1, AuthorID: 1,
 			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
 			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}, err: errors.New(" Duplicate entry '1' for key 'PRIMARY'")},
@@ -43,14 +78,28 @@ func Test_Post(t *testing.T) {
 
 	for i, v := range testcases {
 		// Mocking insert query for book
-		mock.ExpectExec("insert into Book(bookId,title,authorId,Publication,PublishedDate) values (?,?,?,?,?)").
-			WithArgs(v.req.BookID, v.req.Title, v.req.AuthorID, v.req.Publication, v.req.PublishedDate).
+		mock.ExpectBegin()
+		mock.ExpectExec("insert into Book(bookId,title,authorId,Publication,PublishedDate,isbn) values (?,?,?,?,?,?)").
+			WithArgs(v.req.BookID, v.req.Title, v.req.AuthorID, v.req.Publication, v.req.PublishedDate, v.req.ISBN).
 			WillReturnResult(sqlmock.NewResult(v.lastInsertID, v.rowAffected)).WillReturnError(v.err)
 
+		// event and history are stored in the same transaction, nothing is stored when insert fails
+		if v.err == nil {
+			mock.ExpectExec(outboxInsert).WithArgs(models.EventBookCreated, models.EntityBook, v.req.BookID, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectQuery(bookQuery).WithArgs(v.req.BookID).WillReturnRows(sqlmock.NewRows(bookColumns).
+				AddRow(1, "2 States", 1, "Scholastic", "16/03/2016", "8129115301"))
+			expectAuthor(mock)
+			expectHistory(mock, models.AuditCreate, v.req.BookID)
+			mock.ExpectCommit()
+		} else {
+			mock.ExpectRollback()
+		}
+
 		// injecting mock db
 		d := New(db)
 
-		resp, err := d.Post(&v.req)
+		resp, err := d.Post(context.Background(), &v.req)
 
 		// comparing body
 		if !reflect.DeepEqual(resp, v.response) {
@@ -79,7 +128,7 @@ func Test_GetAll(t *testing.T) {
 			{BookID: 2, AuthorID: 1,
 				Auth:  models.Author{AuthID: 1, FirstName: "Vikram", LastName: "Seth", Dob: "26/04/2001", PenName: "Vikram"},
 				Title: "3 States", Publication: "Penguin", PublishedDate: "11/03/2016"}},
-			columns: []string{"bookId", "auth", "title", "authorId", "Publication", "PublishedDate"}},
+			columns: []string{"bookId", "auth", "title", "authorId", "Publication", "PublishedDate", "isbn"}},
 	}
 
 	// Customize SQL query matching
@@ -93,16 +142,16 @@ func Test_GetAll(t *testing.T) {
 
 	for i, v := range testcases {
 		// Mocking select all books query
-		mock.ExpectQuery("SELECT * FROM Book").WillReturnRows(sqlmock.NewRows(v.columns)).WillReturnError(v.err)
+		mock.ExpectQuery("SELECT bookId,title,authorId,Publication,PublishedDate,isbn FROM Book WHERE deleted_at IS NULL").WillReturnRows(sqlmock.NewRows(v.columns)).WillReturnError(v.err)
 
 		// Mocking select author for book query
-		mock.ExpectQuery("SELECT * FROM Author where authorId=?").WithArgs(v.resp[0].Auth.AuthID).
+		mock.ExpectQuery("SELECT authorId,firstName,lastName,dob,penName FROM Author where authorId=?").WithArgs(v.resp[0].Auth.AuthID).
 			WillReturnRows(sqlmock.NewRows(v.columns).FromCSVString("1, Chetan, Bhagat, 06/04/2001, Chetan")).WillReturnError(v.err)
 
 		// injecting mock db
 		datastore := New(db)
 
-		resp, err := datastore.GetAll()
+		resp, err := datastore.GetAll(context.Background())
 
 		// Comparing body
 		if reflect.DeepEqual(resp, v.resp) {
@@ -147,17 +196,17 @@ func Test_GetbyidInvalid(t *testing.T) {
 		}
 
 		// Mocking Query for reading book
-		mock.ExpectQuery("select * from Book where bookId=?").WithArgs(id).
+		mock.ExpectQuery("select bookId,title,authorId,Publication,PublishedDate,isbn from Book where bookId=? and deleted_at IS NULL").WithArgs(id).
 			WillReturnRows(sqlmock.NewRows([]string{""})).WillReturnError(v.err)
 
 		// Mocking Query for reading author of that book
-		mock.ExpectQuery("SELECT * FROM Author where authorId=?").WithArgs(v.resp.AuthorID).
+		mock.ExpectQuery("SELECT authorId,firstName,lastName,dob,penName FROM Author where authorId=?").WithArgs(v.resp.AuthorID).
 			WillReturnRows(sqlmock.NewRows([]string{""})).WillReturnError(v.err)
 
 		// Injecting mock DB
 		d := New(db)
 
-		resp, err := d.Getbyid(v.id)
+		resp, err := d.Getbyid(context.Background(), v.id)
 
 		// Comparing body
 		if reflect.DeepEqual(resp, v.resp) {
@@ -181,7 +230,8 @@ func Test_GetbyidValid(t *testing.T) {
 	}{
 		{desc: "valid", id: "1", resp: models.Book{BookID: 1, AuthorID: 1,
 			Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001",
-				PenName: "Chetan"}, Title: "States", Publication: "Scholastic", PublishedDate: "16/03/2016"}},
+				PenName: "Chetan"}, Title: "States", Publication: "Scholastic", PublishedDate: "16/03/2016",
+			ISBN: "8129115301"}},
 	}
 
 	// Customize SQL query matching
@@ -201,19 +251,19 @@ func Test_GetbyidValid(t *testing.T) {
 		}
 
 		// Mocking Query for reading book
-		mock.ExpectQuery("select * from Book where bookId=?").WithArgs(id).
-			WillReturnRows(sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate"}).
-				FromCSVString("1,States,1,Scholastic,16/03/2016")).WillReturnError(v.err)
+		mock.ExpectQuery("select bookId,title,authorId,Publication,PublishedDate,isbn from Book where bookId=? and deleted_at IS NULL").WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn"}).
+				FromCSVString("1,States,1,Scholastic,16/03/2016,8129115301")).WillReturnError(v.err)
 
 		// Mocking Query for reading author of that book
-		mock.ExpectQuery("SELECT * FROM Author where authorId=?").WithArgs(v.resp.AuthorID).
+		mock.ExpectQuery("SELECT authorId,firstName,lastName,dob,penName FROM Author where authorId=?").WithArgs(v.resp.AuthorID).
 			WillReturnRows(sqlmock.NewRows([]string{"authorId", "firstName", "lastName", "dob", "penName"}).
 				FromCSVString("1,Chetan,Bhagat,06/04/2001,Chetan")).WillReturnError(v.err)
 
 		// Injecting mock DB
 		d := New(db)
 
-		resp, err := d.Getbyid(v.id)
+		resp, err := d.Getbyid(context.Background(), v.id)
 
 		// Comparing body
 		if !reflect.DeepEqual(resp, v.resp) {
@@ -239,14 +289,13 @@ func Test_Put(t *testing.T) {
 		rowAffected  int64
 		err          error
 	}{
-		{desc: "valid", id: "1", req: models.Book{BookID: 1, AuthorID: 1,
+		{desc: "valid", id: "1", req: models.Book{BookID: 1, AuthorID: 9,
 			Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2016"}, lastInsertID: 1, rowAffected: 1,
-			resp: models.Book{BookID: 1, AuthorID: 1, Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2016"}, row: sqlmock.
-				NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate"}).
-				AddRow(1, "300 Days", 1, "Penguin", "17/03/2016")},
+			resp: models.Book{BookID: 1, AuthorID: 1, Auth: chetan, Title: "300 Days", Publication: "Penguin",
+				PublishedDate: "17/03/2016"}, row: sqlmock.NewRows(bookColumns).AddRow(1, "200 Days", 1, "Penguin", "17/03/2016", "")},
 		{desc: "id not exist", id: "11", req: models.Book{BookID: 1, AuthorID: 1,
 			Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2016"}, err: errors.New("sql: no rows in result set"), row: sqlmock.
-			NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate"})},
+			NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn"})},
 	}
 
 	// Customize SQL query matching
@@ -264,18 +313,30 @@ func Test_Put(t *testing.T) {
 			log.Printf("%v", err)
 		}
 
-		mock.ExpectQuery("select * from Book where bookId=?").WithArgs(id).
-			WillReturnRows(v.row).WillReturnError(v.err)
-
-		// Mocking Exec query for updating data
-		mock.ExpectExec("UPDATE Book SET title=?, Publication=? , PublishedDate=? WHERE bookId=?").
-			WithArgs(v.resp.Title, v.resp.Publication, v.resp.PublishedDate, id).
-			WillReturnResult(sqlmock.NewResult(v.lastInsertID, v.rowAffected)).WillReturnError(v.err)
+		// state before the change is read in its transaction
+		mock.ExpectBegin()
+		mock.ExpectQuery(bookQuery).WithArgs(id).WillReturnRows(v.row).WillReturnError(v.err)
+
+		// Mocking Exec query for updating data, the event and history have the author of stored book
+		if v.err == nil {
+			expectAuthor(mock)
+			mock.ExpectExec("UPDATE Book SET title=?, Publication=? , PublishedDate=? , isbn=? WHERE bookId=? and deleted_at IS NULL").
+				WithArgs(v.resp.Title, v.resp.Publication, v.resp.PublishedDate, v.resp.ISBN, id).
+				WillReturnResult(sqlmock.NewResult(v.lastInsertID, v.rowAffected))
+			mock.ExpectExec(outboxInsert).WithArgs(models.EventBookUpdated, models.EntityBook, id,
+				[]byte(`{"bookID":1,"authorID":1,"auth":{"authID":1,"firstName":"Chetan","lastName":"Bhagat","dob":"06/04/2001",`+
+					`"penName":"Chetan"},"title":"300 Days","publication":"Penguin","publishedDate":"17/03/2016"}`)).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			expectHistory(mock, models.AuditUpdate, id)
+			mock.ExpectCommit()
+		} else {
+			mock.ExpectRollback()
+		}
 
 		// Injecting mock Db
 		d := New(db)
 
-		resp, err := d.Update(v.id, &v.req)
+		resp, err := d.Update(context.Background(), v.id, &v.req)
 
 		if !reflect.DeepEqual(resp, v.resp) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
@@ -289,18 +350,23 @@ func Test_Put(t *testing.T) {
 
 // Test_Delete book
 func Test_Delete(t *testing.T) {
+	auditErr := errors.New("connection refused")
+
 	testcases := []struct {
 		desc           string
 		id             string
 		row            *sqlmock.Rows
 		rowAffected    int64
 		lastInsertedID int64
+		auditErr       error
 		err            error
 	}{
 		{desc: "valid", id: "1", rowAffected: 1, lastInsertedID: 1, row: sqlmock.
-			NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate"}).AddRow(1, "Journey", 1, "Penguin", "12/04/2001")},
+			NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn"}).AddRow(1, "Journey", 1, "Penguin", "12/04/2001", "")},
 		{desc: "id not exist", id: "11", err: errors.New("sql: no rows in result set"), row: sqlmock.
-			NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate"})},
+			NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn"})},
+		{desc: "audit fails", id: "1", rowAffected: 1, lastInsertedID: 1, auditErr: auditErr, err: auditErr,
+			row: sqlmock.NewRows(bookColumns).AddRow(1, "Journey", 1, "Penguin", "12/04/2001", "")},
 	}
 
 	// Customize SQL query matching
@@ -320,16 +386,40 @@ func Test_Delete(t *testing.T) {
 		}
 
 		// Mocking for checking book Id
-		mock.ExpectQuery("select * from Book where bookId=?").WithArgs(id).WillReturnRows(v.row).WillReturnError(v.err)
+		mock.ExpectBegin()
+
+		query := mock.ExpectQuery(bookQuery).WithArgs(id).WillReturnRows(v.row)
+		if v.auditErr == nil {
+			query.WillReturnError(v.err)
+		}
 
-		// Mocking delete query from book
-		mock.ExpectExec("DELETE FROM Book where bookId=?").WithArgs(id).
-			WillReturnResult(sqlmock.NewResult(v.lastInsertedID, v.rowAffected)).WillReturnError(v.err)
+		// Mocking delete query from book with its event and history
+		switch {
+		case v.auditErr != nil:
+			// the delete is rolled back with its event when the audit entry can not be stored
+			expectAuthor(mock)
+			mock.ExpectExec("UPDATE Book SET deleted_at=NOW() where bookId=? and deleted_at IS NULL").WithArgs(id).
+				WillReturnResult(sqlmock.NewResult(v.lastInsertedID, v.rowAffected))
+			mock.ExpectExec(outboxInsert).WithArgs(models.EventBookDeleted, models.EntityBook, id, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectExec(auditInsert).WillReturnError(v.auditErr)
+			mock.ExpectRollback()
+		case v.err == nil:
+			expectAuthor(mock)
+			mock.ExpectExec("UPDATE Book SET deleted_at=NOW() where bookId=? and deleted_at IS NULL").WithArgs(id).
+				WillReturnResult(sqlmock.NewResult(v.lastInsertedID, v.rowAffected))
+			mock.ExpectExec(outboxInsert).WithArgs(models.EventBookDeleted, models.EntityBook, id, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			expectHistory(mock, models.AuditDelete, id)
+			mock.ExpectCommit()
+		default:
+			mock.ExpectRollback()
+		}
 
 		// Injecting mock DB
 		d := New(db)
 
-		resp, err := d.Delete(v.id)
+		resp, err := d.Delete(context.Background(), v.id)
 
 		// Comparing body
 		if reflect.DeepEqual(resp, v.rowAffected) {
@@ -342,3 +432,145 @@ func Test_Delete(t *testing.T) {
 		}
 	}
 }
+
+// Test_GetDeleted books in trash
+func Test_GetDeleted(t *testing.T) {
+	deletedAt := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc string
+		rows *sqlmock.Rows
+		resp []models.Book
+		err  error
+	}{
+		{desc: "valid", rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "deleted_at"}).
+			AddRow(1, "Journey", 1, "Penguin", "12/04/2001", "", deletedAt), resp: []models.Book{{BookID: 1, Title: "Journey",
+			AuthorID: 1, Publication: "Penguin", PublishedDate: "12/04/2001", DeletedAt: &deletedAt}}},
+		{desc: "empty trash", rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "deleted_at"})},
+		{desc: "query error", err: errors.New("connection refused")},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		query := mock.ExpectQuery("SELECT bookId,title,authorId,Publication,PublishedDate,isbn,deleted_at FROM Book WHERE deleted_at IS NOT NULL")
+		if v.err != nil {
+			query.WillReturnError(v.err)
+		} else {
+			query.WillReturnRows(v.rows)
+		}
+
+		d := New(db)
+
+		resp, err := d.GetDeleted(context.Background())
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Test_Restore book from trash
+func Test_Restore(t *testing.T) {
+	testcases := []struct {
+		desc          string
+		id            string
+		authorTrashed bool
+		rowAffected   int64
+		resp          int
+		err           error
+	}{
+		{desc: "valid", id: "1", rowAffected: 1, resp: 1},
+		{desc: "not in trash", id: "2", rowAffected: 0, err: sql.ErrNoRows},
+		{desc: "author in trash", id: "3", authorTrashed: true, err: datastore.ErrAuthorTrashed},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		id, _ := strconv.Atoi(v.id)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT Author.deleted_at IS NOT NULL FROM Book JOIN Author ON Author.authorId=Book.authorId " +
+			"WHERE bookId=? FOR UPDATE").WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"trashed"}).AddRow(v.authorTrashed))
+
+		if !v.authorTrashed {
+			mock.ExpectExec("UPDATE Book SET deleted_at=NULL where bookId=? and deleted_at IS NOT NULL").WithArgs(id).
+				WillReturnResult(sqlmock.NewResult(0, v.rowAffected))
+		}
+
+		if v.err == nil {
+			mock.ExpectQuery(bookQuery).WithArgs(id).
+				WillReturnRows(sqlmock.NewRows(bookColumns).AddRow(id, "Journey", 1, "Penguin", "12/04/2001", ""))
+			expectAuthor(mock)
+			mock.ExpectExec(outboxInsert).WithArgs(models.EventBookRestored, models.EntityBook, id, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			expectHistory(mock, models.AuditRestore, id)
+			mock.ExpectCommit()
+		} else {
+			mock.ExpectRollback()
+		}
+
+		d := New(db)
+
+		resp, err := d.Restore(context.Background(), v.id)
+
+		if resp != v.resp {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Test_Purge books deleted before retention, books with loans are left
+func Test_Purge(t *testing.T) {
+	before := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc     string
+		affected int64
+		err      error
+		resp     int
+	}{
+		{desc: "purged", affected: 3, resp: 3},
+		// the only trashed book was loaned, nothing is removed and purge still succeeds
+		{desc: "loaned book kept", affected: 0, resp: 0},
+		{desc: "connection lost", err: errors.New("connection refused")},
+	}
+
+	for i, v := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		mock.ExpectExec("DELETE FROM Book where deleted_at IS NOT NULL and deleted_at < ? " +
+			"and bookId NOT IN (SELECT bookId FROM Loan)").WithArgs(before).
+			WillReturnResult(sqlmock.NewResult(0, v.affected)).WillReturnError(v.err)
+
+		resp, err := New(db).Purge(context.Background(), before)
+		if resp != v.resp || !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v, %v\tExpected %v, %v\n", v.desc, i+1, resp, err, v.resp, v.err)
+		}
+
+		db.Close()
+	}
+}
@@ -0,0 +1,75 @@
+package consistency
+
+import (
+	"context"
+	"database/sql"
+
+	"Three-Layer-Architecture/models"
+)
+
+// check is a query returning the id of each row breaking a rule and the id it refers to
+type check struct {
+	name   string
+	entity string
+	query  string
+	detail func(ref string) string
+}
+
+var checks = []check{
+	{name: "book.missing_author", entity: models.EntityBook,
+		query: "SELECT b.bookId,b.authorId FROM Book b LEFT JOIN Author a ON a.authorId=b.authorId " +
+			"WHERE a.authorId IS NULL",
+		detail: func(ref string) string { return "author " + ref + " does not exist" }},
+	{name: "book.trashed_author", entity: models.EntityBook,
+		query: "SELECT b.bookId,b.authorId FROM Book b JOIN Author a ON a.authorId=b.authorId " +
+			"WHERE b.deleted_at IS NULL and a.deleted_at IS NOT NULL",
+		detail: func(ref string) string { return "author " + ref + " is in trash" }},
+	{name: "loan.trashed_book", entity: "loan",
+		query: "SELECT l.id,l.bookId FROM Loan l JOIN Book b ON b.bookId=l.bookId " +
+			"WHERE l.returnedAt IS NULL and b.deleted_at IS NOT NULL",
+		detail: func(ref string) string { return "book " + ref + " is on loan and in trash" }},
+}
+
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// Check method is to find rows breaking the rules the schema does not enforce, they are left as they are
+func (d Datastore) Check(ctx context.Context) ([]models.Inconsistency, error) {
+	var found []models.Inconsistency
+
+	for _, c := range checks {
+		rows, err := d.db.QueryContext(ctx, c.query)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var (
+				id  int
+				ref string
+			)
+
+			if err = rows.Scan(&id, &ref); err != nil {
+				rows.Close()
+
+				return nil, err
+			}
+
+			found = append(found, models.Inconsistency{Check: c.name, Entity: c.entity, EntityID: id, Detail: c.detail(ref)})
+		}
+
+		err = rows.Err()
+		rows.Close()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return found, nil
+}
@@ -0,0 +1,58 @@
+package consistency
+
+import (
+	"context"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"Three-Layer-Architecture/models"
+)
+
+// Test_Check function is to test every rule is queried and reported rows keep their rule
+func Test_Check(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery(checks[0].query).WillReturnRows(sqlmock.NewRows([]string{"bookId", "authorId"}).AddRow(3, "9"))
+	mock.ExpectQuery(checks[1].query).WillReturnRows(sqlmock.NewRows([]string{"bookId", "authorId"}))
+	mock.ExpectQuery(checks[2].query).WillReturnRows(sqlmock.NewRows([]string{"id", "bookId"}).AddRow(12, "4"))
+
+	expected := []models.Inconsistency{
+		{Check: "book.missing_author", Entity: models.EntityBook, EntityID: 3, Detail: "author 9 does not exist"},
+		{Check: "loan.trashed_book", Entity: "loan", EntityID: 12, Detail: "book 4 is on loan and in trash"},
+	}
+
+	found, err := New(db).Check(context.Background())
+	if err != nil || !reflect.DeepEqual(found, expected) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "inconsistencies", 1, found, err, expected)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "expectations", 2, err, nil)
+	}
+}
+
+// Test_CheckError function is to test a failing query stops the check
+func Test_CheckError(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	expected := errors.New("connection refused")
+	mock.ExpectQuery(checks[0].query).WillReturnError(expected)
+
+	if _, err = New(db).Check(context.Background()); !errors.Is(err, expected) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "query error", 1, err, expected)
+	}
+}
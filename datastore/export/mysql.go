@@ -0,0 +1,68 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"Three-Layer-Architecture/models"
+)
+
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// Stream method is to pass books with their author to fn one by one while reading them from a cursor.
+// Reading stops at the first error of fn, which is returned.
+func (d Datastore) Stream(ctx context.Context, filter models.BookFilter, fn func(models.Book) error) error {
+	where := []string{"b.deleted_at IS NULL"}
+
+	var args []any
+
+	if filter.AuthorID != 0 {
+		where = append(where, "b.authorId=?")
+		args = append(args, filter.AuthorID)
+	}
+
+	if filter.Publication != "" {
+		where = append(where, "b.Publication=?")
+		args = append(args, filter.Publication)
+	}
+
+	if filter.Title != "" {
+		where = append(where, "b.title LIKE ?")
+		args = append(args, "%"+filter.Title+"%")
+	}
+
+	query := "SELECT b.bookId,b.title,b.authorId,b.Publication,b.PublishedDate,b.isbn,a.firstName,a.lastName,a.dob,a.penName " +
+		"FROM Book b JOIN Author a ON a.authorId=b.authorId WHERE " + strings.Join(where, " and ") + " ORDER BY b.bookId"
+
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var b models.Book
+
+		err = rows.Scan(&b.BookID, &b.Title, &b.AuthorID, &b.Publication, &b.PublishedDate, &b.ISBN, &b.Auth.FirstName,
+			&b.Auth.LastName, &b.Auth.Dob, &b.Auth.PenName)
+		if err != nil {
+			return err
+		}
+
+		b.Auth.AuthID = b.AuthorID
+
+		if err = fn(b); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
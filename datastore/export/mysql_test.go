@@ -0,0 +1,79 @@
+package export
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"Three-Layer-Architecture/models"
+)
+
+const columns = "SELECT b.bookId,b.title,b.authorId,b.Publication,b.PublishedDate,b.isbn,a.firstName,a.lastName,a.dob,a.penName " +
+	"FROM Book b JOIN Author a ON a.authorId=b.authorId WHERE "
+
+// Test_Stream function is to test books are passed on one by one with filters applied
+func Test_Stream(t *testing.T) {
+	author := models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}
+	books := []models.Book{
+		{BookID: 1, AuthorID: 1, Auth: author, Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016",
+			ISBN: "8129115301"},
+		{BookID: 3, AuthorID: 1, Auth: author, Title: "One Indian Girl", Publication: "Penguin", PublishedDate: "01/10/2016"},
+	}
+	stop := errors.New("client gone")
+
+	testcases := []struct {
+		desc   string
+		filter models.BookFilter
+		query  string
+		args   []driver.Value
+		fnErr  error
+		resp   []models.Book
+		err    error
+	}{
+		{desc: "no filter", query: columns + "b.deleted_at IS NULL ORDER BY b.bookId", resp: books},
+		{desc: "every filter", filter: models.BookFilter{AuthorID: 1, Publication: "Penguin", Title: "Girl"},
+			query: columns + "b.deleted_at IS NULL and b.authorId=? and b.Publication=? and b.title LIKE ? ORDER BY b.bookId",
+			args:  []driver.Value{1, "Penguin", "%Girl%"}, resp: books},
+		{desc: "error from fn", query: columns + "b.deleted_at IS NULL ORDER BY b.bookId", fnErr: stop,
+			resp: books[:1], err: stop},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		rows := sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "firstName",
+			"lastName", "dob", "penName"})
+		for _, b := range books {
+			rows.AddRow(b.BookID, b.Title, b.AuthorID, b.Publication, b.PublishedDate, b.ISBN, b.Auth.FirstName,
+				b.Auth.LastName, b.Auth.Dob, b.Auth.PenName)
+		}
+
+		mock.ExpectQuery(v.query).WithArgs(v.args...).WillReturnRows(rows)
+
+		var got []models.Book
+
+		err := New(db).Stream(context.Background(), v.filter, func(b models.Book) error {
+			got = append(got, b)
+
+			return v.fnErr
+		})
+
+		if !reflect.DeepEqual(got, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, got, v.resp)
+		}
+
+		if !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package graphql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"Three-Layer-Architecture/models"
+)
+
+const (
+	bookColumns   = "SELECT bookId,title,authorId,Publication,PublishedDate,isbn FROM Book WHERE deleted_at IS NULL"
+	authorColumns = "SELECT authorId,firstName,lastName,dob,penName FROM Author WHERE deleted_at IS NULL"
+)
+
+// Datastore reads books and authors in batches, books are read without their author so it can be loaded once per id
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// Books method is to get every book which is not in trash, in id order
+func (d Datastore) Books(ctx context.Context) ([]models.Book, error) {
+	return d.books(ctx, bookColumns+" ORDER BY bookId")
+}
+
+// BooksByID method is to get the books of ids which are not in trash
+func (d Datastore) BooksByID(ctx context.Context, ids []int) ([]models.Book, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	return d.books(ctx, bookColumns+" AND bookId IN ("+placeholders(len(ids))+") ORDER BY bookId", args(ids)...)
+}
+
+// BooksByAuthor method is to get the books written by any of authorIDs, in id order
+func (d Datastore) BooksByAuthor(ctx context.Context, authorIDs []int) ([]models.Book, error) {
+	if len(authorIDs) == 0 {
+		return nil, nil
+	}
+
+	return d.books(ctx, bookColumns+" AND authorId IN ("+placeholders(len(authorIDs))+") ORDER BY bookId", args(authorIDs)...)
+}
+
+// Authors method is to get the authors of ids which are not in trash
+func (d Datastore) Authors(ctx context.Context, ids []int) ([]models.Author, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err := d.db.QueryContext(ctx, authorColumns+" AND authorId IN ("+placeholders(len(ids))+")", args(ids)...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var authors []models.Author
+
+	for rows.Next() {
+		var a models.Author
+
+		if err := rows.Scan(&a.AuthID, &a.FirstName, &a.LastName, &a.Dob, &a.PenName); err != nil {
+			return nil, err
+		}
+
+		authors = append(authors, a)
+	}
+
+	return authors, rows.Err()
+}
+
+func (d Datastore) books(ctx context.Context, query string, args ...any) ([]models.Book, error) {
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var books []models.Book
+
+	for rows.Next() {
+		var b models.Book
+
+		if err := rows.Scan(&b.BookID, &b.Title, &b.AuthorID, &b.Publication, &b.PublishedDate, &b.ISBN); err != nil {
+			return nil, err
+		}
+
+		books = append(books, b)
+	}
+
+	return books, rows.Err()
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+func args(ids []int) []any {
+	out := make([]any, len(ids))
+	for i, id := range ids {
+		out[i] = id
+	}
+
+	return out
+}
@@ -0,0 +1,101 @@
+package graphql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"Three-Layer-Architecture/models"
+)
+
+var bookRows = []string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn"}
+
+// Test_BooksByAuthor function is to test books of several authors are read by one query
+func Test_BooksByAuthor(t *testing.T) {
+	books := []models.Book{
+		{BookID: 1, AuthorID: 1, Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016", ISBN: "8129115301"},
+		{BookID: 2, AuthorID: 2, Title: "Godan", Publication: "Penguin", PublishedDate: "01/01/1936"},
+	}
+
+	testcases := []struct {
+		desc  string
+		ids   []int
+		query bool
+		resp  []models.Book
+		err   error
+	}{
+		{desc: "two authors", ids: []int{1, 2}, query: true, resp: books},
+		{desc: "no authors", ids: nil},
+		{desc: "error of db", ids: []int{3}, query: true, err: errors.New("connection refused")},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		if v.query {
+			args := make([]driver.Value, len(v.ids))
+			for j, id := range v.ids {
+				args[j] = id
+			}
+
+			query := mock.ExpectQuery(bookColumns + " AND authorId IN (" + placeholders(len(v.ids)) + ") ORDER BY bookId").
+				WithArgs(args...)
+
+			if v.err != nil {
+				query.WillReturnError(v.err)
+			} else {
+				rows := sqlmock.NewRows(bookRows)
+				for _, b := range v.resp {
+					rows.AddRow(b.BookID, b.Title, b.AuthorID, b.Publication, b.PublishedDate, b.ISBN)
+				}
+
+				query.WillReturnRows(rows)
+			}
+		}
+
+		resp, err := New(db).BooksByAuthor(context.Background(), v.ids)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+// Test_Authors function is to test authors are read by one query
+func Test_Authors(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	author := models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}
+
+	mock.ExpectQuery(authorColumns+" AND authorId IN (?,?)").WithArgs(1, 9).
+		WillReturnRows(sqlmock.NewRows([]string{"authorId", "firstName", "lastName", "dob", "penName"}).
+			AddRow(author.AuthID, author.FirstName, author.LastName, author.Dob, author.PenName))
+
+	resp, err := New(db).Authors(context.Background(), []int{1, 9})
+	if err != nil || !reflect.DeepEqual(resp, []models.Author{author}) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "one of two found", 1, resp, err, author)
+	}
+}
@@ -0,0 +1,19 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+)
+
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// Ping method is to check the database answers
+func (d Datastore) Ping(ctx context.Context) error {
+	return d.db.PingContext(ctx)
+}
@@ -0,0 +1,28 @@
+package importer
+
+import (
+	"context"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/datastore/book"
+	"Three-Layer-Architecture/models"
+)
+
+// Cached drops cached books after an import, imported books are missing from cached lists
+type Cached struct {
+	next  datastore.Import
+	cache datastore.Cache
+}
+
+func NewCached(next datastore.Import, c datastore.Cache) Cached {
+	return Cached{next: next, cache: c}
+}
+
+func (c Cached) Commit(ctx context.Context, books []models.Book) ([]error, error) {
+	rowErrs, err := c.next.Commit(ctx, books)
+	if err == nil {
+		c.cache.DeletePrefix(ctx, book.KeyPrefix)
+	}
+
+	return rowErrs, err
+}
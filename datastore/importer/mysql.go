@@ -0,0 +1,102 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"Three-Layer-Architecture/datastore/audit"
+	"Three-Layer-Architecture/datastore/outbox"
+	"Three-Layer-Architecture/datastore/revision"
+	"Three-Layer-Architecture/models"
+)
+
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// Commit method is to store books and their missing authors in one transaction.
+// A failing row is rolled back to its savepoint alone, its error is returned at its index and nil for stored rows.
+// Authors which already exist are kept as they are.
+func (d Datastore) Commit(ctx context.Context, books []models.Book) ([]error, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rowErrs := make([]error, len(books))
+
+	for i := range books {
+		if _, err = tx.ExecContext(ctx, "SAVEPOINT row"); err != nil {
+			_ = tx.Rollback()
+
+			return nil, err
+		}
+
+		if err = insert(ctx, tx, books[i]); err != nil {
+			rowErrs[i] = err
+
+			if _, err = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT row"); err != nil {
+				_ = tx.Rollback()
+
+				return nil, err
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return rowErrs, nil
+}
+
+// insert stores one book with its events, audit entries and revisions like posted ones, the author only when it was
+// created
+func insert(ctx context.Context, tx *sql.Tx, book models.Book) error {
+	// no-op update keeps existing author, rows affected tells if it was inserted
+	res, err := tx.ExecContext(ctx, "insert into Author(authorId,firstName,lastName,dob,penName) values (?,?,?,?,?) "+
+		"ON DUPLICATE KEY UPDATE authorId=authorId", book.Auth.AuthID, book.Auth.FirstName, book.Auth.LastName, book.Auth.Dob,
+		book.Auth.PenName)
+	if err != nil {
+		return fmt.Errorf("author %v : %w", book.Auth.AuthID, err)
+	}
+
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "insert into Book(bookId,title,authorId,Publication,PublishedDate,isbn) values (?,?,?,?,?,?)",
+		book.BookID, book.Title, book.AuthorID, book.Publication, book.PublishedDate, book.ISBN)
+	if err != nil {
+		return fmt.Errorf("book %v : %w", book.BookID, err)
+	}
+
+	if err = record(ctx, tx, models.EventBookCreated, models.EntityBook, book.BookID, book); err != nil {
+		return err
+	}
+
+	if inserted == 0 {
+		return nil
+	}
+
+	return record(ctx, tx, models.EventAuthorCreated, models.EntityAuthor, book.Auth.AuthID, book.Auth)
+}
+
+// record stores the event, audit entry and revision of a created record
+func record(ctx context.Context, tx *sql.Tx, event, entity string, id int, data any) error {
+	if err := outbox.Record(ctx, tx, event, entity, id, data); err != nil {
+		return err
+	}
+
+	if err := audit.Record(ctx, tx, models.AuditCreate, entity, id, nil, data); err != nil {
+		return err
+	}
+
+	return revision.Record(ctx, tx, models.AuditCreate, entity, id, data)
+}
@@ -0,0 +1,112 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"Three-Layer-Architecture/models"
+)
+
+const (
+	authorQuery = "insert into Author(authorId,firstName,lastName,dob,penName) values (?,?,?,?,?) " +
+		"ON DUPLICATE KEY UPDATE authorId=authorId"
+	bookQuery   = "insert into Book(bookId,title,authorId,Publication,PublishedDate,isbn) values (?,?,?,?,?,?)"
+	outboxQuery = "INSERT INTO Outbox(event,entity,entityId,payload,createdAt) VALUES (?,?,?,?,NOW())"
+	auditQuery  = "insert into Audit(actor,occurredAt,operation,entity,entityId,beforeData,afterData) values (?,?,?,?,?,?,?)"
+	latestRev   = "SELECT COALESCE(MAX(rev),0) FROM Revision WHERE entity=? and entityId=? FOR UPDATE"
+	revQuery    = "insert into Revision(entity,entityId,rev,operation,actor,createdAt,data) values (?,?,?,?,?,?,?)"
+)
+
+// expectCreated expects the event, audit entry and first revision of a created record
+func expectCreated(mock sqlmock.Sqlmock, event, entity string, id int) {
+	mock.ExpectExec(outboxQuery).WithArgs(event, entity, id, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(auditQuery).WithArgs("anonymous", sqlmock.AnyArg(), models.AuditCreate, entity, id, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(latestRev).WithArgs(entity, id).WillReturnRows(sqlmock.NewRows([]string{"rev"}).AddRow(0))
+	mock.ExpectExec(revQuery).WithArgs(entity, id, 1, models.AuditCreate, "anonymous", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+// Test_Commit function is to test storing a batch with a row failing alone
+func Test_Commit(t *testing.T) {
+	author := models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}
+	books := []models.Book{
+		{BookID: 1, AuthorID: 1, Auth: author, Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016",
+			ISBN: "8129115301"},
+		{BookID: 2, AuthorID: 1, Auth: author, Title: "Half Girlfriend", Publication: "Penguin", PublishedDate: "01/10/2014"},
+		{BookID: 3, AuthorID: 1, Auth: author, Title: "One Indian Girl", Publication: "Penguin", PublishedDate: "01/10/2016"},
+	}
+	duplicate := errors.New("Duplicate entry '2' for key 'PRIMARY'")
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+
+	// author is created by first row and kept by the others
+	for i, affected := range []int64{1, 0, 0} {
+		b := books[i]
+
+		mock.ExpectExec("SAVEPOINT row").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(authorQuery).WithArgs(author.AuthID, author.FirstName, author.LastName, author.Dob, author.PenName).
+			WillReturnResult(sqlmock.NewResult(0, affected))
+
+		exec := mock.ExpectExec(bookQuery).WithArgs(b.BookID, b.Title, b.AuthorID, b.Publication, b.PublishedDate, b.ISBN)
+		if i == 1 {
+			exec.WillReturnError(duplicate)
+			mock.ExpectExec("ROLLBACK TO SAVEPOINT row").WillReturnResult(sqlmock.NewResult(0, 0))
+
+			continue
+		}
+
+		exec.WillReturnResult(sqlmock.NewResult(0, 1))
+
+		// events and history of stored rows are in the same transaction
+		expectCreated(mock, models.EventBookCreated, models.EntityBook, b.BookID)
+
+		if affected == 1 {
+			expectCreated(mock, models.EventAuthorCreated, models.EntityAuthor, author.AuthID)
+		}
+	}
+
+	mock.ExpectCommit()
+
+	rowErrs, err := New(db).Commit(context.Background(), books)
+	if err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "commit", 1, err, nil)
+	}
+
+	if rowErrs[0] != nil || rowErrs[2] != nil || !errors.Is(rowErrs[1], duplicate) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "row errors", 2, rowErrs, duplicate)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "expectations", 3, err, nil)
+	}
+}
+
+// Test_CommitBeginError function is to test nothing is stored when transaction can not start
+func Test_CommitBeginError(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	expected := errors.New("connection refused")
+	mock.ExpectBegin().WillReturnError(expected)
+
+	_, err = New(db).Commit(context.Background(), []models.Book{{BookID: 1}})
+	if !errors.Is(err, expected) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "begin error", 1, err, expected)
+	}
+}
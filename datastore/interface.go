@@ -1,19 +1,158 @@
 package datastore
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"Three-Layer-Architecture/models"
 )
 
+// ErrDuplicate is returned by writes which would break a unique key, like a second active loan of a book
+var ErrDuplicate = errors.New("duplicate entry")
+
+// ErrAuthorTrashed is returned when a book of an author in trash is restored, restoring the author brings it back
+var ErrAuthorTrashed = errors.New("author is in trash")
+
+// ErrLimit is returned by writes which would go over a limit checked in their transaction, like the loans of a user
+var ErrLimit = errors.New("limit reached")
+
 type Book interface {
-	Post(book *models.Book) (models.Book, error)
-	GetAll() ([]models.Book, error)
-	Getbyid(id string) (models.Book, error)
-	Update(id string, book *models.Book) (models.Book, error)
-	Delete(id string) (int, error)
+	Post(ctx context.Context, book *models.Book) (models.Book, error)
+	GetAll(ctx context.Context) ([]models.Book, error)
+	Getbyid(ctx context.Context, id string) (models.Book, error)
+	Update(ctx context.Context, id string, book *models.Book) (models.Book, error)
+	Delete(ctx context.Context, id string) (int, error)
+	GetDeleted(ctx context.Context) ([]models.Book, error)
+	Restore(ctx context.Context, id string) (int, error)
+	Purge(ctx context.Context, before time.Time) (int, error)
 }
 
 type Author interface {
-	Post(models.Author) (models.Author, error)
-	Update(id string, author models.Author) (models.Author, error)
-	Delete(id string) (int, error)
+	Post(ctx context.Context, author models.Author) (models.Author, error)
+	Getbyid(ctx context.Context, id string) (models.Author, error)
+	Update(ctx context.Context, id string, author models.Author) (models.Author, error)
+	Delete(ctx context.Context, id string) (int, error)
+	GetDeleted(ctx context.Context) ([]models.Author, error)
+	Restore(ctx context.Context, id string) (int, error)
+	Purge(ctx context.Context, before time.Time) (int, error)
+}
+
+type Audit interface {
+	Get(ctx context.Context, filter models.AuditFilter) ([]models.Audit, error)
+}
+
+type Revision interface {
+	GetAll(ctx context.Context, entity string, id int) ([]models.Revision, error)
+	Get(ctx context.Context, entity string, id, rev int) (models.Revision, error)
+	GetAsOf(ctx context.Context, entity string, id int, at time.Time) (models.Revision, error)
+}
+
+type APIKey interface {
+	Post(ctx context.Context, key models.APIKey) (models.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (models.APIKey, error)
+	Revoke(ctx context.Context, id string) (int, error)
+}
+
+type RBAC interface {
+	GetPermissions(ctx context.Context, principal string) ([]string, error)
+	GetRoles(ctx context.Context) ([]models.Role, error)
+	GetAssignments(ctx context.Context, principal string) ([]string, error)
+	Assign(ctx context.Context, principal, role string) error
+	Unassign(ctx context.Context, principal, role string) (int, error)
+}
+
+type User interface {
+	Post(ctx context.Context, user models.User) (models.User, error)
+	GetByUsername(ctx context.Context, username string) (models.User, error)
+	Getbyid(ctx context.Context, id int) (models.User, error)
+	RecordFailedLogin(ctx context.Context, id int, now time.Time) (int, error)
+	Lock(ctx context.Context, id int, until time.Time) error
+	ResetFailedLogins(ctx context.Context, id int) error
+	UpdatePassword(ctx context.Context, id int, hash string) error
+	PostReset(ctx context.Context, reset models.PasswordReset) error
+	UseReset(ctx context.Context, tokenHash string, now time.Time) (models.PasswordReset, error)
+}
+
+type Session interface {
+	Post(ctx context.Context, session models.Session) (models.Session, error)
+	GetActive(ctx context.Context, tokenHash string, now time.Time) (models.Session, error)
+	Revoke(ctx context.Context, tokenHash string) (int, error)
+	RevokeAll(ctx context.Context, userID int) (int, error)
+}
+
+type Import interface {
+	Commit(ctx context.Context, books []models.Book) ([]error, error)
+}
+
+// Export reads books from a cursor, the catalogue is never loaded at once
+type Export interface {
+	Stream(ctx context.Context, filter models.BookFilter, fn func(models.Book) error) error
+}
+
+type OAI interface {
+	Earliest(ctx context.Context) (time.Time, error)
+	List(ctx context.Context, filter models.OAIFilter) ([]models.OAIRecord, error)
+	Get(ctx context.Context, id int) (models.OAIRecord, error)
+}
+
+// SRU finds books matching a search, with the count of all matches for paging
+type SRU interface {
+	Search(ctx context.Context, query *models.BookQuery, offset, limit int) ([]models.Book, int, error)
+}
+
+// GraphQL reads books and authors by many ids at once, books come without their author
+type GraphQL interface {
+	Books(ctx context.Context) ([]models.Book, error)
+	BooksByID(ctx context.Context, ids []int) ([]models.Book, error)
+	BooksByAuthor(ctx context.Context, authorIDs []int) ([]models.Book, error)
+	Authors(ctx context.Context, ids []int) ([]models.Author, error)
+}
+
+type Loan interface {
+	Post(ctx context.Context, loan models.Loan, maxLoans int) (models.Loan, error)
+	GetActive(ctx context.Context, bookID int) (models.Loan, error)
+	GetByUser(ctx context.Context, userID int) ([]models.Loan, error)
+	Return(ctx context.Context, loan models.Loan) error
+	Renew(ctx context.Context, loan models.Loan, maxRenewals int) error
+}
+
+// Webhook stores subscriptions and the queue of their deliveries
+type Webhook interface {
+	Post(ctx context.Context, webhook models.Webhook) (models.Webhook, error)
+	GetAll(ctx context.Context) ([]models.Webhook, error)
+	Get(ctx context.Context, id int) (models.Webhook, error)
+	Update(ctx context.Context, webhook models.Webhook) error
+	Delete(ctx context.Context, id int) (int, error)
+	PostDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error
+	GetDeliveries(ctx context.Context, webhookID, limit int) ([]models.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, id int) (models.WebhookDelivery, error)
+	Due(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error)
+	Claim(ctx context.Context, delivery models.WebhookDelivery, until time.Time) (bool, error)
+	UpdateDelivery(ctx context.Context, delivery models.WebhookDelivery) error
+}
+
+// Outbox reads events stored in the transactions of book and author changes, oldest first
+type Outbox interface {
+	Advance(ctx context.Context, sink string, limit int, fn func(events []models.OutboxEvent) (int64, error)) error
+	After(ctx context.Context, id int64, limit int) ([]models.OutboxEvent, error)
+	Last(ctx context.Context) (int64, error)
+	Purge(ctx context.Context, sinks []string, before time.Time) (int, error)
+}
+
+type Health interface {
+	Ping(ctx context.Context) error
+}
+
+// Cache stores encoded values of caching datastores, implementations may share them between replicas
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+	Delete(ctx context.Context, keys ...string)
+	DeletePrefix(ctx context.Context, prefix string)
+}
+
+// Consistency finds rows which break rules the schema does not enforce
+type Consistency interface {
+	Check(ctx context.Context) ([]models.Inconsistency, error)
 }
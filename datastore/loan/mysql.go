@@ -0,0 +1,199 @@
+package loan
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/datastore/outbox"
+	"Three-Layer-Architecture/models"
+)
+
+const columns = "SELECT id,bookId,userId,checkedOutAt,dueAt,renewals,returnedAt FROM Loan"
+
+// duplicateEntry is the error number of MySQL for a row breaking a unique key
+const duplicateEntry = 1062
+
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// Post method is to start a loan, loan.checked_out event is stored with it. The table keeps a book from having two
+// active loans and datastore.ErrDuplicate is returned for the second one, datastore.ErrLimit is returned when the
+// user already has maxLoans books
+func (d Datastore) Post(ctx context.Context, loan models.Loan, maxLoans int) (models.Loan, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Loan{}, err
+	}
+
+	// rollback after commit is a no-op
+	defer func() { _ = tx.Rollback() }()
+
+	// the row of the user is locked so concurrent checkouts of one user are counted one after the other
+	var userID, active int
+
+	if err = tx.QueryRowContext(ctx, "SELECT id FROM User WHERE id=? FOR UPDATE", loan.UserID).Scan(&userID); err != nil {
+		return models.Loan{}, err
+	}
+
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM Loan WHERE userId=? and returnedAt IS NULL", loan.UserID).
+		Scan(&active)
+	if err != nil {
+		return models.Loan{}, err
+	}
+
+	if active >= maxLoans {
+		return models.Loan{}, fmt.Errorf("%w : %d active loans", datastore.ErrLimit, active)
+	}
+
+	res, err := tx.ExecContext(ctx, "insert into Loan(bookId,userId,checkedOutAt,dueAt) values (?,?,?,?)",
+		loan.BookID, loan.UserID, loan.CheckedOutAt, loan.DueAt)
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == duplicateEntry {
+		return models.Loan{}, fmt.Errorf("%w : %v", datastore.ErrDuplicate, err)
+	}
+
+	if err != nil {
+		return models.Loan{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.Loan{}, err
+	}
+
+	loan.ID = int(id)
+
+	if err = outbox.Record(ctx, tx, models.EventLoanCheckedOut, models.EntityLoan, loan.ID, loan); err != nil {
+		return models.Loan{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.Loan{}, err
+	}
+
+	return loan, nil
+}
+
+// GetActive method is to get the loan a book is out on, sql.ErrNoRows when it is on the shelf
+func (d Datastore) GetActive(ctx context.Context, bookID int) (models.Loan, error) {
+	return scan(d.db.QueryRowContext(ctx, columns+" WHERE bookId=? and returnedAt IS NULL", bookID))
+}
+
+// GetByUser method is to get the active loans of a user by due date
+func (d Datastore) GetByUser(ctx context.Context, userID int) ([]models.Loan, error) {
+	rows, err := d.db.QueryContext(ctx, columns+" WHERE userId=? and returnedAt IS NULL ORDER BY dueAt,id", userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var loans []models.Loan
+
+	for rows.Next() {
+		loan, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		loans = append(loans, loan)
+	}
+
+	return loans, rows.Err()
+}
+
+// Return method is to end an active loan at its ReturnedAt, loan.checked_in event is stored with it.
+// sql.ErrNoRows is returned when it has already ended
+func (d Datastore) Return(ctx context.Context, loan models.Loan) error {
+	return d.update(ctx, models.EventLoanCheckedIn, loan, "UPDATE Loan SET returnedAt=? WHERE id=? and returnedAt IS NULL",
+		loan.ReturnedAt, loan.ID)
+}
+
+// Renew method is to move the due date of an active loan to its DueAt and count the renewal, loan.renewed event is
+// stored with it. sql.ErrNoRows is returned when it has ended and datastore.ErrLimit when it had maxRenewals
+func (d Datastore) Renew(ctx context.Context, loan models.Loan, maxRenewals int) error {
+	err := d.update(ctx, models.EventLoanRenewed, loan,
+		"UPDATE Loan SET dueAt=?, renewals=renewals+1 WHERE id=? and returnedAt IS NULL and renewals<?",
+		loan.DueAt, loan.ID, maxRenewals)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	// no row changed, the loan is still active when a concurrent renewal used up the last one
+	var renewals int
+
+	err = d.db.QueryRowContext(ctx, "SELECT renewals FROM Loan WHERE id=? and returnedAt IS NULL", loan.ID).Scan(&renewals)
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w : %d renewals", datastore.ErrLimit, renewals)
+}
+
+// update changes the loan by query and stores event with the loan after the change in one transaction
+func (d Datastore) update(ctx context.Context, event string, loan models.Loan, query string, args ...any) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// rollback after commit is a no-op
+	defer func() { _ = tx.Rollback() }()
+
+	if err = changed(tx.ExecContext(ctx, query, args...)); err != nil {
+		return err
+	}
+
+	if err = outbox.Record(ctx, tx, event, models.EntityLoan, loan.ID, loan); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// changed returns sql.ErrNoRows when an update found no row, a concurrent request got there first
+func changed(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func scan(row interface{ Scan(dest ...any) error }) (models.Loan, error) {
+	var (
+		loan     models.Loan
+		returned sql.NullTime
+	)
+
+	err := row.Scan(&loan.ID, &loan.BookID, &loan.UserID, &loan.CheckedOutAt, &loan.DueAt, &loan.Renewals, &returned)
+	if err != nil {
+		return models.Loan{}, err
+	}
+
+	if returned.Valid {
+		loan.ReturnedAt = &returned.Time
+	}
+
+	return loan, nil
+}
@@ -0,0 +1,221 @@
+package loan
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+const outboxInsert = "INSERT INTO Outbox(event,entity,entityId,payload,createdAt) VALUES (?,?,?,?,NOW())"
+
+var loanColumns = []string{"id", "bookId", "userId", "checkedOutAt", "dueAt", "renewals", "returnedAt"}
+
+// Test_Post function is to test a loan gets the id of its row and its event is stored with it
+func Test_Post(t *testing.T) {
+	at := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	loan := models.Loan{BookID: 1, UserID: 2, CheckedOutAt: at, DueAt: at.Add(21 * 24 * time.Hour)}
+	dup := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'activeBookId'"}
+	lost := errors.New("connection refused")
+
+	testcases := []struct {
+		desc    string
+		active  int
+		execErr error
+		err     error
+		resp    models.Loan
+	}{
+		{desc: "created", active: 1,
+			resp: models.Loan{ID: 7, BookID: 1, UserID: 2, CheckedOutAt: loan.CheckedOutAt, DueAt: loan.DueAt}},
+		{desc: "already on loan", execErr: dup, err: datastore.ErrDuplicate},
+		{desc: "connection lost", execErr: lost, err: lost},
+		{desc: "loan limit", active: 2, err: datastore.ErrLimit},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM User WHERE id=? FOR UPDATE").WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectQuery("SELECT COUNT(*) FROM Loan WHERE userId=? and returnedAt IS NULL").WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(v.active))
+
+		switch {
+		case v.active >= 2:
+			mock.ExpectRollback()
+		case v.execErr != nil:
+			mock.ExpectExec("insert into Loan(bookId,userId,checkedOutAt,dueAt) values (?,?,?,?)").
+				WithArgs(1, 2, loan.CheckedOutAt, loan.DueAt).WillReturnError(v.execErr)
+			mock.ExpectRollback()
+		default:
+			mock.ExpectExec("insert into Loan(bookId,userId,checkedOutAt,dueAt) values (?,?,?,?)").
+				WithArgs(1, 2, loan.CheckedOutAt, loan.DueAt).WillReturnResult(sqlmock.NewResult(7, 1))
+			mock.ExpectExec(outboxInsert).WithArgs(models.EventLoanCheckedOut, models.EntityLoan, 7, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+		}
+
+		resp, err := New(db).Post(context.Background(), loan, 2)
+
+		if !reflect.DeepEqual(resp, v.resp) || !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", v.desc, i+1, resp, err, v.resp, v.err)
+		}
+	}
+}
+
+// Test_GetActive function is to test the loan of a book is found until it is returned
+func Test_GetActive(t *testing.T) {
+	at := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	query := columns + " WHERE bookId=? and returnedAt IS NULL"
+
+	mock.ExpectQuery(query).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(loanColumns).AddRow(7, 1, 2, at, at.Add(time.Hour), 1, nil))
+	mock.ExpectQuery(query).WithArgs(2).WillReturnError(sql.ErrNoRows)
+
+	expected := models.Loan{ID: 7, BookID: 1, UserID: 2, CheckedOutAt: at, DueAt: at.Add(time.Hour), Renewals: 1}
+
+	if resp, err := New(db).GetActive(context.Background(), 1); err != nil || !reflect.DeepEqual(resp, expected) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "on loan", 1, resp, err, expected)
+	}
+
+	if _, err = New(db).GetActive(context.Background(), 2); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "on shelf", 2, err, sql.ErrNoRows)
+	}
+}
+
+// Test_GetByUser function is to test active loans of a user are listed
+func Test_GetByUser(t *testing.T) {
+	at := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery(columns + " WHERE userId=? and returnedAt IS NULL ORDER BY dueAt,id").WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(loanColumns).AddRow(7, 1, 2, at, at, 0, nil).AddRow(8, 3, 2, at, at, 2, nil))
+
+	expected := []models.Loan{{ID: 7, BookID: 1, UserID: 2, CheckedOutAt: at, DueAt: at},
+		{ID: 8, BookID: 3, UserID: 2, CheckedOutAt: at, DueAt: at, Renewals: 2}}
+
+	if resp, err := New(db).GetByUser(context.Background(), 2); err != nil || !reflect.DeepEqual(resp, expected) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "active loans", 1, resp, err, expected)
+	}
+}
+
+// Test_Return function is to test only active loans are returned with their event, a loan ended meanwhile is not
+func Test_Return(t *testing.T) {
+	at := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc     string
+		affected int64
+		err      error
+	}{
+		{desc: "active", affected: 1},
+		{desc: "ended meanwhile", affected: 0, err: sql.ErrNoRows},
+	}
+
+	for i, v := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE Loan SET returnedAt=? WHERE id=? and returnedAt IS NULL").WithArgs(at, 7).
+			WillReturnResult(sqlmock.NewResult(0, v.affected))
+
+		if v.err != nil {
+			mock.ExpectRollback()
+		} else {
+			mock.ExpectExec(outboxInsert).WithArgs(models.EventLoanCheckedIn, models.EntityLoan, 7, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+		}
+
+		if err = New(db).Return(context.Background(), models.Loan{ID: 7, ReturnedAt: &at}); !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, nil)
+		}
+
+		db.Close()
+	}
+}
+
+// Test_Renew function is to test active loans are renewed with their event up to the renewals given, a loan ended or
+// renewed by another request meanwhile is not
+func Test_Renew(t *testing.T) {
+	at := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc     string
+		affected int64
+		// renewals is the count read back when no row changed, nil when the loan ended
+		renewals *sqlmock.Rows
+		err      error
+	}{
+		{desc: "active", affected: 1},
+		{desc: "ended meanwhile", renewals: sqlmock.NewRows([]string{"renewals"}), err: sql.ErrNoRows},
+		{desc: "renewed meanwhile", renewals: sqlmock.NewRows([]string{"renewals"}).AddRow(2), err: datastore.ErrLimit},
+	}
+
+	for i, v := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE Loan SET dueAt=?, renewals=renewals+1 WHERE id=? and returnedAt IS NULL and renewals<?").
+			WithArgs(at, 8, 2).WillReturnResult(sqlmock.NewResult(0, v.affected))
+
+		if v.err != nil {
+			mock.ExpectRollback()
+			mock.ExpectQuery("SELECT renewals FROM Loan WHERE id=? and returnedAt IS NULL").WithArgs(8).
+				WillReturnRows(v.renewals)
+		} else {
+			mock.ExpectExec(outboxInsert).WithArgs(models.EventLoanRenewed, models.EntityLoan, 8, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+		}
+
+		if err = New(db).Renew(context.Background(), models.Loan{ID: 8, DueAt: at}, 2); !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, nil)
+		}
+
+		db.Close()
+	}
+}
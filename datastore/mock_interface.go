@@ -6,172 +6,1570 @@ package datastore
 
 import (
 	models "Three-Layer-Architecture/models"
-	gomock "github.com/golang/mock/gomock"
+	context "context"
 	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
 )
 
-// MockBook is a mock of Book interface
+// MockBook is a mock of Book interface.
 type MockBook struct {
 	ctrl     *gomock.Controller
 	recorder *MockBookMockRecorder
 }
 
-// MockBookMockRecorder is the mock recorder for MockBook
+// MockBookMockRecorder is the mock recorder for MockBook.
 type MockBookMockRecorder struct {
 	mock *MockBook
 }
 
-// NewMockBook creates a new mock instance
+// NewMockBook creates a new mock instance.
 func NewMockBook(ctrl *gomock.Controller) *MockBook {
 	mock := &MockBook{ctrl: ctrl}
 	mock.recorder = &MockBookMockRecorder{mock}
 	return mock
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
+// EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockBook) EXPECT() *MockBookMockRecorder {
 	return m.recorder
 }
 
-// Post mocks base method
-func (m *MockBook) Post(arg0 *models.Book) (models.Book, error) {
+// Delete mocks base method.
+func (m *MockBook) Delete(ctx context.Context, id string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Post", arg0)
-	ret0, _ := ret[0].(models.Book)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBookMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBook)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockBook) GetAll(ctx context.Context) ([]models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Post indicates an expected call of Post
-func (mr *MockBookMockRecorder) Post(arg0 interface{}) *gomock.Call {
+// GetAll indicates an expected call of GetAll.
+func (mr *MockBookMockRecorder) GetAll(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockBook)(nil).Post), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBook)(nil).GetAll), ctx)
 }
 
-// GetAll mocks base method
-func (m *MockBook) GetAll() ([]models.Book, error) {
+// GetDeleted mocks base method.
+func (m *MockBook) GetDeleted(ctx context.Context) ([]models.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetDeleted", ctx)
 	ret0, _ := ret[0].([]models.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAll indicates an expected call of GetAll
-func (mr *MockBookMockRecorder) GetAll() *gomock.Call {
+// GetDeleted indicates an expected call of GetDeleted.
+func (mr *MockBookMockRecorder) GetDeleted(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBook)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleted", reflect.TypeOf((*MockBook)(nil).GetDeleted), ctx)
 }
 
-// Getbyid mocks base method
-func (m *MockBook) Getbyid(id string) (models.Book, error) {
+// Getbyid mocks base method.
+func (m *MockBook) Getbyid(ctx context.Context, id string) (models.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Getbyid", id)
+	ret := m.ctrl.Call(m, "Getbyid", ctx, id)
 	ret0, _ := ret[0].(models.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Getbyid indicates an expected call of Getbyid
-func (mr *MockBookMockRecorder) Getbyid(id interface{}) *gomock.Call {
+// Getbyid indicates an expected call of Getbyid.
+func (mr *MockBookMockRecorder) Getbyid(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Getbyid", reflect.TypeOf((*MockBook)(nil).Getbyid), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Getbyid", reflect.TypeOf((*MockBook)(nil).Getbyid), ctx, id)
 }
 
-// Update mocks base method
-func (m *MockBook) Update(id string, book *models.Book) (models.Book, error) {
+// Post mocks base method.
+func (m *MockBook) Post(ctx context.Context, book *models.Book) (models.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", id, book)
+	ret := m.ctrl.Call(m, "Post", ctx, book)
 	ret0, _ := ret[0].(models.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Update indicates an expected call of Update
-func (mr *MockBookMockRecorder) Update(id, book interface{}) *gomock.Call {
+// Post indicates an expected call of Post.
+func (mr *MockBookMockRecorder) Post(ctx, book interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockBook)(nil).Post), ctx, book)
+}
+
+// Purge mocks base method.
+func (m *MockBook) Purge(ctx context.Context, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockBookMockRecorder) Purge(ctx, before interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBook)(nil).Update), id, book)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockBook)(nil).Purge), ctx, before)
 }
 
-// Delete mocks base method
-func (m *MockBook) Delete(id string) (int, error) {
+// Restore mocks base method.
+func (m *MockBook) Restore(ctx context.Context, id string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Delete indicates an expected call of Delete
-func (mr *MockBookMockRecorder) Delete(id interface{}) *gomock.Call {
+// Restore indicates an expected call of Restore.
+func (mr *MockBookMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockBook)(nil).Restore), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockBook) Update(ctx context.Context, id string, book *models.Book) (models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, book)
+	ret0, _ := ret[0].(models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBookMockRecorder) Update(ctx, id, book interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBook)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBook)(nil).Update), ctx, id, book)
 }
 
-// MockAuthor is a mock of Author interface
+// MockAuthor is a mock of Author interface.
 type MockAuthor struct {
 	ctrl     *gomock.Controller
 	recorder *MockAuthorMockRecorder
 }
 
-// MockAuthorMockRecorder is the mock recorder for MockAuthor
+// MockAuthorMockRecorder is the mock recorder for MockAuthor.
 type MockAuthorMockRecorder struct {
 	mock *MockAuthor
 }
 
-// NewMockAuthor creates a new mock instance
+// NewMockAuthor creates a new mock instance.
 func NewMockAuthor(ctrl *gomock.Controller) *MockAuthor {
 	mock := &MockAuthor{ctrl: ctrl}
 	mock.recorder = &MockAuthorMockRecorder{mock}
 	return mock
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
+// EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockAuthor) EXPECT() *MockAuthorMockRecorder {
 	return m.recorder
 }
 
-// Post mocks base method
-func (m *MockAuthor) Post(arg0 models.Author) (models.Author, error) {
+// Delete mocks base method.
+func (m *MockAuthor) Delete(ctx context.Context, id string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Post", arg0)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAuthorMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuthor)(nil).Delete), ctx, id)
+}
+
+// GetDeleted mocks base method.
+func (m *MockAuthor) GetDeleted(ctx context.Context) ([]models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleted", ctx)
+	ret0, _ := ret[0].([]models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeleted indicates an expected call of GetDeleted.
+func (mr *MockAuthorMockRecorder) GetDeleted(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleted", reflect.TypeOf((*MockAuthor)(nil).GetDeleted), ctx)
+}
+
+// Getbyid mocks base method.
+func (m *MockAuthor) Getbyid(ctx context.Context, id string) (models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Getbyid", ctx, id)
+	ret0, _ := ret[0].(models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Getbyid indicates an expected call of Getbyid.
+func (mr *MockAuthorMockRecorder) Getbyid(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Getbyid", reflect.TypeOf((*MockAuthor)(nil).Getbyid), ctx, id)
+}
+
+// Post mocks base method.
+func (m *MockAuthor) Post(ctx context.Context, author models.Author) (models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, author)
 	ret0, _ := ret[0].(models.Author)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Post indicates an expected call of Post
-func (mr *MockAuthorMockRecorder) Post(arg0 interface{}) *gomock.Call {
+// Post indicates an expected call of Post.
+func (mr *MockAuthorMockRecorder) Post(ctx, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockAuthor)(nil).Post), ctx, author)
+}
+
+// Purge mocks base method.
+func (m *MockAuthor) Purge(ctx context.Context, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockAuthorMockRecorder) Purge(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockAuthor)(nil).Purge), ctx, before)
+}
+
+// Restore mocks base method.
+func (m *MockAuthor) Restore(ctx context.Context, id string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockAuthorMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockAuthor)(nil).Post), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockAuthor)(nil).Restore), ctx, id)
 }
 
-// Update mocks base method
-func (m *MockAuthor) Update(id string, author models.Author) (models.Author, error) {
+// Update mocks base method.
+func (m *MockAuthor) Update(ctx context.Context, id string, author models.Author) (models.Author, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", id, author)
+	ret := m.ctrl.Call(m, "Update", ctx, id, author)
 	ret0, _ := ret[0].(models.Author)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Update indicates an expected call of Update
-func (mr *MockAuthorMockRecorder) Update(id, author interface{}) *gomock.Call {
+// Update indicates an expected call of Update.
+func (mr *MockAuthorMockRecorder) Update(ctx, id, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuthor)(nil).Update), ctx, id, author)
+}
+
+// MockAudit is a mock of Audit interface.
+type MockAudit struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditMockRecorder
+}
+
+// MockAuditMockRecorder is the mock recorder for MockAudit.
+type MockAuditMockRecorder struct {
+	mock *MockAudit
+}
+
+// NewMockAudit creates a new mock instance.
+func NewMockAudit(ctrl *gomock.Controller) *MockAudit {
+	mock := &MockAudit{ctrl: ctrl}
+	mock.recorder = &MockAuditMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAudit) EXPECT() *MockAuditMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockAudit) Get(ctx context.Context, filter models.AuditFilter) ([]models.Audit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, filter)
+	ret0, _ := ret[0].([]models.Audit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAuditMockRecorder) Get(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAudit)(nil).Get), ctx, filter)
+}
+
+// MockRevision is a mock of Revision interface.
+type MockRevision struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevisionMockRecorder
+}
+
+// MockRevisionMockRecorder is the mock recorder for MockRevision.
+type MockRevisionMockRecorder struct {
+	mock *MockRevision
+}
+
+// NewMockRevision creates a new mock instance.
+func NewMockRevision(ctrl *gomock.Controller) *MockRevision {
+	mock := &MockRevision{ctrl: ctrl}
+	mock.recorder = &MockRevisionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevision) EXPECT() *MockRevisionMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockRevision) Get(ctx context.Context, entity string, id, rev int) (models.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, entity, id, rev)
+	ret0, _ := ret[0].(models.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRevisionMockRecorder) Get(ctx, entity, id, rev interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRevision)(nil).Get), ctx, entity, id, rev)
+}
+
+// GetAll mocks base method.
+func (m *MockRevision) GetAll(ctx context.Context, entity string, id int) ([]models.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, entity, id)
+	ret0, _ := ret[0].([]models.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockRevisionMockRecorder) GetAll(ctx, entity, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRevision)(nil).GetAll), ctx, entity, id)
+}
+
+// GetAsOf mocks base method.
+func (m *MockRevision) GetAsOf(ctx context.Context, entity string, id int, at time.Time) (models.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAsOf", ctx, entity, id, at)
+	ret0, _ := ret[0].(models.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAsOf indicates an expected call of GetAsOf.
+func (mr *MockRevisionMockRecorder) GetAsOf(ctx, entity, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAsOf", reflect.TypeOf((*MockRevision)(nil).GetAsOf), ctx, entity, id, at)
+}
+
+// MockAPIKey is a mock of APIKey interface.
+type MockAPIKey struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyMockRecorder
+}
+
+// MockAPIKeyMockRecorder is the mock recorder for MockAPIKey.
+type MockAPIKeyMockRecorder struct {
+	mock *MockAPIKey
+}
+
+// NewMockAPIKey creates a new mock instance.
+func NewMockAPIKey(ctrl *gomock.Controller) *MockAPIKey {
+	mock := &MockAPIKey{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKey) EXPECT() *MockAPIKeyMockRecorder {
+	return m.recorder
+}
+
+// GetByPrefix mocks base method.
+func (m *MockAPIKey) GetByPrefix(ctx context.Context, prefix string) (models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPrefix", ctx, prefix)
+	ret0, _ := ret[0].(models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPrefix indicates an expected call of GetByPrefix.
+func (mr *MockAPIKeyMockRecorder) GetByPrefix(ctx, prefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPrefix", reflect.TypeOf((*MockAPIKey)(nil).GetByPrefix), ctx, prefix)
+}
+
+// Post mocks base method.
+func (m *MockAPIKey) Post(ctx context.Context, key models.APIKey) (models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, key)
+	ret0, _ := ret[0].(models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockAPIKeyMockRecorder) Post(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockAPIKey)(nil).Post), ctx, key)
+}
+
+// Revoke mocks base method.
+func (m *MockAPIKey) Revoke(ctx context.Context, id string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAPIKeyMockRecorder) Revoke(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAPIKey)(nil).Revoke), ctx, id)
+}
+
+// MockRBAC is a mock of RBAC interface.
+type MockRBAC struct {
+	ctrl     *gomock.Controller
+	recorder *MockRBACMockRecorder
+}
+
+// MockRBACMockRecorder is the mock recorder for MockRBAC.
+type MockRBACMockRecorder struct {
+	mock *MockRBAC
+}
+
+// NewMockRBAC creates a new mock instance.
+func NewMockRBAC(ctrl *gomock.Controller) *MockRBAC {
+	mock := &MockRBAC{ctrl: ctrl}
+	mock.recorder = &MockRBACMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRBAC) EXPECT() *MockRBACMockRecorder {
+	return m.recorder
+}
+
+// Assign mocks base method.
+func (m *MockRBAC) Assign(ctx context.Context, principal, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Assign", ctx, principal, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Assign indicates an expected call of Assign.
+func (mr *MockRBACMockRecorder) Assign(ctx, principal, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Assign", reflect.TypeOf((*MockRBAC)(nil).Assign), ctx, principal, role)
+}
+
+// GetAssignments mocks base method.
+func (m *MockRBAC) GetAssignments(ctx context.Context, principal string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignments", ctx, principal)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignments indicates an expected call of GetAssignments.
+func (mr *MockRBACMockRecorder) GetAssignments(ctx, principal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignments", reflect.TypeOf((*MockRBAC)(nil).GetAssignments), ctx, principal)
+}
+
+// GetPermissions mocks base method.
+func (m *MockRBAC) GetPermissions(ctx context.Context, principal string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPermissions", ctx, principal)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPermissions indicates an expected call of GetPermissions.
+func (mr *MockRBACMockRecorder) GetPermissions(ctx, principal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissions", reflect.TypeOf((*MockRBAC)(nil).GetPermissions), ctx, principal)
+}
+
+// GetRoles mocks base method.
+func (m *MockRBAC) GetRoles(ctx context.Context) ([]models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoles", ctx)
+	ret0, _ := ret[0].([]models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoles indicates an expected call of GetRoles.
+func (mr *MockRBACMockRecorder) GetRoles(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuthor)(nil).Update), id, author)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoles", reflect.TypeOf((*MockRBAC)(nil).GetRoles), ctx)
 }
 
-// Delete mocks base method
-func (m *MockAuthor) Delete(id string) (int, error) {
+// Unassign mocks base method.
+func (m *MockRBAC) Unassign(ctx context.Context, principal, role string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Unassign", ctx, principal, role)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Delete indicates an expected call of Delete
-func (mr *MockAuthorMockRecorder) Delete(id interface{}) *gomock.Call {
+// Unassign indicates an expected call of Unassign.
+func (mr *MockRBACMockRecorder) Unassign(ctx, principal, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unassign", reflect.TypeOf((*MockRBAC)(nil).Unassign), ctx, principal, role)
+}
+
+// MockUser is a mock of User interface.
+type MockUser struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserMockRecorder
+}
+
+// MockUserMockRecorder is the mock recorder for MockUser.
+type MockUserMockRecorder struct {
+	mock *MockUser
+}
+
+// NewMockUser creates a new mock instance.
+func NewMockUser(ctrl *gomock.Controller) *MockUser {
+	mock := &MockUser{ctrl: ctrl}
+	mock.recorder = &MockUserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUser) EXPECT() *MockUserMockRecorder {
+	return m.recorder
+}
+
+// GetByUsername mocks base method.
+func (m *MockUser) GetByUsername(ctx context.Context, username string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUsername", ctx, username)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUsername indicates an expected call of GetByUsername.
+func (mr *MockUserMockRecorder) GetByUsername(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUsername", reflect.TypeOf((*MockUser)(nil).GetByUsername), ctx, username)
+}
+
+// Getbyid mocks base method.
+func (m *MockUser) Getbyid(ctx context.Context, id int) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Getbyid", ctx, id)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Getbyid indicates an expected call of Getbyid.
+func (mr *MockUserMockRecorder) Getbyid(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Getbyid", reflect.TypeOf((*MockUser)(nil).Getbyid), ctx, id)
+}
+
+// Lock mocks base method.
+func (m *MockUser) Lock(ctx context.Context, id int, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, id, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockUserMockRecorder) Lock(ctx, id, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockUser)(nil).Lock), ctx, id, until)
+}
+
+// Post mocks base method.
+func (m *MockUser) Post(ctx context.Context, user models.User) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, user)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockUserMockRecorder) Post(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockUser)(nil).Post), ctx, user)
+}
+
+// PostReset mocks base method.
+func (m *MockUser) PostReset(ctx context.Context, reset models.PasswordReset) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostReset", ctx, reset)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostReset indicates an expected call of PostReset.
+func (mr *MockUserMockRecorder) PostReset(ctx, reset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostReset", reflect.TypeOf((*MockUser)(nil).PostReset), ctx, reset)
+}
+
+// RecordFailedLogin mocks base method.
+func (m *MockUser) RecordFailedLogin(ctx context.Context, id int, now time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailedLogin", ctx, id, now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordFailedLogin indicates an expected call of RecordFailedLogin.
+func (mr *MockUserMockRecorder) RecordFailedLogin(ctx, id, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailedLogin", reflect.TypeOf((*MockUser)(nil).RecordFailedLogin), ctx, id, now)
+}
+
+// ResetFailedLogins mocks base method.
+func (m *MockUser) ResetFailedLogins(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetFailedLogins", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetFailedLogins indicates an expected call of ResetFailedLogins.
+func (mr *MockUserMockRecorder) ResetFailedLogins(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetFailedLogins", reflect.TypeOf((*MockUser)(nil).ResetFailedLogins), ctx, id)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUser) UpdatePassword(ctx context.Context, id int, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, id, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserMockRecorder) UpdatePassword(ctx, id, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUser)(nil).UpdatePassword), ctx, id, hash)
+}
+
+// UseReset mocks base method.
+func (m *MockUser) UseReset(ctx context.Context, tokenHash string, now time.Time) (models.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseReset", ctx, tokenHash, now)
+	ret0, _ := ret[0].(models.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseReset indicates an expected call of UseReset.
+func (mr *MockUserMockRecorder) UseReset(ctx, tokenHash, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseReset", reflect.TypeOf((*MockUser)(nil).UseReset), ctx, tokenHash, now)
+}
+
+// MockSession is a mock of Session interface.
+type MockSession struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionMockRecorder
+}
+
+// MockSessionMockRecorder is the mock recorder for MockSession.
+type MockSessionMockRecorder struct {
+	mock *MockSession
+}
+
+// NewMockSession creates a new mock instance.
+func NewMockSession(ctrl *gomock.Controller) *MockSession {
+	mock := &MockSession{ctrl: ctrl}
+	mock.recorder = &MockSessionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSession) EXPECT() *MockSessionMockRecorder {
+	return m.recorder
+}
+
+// GetActive mocks base method.
+func (m *MockSession) GetActive(ctx context.Context, tokenHash string, now time.Time) (models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActive", ctx, tokenHash, now)
+	ret0, _ := ret[0].(models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActive indicates an expected call of GetActive.
+func (mr *MockSessionMockRecorder) GetActive(ctx, tokenHash, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActive", reflect.TypeOf((*MockSession)(nil).GetActive), ctx, tokenHash, now)
+}
+
+// Post mocks base method.
+func (m *MockSession) Post(ctx context.Context, session models.Session) (models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, session)
+	ret0, _ := ret[0].(models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockSessionMockRecorder) Post(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockSession)(nil).Post), ctx, session)
+}
+
+// Revoke mocks base method.
+func (m *MockSession) Revoke(ctx context.Context, tokenHash string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, tokenHash)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockSessionMockRecorder) Revoke(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockSession)(nil).Revoke), ctx, tokenHash)
+}
+
+// RevokeAll mocks base method.
+func (m *MockSession) RevokeAll(ctx context.Context, userID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAll", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAll indicates an expected call of RevokeAll.
+func (mr *MockSessionMockRecorder) RevokeAll(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAll", reflect.TypeOf((*MockSession)(nil).RevokeAll), ctx, userID)
+}
+
+// MockImport is a mock of Import interface.
+type MockImport struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportMockRecorder
+}
+
+// MockImportMockRecorder is the mock recorder for MockImport.
+type MockImportMockRecorder struct {
+	mock *MockImport
+}
+
+// NewMockImport creates a new mock instance.
+func NewMockImport(ctrl *gomock.Controller) *MockImport {
+	mock := &MockImport{ctrl: ctrl}
+	mock.recorder = &MockImportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImport) EXPECT() *MockImportMockRecorder {
+	return m.recorder
+}
+
+// Commit mocks base method.
+func (m *MockImport) Commit(ctx context.Context, books []models.Book) ([]error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Commit", ctx, books)
+	ret0, _ := ret[0].([]error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Commit indicates an expected call of Commit.
+func (mr *MockImportMockRecorder) Commit(ctx, books interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockImport)(nil).Commit), ctx, books)
+}
+
+// MockExport is a mock of Export interface.
+type MockExport struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportMockRecorder
+}
+
+// MockExportMockRecorder is the mock recorder for MockExport.
+type MockExportMockRecorder struct {
+	mock *MockExport
+}
+
+// NewMockExport creates a new mock instance.
+func NewMockExport(ctrl *gomock.Controller) *MockExport {
+	mock := &MockExport{ctrl: ctrl}
+	mock.recorder = &MockExportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExport) EXPECT() *MockExportMockRecorder {
+	return m.recorder
+}
+
+// Stream mocks base method.
+func (m *MockExport) Stream(ctx context.Context, filter models.BookFilter, fn func(models.Book) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockExportMockRecorder) Stream(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockExport)(nil).Stream), ctx, filter, fn)
+}
+
+// MockOAI is a mock of OAI interface.
+type MockOAI struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAIMockRecorder
+}
+
+// MockOAIMockRecorder is the mock recorder for MockOAI.
+type MockOAIMockRecorder struct {
+	mock *MockOAI
+}
+
+// NewMockOAI creates a new mock instance.
+func NewMockOAI(ctrl *gomock.Controller) *MockOAI {
+	mock := &MockOAI{ctrl: ctrl}
+	mock.recorder = &MockOAIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAI) EXPECT() *MockOAIMockRecorder {
+	return m.recorder
+}
+
+// Earliest mocks base method.
+func (m *MockOAI) Earliest(ctx context.Context) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Earliest", ctx)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Earliest indicates an expected call of Earliest.
+func (mr *MockOAIMockRecorder) Earliest(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Earliest", reflect.TypeOf((*MockOAI)(nil).Earliest), ctx)
+}
+
+// Get mocks base method.
+func (m *MockOAI) Get(ctx context.Context, id int) (models.OAIRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(models.OAIRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockOAIMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockOAI)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockOAI) List(ctx context.Context, filter models.OAIFilter) ([]models.OAIRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]models.OAIRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockOAIMockRecorder) List(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOAI)(nil).List), ctx, filter)
+}
+
+// MockSRU is a mock of SRU interface.
+type MockSRU struct {
+	ctrl     *gomock.Controller
+	recorder *MockSRUMockRecorder
+}
+
+// MockSRUMockRecorder is the mock recorder for MockSRU.
+type MockSRUMockRecorder struct {
+	mock *MockSRU
+}
+
+// NewMockSRU creates a new mock instance.
+func NewMockSRU(ctrl *gomock.Controller) *MockSRU {
+	mock := &MockSRU{ctrl: ctrl}
+	mock.recorder = &MockSRUMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSRU) EXPECT() *MockSRUMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *MockSRU) Search(ctx context.Context, query *models.BookQuery, offset, limit int) ([]models.Book, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, offset, limit)
+	ret0, _ := ret[0].([]models.Book)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSRUMockRecorder) Search(ctx, query, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSRU)(nil).Search), ctx, query, offset, limit)
+}
+
+// MockGraphQL is a mock of GraphQL interface.
+type MockGraphQL struct {
+	ctrl     *gomock.Controller
+	recorder *MockGraphQLMockRecorder
+}
+
+// MockGraphQLMockRecorder is the mock recorder for MockGraphQL.
+type MockGraphQLMockRecorder struct {
+	mock *MockGraphQL
+}
+
+// NewMockGraphQL creates a new mock instance.
+func NewMockGraphQL(ctrl *gomock.Controller) *MockGraphQL {
+	mock := &MockGraphQL{ctrl: ctrl}
+	mock.recorder = &MockGraphQLMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGraphQL) EXPECT() *MockGraphQLMockRecorder {
+	return m.recorder
+}
+
+// Authors mocks base method.
+func (m *MockGraphQL) Authors(ctx context.Context, ids []int) ([]models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authors", ctx, ids)
+	ret0, _ := ret[0].([]models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authors indicates an expected call of Authors.
+func (mr *MockGraphQLMockRecorder) Authors(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authors", reflect.TypeOf((*MockGraphQL)(nil).Authors), ctx, ids)
+}
+
+// Books mocks base method.
+func (m *MockGraphQL) Books(ctx context.Context) ([]models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Books", ctx)
+	ret0, _ := ret[0].([]models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Books indicates an expected call of Books.
+func (mr *MockGraphQLMockRecorder) Books(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Books", reflect.TypeOf((*MockGraphQL)(nil).Books), ctx)
+}
+
+// BooksByAuthor mocks base method.
+func (m *MockGraphQL) BooksByAuthor(ctx context.Context, authorIDs []int) ([]models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BooksByAuthor", ctx, authorIDs)
+	ret0, _ := ret[0].([]models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BooksByAuthor indicates an expected call of BooksByAuthor.
+func (mr *MockGraphQLMockRecorder) BooksByAuthor(ctx, authorIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BooksByAuthor", reflect.TypeOf((*MockGraphQL)(nil).BooksByAuthor), ctx, authorIDs)
+}
+
+// BooksByID mocks base method.
+func (m *MockGraphQL) BooksByID(ctx context.Context, ids []int) ([]models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BooksByID", ctx, ids)
+	ret0, _ := ret[0].([]models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BooksByID indicates an expected call of BooksByID.
+func (mr *MockGraphQLMockRecorder) BooksByID(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BooksByID", reflect.TypeOf((*MockGraphQL)(nil).BooksByID), ctx, ids)
+}
+
+// MockLoan is a mock of Loan interface.
+type MockLoan struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoanMockRecorder
+}
+
+// MockLoanMockRecorder is the mock recorder for MockLoan.
+type MockLoanMockRecorder struct {
+	mock *MockLoan
+}
+
+// NewMockLoan creates a new mock instance.
+func NewMockLoan(ctrl *gomock.Controller) *MockLoan {
+	mock := &MockLoan{ctrl: ctrl}
+	mock.recorder = &MockLoanMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoan) EXPECT() *MockLoanMockRecorder {
+	return m.recorder
+}
+
+// GetActive mocks base method.
+func (m *MockLoan) GetActive(ctx context.Context, bookID int) (models.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActive", ctx, bookID)
+	ret0, _ := ret[0].(models.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActive indicates an expected call of GetActive.
+func (mr *MockLoanMockRecorder) GetActive(ctx, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActive", reflect.TypeOf((*MockLoan)(nil).GetActive), ctx, bookID)
+}
+
+// GetByUser mocks base method.
+func (m *MockLoan) GetByUser(ctx context.Context, userID int) ([]models.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", ctx, userID)
+	ret0, _ := ret[0].([]models.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockLoanMockRecorder) GetByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockLoan)(nil).GetByUser), ctx, userID)
+}
+
+// Post mocks base method.
+func (m *MockLoan) Post(ctx context.Context, loan models.Loan, maxLoans int) (models.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, loan, maxLoans)
+	ret0, _ := ret[0].(models.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockLoanMockRecorder) Post(ctx, loan, maxLoans interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockLoan)(nil).Post), ctx, loan, maxLoans)
+}
+
+// Renew mocks base method.
+func (m *MockLoan) Renew(ctx context.Context, loan models.Loan, maxRenewals int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", ctx, loan, maxRenewals)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MockLoanMockRecorder) Renew(ctx, loan, maxRenewals interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockLoan)(nil).Renew), ctx, loan, maxRenewals)
+}
+
+// Return mocks base method.
+func (m *MockLoan) Return(ctx context.Context, loan models.Loan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Return", ctx, loan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Return indicates an expected call of Return.
+func (mr *MockLoanMockRecorder) Return(ctx, loan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Return", reflect.TypeOf((*MockLoan)(nil).Return), ctx, loan)
+}
+
+// MockWebhook is a mock of Webhook interface.
+type MockWebhook struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookMockRecorder
+}
+
+// MockWebhookMockRecorder is the mock recorder for MockWebhook.
+type MockWebhookMockRecorder struct {
+	mock *MockWebhook
+}
+
+// NewMockWebhook creates a new mock instance.
+func NewMockWebhook(ctrl *gomock.Controller) *MockWebhook {
+	mock := &MockWebhook{ctrl: ctrl}
+	mock.recorder = &MockWebhookMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhook) EXPECT() *MockWebhookMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockWebhook) Claim(ctx context.Context, delivery models.WebhookDelivery, until time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, delivery, until)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockWebhookMockRecorder) Claim(ctx, delivery, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockWebhook)(nil).Claim), ctx, delivery, until)
+}
+
+// Delete mocks base method.
+func (m *MockWebhook) Delete(ctx context.Context, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhook)(nil).Delete), ctx, id)
+}
+
+// Due mocks base method.
+func (m *MockWebhook) Due(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Due", ctx, now, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Due indicates an expected call of Due.
+func (mr *MockWebhookMockRecorder) Due(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Due", reflect.TypeOf((*MockWebhook)(nil).Due), ctx, now, limit)
+}
+
+// Get mocks base method.
+func (m *MockWebhook) Get(ctx context.Context, id int) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWebhookMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWebhook)(nil).Get), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockWebhook) GetAll(ctx context.Context) ([]models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockWebhookMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWebhook)(nil).GetAll), ctx)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockWebhook) GetDeliveries(ctx context.Context, webhookID, limit int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, webhookID, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockWebhookMockRecorder) GetDeliveries(ctx, webhookID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockWebhook)(nil).GetDeliveries), ctx, webhookID, limit)
+}
+
+// GetDelivery mocks base method.
+func (m *MockWebhook) GetDelivery(ctx context.Context, id int) (models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelivery", ctx, id)
+	ret0, _ := ret[0].(models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDelivery indicates an expected call of GetDelivery.
+func (mr *MockWebhookMockRecorder) GetDelivery(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelivery", reflect.TypeOf((*MockWebhook)(nil).GetDelivery), ctx, id)
+}
+
+// Post mocks base method.
+func (m *MockWebhook) Post(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, webhook)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockWebhookMockRecorder) Post(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockWebhook)(nil).Post), ctx, webhook)
+}
+
+// PostDeliveries mocks base method.
+func (m *MockWebhook) PostDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostDeliveries", ctx, deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostDeliveries indicates an expected call of PostDeliveries.
+func (mr *MockWebhookMockRecorder) PostDeliveries(ctx, deliveries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostDeliveries", reflect.TypeOf((*MockWebhook)(nil).PostDeliveries), ctx, deliveries)
+}
+
+// Update mocks base method.
+func (m *MockWebhook) Update(ctx context.Context, webhook models.Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, webhook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWebhookMockRecorder) Update(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhook)(nil).Update), ctx, webhook)
+}
+
+// UpdateDelivery mocks base method.
+func (m *MockWebhook) UpdateDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockWebhookMockRecorder) UpdateDelivery(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockWebhook)(nil).UpdateDelivery), ctx, delivery)
+}
+
+// MockOutbox is a mock of Outbox interface.
+type MockOutbox struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxMockRecorder
+}
+
+// MockOutboxMockRecorder is the mock recorder for MockOutbox.
+type MockOutboxMockRecorder struct {
+	mock *MockOutbox
+}
+
+// NewMockOutbox creates a new mock instance.
+func NewMockOutbox(ctrl *gomock.Controller) *MockOutbox {
+	mock := &MockOutbox{ctrl: ctrl}
+	mock.recorder = &MockOutboxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutbox) EXPECT() *MockOutboxMockRecorder {
+	return m.recorder
+}
+
+// Advance mocks base method.
+func (m *MockOutbox) Advance(ctx context.Context, sink string, limit int, fn func([]models.OutboxEvent) (int64, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Advance", ctx, sink, limit, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Advance indicates an expected call of Advance.
+func (mr *MockOutboxMockRecorder) Advance(ctx, sink, limit, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Advance", reflect.TypeOf((*MockOutbox)(nil).Advance), ctx, sink, limit, fn)
+}
+
+// After mocks base method.
+func (m *MockOutbox) After(ctx context.Context, id int64, limit int) ([]models.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "After", ctx, id, limit)
+	ret0, _ := ret[0].([]models.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// After indicates an expected call of After.
+func (mr *MockOutboxMockRecorder) After(ctx, id, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "After", reflect.TypeOf((*MockOutbox)(nil).After), ctx, id, limit)
+}
+
+// Last mocks base method.
+func (m *MockOutbox) Last(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Last", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Last indicates an expected call of Last.
+func (mr *MockOutboxMockRecorder) Last(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Last", reflect.TypeOf((*MockOutbox)(nil).Last), ctx)
+}
+
+// Purge mocks base method.
+func (m *MockOutbox) Purge(ctx context.Context, sinks []string, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, sinks, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockOutboxMockRecorder) Purge(ctx, sinks, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockOutbox)(nil).Purge), ctx, sinks, before)
+}
+
+// MockHealth is a mock of Health interface.
+type MockHealth struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthMockRecorder
+}
+
+// MockHealthMockRecorder is the mock recorder for MockHealth.
+type MockHealthMockRecorder struct {
+	mock *MockHealth
+}
+
+// NewMockHealth creates a new mock instance.
+func NewMockHealth(ctrl *gomock.Controller) *MockHealth {
+	mock := &MockHealth{ctrl: ctrl}
+	mock.recorder = &MockHealthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealth) EXPECT() *MockHealthMockRecorder {
+	return m.recorder
+}
+
+// Ping mocks base method.
+func (m *MockHealth) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockHealthMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockHealth)(nil).Ping), ctx)
+}
+
+// MockCache is a mock of Cache interface.
+type MockCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheMockRecorder
+}
+
+// MockCacheMockRecorder is the mock recorder for MockCache.
+type MockCacheMockRecorder struct {
+	mock *MockCache
+}
+
+// NewMockCache creates a new mock instance.
+func NewMockCache(ctrl *gomock.Controller) *MockCache {
+	mock := &MockCache{ctrl: ctrl}
+	mock.recorder = &MockCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCache) EXPECT() *MockCacheMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockCache) Delete(ctx context.Context, keys ...string) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Delete", varargs...)
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCacheMockRecorder) Delete(ctx interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCache)(nil).Delete), varargs...)
+}
+
+// DeletePrefix mocks base method.
+func (m *MockCache) DeletePrefix(ctx context.Context, prefix string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeletePrefix", ctx, prefix)
+}
+
+// DeletePrefix indicates an expected call of DeletePrefix.
+func (mr *MockCacheMockRecorder) DeletePrefix(ctx, prefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePrefix", reflect.TypeOf((*MockCache)(nil).DeletePrefix), ctx, prefix)
+}
+
+// Get mocks base method.
+func (m *MockCache) Get(ctx context.Context, key string) ([]byte, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCacheMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCache)(nil).Get), ctx, key)
+}
+
+// Set mocks base method.
+func (m *MockCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Set", ctx, key, value, ttl)
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockCacheMockRecorder) Set(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockCache)(nil).Set), ctx, key, value, ttl)
+}
+
+// MockConsistency is a mock of Consistency interface.
+type MockConsistency struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsistencyMockRecorder
+}
+
+// MockConsistencyMockRecorder is the mock recorder for MockConsistency.
+type MockConsistencyMockRecorder struct {
+	mock *MockConsistency
+}
+
+// NewMockConsistency creates a new mock instance.
+func NewMockConsistency(ctrl *gomock.Controller) *MockConsistency {
+	mock := &MockConsistency{ctrl: ctrl}
+	mock.recorder = &MockConsistencyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsistency) EXPECT() *MockConsistencyMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockConsistency) Check(ctx context.Context) ([]models.Inconsistency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx)
+	ret0, _ := ret[0].([]models.Inconsistency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockConsistencyMockRecorder) Check(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuthor)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockConsistency)(nil).Check), ctx)
 }
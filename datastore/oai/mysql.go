@@ -0,0 +1,106 @@
+package oai
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"Three-Layer-Architecture/models"
+)
+
+// datestamp is the last change of a book or its author, updated_at columns are kept by the database
+const datestamp = "GREATEST(b.updated_at, a.updated_at)"
+
+const columns = "SELECT b.bookId,b.title,b.authorId,b.Publication,b.PublishedDate,a.firstName,a.lastName,a.dob,a.penName," +
+	datestamp + ",b.deleted_at IS NOT NULL FROM Book b JOIN Author a ON a.authorId=b.authorId"
+
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// Earliest method is to get the oldest datestamp, zero when there are no books
+func (d Datastore) Earliest(ctx context.Context) (time.Time, error) {
+	var earliest sql.NullTime
+
+	row := d.db.QueryRowContext(ctx, "SELECT MIN("+datestamp+") FROM Book b JOIN Author a ON a.authorId=b.authorId")
+	if err := row.Scan(&earliest); err != nil {
+		return time.Time{}, err
+	}
+
+	return earliest.Time, nil
+}
+
+// List method is to get books in id order, deleted ones included
+func (d Datastore) List(ctx context.Context, filter models.OAIFilter) ([]models.OAIRecord, error) {
+	var (
+		where []string
+		args  []any
+	)
+
+	if !filter.From.IsZero() {
+		where = append(where, datestamp+">=?")
+		args = append(args, filter.From)
+	}
+
+	if !filter.Before.IsZero() {
+		where = append(where, datestamp+"<?")
+		args = append(args, filter.Before)
+	}
+
+	if filter.After != 0 {
+		where = append(where, "b.bookId>?")
+		args = append(args, filter.After)
+	}
+
+	query := columns
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " and ")
+	}
+
+	rows, err := d.db.QueryContext(ctx, query+" ORDER BY b.bookId LIMIT ?", append(args, filter.Limit)...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var records []models.OAIRecord
+
+	for rows.Next() {
+		rec, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, rec)
+	}
+
+	return records, rows.Err()
+}
+
+// Get method is to get a book by id, deleted or not
+func (d Datastore) Get(ctx context.Context, id int) (models.OAIRecord, error) {
+	return scan(d.db.QueryRowContext(ctx, columns+" WHERE b.bookId=?", id))
+}
+
+func scan(row interface{ Scan(dest ...any) error }) (models.OAIRecord, error) {
+	var rec models.OAIRecord
+
+	b := &rec.Book
+
+	err := row.Scan(&b.BookID, &b.Title, &b.AuthorID, &b.Publication, &b.PublishedDate, &b.Auth.FirstName, &b.Auth.LastName,
+		&b.Auth.Dob, &b.Auth.PenName, &rec.Datestamp, &rec.Deleted)
+	if err != nil {
+		return models.OAIRecord{}, err
+	}
+
+	b.Auth.AuthID = b.AuthorID
+	rec.Datestamp = rec.Datestamp.UTC()
+
+	return rec, nil
+}
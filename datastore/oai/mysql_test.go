@@ -0,0 +1,100 @@
+package oai
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"Three-Layer-Architecture/models"
+)
+
+var rowColumns = []string{"bookId", "title", "authorId", "Publication", "PublishedDate", "firstName", "lastName", "dob",
+	"penName", "datestamp", "deleted"}
+
+// Test_List function is to test datestamp and paging filters
+func Test_List(t *testing.T) {
+	stamp := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	book := models.Book{BookID: 2, AuthorID: 1,
+		Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
+		Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}
+
+	testcases := []struct {
+		desc   string
+		filter models.OAIFilter
+		query  string
+		args   []driver.Value
+		resp   []models.OAIRecord
+		err    error
+	}{
+		{desc: "first page", filter: models.OAIFilter{Limit: 11}, query: columns + " ORDER BY b.bookId LIMIT ?",
+			args: []driver.Value{11}, resp: []models.OAIRecord{{Book: book, Datestamp: stamp, Deleted: true}}},
+		{desc: "every filter", filter: models.OAIFilter{From: from, Before: before, After: 1, Limit: 11},
+			query: columns + " WHERE " + datestamp + ">=? and " + datestamp + "<? and b.bookId>? ORDER BY b.bookId LIMIT ?",
+			args:  []driver.Value{from, before, 1, 11}, resp: []models.OAIRecord{{Book: book, Datestamp: stamp, Deleted: true}}},
+		{desc: "error", filter: models.OAIFilter{Limit: 11}, query: columns + " ORDER BY b.bookId LIMIT ?",
+			args: []driver.Value{11}, err: errors.New("connection refused")},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		exp := mock.ExpectQuery(v.query).WithArgs(v.args...)
+		if v.err != nil {
+			exp.WillReturnError(v.err)
+		} else {
+			exp.WillReturnRows(sqlmock.NewRows(rowColumns).AddRow(book.BookID, book.Title, book.AuthorID, book.Publication,
+				book.PublishedDate, book.Auth.FirstName, book.Auth.LastName, book.Auth.Dob, book.Auth.PenName, stamp, true))
+		}
+
+		resp, err := New(db).List(context.Background(), v.filter)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Test_GetAndEarliest function is to test reading one record and the oldest datestamp
+func Test_GetAndEarliest(t *testing.T) {
+	stamp := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery(columns + " WHERE b.bookId=?").WithArgs(1).WillReturnRows(sqlmock.NewRows(rowColumns).
+		AddRow(1, "2 States", 1, "Scholastic", "16/03/2016", "Chetan", "Bhagat", "06/04/2001", "Chetan", stamp, false))
+
+	rec, err := New(db).Get(context.Background(), 1)
+	if err != nil || rec.Book.Auth.AuthID != 1 || !rec.Datestamp.Equal(stamp) || rec.Deleted {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "get", 1, rec, err, stamp)
+	}
+
+	mock.ExpectQuery("SELECT MIN(" + datestamp + ") FROM Book b JOIN Author a ON a.authorId=b.authorId").
+		WillReturnRows(sqlmock.NewRows([]string{"earliest"}).AddRow(stamp))
+
+	earliest, err := New(db).Earliest(context.Background())
+	if err != nil || !earliest.Equal(stamp) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "earliest", 2, earliest, stamp)
+	}
+}
@@ -0,0 +1,175 @@
+// Package outbox stores change events in the transaction of the change and reads them back for the dispatcher
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"Three-Layer-Architecture/models"
+)
+
+const columns = "SELECT id,event,entity,entityId,payload,createdAt FROM Outbox"
+
+// Record stores the event of a change in tx, so the event is kept exactly when the change is
+func Record(ctx context.Context, tx *sql.Tx, event, entity string, entityID int, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO Outbox(event,entity,entityId,payload,createdAt) VALUES (?,?,?,?,NOW())",
+		event, entity, entityID, payload)
+
+	return err
+}
+
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// Advance method is to give fn the events sink has not sent, oldest first, and mark the ones up to the id fn returns
+// as sent. Ids are given out when events are stored but seen when they are committed, so an event may show up after
+// later ones and each one is marked instead of keeping a position. A new sink starts after the latest event.
+// The cursor row is locked until fn returns, a replica which finds it locked skips the sink, so every event is sent
+// by one replica at a time. The error of fn is returned after the sent events are marked.
+func (d Datastore) Advance(ctx context.Context, sink string, limit int,
+	fn func(events []models.OutboxEvent) (int64, error)) error {
+	_, err := d.db.ExecContext(ctx, "INSERT IGNORE INTO OutboxCursor(sink,lastId) SELECT ?, COALESCE(MAX(id),0) FROM Outbox",
+		sink)
+	if err != nil {
+		return err
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// rollback after commit is a no-op
+	defer func() { _ = tx.Rollback() }()
+
+	var start int64
+
+	err = tx.QueryRowContext(ctx, "SELECT lastId FROM OutboxCursor WHERE sink=? FOR UPDATE SKIP LOCKED", sink).Scan(&start)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	events, err := query(ctx, tx, columns+" o WHERE o.id>? and NOT EXISTS (SELECT 1 FROM OutboxSent s "+
+		"WHERE s.sink=? and s.outboxId=o.id) ORDER BY o.id LIMIT ?", start, sink, limit)
+	if err != nil || len(events) == 0 {
+		return err
+	}
+
+	last, sendErr := fn(events)
+
+	var args []any
+
+	for _, event := range events {
+		if event.ID <= last {
+			args = append(args, sink, event.ID)
+		}
+	}
+
+	if len(args) > 0 {
+		_, err = tx.ExecContext(ctx, "INSERT INTO OutboxSent(sink,outboxId) VALUES (?,?)"+
+			strings.Repeat(",(?,?)", len(args)/2-1), args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return sendErr
+}
+
+// After method is to get events after id, oldest first
+func (d Datastore) After(ctx context.Context, id int64, limit int) ([]models.OutboxEvent, error) {
+	return query(ctx, d.db, columns+" WHERE id>? ORDER BY id LIMIT ?", id, limit)
+}
+
+// Last method is to get the id of the latest event, 0 when there is none
+func (d Datastore) Last(ctx context.Context) (int64, error) {
+	var last int64
+
+	err := d.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(id),0) FROM Outbox").Scan(&last)
+
+	return last, err
+}
+
+// Purge method is to remove events created before given time which every sink has sent
+func (d Datastore) Purge(ctx context.Context, sinks []string, before time.Time) (int, error) {
+	query := "DELETE FROM Outbox WHERE createdAt<?"
+	args := []any{before}
+
+	// marks of removed events go with them
+	if len(sinks) > 0 {
+		query += " and NOT EXISTS (SELECT 1 FROM OutboxCursor c WHERE c.sink IN (?" + strings.Repeat(",?", len(sinks)-1) +
+			") and Outbox.id>c.lastId and NOT EXISTS (SELECT 1 FROM OutboxSent s WHERE s.sink=c.sink and " +
+			"s.outboxId=Outbox.id))"
+
+		for _, sink := range sinks {
+			args = append(args, sink)
+		}
+	}
+
+	res, err := d.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	rowAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowAffected), nil
+}
+
+// queryer is a database or a transaction
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func query(ctx context.Context, db queryer, query string, args ...any) ([]models.OutboxEvent, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+
+	for rows.Next() {
+		var (
+			event   models.OutboxEvent
+			payload []byte
+		)
+
+		err = rows.Scan(&event.ID, &event.Event, &event.Entity, &event.EntityID, &payload, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		event.Data = payload
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
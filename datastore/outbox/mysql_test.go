@@ -0,0 +1,192 @@
+package outbox
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"Three-Layer-Architecture/models"
+)
+
+var eventColumns = []string{"id", "event", "entity", "entityId", "payload", "createdAt"}
+
+const (
+	cursorInsert = "INSERT IGNORE INTO OutboxCursor(sink,lastId) SELECT ?, COALESCE(MAX(id),0) FROM Outbox"
+	cursorLock   = "SELECT lastId FROM OutboxCursor WHERE sink=? FOR UPDATE SKIP LOCKED"
+	unsent       = columns + " o WHERE o.id>? and NOT EXISTS (SELECT 1 FROM OutboxSent s WHERE s.sink=? and " +
+		"s.outboxId=o.id) ORDER BY o.id LIMIT ?"
+	sentInsert = "INSERT INTO OutboxSent(sink,outboxId) VALUES (?,?)"
+)
+
+// Test_Advance function is to test the events a sink sent are marked and the others are left for next time
+func Test_Advance(t *testing.T) {
+	at := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	lost := errors.New("connection refused")
+	events := []models.OutboxEvent{
+		{ID: 4, Event: models.EventBookCreated, Entity: models.EntityBook, EntityID: 1, Data: []byte(`{}`), CreatedAt: at},
+		{ID: 6, Event: models.EventBookUpdated, Entity: models.EntityBook, EntityID: 1, Data: []byte(`{}`), CreatedAt: at},
+	}
+
+	testcases := []struct {
+		desc   string
+		sent   int64
+		marked []driver.Value
+		sendTo error
+		err    error
+	}{
+		{desc: "all sent", sent: 6, marked: []driver.Value{"webhook", 4, "webhook", 6}},
+		{desc: "second failed", sent: 4, marked: []driver.Value{"webhook", 4}, sendTo: lost, err: lost},
+		{desc: "first failed", sent: 3, sendTo: lost, err: lost},
+	}
+
+	for i, v := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		mock.ExpectExec(cursorInsert).WithArgs("webhook").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectBegin()
+		mock.ExpectQuery(cursorLock).WithArgs("webhook").WillReturnRows(sqlmock.NewRows([]string{"lastId"}).AddRow(3))
+		mock.ExpectQuery(unsent).WithArgs(3, "webhook", 10).WillReturnRows(
+			sqlmock.NewRows(eventColumns).
+				AddRow(4, models.EventBookCreated, models.EntityBook, 1, []byte(`{}`), at).
+				AddRow(6, models.EventBookUpdated, models.EntityBook, 1, []byte(`{}`), at))
+
+		// nothing is marked when nothing was sent
+		if len(v.marked) > 0 {
+			mock.ExpectExec(sentInsert + strings.Repeat(",(?,?)", len(v.marked)/2-1)).WithArgs(v.marked...).
+				WillReturnResult(sqlmock.NewResult(0, int64(len(v.marked)/2)))
+		}
+
+		mock.ExpectCommit()
+
+		var got []models.OutboxEvent
+
+		err = New(db).Advance(context.Background(), "webhook", 10, func(e []models.OutboxEvent) (int64, error) {
+			got = e
+
+			return v.sent, v.sendTo
+		})
+
+		if !errors.Is(err, v.err) || !reflect.DeepEqual(got, events) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", v.desc, i+1, got, err, events, v.err)
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, nil)
+		}
+
+		db.Close()
+	}
+}
+
+// Test_AdvanceLateCommit function is to test an event committed after a later one is still given to the sink
+func Test_AdvanceLateCommit(t *testing.T) {
+	at := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	// 6 was sent while the transaction of 5 was open, the start of the sink stays at 3
+	mock.ExpectExec(cursorInsert).WithArgs("webhook").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectQuery(cursorLock).WithArgs("webhook").WillReturnRows(sqlmock.NewRows([]string{"lastId"}).AddRow(3))
+	mock.ExpectQuery(unsent).WithArgs(3, "webhook", 10).WillReturnRows(
+		sqlmock.NewRows(eventColumns).AddRow(5, models.EventBookCreated, models.EntityBook, 2, []byte(`{}`), at))
+	mock.ExpectExec(sentInsert).WithArgs("webhook", 5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var got []int64
+
+	err = New(db).Advance(context.Background(), "webhook", 10, func(e []models.OutboxEvent) (int64, error) {
+		for _, event := range e {
+			got = append(got, event.ID)
+		}
+
+		return e[len(e)-1].ID, nil
+	})
+
+	if err != nil || !reflect.DeepEqual(got, []int64{5}) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "late commit", 1, got, err, []int64{5})
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "late commit", 2, err, nil)
+	}
+}
+
+// Test_AdvanceLocked function is to test a sink locked by another replica is skipped
+func Test_AdvanceLocked(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectExec(cursorInsert).WithArgs("webhook").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectQuery(cursorLock).WithArgs("webhook").WillReturnRows(sqlmock.NewRows([]string{"lastId"}))
+	mock.ExpectRollback()
+
+	called := false
+
+	err = New(db).Advance(context.Background(), "webhook", 10, func([]models.OutboxEvent) (int64, error) {
+		called = true
+
+		return 0, nil
+	})
+
+	if err != nil || called {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", "locked", 1, err, called, nil, false)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "locked", 2, err, nil)
+	}
+}
+
+// Test_Purge function is to test events are kept until every sink sent them
+func Test_Purge(t *testing.T) {
+	before := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc  string
+		sinks []string
+		query string
+		args  []driver.Value
+	}{
+		{desc: "two sinks", sinks: []string{"webhook", "file:/var/log/events"},
+			query: "DELETE FROM Outbox WHERE createdAt<? and NOT EXISTS (SELECT 1 FROM OutboxCursor c WHERE c.sink IN " +
+				"(?,?) and Outbox.id>c.lastId and NOT EXISTS (SELECT 1 FROM OutboxSent s WHERE s.sink=c.sink and " +
+				"s.outboxId=Outbox.id))", args: []driver.Value{before, "webhook", "file:/var/log/events"}},
+		{desc: "no sink", query: "DELETE FROM Outbox WHERE createdAt<?", args: []driver.Value{before}},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectExec(v.query).WithArgs(v.args...).WillReturnResult(sqlmock.NewResult(0, 2))
+
+		if resp, err := New(db).Purge(context.Background(), v.sinks, before); resp != 2 || err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", v.desc, i+1, resp, err, 2)
+		}
+	}
+}
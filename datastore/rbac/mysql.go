@@ -0,0 +1,132 @@
+package rbac
+
+import (
+	"context"
+	"database/sql"
+
+	"Three-Layer-Architecture/models"
+)
+
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// GetPermissions method is to get every permission a principal has through its roles
+func (d Datastore) GetPermissions(ctx context.Context, principal string) ([]string, error) {
+	rows, err := d.db.QueryContext(ctx, "SELECT DISTINCT p.permission FROM RoleAssignment a "+
+		"JOIN RolePermission p ON p.role=a.role WHERE a.principal=?", principal)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var permissions []string
+
+	for rows.Next() {
+		var permission string
+
+		if err = rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+
+		permissions = append(permissions, permission)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// GetRoles method is to get all roles with their permissions
+func (d Datastore) GetRoles(ctx context.Context) ([]models.Role, error) {
+	rows, err := d.db.QueryContext(ctx, "SELECT r.name,p.permission FROM Role r LEFT JOIN RolePermission p ON p.role=r.name ORDER BY r.name,p.permission")
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var roles []models.Role
+
+	for rows.Next() {
+		var (
+			name       string
+			permission sql.NullString
+		)
+
+		if err = rows.Scan(&name, &permission); err != nil {
+			return nil, err
+		}
+
+		if len(roles) == 0 || roles[len(roles)-1].Name != name {
+			roles = append(roles, models.Role{Name: name, Permissions: []string{}})
+		}
+
+		if permission.Valid {
+			roles[len(roles)-1].Permissions = append(roles[len(roles)-1].Permissions, permission.String)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// GetAssignments method is to get role names assigned to a principal
+func (d Datastore) GetAssignments(ctx context.Context, principal string) ([]string, error) {
+	rows, err := d.db.QueryContext(ctx, "SELECT role FROM RoleAssignment WHERE principal=? ORDER BY role", principal)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	roles := []string{}
+
+	for rows.Next() {
+		var role string
+
+		if err = rows.Scan(&role); err != nil {
+			return nil, err
+		}
+
+		roles = append(roles, role)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// Assign method is to give a role to a principal, assigning twice is not an error
+func (d Datastore) Assign(ctx context.Context, principal, role string) error {
+	_, err := d.db.ExecContext(ctx, "INSERT IGNORE INTO RoleAssignment(principal,role) values (?,?)", principal, role)
+
+	return err
+}
+
+// Unassign method is to take a role away from a principal
+func (d Datastore) Unassign(ctx context.Context, principal, role string) (int, error) {
+	res, err := d.db.ExecContext(ctx, "DELETE FROM RoleAssignment WHERE principal=? and role=?", principal, role)
+	if err != nil {
+		return 0, err
+	}
+
+	rowAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowAffected), nil
+}
@@ -0,0 +1,59 @@
+package rbac
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"Three-Layer-Architecture/models"
+)
+
+// Test_GetPermissions of a principal
+func Test_GetPermissions(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT DISTINCT p.permission FROM RoleAssignment a JOIN RolePermission p ON p.role=a.role WHERE a.principal=?").
+		WithArgs("u-1").WillReturnRows(sqlmock.NewRows([]string{"permission"}).AddRow("book:read").AddRow("author:read"))
+
+	d := New(db)
+
+	resp, err := d.GetPermissions(context.Background(), "u-1")
+	if !reflect.DeepEqual(resp, []string{"book:read", "author:read"}) || err != nil {
+		t.Errorf("[TEST1]Failed. Got %v, %v\tExpected [book:read author:read], <nil>\n", resp, err)
+	}
+}
+
+// Test_GetRoles groups permissions by role
+func Test_GetRoles(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT r.name,p.permission FROM Role r LEFT JOIN RolePermission p ON p.role=r.name ORDER BY r.name,p.permission").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "permission"}).
+			AddRow("admin", "*").AddRow("empty", nil).AddRow("patron", "author:read").AddRow("patron", "book:read"))
+
+	expected := []models.Role{
+		{Name: "admin", Permissions: []string{"*"}},
+		{Name: "empty", Permissions: []string{}},
+		{Name: "patron", Permissions: []string{"author:read", "book:read"}},
+	}
+
+	d := New(db)
+
+	resp, err := d.GetRoles(context.Background())
+	if !reflect.DeepEqual(resp, expected) || err != nil {
+		t.Errorf("[TEST1]Failed. Got %v, %v\tExpected %v, <nil>\n", resp, err, expected)
+	}
+}
@@ -0,0 +1,105 @@
+package revision
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/principal"
+)
+
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// Record stores snapshot as the next revision of a record changed by the caller in ctx, in the transaction of the
+// change so the revision is kept exactly when the change is
+func Record(ctx context.Context, tx *sql.Tx, operation, entity string, entityID int, snapshot any) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	var rev int
+
+	// locking latest revision of the record so concurrent writers get distinct numbers
+	row := tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(rev),0) FROM Revision WHERE entity=? and entityId=? FOR UPDATE",
+		entity, entityID)
+	if err = row.Scan(&rev); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "insert into Revision(entity,entityId,rev,operation,actor,createdAt,data) values (?,?,?,?,?,?,?)",
+		entity, entityID, rev+1, operation, principal.FromContext(ctx).ID, time.Now().UTC(), string(data))
+
+	return err
+}
+
+// GetAll method is to get every revision of a record, oldest first
+func (d Datastore) GetAll(ctx context.Context, entity string, id int) ([]models.Revision, error) {
+	rows, err := d.db.QueryContext(ctx, "SELECT entity,entityId,rev,operation,actor,createdAt,data FROM Revision "+
+		"WHERE entity=? and entityId=? ORDER BY rev", entity, id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var revisions []models.Revision
+
+	for rows.Next() {
+		rev, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, rev)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// Get method is to get one revision of a record
+func (d Datastore) Get(ctx context.Context, entity string, id, rev int) (models.Revision, error) {
+	row := d.db.QueryRowContext(ctx, "SELECT entity,entityId,rev,operation,actor,createdAt,data FROM Revision "+
+		"WHERE entity=? and entityId=? and rev=?", entity, id, rev)
+
+	return scan(row)
+}
+
+// GetAsOf method is to get the revision of a record which was current at given time
+func (d Datastore) GetAsOf(ctx context.Context, entity string, id int, at time.Time) (models.Revision, error) {
+	row := d.db.QueryRowContext(ctx, "SELECT entity,entityId,rev,operation,actor,createdAt,data FROM Revision "+
+		"WHERE entity=? and entityId=? and createdAt<=? ORDER BY rev DESC LIMIT 1", entity, id, at)
+
+	return scan(row)
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scan(row scanner) (models.Revision, error) {
+	var (
+		rev  models.Revision
+		data []byte
+	)
+
+	if err := row.Scan(&rev.Entity, &rev.EntityID, &rev.Rev, &rev.Operation, &rev.Actor, &rev.CreatedAt, &data); err != nil {
+		return models.Revision{}, err
+	}
+
+	rev.Data = data
+
+	return rev, nil
+}
@@ -0,0 +1,139 @@
+package revision
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/principal"
+)
+
+var columns = []string{"entity", "entityId", "rev", "operation", "actor", "createdAt", "data"}
+
+// Test_Record revision gets next number in the transaction of the change
+func Test_Record(t *testing.T) {
+	ctx := principal.NewContext(context.Background(), models.Principal{ID: "user:cataloguer-1"})
+
+	testcases := []struct {
+		desc    string
+		latest  int
+		execErr error
+		err     error
+	}{
+		{desc: "first revision", latest: 0},
+		{desc: "next revision", latest: 4},
+		{desc: "insert error", latest: 4, execErr: errors.New("connection refused"), err: errors.New("connection refused")},
+	}
+
+	for i, v := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT COALESCE(MAX(rev),0) FROM Revision WHERE entity=? and entityId=? FOR UPDATE").
+			WithArgs("book", 1).WillReturnRows(sqlmock.NewRows([]string{"rev"}).AddRow(v.latest))
+
+		exec := mock.ExpectExec("insert into Revision(entity,entityId,rev,operation,actor,createdAt,data) values (?,?,?,?,?,?,?)").
+			WithArgs("book", 1, v.latest+1, "update", "user:cataloguer-1", sqlmock.AnyArg(), `{"title":"Journey"}`)
+
+		if v.execErr != nil {
+			exec.WillReturnError(v.execErr)
+		} else {
+			exec.WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+
+		tx, _ := db.Begin()
+
+		err = Record(ctx, tx, "update", "book", 1, map[string]string{"title": "Journey"})
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, nil)
+		}
+
+		db.Close()
+	}
+}
+
+// Test_GetAll revisions of a record
+func Test_GetAll(t *testing.T) {
+	at := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT entity,entityId,rev,operation,actor,createdAt,data FROM Revision WHERE entity=? and entityId=? ORDER BY rev").
+		WithArgs("book", 1).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow("book", 1, 1, "create", "anonymous", at, []byte(`{"title":"a"}`)).
+		AddRow("book", 1, 2, "update", "anonymous", at, []byte(`{"title":"b"}`)))
+
+	expected := []models.Revision{
+		{Entity: "book", EntityID: 1, Rev: 1, Operation: "create", Actor: "anonymous", CreatedAt: at, Data: json.RawMessage(`{"title":"a"}`)},
+		{Entity: "book", EntityID: 1, Rev: 2, Operation: "update", Actor: "anonymous", CreatedAt: at, Data: json.RawMessage(`{"title":"b"}`)},
+	}
+
+	d := New(db)
+
+	resp, err := d.GetAll(context.Background(), "book", 1)
+	if !reflect.DeepEqual(resp, expected) || err != nil {
+		t.Errorf("[TEST1]Failed. Got %v, %v\tExpected %v, <nil>\n", resp, err, expected)
+	}
+}
+
+// Test_GetAsOf revision current at a time
+func Test_GetAsOf(t *testing.T) {
+	at := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc string
+		rows *sqlmock.Rows
+		resp models.Revision
+		err  error
+	}{
+		{desc: "found", rows: sqlmock.NewRows(columns).AddRow("book", 1, 3, "update", "anonymous", at, []byte(`{}`)),
+			resp: models.Revision{Entity: "book", EntityID: 1, Rev: 3, Operation: "update", Actor: "anonymous",
+				CreatedAt: at, Data: json.RawMessage(`{}`)}},
+		{desc: "did not exist yet", rows: sqlmock.NewRows(columns), err: sql.ErrNoRows},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectQuery("SELECT entity,entityId,rev,operation,actor,createdAt,data FROM Revision "+
+			"WHERE entity=? and entityId=? and createdAt<=? ORDER BY rev DESC LIMIT 1").
+			WithArgs("book", 1, at).WillReturnRows(v.rows)
+
+		d := New(db)
+
+		resp, err := d.GetAsOf(context.Background(), "book", 1, at)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
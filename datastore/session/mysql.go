@@ -0,0 +1,79 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"Three-Layer-Architecture/models"
+)
+
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// Post method is to store a new session
+func (d Datastore) Post(ctx context.Context, session models.Session) (models.Session, error) {
+	res, err := d.db.ExecContext(ctx, "insert into Session(tokenHash,userId,createdAt,expiresAt) values (?,?,?,?)",
+		session.TokenHash, session.UserID, session.CreatedAt, session.ExpiresAt)
+	if err != nil {
+		return models.Session{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.Session{}, err
+	}
+
+	session.ID = int(id)
+
+	return session, nil
+}
+
+// GetActive method is to get a session by token hash which is neither revoked nor expired
+func (d Datastore) GetActive(ctx context.Context, tokenHash string, now time.Time) (models.Session, error) {
+	row := d.db.QueryRowContext(ctx, "SELECT id,tokenHash,userId,createdAt,expiresAt FROM Session "+
+		"WHERE tokenHash=? and revokedAt IS NULL and expiresAt>?", tokenHash, now)
+
+	var session models.Session
+
+	if err := row.Scan(&session.ID, &session.TokenHash, &session.UserID, &session.CreatedAt, &session.ExpiresAt); err != nil {
+		return models.Session{}, err
+	}
+
+	return session, nil
+}
+
+// Revoke method is to end one session
+func (d Datastore) Revoke(ctx context.Context, tokenHash string) (int, error) {
+	res, err := d.db.ExecContext(ctx, "UPDATE Session SET revokedAt=NOW() WHERE tokenHash=? and revokedAt IS NULL", tokenHash)
+	if err != nil {
+		return 0, err
+	}
+
+	rowAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowAffected), nil
+}
+
+// RevokeAll method is to end every session of a user
+func (d Datastore) RevokeAll(ctx context.Context, userID int) (int, error) {
+	res, err := d.db.ExecContext(ctx, "UPDATE Session SET revokedAt=NOW() WHERE userId=? and revokedAt IS NULL", userID)
+	if err != nil {
+		return 0, err
+	}
+
+	rowAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowAffected), nil
+}
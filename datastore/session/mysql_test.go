@@ -0,0 +1,76 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"Three-Layer-Architecture/models"
+)
+
+// Test_GetActive session
+func Test_GetActive(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	columns := []string{"id", "tokenHash", "userId", "createdAt", "expiresAt"}
+
+	testcases := []struct {
+		desc string
+		rows *sqlmock.Rows
+		resp models.Session
+		err  error
+	}{
+		{desc: "active", rows: sqlmock.NewRows(columns).AddRow(1, "f00d", 3, now, now.Add(time.Hour)),
+			resp: models.Session{ID: 1, TokenHash: "f00d", UserID: 3, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}},
+		{desc: "revoked or expired", rows: sqlmock.NewRows(columns), err: sql.ErrNoRows},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectQuery("SELECT id,tokenHash,userId,createdAt,expiresAt FROM Session "+
+			"WHERE tokenHash=? and revokedAt IS NULL and expiresAt>?").WithArgs("f00d", now).WillReturnRows(v.rows)
+
+		d := New(db)
+
+		resp, err := d.GetActive(context.Background(), "f00d", now)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Test_RevokeAll sessions of a user
+func Test_RevokeAll(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE Session SET revokedAt=NOW() WHERE userId=? and revokedAt IS NULL").WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	d := New(db)
+
+	resp, err := d.RevokeAll(context.Background(), 3)
+	if resp != 2 || err != nil {
+		t.Errorf("[TEST1]Failed. Got %v, %v\tExpected 2, <nil>\n", resp, err)
+	}
+}
@@ -0,0 +1,154 @@
+package sru
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"Three-Layer-Architecture/models"
+)
+
+const (
+	from    = " FROM Book b JOIN Author a ON a.authorId=b.authorId WHERE b.deleted_at IS NULL"
+	columns = "SELECT b.bookId,b.title,b.authorId,b.Publication,b.PublishedDate,b.isbn,a.firstName,a.lastName,a.dob,a.penName" +
+		from
+
+	// published is the publishing date, it is stored as dd/mm/yyyy
+	published = "STR_TO_DATE(b.PublishedDate,'%d/%m/%Y')"
+)
+
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// Search method is to get the count of matching books and the page of them after offset, in id order.
+// A limit of 0 only counts.
+func (d Datastore) Search(ctx context.Context, query *models.BookQuery, offset, limit int) ([]models.Book, int, error) {
+	where, args, err := condition(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if where != "" {
+		where = " and " + where
+	}
+
+	var total int
+
+	if err = d.db.QueryRowContext(ctx, "SELECT COUNT(*)"+from+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	if limit == 0 || offset >= total {
+		return nil, total, nil
+	}
+
+	rows, err := d.db.QueryContext(ctx, columns+where+" ORDER BY b.bookId LIMIT ? OFFSET ?",
+		append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+
+	var books []models.Book
+
+	for rows.Next() {
+		var b models.Book
+
+		err = rows.Scan(&b.BookID, &b.Title, &b.AuthorID, &b.Publication, &b.PublishedDate, &b.ISBN, &b.Auth.FirstName,
+			&b.Auth.LastName, &b.Auth.Dob, &b.Auth.PenName)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		b.Auth.AuthID = b.AuthorID
+		books = append(books, b)
+	}
+
+	return books, total, rows.Err()
+}
+
+// condition returns the WHERE clause of a query with its arguments, nil queries match every book
+func condition(q *models.BookQuery) (string, []any, error) {
+	if q == nil {
+		return "", nil, nil
+	}
+
+	if q.Op != "" {
+		return join(q)
+	}
+
+	switch q.Field {
+	case models.SearchAll:
+		return "TRUE", nil, nil
+	case models.SearchTitle, models.SearchPublisher, models.SearchAuthor:
+		if q.Relation != models.RelationLike {
+			return "", nil, fmt.Errorf("relation %v is not supported for %v", q.Relation, q.Field)
+		}
+
+		switch q.Field {
+		case models.SearchTitle:
+			return "b.title LIKE ?", []any{q.Term}, nil
+		case models.SearchPublisher:
+			return "b.Publication LIKE ?", []any{q.Term}, nil
+		default:
+			return "(a.firstName LIKE ? OR a.lastName LIKE ? OR a.penName LIKE ? OR CONCAT(a.firstName,' ',a.lastName) LIKE ?)",
+				[]any{q.Term, q.Term, q.Term, q.Term}, nil
+		}
+	case models.SearchDate:
+		return date(q)
+	default:
+		return "", nil, fmt.Errorf("unknown field %v", q.Field)
+	}
+}
+
+func join(q *models.BookQuery) (string, []any, error) {
+	right, args, err := condition(q.Right)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if q.Op == models.OpNot && q.Left == nil {
+		return "NOT (" + right + ")", args, nil
+	}
+
+	left, leftArgs, err := condition(q.Left)
+	if err != nil {
+		return "", nil, err
+	}
+
+	args = append(leftArgs, args...)
+
+	switch q.Op {
+	case models.OpAnd:
+		return "(" + left + " AND " + right + ")", args, nil
+	case models.OpOr:
+		return "(" + left + " OR " + right + ")", args, nil
+	case models.OpNot:
+		return "(" + left + " AND NOT (" + right + "))", args, nil
+	default:
+		return "", nil, fmt.Errorf("unknown operator %v", q.Op)
+	}
+}
+
+// date compares years when the term is one, else whole dates
+func date(q *models.BookQuery) (string, []any, error) {
+	switch q.Relation {
+	case models.RelationEqual, models.RelationLess, models.RelationLessEqual, models.RelationGreater,
+		models.RelationGreaterEqual:
+	default:
+		return "", nil, fmt.Errorf("relation %v is not supported for %v", q.Relation, q.Field)
+	}
+
+	if year, err := strconv.Atoi(q.Term); err == nil && len(q.Term) == 4 {
+		return "YEAR(" + published + ")" + q.Relation + "?", []any{year}, nil
+	}
+
+	return published + q.Relation + "?", []any{q.Term}, nil
+}
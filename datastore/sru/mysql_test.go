@@ -0,0 +1,121 @@
+package sru
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"Three-Layer-Architecture/models"
+)
+
+const count = "SELECT COUNT(*) FROM Book b JOIN Author a ON a.authorId=b.authorId WHERE b.deleted_at IS NULL"
+
+// Test_Search function is to test queries are translated into WHERE clauses and paged
+func Test_Search(t *testing.T) {
+	author := models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}
+	books := []models.Book{
+		{BookID: 1, AuthorID: 1, Auth: author, Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016",
+			ISBN: "8129115301"},
+		{BookID: 3, AuthorID: 1, Auth: author, Title: "One Indian Girl", Publication: "Penguin", PublishedDate: "01/10/2016"},
+	}
+	byAuthor := "(a.firstName LIKE ? OR a.lastName LIKE ? OR a.penName LIKE ? OR CONCAT(a.firstName,' ',a.lastName) LIKE ?)"
+
+	testcases := []struct {
+		desc   string
+		query  *models.BookQuery
+		offset int
+		limit  int
+		where  string
+		args   []driver.Value
+		total  int
+		rows   bool
+		resp   []models.Book
+		err    bool
+	}{
+		{desc: "every book", limit: 10, total: 2, rows: true, resp: books},
+		{desc: "title and not author", offset: 1, limit: 1, total: 3, rows: true, resp: books,
+			query: &models.BookQuery{Op: models.OpNot,
+				Left:  &models.BookQuery{Field: models.SearchTitle, Relation: models.RelationLike, Term: "%Girl%"},
+				Right: &models.BookQuery{Field: models.SearchAuthor, Relation: models.RelationLike, Term: "Lee"}},
+			where: " and (b.title LIKE ? AND NOT (" + byAuthor + "))",
+			args:  []driver.Value{"%Girl%", "Lee", "Lee", "Lee", "Lee"}},
+		{desc: "publisher or year", limit: 10, total: 2, rows: true, resp: books,
+			query: &models.BookQuery{Op: models.OpOr,
+				Left: &models.BookQuery{Field: models.SearchPublisher, Relation: models.RelationLike, Term: "Penguin"},
+				Right: &models.BookQuery{Field: models.SearchDate, Relation: models.RelationLess,
+					Term: "2017"}},
+			where: " and (b.Publication LIKE ? OR YEAR(STR_TO_DATE(b.PublishedDate,'%d/%m/%Y'))<?)",
+			args:  []driver.Value{"Penguin", 2017}},
+		{desc: "date only counted", total: 5,
+			query: &models.BookQuery{Op: models.OpNot,
+				Right: &models.BookQuery{Field: models.SearchDate, Relation: models.RelationEqual, Term: "2016-03-16"}},
+			where: " and NOT (STR_TO_DATE(b.PublishedDate,'%d/%m/%Y')=?)", args: []driver.Value{"2016-03-16"}},
+		{desc: "offset past the end", offset: 2, limit: 10, total: 2},
+		{desc: "unknown field", limit: 10, err: true, query: &models.BookQuery{Field: "isbn", Relation: "like"}},
+		{desc: "relation of another field", limit: 10, err: true,
+			query: &models.BookQuery{Field: models.SearchTitle, Relation: models.RelationLess, Term: "a"}},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		if !v.err {
+			mock.ExpectQuery(count + v.where).WithArgs(v.args...).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(v.total))
+		}
+
+		if v.rows {
+			rows := sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "firstName",
+				"lastName", "dob", "penName"})
+			for _, b := range books {
+				rows.AddRow(b.BookID, b.Title, b.AuthorID, b.Publication, b.PublishedDate, b.ISBN, b.Auth.FirstName,
+					b.Auth.LastName, b.Auth.Dob, b.Auth.PenName)
+			}
+
+			mock.ExpectQuery(columns + v.where + " ORDER BY b.bookId LIMIT ? OFFSET ?").
+				WithArgs(append(v.args, v.limit, v.offset)...).WillReturnRows(rows)
+		}
+
+		got, total, err := New(db).Search(context.Background(), v.query, v.offset, v.limit)
+
+		if !reflect.DeepEqual(got, v.resp) || total != v.total {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", v.desc, i+1, got, total, v.resp, v.total)
+		}
+
+		if (err != nil) != v.err {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "expectations", len(testcases)+1, err, nil)
+	}
+}
+
+// Test_Search_Error function is to test database errors are returned
+func Test_Search_Error(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	lost := errors.New("connection lost")
+
+	mock.ExpectQuery(count).WillReturnError(lost)
+
+	if _, _, err = New(db).Search(context.Background(), nil, 0, 10); !errors.Is(err, lost) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "count error", 1, err, lost)
+	}
+}
@@ -0,0 +1,160 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"Three-Layer-Architecture/models"
+)
+
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// Post method is to create a user account
+func (d Datastore) Post(ctx context.Context, user models.User) (models.User, error) {
+	res, err := d.db.ExecContext(ctx, "insert into User(username,email,passwordHash,createdAt) values (?,?,?,?)",
+		user.Username, user.Email, user.PasswordHash, user.CreatedAt)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user.ID = int(id)
+
+	return user, nil
+}
+
+// GetByUsername method is to get a user by its login name
+func (d Datastore) GetByUsername(ctx context.Context, username string) (models.User, error) {
+	row := d.db.QueryRowContext(ctx, "SELECT id,username,email,passwordHash,failedLogins,lockedUntil,createdAt FROM User WHERE username=?", username)
+
+	return scan(row)
+}
+
+// Getbyid method is to get a user by its id
+func (d Datastore) Getbyid(ctx context.Context, id int) (models.User, error) {
+	row := d.db.QueryRowContext(ctx, "SELECT id,username,email,passwordHash,failedLogins,lockedUntil,createdAt FROM User WHERE id=?", id)
+
+	return scan(row)
+}
+
+// RecordFailedLogin method is to count a wrong password and get the failures so far, failures of a lock which expired
+// by now count from zero again. The count is read back in the transaction of the increment so concurrent failures are
+// all counted. sql.ErrNoRows is returned while the account is locked.
+func (d Datastore) RecordFailedLogin(ctx context.Context, id int, now time.Time) (int, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	// rollback after commit is a no-op
+	defer func() { _ = tx.Rollback() }()
+
+	// failedLogins is set before lockedUntil is cleared, MySQL assigns in order
+	res, err := tx.ExecContext(ctx, "UPDATE User SET failedLogins=IF(lockedUntil IS NULL, failedLogins, 0)+1, "+
+		"lockedUntil=NULL WHERE id=? and (lockedUntil IS NULL or lockedUntil<=?)", id, now)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if n == 0 {
+		return 0, sql.ErrNoRows
+	}
+
+	var failures int
+
+	if err = tx.QueryRowContext(ctx, "SELECT failedLogins FROM User WHERE id=?", id).Scan(&failures); err != nil {
+		return 0, err
+	}
+
+	return failures, tx.Commit()
+}
+
+// Lock method is to refuse logins of a user until the given time
+func (d Datastore) Lock(ctx context.Context, id int, until time.Time) error {
+	_, err := d.db.ExecContext(ctx, "UPDATE User SET lockedUntil=? WHERE id=?", until, id)
+
+	return err
+}
+
+// ResetFailedLogins method is to clear failures and lock after a successful login
+func (d Datastore) ResetFailedLogins(ctx context.Context, id int) error {
+	_, err := d.db.ExecContext(ctx, "UPDATE User SET failedLogins=0, lockedUntil=NULL WHERE id=?", id)
+
+	return err
+}
+
+// UpdatePassword method is to store a new password hash, it also unlocks the account
+func (d Datastore) UpdatePassword(ctx context.Context, id int, hash string) error {
+	_, err := d.db.ExecContext(ctx, "UPDATE User SET passwordHash=?, failedLogins=0, lockedUntil=NULL WHERE id=?", hash, id)
+
+	return err
+}
+
+// PostReset method is to store a password reset token
+func (d Datastore) PostReset(ctx context.Context, reset models.PasswordReset) error {
+	_, err := d.db.ExecContext(ctx, "insert into PasswordReset(tokenHash,userId,expiresAt) values (?,?,?)",
+		reset.TokenHash, reset.UserID, reset.ExpiresAt)
+
+	return err
+}
+
+// UseReset method is to consume a reset token, it returns the token only once and only before it expires
+func (d Datastore) UseReset(ctx context.Context, tokenHash string, now time.Time) (models.PasswordReset, error) {
+	res, err := d.db.ExecContext(ctx, "UPDATE PasswordReset SET usedAt=? WHERE tokenHash=? and usedAt IS NULL and expiresAt>?",
+		now, tokenHash, now)
+	if err != nil {
+		return models.PasswordReset{}, err
+	}
+
+	rowAffected, err := res.RowsAffected()
+	if err != nil {
+		return models.PasswordReset{}, err
+	}
+
+	if rowAffected == 0 {
+		return models.PasswordReset{}, sql.ErrNoRows
+	}
+
+	var reset models.PasswordReset
+
+	row := d.db.QueryRowContext(ctx, "SELECT tokenHash,userId,expiresAt FROM PasswordReset WHERE tokenHash=?", tokenHash)
+	if err = row.Scan(&reset.TokenHash, &reset.UserID, &reset.ExpiresAt); err != nil {
+		return models.PasswordReset{}, err
+	}
+
+	return reset, nil
+}
+
+func scan(row *sql.Row) (models.User, error) {
+	var (
+		user        models.User
+		lockedUntil sql.NullTime
+	)
+
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.FailedLogins, &lockedUntil, &user.CreatedAt)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if lockedUntil.Valid {
+		user.LockedUntil = &lockedUntil.Time
+	}
+
+	return user, nil
+}
@@ -0,0 +1,178 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"Three-Layer-Architecture/models"
+)
+
+// Test_GetByUsername user
+func Test_GetByUsername(t *testing.T) {
+	at := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	columns := []string{"id", "username", "email", "passwordHash", "failedLogins", "lockedUntil", "createdAt"}
+
+	testcases := []struct {
+		desc     string
+		username string
+		rows     *sqlmock.Rows
+		resp     models.User
+		err      error
+	}{
+		{desc: "valid", username: "alice", rows: sqlmock.NewRows(columns).AddRow(1, "alice", "alice@library.test", "$2a$10$h",
+			0, nil, at), resp: models.User{ID: 1, Username: "alice", Email: "alice@library.test", PasswordHash: "$2a$10$h",
+			CreatedAt: at}},
+		{desc: "locked", username: "bob", rows: sqlmock.NewRows(columns).AddRow(2, "bob", "bob@library.test", "$2a$10$h",
+			5, at.Add(time.Minute), at), resp: models.User{ID: 2, Username: "bob", Email: "bob@library.test", PasswordHash: "$2a$10$h",
+			FailedLogins: 5, LockedUntil: timePtr(at.Add(time.Minute)), CreatedAt: at}},
+		{desc: "unknown", username: "carol", rows: sqlmock.NewRows(columns), err: sql.ErrNoRows},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectQuery("SELECT id,username,email,passwordHash,failedLogins,lockedUntil,createdAt FROM User WHERE username=?").
+			WithArgs(v.username).WillReturnRows(v.rows)
+
+		d := New(db)
+
+		resp, err := d.GetByUsername(context.Background(), v.username)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Test_UseReset password reset token
+func Test_UseReset(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc        string
+		rowAffected int64
+		resp        models.PasswordReset
+		err         error
+	}{
+		{desc: "valid", rowAffected: 1, resp: models.PasswordReset{TokenHash: "f00d", UserID: 1, ExpiresAt: now.Add(time.Hour)}},
+		{desc: "used or expired", rowAffected: 0, err: sql.ErrNoRows},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectExec("UPDATE PasswordReset SET usedAt=? WHERE tokenHash=? and usedAt IS NULL and expiresAt>?").
+			WithArgs(now, "f00d", now).WillReturnResult(sqlmock.NewResult(0, v.rowAffected))
+
+		if v.rowAffected == 1 {
+			mock.ExpectQuery("SELECT tokenHash,userId,expiresAt FROM PasswordReset WHERE tokenHash=?").WithArgs("f00d").
+				WillReturnRows(sqlmock.NewRows([]string{"tokenHash", "userId", "expiresAt"}).AddRow("f00d", 1, now.Add(time.Hour)))
+		}
+
+		d := New(db)
+
+		resp, err := d.UseReset(context.Background(), "f00d", now)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Test_RecordFailedLogin user, the count is incremented and read back in one transaction
+func Test_RecordFailedLogin(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc     string
+		affected int64
+		resp     int
+		err      error
+	}{
+		{desc: "counted", affected: 1, resp: 3},
+		{desc: "locked meanwhile", affected: 0, err: sql.ErrNoRows},
+	}
+
+	for i, v := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE User SET failedLogins=IF(lockedUntil IS NULL, failedLogins, 0)+1, "+
+			"lockedUntil=NULL WHERE id=? and (lockedUntil IS NULL or lockedUntil<=?)").WithArgs(1, now).
+			WillReturnResult(sqlmock.NewResult(0, v.affected))
+
+		if v.err == nil {
+			mock.ExpectQuery("SELECT failedLogins FROM User WHERE id=?").WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"failedLogins"}).AddRow(3))
+			mock.ExpectCommit()
+		} else {
+			mock.ExpectRollback()
+		}
+
+		resp, err := New(db).RecordFailedLogin(context.Background(), 1, now)
+		if resp != v.resp || !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", v.desc, i+1, resp, err, v.resp, v.err)
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, nil)
+		}
+
+		db.Close()
+	}
+}
+
+// Test_Lock user
+func Test_Lock(t *testing.T) {
+	until := time.Date(2022, 5, 1, 10, 15, 0, 0, time.UTC)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE User SET lockedUntil=? WHERE id=?").WithArgs(until, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err = New(db).Lock(context.Background(), 1, until); err != nil {
+		t.Errorf("[TEST1]Failed. Got %v\tExpected <nil>\n", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("[TEST1]Failed. Got %v\tExpected <nil>\n", err)
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
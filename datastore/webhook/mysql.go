@@ -0,0 +1,233 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"Three-Layer-Architecture/models"
+)
+
+const (
+	webhookColumns  = "SELECT id,url,events,secret,active,createdAt FROM Webhook"
+	deliveryColumns = "SELECT id,webhookId,eventId,event,payload,status,attempts,responseCode,error,nextAttemptAt," +
+		"createdAt,deliveredAt FROM WebhookDelivery"
+)
+
+type Datastore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) Datastore {
+	return Datastore{db: db}
+}
+
+// Post method is to store a webhook, its id is set from the table
+func (d Datastore) Post(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	res, err := d.db.ExecContext(ctx, "INSERT INTO Webhook(url,events,secret,active,createdAt) VALUES (?,?,?,?,?)",
+		webhook.URL, strings.Join(webhook.Events, ","), webhook.Secret, webhook.Active, webhook.CreatedAt)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	webhook.ID = int(id)
+
+	return webhook, nil
+}
+
+// GetAll method is to get every webhook in id order
+func (d Datastore) GetAll(ctx context.Context) ([]models.Webhook, error) {
+	rows, err := d.db.QueryContext(ctx, webhookColumns+" ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var webhooks []models.Webhook
+
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// Get method is to get a webhook by its id, sql.ErrNoRows when there is none
+func (d Datastore) Get(ctx context.Context, id int) (models.Webhook, error) {
+	return scanWebhook(d.db.QueryRowContext(ctx, webhookColumns+" WHERE id=?", id))
+}
+
+// Update method is to change url, events and active of a webhook, the secret is kept
+func (d Datastore) Update(ctx context.Context, webhook models.Webhook) error {
+	_, err := d.db.ExecContext(ctx, "UPDATE Webhook SET url=?, events=?, active=? WHERE id=?",
+		webhook.URL, strings.Join(webhook.Events, ","), webhook.Active, webhook.ID)
+
+	return err
+}
+
+// Delete method is to remove a webhook with its deliveries
+func (d Datastore) Delete(ctx context.Context, id int) (int, error) {
+	res, err := d.db.ExecContext(ctx, "DELETE FROM Webhook WHERE id=?", id)
+	if err != nil {
+		return 0, err
+	}
+
+	rowAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if rowAffected == 0 {
+		return 0, sql.ErrNoRows
+	}
+
+	return int(rowAffected), nil
+}
+
+// PostDeliveries method is to queue deliveries with one insert
+func (d Datastore) PostDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(deliveries))
+	args := make([]any, 0, 7*len(deliveries))
+
+	for _, dl := range deliveries {
+		values = append(values, "(?,?,?,?,?,?,?)")
+		args = append(args, dl.WebhookID, dl.EventID, dl.Event, []byte(dl.Payload), dl.Status, dl.NextAttemptAt, dl.CreatedAt)
+	}
+
+	_, err := d.db.ExecContext(ctx, "INSERT INTO WebhookDelivery(webhookId,eventId,event,payload,status,nextAttemptAt,"+
+		"createdAt) VALUES "+strings.Join(values, ","), args...)
+
+	return err
+}
+
+// GetDeliveries method is to get the latest deliveries of a webhook, newest first
+func (d Datastore) GetDeliveries(ctx context.Context, webhookID, limit int) ([]models.WebhookDelivery, error) {
+	return d.deliveries(ctx, deliveryColumns+" WHERE webhookId=? ORDER BY id DESC LIMIT ?", webhookID, limit)
+}
+
+// GetDelivery method is to get a delivery by its id, sql.ErrNoRows when there is none
+func (d Datastore) GetDelivery(ctx context.Context, id int) (models.WebhookDelivery, error) {
+	return scanDelivery(d.db.QueryRowContext(ctx, deliveryColumns+" WHERE id=?", id))
+}
+
+// Due method is to get pending deliveries whose next attempt is not after now, oldest first
+func (d Datastore) Due(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	return d.deliveries(ctx, deliveryColumns+" WHERE status=? and nextAttemptAt<=? ORDER BY nextAttemptAt,id LIMIT ?",
+		models.DeliveryPending, now, limit)
+}
+
+// Claim method is to move the next attempt of a due delivery to until, it is false when another replica claimed it first
+func (d Datastore) Claim(ctx context.Context, delivery models.WebhookDelivery, until time.Time) (bool, error) {
+	res, err := d.db.ExecContext(ctx, "UPDATE WebhookDelivery SET nextAttemptAt=? WHERE id=? and status=? and nextAttemptAt=?",
+		until, delivery.ID, models.DeliveryPending, delivery.NextAttemptAt)
+	if err != nil {
+		return false, err
+	}
+
+	rowAffected, err := res.RowsAffected()
+
+	return rowAffected == 1, err
+}
+
+// UpdateDelivery method is to store the result of an attempt
+func (d Datastore) UpdateDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	var code sql.NullInt64
+	if delivery.ResponseCode != 0 {
+		code = sql.NullInt64{Int64: int64(delivery.ResponseCode), Valid: true}
+	}
+
+	var reason sql.NullString
+	if delivery.Error != "" {
+		reason = sql.NullString{String: delivery.Error, Valid: true}
+	}
+
+	_, err := d.db.ExecContext(ctx, "UPDATE WebhookDelivery SET status=?, attempts=?, responseCode=?, error=?, "+
+		"nextAttemptAt=?, deliveredAt=? WHERE id=?", delivery.Status, delivery.Attempts, code, reason,
+		delivery.NextAttemptAt, delivery.DeliveredAt, delivery.ID)
+
+	return err
+}
+
+func (d Datastore) deliveries(ctx context.Context, query string, args ...any) ([]models.WebhookDelivery, error) {
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func scanWebhook(row interface{ Scan(dest ...any) error }) (models.Webhook, error) {
+	var (
+		webhook models.Webhook
+		events  string
+	)
+
+	err := row.Scan(&webhook.ID, &webhook.URL, &events, &webhook.Secret, &webhook.Active, &webhook.CreatedAt)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	webhook.Events = strings.Split(events, ",")
+
+	return webhook, nil
+}
+
+func scanDelivery(row interface{ Scan(dest ...any) error }) (models.WebhookDelivery, error) {
+	var (
+		delivery        models.WebhookDelivery
+		payload         []byte
+		code            sql.NullInt64
+		reason          sql.NullString
+		next, delivered sql.NullTime
+	)
+
+	err := row.Scan(&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.Event, &payload, &delivery.Status,
+		&delivery.Attempts, &code, &reason, &next, &delivery.CreatedAt, &delivered)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	delivery.Payload = payload
+	delivery.ResponseCode = int(code.Int64)
+	delivery.Error = reason.String
+
+	if next.Valid {
+		delivery.NextAttemptAt = &next.Time
+	}
+
+	if delivered.Valid {
+		delivery.DeliveredAt = &delivered.Time
+	}
+
+	return delivery, nil
+}
@@ -0,0 +1,236 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"Three-Layer-Architecture/models"
+)
+
+var (
+	webhookRows  = []string{"id", "url", "events", "secret", "active", "createdAt"}
+	deliveryRows = []string{"id", "webhookId", "eventId", "event", "payload", "status", "attempts", "responseCode", "error",
+		"nextAttemptAt", "createdAt", "deliveredAt"}
+)
+
+// Test_Post function is to test a webhook gets the id of its row with events joined
+func Test_Post(t *testing.T) {
+	at := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	webhook := models.Webhook{URL: "https://example.com/hook", Events: []string{"book.*", "loan.renewed"}, Secret: "s",
+		Active: true, CreatedAt: at}
+	created := webhook
+	created.ID = 3
+
+	testcases := []struct {
+		desc string
+		err  error
+		resp models.Webhook
+	}{
+		{desc: "created", resp: created},
+		{desc: "db down", err: errors.New("connection refused")},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		exec := mock.ExpectExec("INSERT INTO Webhook(url,events,secret,active,createdAt) VALUES (?,?,?,?,?)").
+			WithArgs(webhook.URL, "book.*,loan.renewed", "s", true, at)
+		if v.err != nil {
+			exec.WillReturnError(v.err)
+		} else {
+			exec.WillReturnResult(sqlmock.NewResult(3, 1))
+		}
+
+		resp, err := New(db).Post(context.Background(), webhook)
+
+		if !reflect.DeepEqual(resp, v.resp) || !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", v.desc, i+1, resp, err, v.resp, v.err)
+		}
+	}
+}
+
+// Test_Get function is to test events of a webhook are split back to a list
+func Test_Get(t *testing.T) {
+	at := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc string
+		id   int
+		err  error
+		resp models.Webhook
+	}{
+		{desc: "found", id: 3, resp: models.Webhook{ID: 3, URL: "https://example.com/hook",
+			Events: []string{"book.*", "loan.renewed"}, Secret: "s", Active: true, CreatedAt: at}},
+		{desc: "not found", id: 4, err: sql.ErrNoRows},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		query := mock.ExpectQuery(webhookColumns + " WHERE id=?").WithArgs(v.id)
+		if v.err != nil {
+			query.WillReturnError(v.err)
+		} else {
+			query.WillReturnRows(sqlmock.NewRows(webhookRows).
+				AddRow(3, "https://example.com/hook", "book.*,loan.renewed", "s", true, at))
+		}
+
+		resp, err := New(db).Get(context.Background(), v.id)
+
+		if !reflect.DeepEqual(resp, v.resp) || !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", v.desc, i+1, resp, err, v.resp, v.err)
+		}
+	}
+}
+
+// Test_Delete function is to test deleting a missing webhook gives sql.ErrNoRows
+func Test_Delete(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		affected int64
+		err      error
+		resp     int
+	}{
+		{desc: "deleted", affected: 1, resp: 1},
+		{desc: "not found", err: sql.ErrNoRows},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectExec("DELETE FROM Webhook WHERE id=?").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, v.affected))
+
+		resp, err := New(db).Delete(context.Background(), 3)
+
+		if resp != v.resp || !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", v.desc, i+1, resp, err, v.resp, v.err)
+		}
+	}
+}
+
+// Test_PostDeliveries function is to test deliveries are queued with one insert
+func Test_PostDeliveries(t *testing.T) {
+	at := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	payload := []byte(`{"id":"e1"}`)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO WebhookDelivery(webhookId,eventId,event,payload,status,nextAttemptAt,createdAt) VALUES "+
+		"(?,?,?,?,?,?,?),(?,?,?,?,?,?,?)").
+		WithArgs(1, "e1", "book.created", payload, models.DeliveryPending, &at, at,
+			2, "e1", "book.created", payload, models.DeliveryPending, &at, at).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+
+	deliveries := []models.WebhookDelivery{
+		{WebhookID: 1, EventID: "e1", Event: "book.created", Payload: payload, Status: models.DeliveryPending,
+			NextAttemptAt: &at, CreatedAt: at},
+		{WebhookID: 2, EventID: "e1", Event: "book.created", Payload: payload, Status: models.DeliveryPending,
+			NextAttemptAt: &at, CreatedAt: at},
+	}
+
+	testcases := []struct {
+		desc       string
+		deliveries []models.WebhookDelivery
+	}{
+		{desc: "two webhooks", deliveries: deliveries},
+		{desc: "no webhook"},
+	}
+
+	for i, v := range testcases {
+		if err := New(db).PostDeliveries(context.Background(), v.deliveries); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, nil)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "one insert", 3, err, nil)
+	}
+}
+
+// Test_Due function is to test pending deliveries are read with nullable columns
+func Test_Due(t *testing.T) {
+	at := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery(deliveryColumns+" WHERE status=? and nextAttemptAt<=? ORDER BY nextAttemptAt,id LIMIT ?").
+		WithArgs(models.DeliveryPending, at, 10).
+		WillReturnRows(sqlmock.NewRows(deliveryRows).
+			AddRow(5, 1, "e1", "book.created", []byte(`{}`), models.DeliveryPending, 2, 500, "500 Internal Server Error",
+				at, at, nil))
+
+	expected := []models.WebhookDelivery{{ID: 5, WebhookID: 1, EventID: "e1", Event: "book.created",
+		Payload: []byte(`{}`), Status: models.DeliveryPending, Attempts: 2, ResponseCode: 500,
+		Error: "500 Internal Server Error", NextAttemptAt: &at, CreatedAt: at}}
+
+	resp, err := New(db).Due(context.Background(), at, 10)
+	if err != nil || !reflect.DeepEqual(resp, expected) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "due", 1, resp, err, expected)
+	}
+}
+
+// Test_Claim function is to test a delivery is claimed only while it is unchanged
+func Test_Claim(t *testing.T) {
+	at := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	until := at.Add(time.Minute)
+	delivery := models.WebhookDelivery{ID: 5, NextAttemptAt: &at}
+
+	testcases := []struct {
+		desc     string
+		affected int64
+		resp     bool
+	}{
+		{desc: "claimed", affected: 1, resp: true},
+		{desc: "taken by another replica"},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectExec("UPDATE WebhookDelivery SET nextAttemptAt=? WHERE id=? and status=? and nextAttemptAt=?").
+			WithArgs(until, 5, models.DeliveryPending, &at).WillReturnResult(sqlmock.NewResult(0, v.affected))
+
+		resp, err := New(db).Claim(context.Background(), delivery, until)
+
+		if resp != v.resp || err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", v.desc, i+1, resp, err, v.resp)
+		}
+	}
+}
@@ -5,16 +5,230 @@ create table Author (
                         lastName varchar(50),
                         dob varchar(50),
                         penName     varchar(50),
-                        PRIMARY KEY (AuthorId)
+                        deleted_at DATETIME NULL,
+                        updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+                        PRIMARY KEY (AuthorId),
+                        INDEX (deleted_at),
+                        INDEX (updated_at)
 )
 
-DROP TABLE IF EXISTS Books;
-CREATE TABLE Books(
+DROP TABLE IF EXISTS Book;
+CREATE TABLE Book(
                       bookId VARCHAR(10) ,
                       authorId INT,
                       title  VARCHAR(50),
-                      Publications VARCHAR(50),
+                      Publication VARCHAR(50),
                       PublishedDate VARCHAR(50),
+                      isbn VARCHAR(13) NOT NULL DEFAULT '',
+                      deleted_at DATETIME NULL,
+                      updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
                       PRIMARY KEY (bookId),
+                      INDEX (deleted_at),
+                      INDEX (updated_at),
                       FOREIGN KEY (authorId) REFERENCES Author(authorId)
-)
\ No newline at end of file
+)
+
+DROP TABLE IF EXISTS Audit;
+CREATE TABLE Audit(
+                      id BIGINT NOT NULL AUTO_INCREMENT,
+                      actor VARCHAR(100) NOT NULL,
+                      occurredAt DATETIME(6) NOT NULL,
+                      operation VARCHAR(10) NOT NULL,
+                      entity VARCHAR(20) NOT NULL,
+                      entityId INT NOT NULL,
+                      beforeData JSON NULL,
+                      afterData JSON NULL,
+                      PRIMARY KEY (id),
+                      INDEX (entity, entityId, occurredAt),
+                      INDEX (occurredAt)
+)
+
+-- Audit is append-only
+CREATE TRIGGER audit_no_update BEFORE UPDATE ON Audit FOR EACH ROW
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'Audit is append-only'
+
+CREATE TRIGGER audit_no_delete BEFORE DELETE ON Audit FOR EACH ROW
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'Audit is append-only'
+
+
+DROP TABLE IF EXISTS Revision;
+CREATE TABLE Revision(
+                         entity VARCHAR(20) NOT NULL,
+                         entityId INT NOT NULL,
+                         rev INT NOT NULL,
+                         operation VARCHAR(10) NOT NULL,
+                         actor VARCHAR(100) NOT NULL,
+                         createdAt DATETIME(6) NOT NULL,
+                         data JSON NOT NULL,
+                         PRIMARY KEY (entity, entityId, rev),
+                         INDEX (entity, entityId, createdAt)
+)
+
+
+DROP TABLE IF EXISTS ApiKey;
+CREATE TABLE ApiKey(
+                       id INT NOT NULL AUTO_INCREMENT,
+                       prefix VARCHAR(16) NOT NULL,
+                       hash CHAR(64) NOT NULL,
+                       name VARCHAR(100) NOT NULL,
+                       principal VARCHAR(100) NOT NULL,
+                       createdAt DATETIME NOT NULL,
+                       revokedAt DATETIME NULL,
+                       PRIMARY KEY (id),
+                       UNIQUE (prefix)
+)
+
+
+DROP TABLE IF EXISTS RoleAssignment;
+DROP TABLE IF EXISTS RolePermission;
+DROP TABLE IF EXISTS Role;
+CREATE TABLE Role(
+                     name VARCHAR(50) NOT NULL,
+                     PRIMARY KEY (name)
+)
+
+CREATE TABLE RolePermission(
+                               role VARCHAR(50) NOT NULL,
+                               permission VARCHAR(50) NOT NULL,
+                               PRIMARY KEY (role, permission),
+                               FOREIGN KEY (role) REFERENCES Role(name)
+)
+
+CREATE TABLE RoleAssignment(
+                               principal VARCHAR(100) NOT NULL,
+                               role VARCHAR(50) NOT NULL,
+                               PRIMARY KEY (principal, role),
+                               FOREIGN KEY (role) REFERENCES Role(name)
+)
+
+INSERT INTO Role(name) VALUES ('admin'), ('librarian'), ('cataloguer'), ('circulation'), ('patron')
+
+INSERT INTO RolePermission(role, permission) VALUES
+    ('admin', '*'),
+    ('librarian', 'book:*'), ('librarian', 'author:*'), ('librarian', 'trash:read'), ('librarian', 'audit:read'),
+    ('cataloguer', 'book:read'), ('cataloguer', 'book:create'), ('cataloguer', 'book:update'),
+    ('cataloguer', 'book:import'),
+    ('cataloguer', 'author:read'), ('cataloguer', 'author:create'), ('cataloguer', 'author:update'),
+    ('librarian', 'loan:manage'),
+    ('circulation', 'book:read'), ('circulation', 'author:read'), ('circulation', 'loan:manage'),
+    ('patron', 'book:read'), ('patron', 'author:read')
+
+
+DROP TABLE IF EXISTS PasswordReset;
+DROP TABLE IF EXISTS Session;
+DROP TABLE IF EXISTS User;
+CREATE TABLE User(
+                     id INT NOT NULL AUTO_INCREMENT,
+                     username VARCHAR(100) NOT NULL,
+                     email VARCHAR(255) NOT NULL,
+                     passwordHash VARCHAR(100) NOT NULL,
+                     failedLogins INT NOT NULL DEFAULT 0,
+                     lockedUntil DATETIME NULL,
+                     createdAt DATETIME NOT NULL,
+                     PRIMARY KEY (id),
+                     UNIQUE (username)
+)
+
+CREATE TABLE Session(
+                        id INT NOT NULL AUTO_INCREMENT,
+                        tokenHash CHAR(64) NOT NULL,
+                        userId INT NOT NULL,
+                        createdAt DATETIME NOT NULL,
+                        expiresAt DATETIME NOT NULL,
+                        revokedAt DATETIME NULL,
+                        PRIMARY KEY (id),
+                        UNIQUE (tokenHash),
+                        FOREIGN KEY (userId) REFERENCES User(id)
+)
+
+CREATE TABLE PasswordReset(
+                              tokenHash CHAR(64) NOT NULL,
+                              userId INT NOT NULL,
+                              expiresAt DATETIME NOT NULL,
+                              usedAt DATETIME NULL,
+                              PRIMARY KEY (tokenHash),
+                              FOREIGN KEY (userId) REFERENCES User(id)
+)
+
+
+DROP TABLE IF EXISTS Loan;
+CREATE TABLE Loan(
+                     id INT NOT NULL AUTO_INCREMENT,
+                     bookId VARCHAR(10) NOT NULL,
+                     userId INT NOT NULL,
+                     checkedOutAt DATETIME NOT NULL,
+                     dueAt DATETIME NOT NULL,
+                     renewals INT NOT NULL DEFAULT 0,
+                     returnedAt DATETIME NULL,
+                     -- a book has at most one active loan
+                     activeBookId VARCHAR(10) AS (IF(returnedAt IS NULL, bookId, NULL)) STORED,
+                     PRIMARY KEY (id),
+                     UNIQUE (activeBookId),
+                     INDEX (userId, returnedAt),
+                     FOREIGN KEY (bookId) REFERENCES Book(bookId),
+                     FOREIGN KEY (userId) REFERENCES User(id)
+)
+
+
+DROP TABLE IF EXISTS WebhookDelivery;
+DROP TABLE IF EXISTS Webhook;
+CREATE TABLE Webhook(
+                        id INT NOT NULL AUTO_INCREMENT,
+                        url VARCHAR(2048) NOT NULL,
+                        -- comma separated event filters like book.created,loan.*
+                        events VARCHAR(1024) NOT NULL,
+                        secret VARCHAR(100) NOT NULL,
+                        active BOOLEAN NOT NULL DEFAULT TRUE,
+                        createdAt DATETIME NOT NULL,
+                        PRIMARY KEY (id)
+)
+
+CREATE TABLE WebhookDelivery(
+                                id INT NOT NULL AUTO_INCREMENT,
+                                webhookId INT NOT NULL,
+                                eventId CHAR(32) NOT NULL,
+                                event VARCHAR(50) NOT NULL,
+                                payload JSON NOT NULL,
+                                status VARCHAR(10) NOT NULL,
+                                attempts INT NOT NULL DEFAULT 0,
+                                responseCode INT NULL,
+                                error VARCHAR(255) NULL,
+                                nextAttemptAt DATETIME NULL,
+                                createdAt DATETIME NOT NULL,
+                                deliveredAt DATETIME NULL,
+                                PRIMARY KEY (id),
+                                INDEX (status, nextAttemptAt),
+                                INDEX (webhookId, id),
+                                FOREIGN KEY (webhookId) REFERENCES Webhook(id) ON DELETE CASCADE
+)
+
+
+DROP TABLE IF EXISTS OutboxSent;
+DROP TABLE IF EXISTS OutboxCursor;
+DROP TABLE IF EXISTS Outbox;
+CREATE TABLE Outbox(
+                       id BIGINT NOT NULL AUTO_INCREMENT,
+                       event VARCHAR(50) NOT NULL,
+                       entity VARCHAR(20) NOT NULL,
+                       entityId INT NOT NULL,
+                       payload JSON NOT NULL,
+                       createdAt DATETIME NOT NULL,
+                       PRIMARY KEY (id),
+                       INDEX (createdAt)
+)
+
+-- each sink gets the events after lastId, the latest one when the sink was added
+CREATE TABLE OutboxCursor(
+                             sink VARCHAR(255) NOT NULL,
+                             lastId BIGINT NOT NULL,
+                             PRIMARY KEY (sink)
+)
+
+-- events each sink has sent, ids are not committed in order so a position is not enough
+CREATE TABLE OutboxSent(
+                           sink VARCHAR(255) NOT NULL,
+                           outboxId BIGINT NOT NULL,
+                           PRIMARY KEY (sink, outboxId),
+                           INDEX (outboxId),
+                           FOREIGN KEY (outboxId) REFERENCES Outbox(id) ON DELETE CASCADE
+)
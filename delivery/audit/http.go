@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+type Delivery struct {
+	service service.Audit
+}
+
+func New(audit service.Audit) Delivery {
+	return Delivery{service: audit}
+}
+
+// Get method is to list audit entries, filtered by entity, id and from/to time in RFC3339
+func (a Delivery) Get(w http.ResponseWriter, r *http.Request) {
+	filter, err := readFilter(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	audits, err := a.service.Get(r.Context(), filter)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	body, err := json.Marshal(audits)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		writeError(err, w)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err = w.Write(body); err != nil {
+		slog.ErrorContext(r.Context(), "could not write response", "error", err)
+
+		return
+	}
+
+	slog.InfoContext(r.Context(), "audit listed")
+}
+
+func readFilter(r *http.Request) (models.AuditFilter, error) {
+	query := r.URL.Query()
+
+	filter := models.AuditFilter{Entity: query.Get("entity")}
+
+	var err error
+
+	if id := query.Get("id"); id != "" {
+		if filter.EntityID, err = strconv.Atoi(id); err != nil {
+			return models.AuditFilter{}, err
+		}
+	}
+
+	if from := query.Get("from"); from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return models.AuditFilter{}, err
+		}
+	}
+
+	if to := query.Get("to"); to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return models.AuditFilter{}, err
+		}
+	}
+
+	return filter, nil
+}
+
+func writeError(err error, w http.ResponseWriter) {
+	_, errs := w.Write([]byte(err.Error()))
+	if errs != nil {
+		slog.Error("could not write response", "error", errs)
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+}
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// TestGetAudit function is to test listing of audit entries
+func TestGetAudit(t *testing.T) {
+	from := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc               string
+		target             string
+		filter             models.AuditFilter
+		expectedStatusCode int
+		err                error
+	}{
+		{desc: "valid", target: "/audit?entity=book&id=1&from=2022-05-01T10:00:00Z",
+			filter: models.AuditFilter{Entity: "book", EntityID: 1, From: from}, expectedStatusCode: http.StatusOK},
+		{desc: "invalid time", target: "/audit?from=yesterday", expectedStatusCode: http.StatusBadRequest},
+		{desc: "invalid id", target: "/audit?id=a", expectedStatusCode: http.StatusBadRequest},
+		{desc: "error from svc", target: "/audit?entity=patron", filter: models.AuditFilter{Entity: "patron"},
+			expectedStatusCode: http.StatusBadRequest, err: errors.New("invalid entity")},
+	}
+
+	ctr := gomock.NewController(t)
+	mockAudit := service.NewMockAudit(ctr)
+	delivery := New(mockAudit)
+
+	for i, v := range testcases {
+		req := httptest.NewRequest(http.MethodGet, v.target, nil)
+		w := httptest.NewRecorder()
+
+		mockAudit.EXPECT().Get(gomock.Any(), v.filter).Return([]models.Audit{}, v.err).AnyTimes()
+
+		delivery.Get(w, req)
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.StatusCode, v.expectedStatusCode)
+		}
+
+		res.Body.Close()
+	}
+}
@@ -0,0 +1,87 @@
+package author
+
+import (
+	"context"
+	"log/slog"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"Three-Layer-Architecture/librarypb"
+	"Three-Layer-Architecture/service"
+)
+
+// Server answers AuthorService over gRPC, errors of the service are mapped to status codes by middleware.GRPCStatus
+type Server struct {
+	librarypb.UnimplementedAuthorServiceServer
+
+	service service.Author
+}
+
+func NewServer(author service.Author) *Server {
+	return &Server{service: author}
+}
+
+// CreateAuthor method is to post details of author
+func (s *Server) CreateAuthor(ctx context.Context, req *librarypb.CreateAuthorRequest) (*librarypb.Author, error) {
+	author, err := s.service.Post(ctx, req.Author.Model())
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "author posted")
+
+	return librarypb.FromAuthor(author), nil
+}
+
+// ValidateAuthor method is to check a new author without storing it
+func (s *Server) ValidateAuthor(ctx context.Context, req *librarypb.ValidateAuthorRequest) (*emptypb.Empty, error) {
+	if err := s.service.Validate(ctx, req.Author.Model()); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// UpdateAuthor method is to update details of author by its id
+func (s *Server) UpdateAuthor(ctx context.Context, req *librarypb.UpdateAuthorRequest) (*librarypb.Author, error) {
+	author, err := s.service.Update(ctx, req.Id, req.Author.Model())
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "author updated")
+
+	return librarypb.FromAuthor(author), nil
+}
+
+// DeleteAuthor method is to move author to trash by its id
+func (s *Server) DeleteAuthor(ctx context.Context, req *librarypb.DeleteAuthorRequest) (*emptypb.Empty, error) {
+	if _, err := s.service.Delete(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "author deleted")
+
+	return &emptypb.Empty{}, nil
+}
+
+// RestoreAuthor method is to bring back deleted author by its id
+func (s *Server) RestoreAuthor(ctx context.Context, req *librarypb.RestoreAuthorRequest) (*emptypb.Empty, error) {
+	if _, err := s.service.Restore(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "author restored")
+
+	return &emptypb.Empty{}, nil
+}
+
+// ListAuthorRevisions method is to get every revision of author, oldest first
+func (s *Server) ListAuthorRevisions(ctx context.Context, req *librarypb.ListAuthorRevisionsRequest) (*librarypb.ListRevisionsResponse, error) {
+	revisions, err := s.service.GetRevisions(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return librarypb.FromRevisions(revisions), nil
+}
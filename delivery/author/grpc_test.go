@@ -0,0 +1,76 @@
+package author
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/protobuf/proto"
+
+	"Three-Layer-Architecture/librarypb"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// TestServer_CreateAuthor function is to test message is converted to the author given to the service
+func TestServer_CreateAuthor(t *testing.T) {
+	author := models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}
+
+	testcases := []struct {
+		desc   string
+		req    *librarypb.CreateAuthorRequest
+		author models.Author
+		resp   *librarypb.Author
+		err    error
+	}{
+		{desc: "valid author", req: &librarypb.CreateAuthorRequest{Author: librarypb.FromAuthor(author)}, author: author,
+			resp: librarypb.FromAuthor(author)},
+		{desc: "missing author", req: &librarypb.CreateAuthorRequest{}, err: errors.New("missing fields")},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockAuthor := service.NewMockAuthor(ctr)
+		mockAuthor.EXPECT().Post(gomock.Any(), v.author).Return(v.author, v.err)
+
+		resp, err := NewServer(mockAuthor).CreateAuthor(context.Background(), v.req)
+
+		if !proto.Equal(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		ctr.Finish()
+	}
+}
+
+// TestServer_DeleteAuthor function is to test id of the request is given to the service
+func TestServer_DeleteAuthor(t *testing.T) {
+	testcases := []struct {
+		desc string
+		id   string
+		err  error
+	}{
+		{desc: "deleted", id: "1"},
+		{desc: "error from svc", id: "x", err: errors.New("invalid id")},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockAuthor := service.NewMockAuthor(ctr)
+		mockAuthor.EXPECT().Delete(gomock.Any(), v.id).Return(1, v.err)
+
+		_, err := NewServer(mockAuthor).DeleteAuthor(context.Background(), &librarypb.DeleteAuthorRequest{Id: v.id})
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		ctr.Finish()
+	}
+}
@@ -2,9 +2,8 @@ package author
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -32,7 +31,7 @@ func (a Delivery) Post(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	author, err := a.service.Post(auth)
+	author, err := a.service.Post(r.Context(), auth)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		writeError(err, w)
@@ -56,7 +55,7 @@ func (a Delivery) Post(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusCreated)
 
-	fmt.Println("Successfully Post data")
+	slog.InfoContext(r.Context(), "author posted")
 }
 
 // Update Request method is to update request
@@ -73,7 +72,7 @@ func (a Delivery) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	auth, err := a.service.Update(vars["id"], author)
+	auth, err := a.service.Update(r.Context(), vars["id"], author)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		writeError(err, w)
@@ -99,7 +98,7 @@ func (a Delivery) Update(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 
-	fmt.Println("Successfully Update data")
+	slog.InfoContext(r.Context(), "author updated")
 }
 
 // Delete method is to delete data from request
@@ -107,7 +106,7 @@ func (a Delivery) Delete(w http.ResponseWriter, r *http.Request) {
 	// storing id in map
 	vars := mux.Vars(r)
 
-	_, err := a.service.Delete(vars["id"])
+	_, err := a.service.Delete(r.Context(), vars["id"])
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		writeError(err, w)
@@ -123,13 +122,58 @@ func (a Delivery) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.WriteHeader(http.StatusNoContent)
-	fmt.Println("Successfully Deleted..!!")
+	slog.InfoContext(r.Context(), "author deleted")
+}
+
+// Restore method is to bring back deleted Author by its id
+func (a Delivery) Restore(w http.ResponseWriter, r *http.Request) {
+	// storing id in map
+	vars := mux.Vars(r)
+
+	_, err := a.service.Restore(r.Context(), vars["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	slog.InfoContext(r.Context(), "author restored")
+}
+
+// GetRevisions method is to list every revision of an author
+func (a Delivery) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	revisions, err := a.service.GetRevisions(r.Context(), vars["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	body, err := json.Marshal(revisions)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		writeError(err, w)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err = w.Write(body); err != nil {
+		slog.ErrorContext(r.Context(), "could not write response", "error", err)
+	}
 }
 
 func writeError(err error, w http.ResponseWriter) {
 	_, errs := w.Write([]byte(err.Error()))
 	if errs != nil {
-		log.Printf("%v", errs)
+		slog.Error("could not write response", "error", errs)
 		w.WriteHeader(http.StatusBadRequest)
 
 		return
@@ -49,7 +49,7 @@ func TestPostAuthor(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/author", bytes.NewReader(body))
 		w := httptest.NewRecorder()
 
-		mockAuthor.EXPECT().Post(v.req).Return(v.resp, v.err).AnyTimes()
+		mockAuthor.EXPECT().Post(gomock.Any(), v.req).Return(v.resp, v.err).AnyTimes()
 
 		delivery.Post(w, req)
 
@@ -106,7 +106,7 @@ func TestUpdateAuthor(t *testing.T) {
 
 		req = mux.SetURLVars(req, map[string]string{"id": v.reqid})
 
-		mockAuthor.EXPECT().Update(v.reqid, v.reqbody).Return(v.resp, v.err).AnyTimes()
+		mockAuthor.EXPECT().Update(gomock.Any(), v.reqid, v.reqbody).Return(v.resp, v.err).AnyTimes()
 
 		// Mocking Update
 		delivery.Update(w, req)
@@ -155,7 +155,7 @@ func TestDeleteAuthor(t *testing.T) {
 
 		req = mux.SetURLVars(req, map[string]string{"id": v.reqid})
 
-		mockAuthor.EXPECT().Delete(v.reqid).Return(v.rowAffected, v.err).AnyTimes()
+		mockAuthor.EXPECT().Delete(gomock.Any(), v.reqid).Return(v.rowAffected, v.err).AnyTimes()
 
 		delivery.Delete(w, req)
 
@@ -169,6 +169,42 @@ func TestDeleteAuthor(t *testing.T) {
 	}
 }
 
+// TestRestoreAuthor function is to test restore method to bring back deleted author
+func TestRestoreAuthor(t *testing.T) {
+	testcases := []struct {
+		desc               string
+		reqid              string
+		expectedStatusCode int
+		err                error
+	}{
+		{desc: "valid", reqid: "1", expectedStatusCode: http.StatusNoContent},
+		{desc: "not in trash", reqid: "2", expectedStatusCode: http.StatusBadRequest, err: errors.New("sql: no rows in result set")},
+	}
+
+	ctr := gomock.NewController(t)
+	mockAuthor := service.NewMockAuthor(ctr)
+	delivery := New(mockAuthor)
+
+	for i, v := range testcases {
+		req := httptest.NewRequest(http.MethodPost, "/author/"+v.reqid+"/restore", nil)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": v.reqid})
+
+		mockAuthor.EXPECT().Restore(gomock.Any(), v.reqid).Return(1, v.err)
+
+		delivery.Restore(w, req)
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.StatusCode, v.expectedStatusCode)
+		}
+
+		res.Body.Close()
+	}
+}
+
 func Helper(author models.Author, res *http.Response) models.Author {
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
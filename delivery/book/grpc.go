@@ -0,0 +1,152 @@
+package book
+
+import (
+	"context"
+	"log/slog"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"Three-Layer-Architecture/librarypb"
+	"Three-Layer-Architecture/service"
+)
+
+// Server answers BookService over gRPC, errors of the service are mapped to status codes by middleware.GRPCStatus
+type Server struct {
+	librarypb.UnimplementedBookServiceServer
+
+	service service.Book
+}
+
+func NewServer(book service.Book) *Server {
+	return &Server{service: book}
+}
+
+// GetBook method is to get the book by its id, as_of gives the book as it was at that time
+func (s *Server) GetBook(ctx context.Context, req *librarypb.GetBookRequest) (*librarypb.Book, error) {
+	if req.AsOf != nil {
+		book, err := s.service.GetAsOf(ctx, req.Id, req.AsOf.AsTime())
+		if err != nil {
+			return nil, err
+		}
+
+		return librarypb.FromBook(book), nil
+	}
+
+	book, err := s.service.Getbyid(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return librarypb.FromBook(book), nil
+}
+
+// ListBooks method is to stream all books, one message each
+func (s *Server) ListBooks(_ *librarypb.ListBooksRequest, stream librarypb.BookService_ListBooksServer) error {
+	books, err := s.service.GetAll(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	for _, book := range books {
+		if err := stream.Send(librarypb.FromBook(book)); err != nil {
+			return err
+		}
+	}
+
+	slog.InfoContext(stream.Context(), "books listed", "count", len(books))
+
+	return nil
+}
+
+// CreateBook method is to post details of book
+func (s *Server) CreateBook(ctx context.Context, req *librarypb.CreateBookRequest) (*librarypb.Book, error) {
+	book := req.Book.Model()
+
+	created, err := s.service.Post(ctx, &book)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "book posted")
+
+	return librarypb.FromBook(created), nil
+}
+
+// ValidateBook method is to check a new book without storing it
+func (s *Server) ValidateBook(ctx context.Context, req *librarypb.ValidateBookRequest) (*emptypb.Empty, error) {
+	book := req.Book.Model()
+
+	if err := s.service.Validate(ctx, &book); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// UpdateBook method is to update details of book by its id
+func (s *Server) UpdateBook(ctx context.Context, req *librarypb.UpdateBookRequest) (*librarypb.Book, error) {
+	book := req.Book.Model()
+
+	updated, err := s.service.Update(ctx, req.Id, &book)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "book updated")
+
+	return librarypb.FromBook(updated), nil
+}
+
+// DeleteBook method is to move book to trash by its id
+func (s *Server) DeleteBook(ctx context.Context, req *librarypb.DeleteBookRequest) (*emptypb.Empty, error) {
+	if _, err := s.service.Delete(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "book deleted")
+
+	return &emptypb.Empty{}, nil
+}
+
+// RestoreBook method is to bring back deleted book by its id
+func (s *Server) RestoreBook(ctx context.Context, req *librarypb.RestoreBookRequest) (*emptypb.Empty, error) {
+	if _, err := s.service.Restore(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "book restored")
+
+	return &emptypb.Empty{}, nil
+}
+
+// ListBookRevisions method is to get every revision of book, oldest first
+func (s *Server) ListBookRevisions(ctx context.Context, req *librarypb.ListBookRevisionsRequest) (*librarypb.ListRevisionsResponse, error) {
+	revisions, err := s.service.GetRevisions(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return librarypb.FromRevisions(revisions), nil
+}
+
+// DiffBookRevisions method is to get fields which differ between two revisions of book
+func (s *Server) DiffBookRevisions(ctx context.Context, req *librarypb.DiffBookRevisionsRequest) (*librarypb.RevisionDiff, error) {
+	diff, err := s.service.DiffRevisions(ctx, req.Id, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return librarypb.FromRevisionDiff(diff)
+}
+
+// RevertBook method is to update book back to given revision
+func (s *Server) RevertBook(ctx context.Context, req *librarypb.RevertBookRequest) (*librarypb.Book, error) {
+	book, err := s.service.Revert(ctx, req.Id, req.Rev)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "book reverted")
+
+	return librarypb.FromBook(book), nil
+}
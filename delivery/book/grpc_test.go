@@ -0,0 +1,213 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"Three-Layer-Architecture/librarypb"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+var grpcBook = models.Book{BookID: 1, AuthorID: 1,
+	Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
+	Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}
+
+// dial serves server on an in-memory listener and returns a client of it
+func dial(t *testing.T, server librarypb.BookServiceServer) librarypb.BookServiceClient {
+	listener := bufconn.Listen(1 << 20)
+
+	s := grpc.NewServer()
+	librarypb.RegisterBookServiceServer(s, server)
+
+	go func() {
+		if err := s.Serve(listener); err != nil {
+			t.Errorf("serve: %v", err)
+		}
+	}()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		s.Stop()
+	})
+
+	return librarypb.NewBookServiceClient(conn)
+}
+
+// TestServer_ListBooks function is to test every book is sent as a message of the stream
+func TestServer_ListBooks(t *testing.T) {
+	second := grpcBook
+	second.BookID, second.Title = 2, "Half Girlfriend"
+
+	testcases := []struct {
+		desc  string
+		books []models.Book
+		err   error
+	}{
+		{desc: "two books", books: []models.Book{grpcBook, second}},
+		{desc: "no books", books: []models.Book{}},
+		{desc: "error from svc", err: errors.New("connection refused")},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockBook := service.NewMockBook(ctr)
+		mockBook.EXPECT().GetAll(gomock.Any()).Return(v.books, v.err)
+
+		stream, err := dial(t, NewServer(mockBook)).ListBooks(context.Background(), &librarypb.ListBooksRequest{})
+		if err != nil {
+			t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, nil)
+		}
+
+		var got []models.Book
+
+		for {
+			msg, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				if v.err == nil {
+					t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, nil)
+				}
+
+				break
+			}
+
+			got = append(got, msg.Model())
+		}
+
+		if len(got) != len(v.books) || (len(got) > 0 && !reflect.DeepEqual(got, v.books)) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, got, v.books)
+		}
+
+		ctr.Finish()
+	}
+}
+
+// TestServer_GetBook function is to test book is read by id, or as of a time
+func TestServer_GetBook(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc string
+		req  *librarypb.GetBookRequest
+		mock func(m *service.MockBook)
+		resp *librarypb.Book
+		err  error
+	}{
+		{desc: "by id", req: &librarypb.GetBookRequest{Id: "1"},
+			mock: func(m *service.MockBook) { m.EXPECT().Getbyid(gomock.Any(), "1").Return(grpcBook, nil) },
+			resp: librarypb.FromBook(grpcBook)},
+		{desc: "as of", req: &librarypb.GetBookRequest{Id: "1", AsOf: timestamppb.New(at)},
+			mock: func(m *service.MockBook) { m.EXPECT().GetAsOf(gomock.Any(), "1", at).Return(grpcBook, nil) },
+			resp: librarypb.FromBook(grpcBook)},
+		{desc: "error from svc", req: &librarypb.GetBookRequest{Id: "-1"}, err: errors.New("invalid id"),
+			mock: func(m *service.MockBook) {
+				m.EXPECT().Getbyid(gomock.Any(), "-1").Return(models.Book{}, errors.New("invalid id"))
+			}},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockBook := service.NewMockBook(ctr)
+		v.mock(mockBook)
+
+		resp, err := NewServer(mockBook).GetBook(context.Background(), v.req)
+
+		if !proto.Equal(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		ctr.Finish()
+	}
+}
+
+// TestServer_CreateBook function is to test message is converted to the book given to the service
+func TestServer_CreateBook(t *testing.T) {
+	testcases := []struct {
+		desc string
+		req  *librarypb.CreateBookRequest
+		book models.Book
+		err  error
+	}{
+		{desc: "valid book", req: &librarypb.CreateBookRequest{Book: librarypb.FromBook(grpcBook)}, book: grpcBook},
+		{desc: "missing book", req: &librarypb.CreateBookRequest{}, err: errors.New("invalid id")},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockBook := service.NewMockBook(ctr)
+
+		book := v.book
+		mockBook.EXPECT().Post(gomock.Any(), &book).Return(v.book, v.err)
+
+		resp, err := NewServer(mockBook).CreateBook(context.Background(), v.req)
+
+		if v.err == nil && !proto.Equal(resp, v.req.Book) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.req.Book)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		ctr.Finish()
+	}
+}
+
+// TestServer_UpdateBook function is to test the ISBN of the message reaches the service and is sent back
+func TestServer_UpdateBook(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockBook := service.NewMockBook(ctr)
+
+	book := grpcBook
+	book.ISBN = "8129115301"
+
+	mockBook.EXPECT().Update(gomock.Any(), "1", &book).Return(book, nil)
+
+	req := &librarypb.UpdateBookRequest{Id: "1", Book: librarypb.FromBook(book)}
+
+	resp, err := dial(t, NewServer(mockBook)).UpdateBook(context.Background(), req)
+	if err != nil || resp.Isbn != book.ISBN {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "isbn kept", 1, resp, req.Book)
+	}
+}
+
+// TestServer_DiffBookRevisions function is to test changed values are kept in the message
+func TestServer_DiffBookRevisions(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockBook := service.NewMockBook(ctr)
+
+	mockBook.EXPECT().DiffRevisions(gomock.Any(), "1", "1", "2").Return(models.RevisionDiff{From: 1, To: 2,
+		Changes: []models.FieldChange{{Field: "title", From: "2 States", To: "Two States"}}}, nil)
+
+	resp, err := NewServer(mockBook).DiffBookRevisions(context.Background(),
+		&librarypb.DiffBookRevisionsRequest{Id: "1", From: "1", To: "2"})
+	if err != nil || len(resp.Changes) != 1 || resp.Changes[0].To.GetStringValue() != "Two States" {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "diff", 1, resp, err, "Two States")
+	}
+}
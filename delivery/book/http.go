@@ -2,17 +2,24 @@ package book
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	"Three-Layer-Architecture/citation"
 	"Three-Layer-Architecture/models"
 	"Three-Layer-Architecture/service"
 )
 
+// cacheControl lets clients reuse catalogue reads for a minute, responses depend on the caller so shared caches must not keep them
+const cacheControl = "private, max-age=60"
+
 type Delivery struct {
 	service service.Book
 }
@@ -31,7 +38,7 @@ func (a Delivery) Post(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	book2, err := a.service.Post(&book)
+	book2, err := a.service.Post(r.Context(), &book)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		writeError(err, w)
@@ -57,13 +64,13 @@ func (a Delivery) Post(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusCreated)
 
-	fmt.Println("Successfully Post data")
+	slog.InfoContext(r.Context(), "book posted")
 }
 
 // GetAll method is get all details of Books
 func (a Delivery) GetAll(w http.ResponseWriter, r *http.Request) {
 	// Getting all books
-	allbooks, err := a.service.GetAll()
+	allbooks, err := a.service.GetAll(r.Context())
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		writeError(err, w)
@@ -72,6 +79,7 @@ func (a Delivery) GetAll(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Updating status
+	w.Header().Set("Cache-Control", cacheControl)
 	w.WriteHeader(http.StatusOK)
 
 	// Encoding
@@ -94,15 +102,30 @@ func (a Delivery) GetAll(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 
-	fmt.Println("Successfully get all books")
+	slog.InfoContext(r.Context(), "books listed")
 }
 
-// Getbyid method is get the book by its id
+// Getbyid method is get the book by its id, asOf query gives the book as it was at that time
 func (a Delivery) Getbyid(w http.ResponseWriter, r *http.Request) {
 	// storing id in map
 	vars := mux.Vars(r)
 
-	book, err := a.service.Getbyid(vars["id"])
+	var (
+		book models.Book
+		err  error
+	)
+
+	if asOf := r.URL.Query().Get("asOf"); asOf != "" {
+		var at time.Time
+
+		at, err = time.Parse(time.RFC3339, asOf)
+		if err == nil {
+			book, err = a.service.GetAsOf(r.Context(), vars["id"], at)
+		}
+	} else {
+		book, err = a.service.Getbyid(r.Context(), vars["id"])
+	}
+
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		writeError(err, w)
@@ -110,8 +133,17 @@ func (a Delivery) Getbyid(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Encoding
-	body, err := json.Marshal(book)
+	// citation formats are served on their Accept media type, json otherwise
+	media := negotiate(r.Header.Get("Accept"))
+
+	var body []byte
+
+	if media == "application/json" {
+		body, err = json.Marshal(book)
+	} else {
+		body, err = citation.Encode(book, media)
+	}
+
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		writeError(err, w)
@@ -119,6 +151,13 @@ func (a Delivery) Getbyid(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("Vary", "Accept")
+
+	if media != "application/json" {
+		w.Header().Set("Content-Type", media)
+	}
+
 	_, err = w.Write(body)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -130,7 +169,41 @@ func (a Delivery) Getbyid(w http.ResponseWriter, r *http.Request) {
 	// Updating Status
 	w.WriteHeader(http.StatusOK)
 
-	fmt.Println("Successfully Get Book")
+	slog.InfoContext(r.Context(), "book fetched")
+}
+
+// Cite method is to render a book as text citation in style query, apa by default
+func (a Delivery) Cite(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	style := r.URL.Query().Get("style")
+	if style == "" {
+		style = citation.StyleAPA
+	}
+
+	book, err := a.service.Getbyid(r.Context(), vars["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	text, err := citation.Text(book, style)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", cacheControl)
+	w.WriteHeader(http.StatusOK)
+
+	if _, err = w.Write([]byte(text + "\n")); err != nil {
+		slog.ErrorContext(r.Context(), "could not write response", "error", err)
+	}
 }
 
 // Update method is to update details of Book
@@ -145,7 +218,7 @@ func (a Delivery) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	bk, err := a.service.Update(vars["id"], &book)
+	bk, err := a.service.Update(r.Context(), vars["id"], &book)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		writeError(err, w)
@@ -170,7 +243,7 @@ func (a Delivery) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.WriteHeader(http.StatusOK)
-	fmt.Println("Successfully Update data")
+	slog.InfoContext(r.Context(), "book updated")
 }
 
 // Delete method is to delete details of Book by its id
@@ -178,7 +251,7 @@ func (a Delivery) Delete(w http.ResponseWriter, r *http.Request) {
 	// storing id in map
 	vars := mux.Vars(r)
 
-	_, err := a.service.Delete(vars["id"])
+	_, err := a.service.Delete(r.Context(), vars["id"])
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		writeError(err, w)
@@ -195,13 +268,133 @@ func (a Delivery) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fmt.Println("Successfully Deleted..!!")
+	slog.InfoContext(r.Context(), "book deleted")
+}
+
+// Restore method is to bring back deleted Book by its id
+func (a Delivery) Restore(w http.ResponseWriter, r *http.Request) {
+	// storing id in map
+	vars := mux.Vars(r)
+
+	_, err := a.service.Restore(r.Context(), vars["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	slog.InfoContext(r.Context(), "book restored")
+}
+
+// GetRevisions method is to list every revision of a book
+func (a Delivery) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	revisions, err := a.service.GetRevisions(r.Context(), vars["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, revisions)
+}
+
+// DiffRevisions method is to compare revisions given by from and to query
+func (a Delivery) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	query := r.URL.Query()
+
+	diff, err := a.service.DiffRevisions(r.Context(), vars["id"], query.Get("from"), query.Get("to"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, diff)
+}
+
+// Revert method is to bring book back to given revision
+func (a Delivery) Revert(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	book, err := a.service.Revert(r.Context(), vars["id"], vars["rev"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, book)
+	slog.InfoContext(r.Context(), "book reverted")
+}
+
+// negotiate picks the media type of Accept with the highest q which a book can be written in
+func negotiate(accept string) string {
+	best, bestQ := "application/json", 0.0
+
+	for _, part := range strings.Split(accept, ",") {
+		media, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		if q > bestQ && (media == "application/json" || media == "*/*" || media == "application/*" ||
+			isCitation(media)) {
+			best, bestQ = media, q
+		}
+	}
+
+	if !isCitation(best) {
+		return "application/json"
+	}
+
+	return best
+}
+
+func isCitation(media string) bool {
+	switch media {
+	case citation.MediaBibTeX, citation.MediaRIS, citation.MediaCSLJSON, citation.MediaDublinCore:
+		return true
+	default:
+		return false
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		writeError(err, w)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if _, err = w.Write(body); err != nil {
+		slog.Error("could not write response", "error", err)
+	}
 }
 
 func writeError(err error, w http.ResponseWriter) {
 	_, errs := w.Write([]byte(err.Error()))
 	if errs != nil {
-		log.Printf("%v", errs)
+		slog.Error("could not write response", "error", errs)
 		w.WriteHeader(http.StatusBadRequest)
 
 		return
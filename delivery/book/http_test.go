@@ -10,11 +10,14 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
 
+	"Three-Layer-Architecture/citation"
 	"Three-Layer-Architecture/models"
 	"Three-Layer-Architecture/service"
 )
@@ -54,9 +57,9 @@ func TestPostBook(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		if v.req.BookID == 1 {
-			mockBook.EXPECT().Post(&v.req).Return(v.resp, v.err)
+			mockBook.EXPECT().Post(gomock.Any(), &v.req).Return(v.resp, v.err)
 		} else {
-			mockBook.EXPECT().Post(&v.req).Return(v.resp, v.err).AnyTimes()
+			mockBook.EXPECT().Post(gomock.Any(), &v.req).Return(v.resp, v.err).AnyTimes()
 		}
 
 		delivery.Post(w, req)
@@ -101,7 +104,7 @@ func TestGetAllBooks(t *testing.T) {
 
 		w := httptest.NewRecorder()
 
-		mockBook.EXPECT().GetAll().Return(v.output, v.err).AnyTimes()
+		mockBook.EXPECT().GetAll(gomock.Any()).Return(v.output, v.err).AnyTimes()
 
 		delivery.GetAll(w, req)
 
@@ -155,7 +158,7 @@ func TestGetBook(t *testing.T) {
 
 		req = mux.SetURLVars(req, map[string]string{"id": v.reqid})
 
-		mockBook.EXPECT().Getbyid(v.reqid).Return(v.resp, v.err).AnyTimes()
+		mockBook.EXPECT().Getbyid(gomock.Any(), v.reqid).Return(v.resp, v.err).AnyTimes()
 
 		delivery.Getbyid(w, req)
 
@@ -210,7 +213,7 @@ func TestUpdateBook(t *testing.T) {
 
 		req = mux.SetURLVars(req, map[string]string{"id": v.reqid})
 
-		mockBook.EXPECT().Update(v.reqid, &v.reqbody).Return(v.resp, v.err).AnyTimes()
+		mockBook.EXPECT().Update(gomock.Any(), v.reqid, &v.reqbody).Return(v.resp, v.err).AnyTimes()
 
 		delivery.Update(w, req)
 
@@ -241,7 +244,7 @@ func TestDeleteBook(t *testing.T) {
 		expectedStatusCode int
 		err                error
 	}{
-		{desc: "valid", reqid: "1", rowAffected: 1, expectedStatusCode: http.StatusOK},
+		{desc: "valid", reqid: "1", rowAffected: 1, expectedStatusCode: http.StatusNoContent},
 		{desc: "missing id", reqid: "", rowAffected: 0, err: errors.New("missing id"), expectedStatusCode: http.StatusBadRequest},
 	}
 
@@ -259,7 +262,7 @@ func TestDeleteBook(t *testing.T) {
 
 		req = mux.SetURLVars(req, map[string]string{"id": v.reqid})
 
-		mockBook.EXPECT().Delete(v.reqid).Return(v.rowAffected, v.err).AnyTimes()
+		mockBook.EXPECT().Delete(gomock.Any(), v.reqid).Return(v.rowAffected, v.err).AnyTimes()
 
 		delivery.Delete(w, req)
 
@@ -273,6 +276,128 @@ func TestDeleteBook(t *testing.T) {
 	}
 }
 
+// TestRestoreBook function is to test restore method to bring back deleted book
+func TestRestoreBook(t *testing.T) {
+	testcases := []struct {
+		desc               string
+		reqid              string
+		expectedStatusCode int
+		err                error
+	}{
+		{desc: "valid", reqid: "1", expectedStatusCode: http.StatusNoContent},
+		{desc: "not in trash", reqid: "2", expectedStatusCode: http.StatusBadRequest, err: errors.New("sql: no rows in result set")},
+	}
+
+	ctr := gomock.NewController(t)
+	mockBook := service.NewMockBook(ctr)
+	delivery := New(mockBook)
+
+	for i, v := range testcases {
+		req := httptest.NewRequest(http.MethodPost, "/book/"+v.reqid+"/restore", nil)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": v.reqid})
+
+		mockBook.EXPECT().Restore(gomock.Any(), v.reqid).Return(1, v.err)
+
+		delivery.Restore(w, req)
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.StatusCode, v.expectedStatusCode)
+		}
+
+		res.Body.Close()
+	}
+}
+
+// TestGetBookAsOf function is to test reading a book at a point in time
+func TestGetBookAsOf(t *testing.T) {
+	at := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc               string
+		asOf               string
+		expectedStatusCode int
+		err                error
+	}{
+		{desc: "valid", asOf: "2022-05-01T10:00:00Z", expectedStatusCode: http.StatusOK},
+		{desc: "invalid time", asOf: "yesterday", expectedStatusCode: http.StatusBadRequest},
+		{desc: "error from svc", asOf: "2022-05-01T10:00:00Z", expectedStatusCode: http.StatusBadRequest,
+			err: errors.New("sql: no rows in result set")},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockBook := service.NewMockBook(ctr)
+		delivery := New(mockBook)
+
+		req := httptest.NewRequest(http.MethodGet, "/book/1?asOf="+url.QueryEscape(v.asOf), nil)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+		mockBook.EXPECT().GetAsOf(gomock.Any(), "1", at).Return(models.Book{BookID: 1}, v.err).AnyTimes()
+
+		delivery.Getbyid(w, req)
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.StatusCode, v.expectedStatusCode)
+		}
+
+		res.Body.Close()
+	}
+}
+
+// TestRevertBook function is to test reverting a book to a revision
+func TestRevertBook(t *testing.T) {
+	testcases := []struct {
+		desc               string
+		rev                string
+		resp               models.Book
+		expectedStatusCode int
+		err                error
+	}{
+		{desc: "valid", rev: "2", resp: models.Book{BookID: 1, Title: "Journey"}, expectedStatusCode: http.StatusOK},
+		{desc: "error from svc", rev: "3", expectedStatusCode: http.StatusBadRequest,
+			err: errors.New("cannot revert to a deleted revision")},
+	}
+
+	ctr := gomock.NewController(t)
+	mockBook := service.NewMockBook(ctr)
+	delivery := New(mockBook)
+
+	for i, v := range testcases {
+		req := httptest.NewRequest(http.MethodPost, "/book/1/revert/"+v.rev, nil)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "1", "rev": v.rev})
+
+		mockBook.EXPECT().Revert(gomock.Any(), "1", v.rev).Return(v.resp, v.err)
+
+		delivery.Revert(w, req)
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.StatusCode, v.expectedStatusCode)
+		}
+
+		if v.err == nil {
+			var book models.Book
+
+			if book = HelperReader(&book, res); !reflect.DeepEqual(book, v.resp) {
+				t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, book, v.resp)
+			}
+		}
+
+		res.Body.Close()
+	}
+}
+
 func HelperReader(book *models.Book, res *http.Response) models.Book {
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
@@ -300,3 +425,107 @@ func GetAllHelper(output *[]models.Book, res *http.Response) []models.Book {
 
 	return *output
 }
+
+// TestGetBookCitation function is to test citation formats are served on Accept header
+func TestGetBookCitation(t *testing.T) {
+	book := models.Book{BookID: 1, AuthorID: 1,
+		Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
+		Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}
+
+	testcases := []struct {
+		desc        string
+		accept      string
+		contentType string
+		prefix      string
+	}{
+		{desc: "no accept", contentType: "", prefix: `{"bookID":1`},
+		{desc: "any", accept: "*/*", contentType: "", prefix: `{"bookID":1`},
+		{desc: "bibtex", accept: "application/x-bibtex", contentType: citation.MediaBibTeX, prefix: "@book{bhagat2016_1,"},
+		{desc: "ris over json by q", accept: "application/json;q=0.5, application/x-research-info-systems",
+			contentType: citation.MediaRIS, prefix: "TY  - BOOK"},
+		{desc: "csl json", accept: "text/html, application/vnd.citationstyles.csl+json;q=0.9",
+			contentType: citation.MediaCSLJSON, prefix: `[{"id":"1"`},
+		{desc: "dublin core", accept: "application/dc+xml", contentType: citation.MediaDublinCore, prefix: "<?xml"},
+		{desc: "unknown", accept: "text/html", contentType: "", prefix: `{"bookID":1`},
+	}
+
+	ctr := gomock.NewController(t)
+	mockBook := service.NewMockBook(ctr)
+	delivery := New(mockBook)
+
+	mockBook.EXPECT().Getbyid(gomock.Any(), "1").Return(book, nil).AnyTimes()
+
+	for i, v := range testcases {
+		req := httptest.NewRequest(http.MethodGet, "/book/1", nil)
+		req.Header.Set("Accept", v.accept)
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+		w := httptest.NewRecorder()
+
+		delivery.Getbyid(w, req)
+
+		res := w.Result()
+		body, _ := io.ReadAll(res.Body)
+
+		if v.contentType != "" && res.Header.Get("Content-Type") != v.contentType {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.Header.Get("Content-Type"),
+				v.contentType)
+		}
+
+		if !strings.HasPrefix(string(body), v.prefix) || res.Header.Get("Vary") != "Accept" {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %s\tExpected %v\n", v.desc, i+1, body, v.prefix)
+		}
+
+		res.Body.Close()
+	}
+}
+
+// TestCiteBook function is to test text citations in every style
+func TestCiteBook(t *testing.T) {
+	book := models.Book{BookID: 1, AuthorID: 1,
+		Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
+		Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}
+
+	testcases := []struct {
+		desc               string
+		style              string
+		err                error
+		resp               string
+		expectedStatusCode int
+	}{
+		{desc: "apa by default", resp: "Bhagat, C. (2016). 2 States. Scholastic.\n", expectedStatusCode: http.StatusOK},
+		{desc: "mla", style: "mla", resp: "Bhagat, Chetan. 2 States. Scholastic, 2016.\n", expectedStatusCode: http.StatusOK},
+		{desc: "chicago", style: "chicago", resp: "Bhagat, Chetan. 2016. 2 States. Scholastic.\n",
+			expectedStatusCode: http.StatusOK},
+		{desc: "unsupported style", style: "harvard", resp: `unsupported style "harvard"`,
+			expectedStatusCode: http.StatusBadRequest},
+		{desc: "error from svc", style: "apa", err: errors.New("sql: no rows in result set"),
+			resp: "sql: no rows in result set", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockBook := service.NewMockBook(ctr)
+		delivery := New(mockBook)
+
+		mockBook.EXPECT().Getbyid(gomock.Any(), "1").Return(book, v.err)
+
+		req := httptest.NewRequest(http.MethodGet, "/book/1/cite?style="+v.style, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+		w := httptest.NewRecorder()
+
+		delivery.Cite(w, req)
+
+		res := w.Result()
+		body, _ := io.ReadAll(res.Body)
+
+		if res.StatusCode != v.expectedStatusCode || string(body) != v.resp {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %s\tExpected %v %v\n", v.desc, i+1, res.StatusCode, body,
+				v.expectedStatusCode, v.resp)
+		}
+
+		res.Body.Close()
+		ctr.Finish()
+	}
+}
@@ -0,0 +1,121 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"Three-Layer-Architecture/service"
+)
+
+type Delivery struct {
+	service   service.Events
+	heartbeat time.Duration
+}
+
+// New returns a delivery which writes a comment every heartbeat so proxies keep idle streams open
+func New(events service.Events, heartbeat time.Duration) Delivery {
+	return Delivery{service: events, heartbeat: heartbeat}
+}
+
+// Stream method is to send changes as server-sent events while the client is connected. topics query filters
+// them, Last-Event-ID header or lastEventId query resumes after an event. A reset event tells the client events
+// were missed and records have to be read again.
+func (a Delivery) Stream(w http.ResponseWriter, r *http.Request) {
+	var topics []string
+
+	if query := r.URL.Query().Get("topics"); query != "" {
+		topics = strings.Split(query, ",")
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("lastEventId")
+	}
+
+	sub, err := a.service.Subscribe(r.Context(), topics, lastID)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	defer sub.Cancel()
+
+	rc := http.NewResponseController(w)
+
+	// the stream is open longer than the server write timeout
+	if errs := rc.SetWriteDeadline(time.Time{}); errs != nil {
+		slog.DebugContext(r.Context(), "could not clear write deadline", "error", errs)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if !sub.Complete {
+		err = writeEvent(w, "", "reset", []byte("{}"))
+	}
+
+	heartbeat := time.NewTicker(a.heartbeat)
+	defer heartbeat.Stop()
+
+	for err == nil {
+		if err = rc.Flush(); err != nil {
+			break
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			_, err = io.WriteString(w, ": heartbeat\n\n")
+		case event, ok := <-sub.Events:
+			// closed when the client is too slow or the server shuts down, the client resumes from its last event
+			if !ok {
+				return
+			}
+
+			var data []byte
+
+			if data, err = json.Marshal(event); err == nil {
+				err = writeEvent(w, strconv.FormatInt(event.ID, 10), event.Event, data)
+			}
+		}
+	}
+
+	slog.DebugContext(r.Context(), "event stream ended", "error", err)
+}
+
+// writeEvent writes one event of the stream, events without id do not move the client's last event id
+func writeEvent(w io.Writer, id, event string, data []byte) error {
+	var b strings.Builder
+
+	if id != "" {
+		b.WriteString("id: " + id + "\n")
+	}
+
+	b.WriteString("event: " + event + "\ndata: ")
+	b.Write(data)
+	b.WriteString("\n\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+func writeError(err error, w http.ResponseWriter) {
+	_, errs := w.Write([]byte(err.Error()))
+	if errs != nil {
+		slog.Error("could not write response", "error", errs)
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+}
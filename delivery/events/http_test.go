@@ -0,0 +1,76 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// TestStream function is to test events are written as server-sent events until the subscription ends
+func TestStream(t *testing.T) {
+	event := models.OutboxEvent{ID: 12, Event: models.EventBookUpdated, Entity: models.EntityBook, EntityID: 1,
+		Data: json.RawMessage(`{"bookID":1}`), CreatedAt: time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)}
+	data, _ := json.Marshal(event)
+
+	testcases := []struct {
+		desc               string
+		target             string
+		lastEventID        string
+		topics             []string
+		lastID             string
+		complete           bool
+		err                error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{desc: "live", target: "/events", complete: true, expectedStatusCode: http.StatusOK,
+			expectedBody: "id: 12\nevent: book.updated\ndata: " + string(data) + "\n\n"},
+		{desc: "resumed from header", target: "/events?topics=book.*,author.*", lastEventID: "11",
+			topics: []string{"book.*", "author.*"}, lastID: "11", complete: true, expectedStatusCode: http.StatusOK,
+			expectedBody: "id: 12\nevent: book.updated\ndata: " + string(data) + "\n\n"},
+		{desc: "resumed after evicted events", target: "/events?lastEventId=3", lastID: "3",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "event: reset\ndata: {}\n\nid: 12\nevent: book.updated\ndata: " + string(data) + "\n\n"},
+		{desc: "error from svc", target: "/events?topics=shelf.*", topics: []string{"shelf.*"},
+			err: errors.New("invalid topic"), expectedStatusCode: http.StatusBadRequest, expectedBody: "invalid topic"},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockEvents := service.NewMockEvents(ctr)
+
+		events := make(chan models.OutboxEvent, 1)
+		events <- event
+		close(events)
+
+		mockEvents.EXPECT().Subscribe(gomock.Any(), v.topics, v.lastID).
+			Return(models.Subscription{Events: events, Complete: v.complete, Cancel: func() {}}, v.err)
+
+		req := httptest.NewRequest(http.MethodGet, v.target, nil)
+		if v.lastEventID != "" {
+			req.Header.Set("Last-Event-ID", v.lastEventID)
+		}
+
+		w := httptest.NewRecorder()
+
+		New(mockEvents, time.Minute).Stream(w, req)
+
+		if w.Code != v.expectedStatusCode || w.Body.String() != v.expectedBody {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %q\tExpected %v %q\n", v.desc, i+1, w.Code, w.Body.String(),
+				v.expectedStatusCode, v.expectedBody)
+		}
+
+		if v.err == nil && w.Header().Get("Content-Type") != "text/event-stream" {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, w.Header().Get("Content-Type"),
+				"text/event-stream")
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package export
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"Three-Layer-Architecture/citation"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// contentTypes of export formats
+var contentTypes = map[string]string{
+	models.ExportCSV:     "text/csv; charset=utf-8",
+	models.ExportNDJSON:  "application/x-ndjson",
+	models.ExportJSON:    "application/json",
+	models.ExportMARC:    "application/marc",
+	models.ExportMARCXML: "application/marcxml+xml",
+	models.ExportBibTeX:  citation.MediaBibTeX,
+	models.ExportRIS:     citation.MediaRIS,
+	models.ExportCSLJSON: citation.MediaCSLJSON,
+	models.ExportDC:      citation.MediaDublinCore,
+}
+
+// extensions of exported file names
+var extensions = map[string]string{
+	models.ExportCSV:     "csv",
+	models.ExportNDJSON:  "ndjson",
+	models.ExportJSON:    "json",
+	models.ExportMARC:    "mrc",
+	models.ExportMARCXML: "xml",
+	models.ExportBibTeX:  "bib",
+	models.ExportRIS:     "ris",
+	models.ExportCSLJSON: "json",
+	models.ExportDC:      "xml",
+}
+
+type Delivery struct {
+	service service.Export
+}
+
+func New(export service.Export) Delivery {
+	return Delivery{service: export}
+}
+
+// Export method is to stream the catalogue in format query, csv by default.
+// fields query selects columns, authorID, publication and title queries filter books.
+func (a Delivery) Export(w http.ResponseWriter, r *http.Request) {
+	opts, err := readOptions(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	// a whole catalogue takes longer than the server write timeout
+	if err = http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		slog.DebugContext(r.Context(), "could not clear write deadline", "error", err)
+	}
+
+	out := &stream{w: w, contentType: contentTypes[opts.Format], filename: "catalogue." + extensions[opts.Format]}
+
+	err = a.service.Export(r.Context(), out, opts)
+	if err != nil && !out.started {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	// status is sent already, the client sees a cut off body
+	if err != nil {
+		slog.ErrorContext(r.Context(), "export failed", "error", err)
+	}
+}
+
+func readOptions(r *http.Request) (models.ExportOptions, error) {
+	query := r.URL.Query()
+
+	opts := models.ExportOptions{Format: query.Get("format"), Filter: models.BookFilter{
+		Publication: query.Get("publication"), Title: query.Get("title")}}
+
+	if opts.Format == "" {
+		opts.Format = models.ExportCSV
+	}
+
+	if _, ok := contentTypes[opts.Format]; !ok {
+		return models.ExportOptions{}, errors.New("unsupported format " + opts.Format)
+	}
+
+	if fields := query.Get("fields"); fields != "" {
+		opts.Fields = strings.Split(fields, ",")
+	}
+
+	if authorID := query.Get("authorID"); authorID != "" {
+		var err error
+		if opts.Filter.AuthorID, err = strconv.Atoi(authorID); err != nil {
+			return models.ExportOptions{}, errors.New("invalid authorID")
+		}
+	}
+
+	return opts, nil
+}
+
+// stream sends headers with the first write, so errors found before it can still be answered with a status
+type stream struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (s *stream) Write(p []byte) (int, error) {
+	if !s.started {
+		s.started = true
+
+		s.w.Header().Set("Content-Type", s.contentType)
+		s.w.Header().Set("Content-Disposition", `attachment; filename="`+s.filename+`"`)
+		s.w.WriteHeader(http.StatusOK)
+	}
+
+	return s.w.Write(p)
+}
+
+func (s *stream) Flush() {
+	if err := http.NewResponseController(s.w).Flush(); err != nil {
+		slog.Debug("could not flush export", "error", err)
+	}
+}
+
+func writeError(err error, w http.ResponseWriter) {
+	_, errs := w.Write([]byte(err.Error()))
+	if errs != nil {
+		slog.Error("could not write response", "error", errs)
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+}
@@ -0,0 +1,88 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// TestExport function is to test options are read from query and headers are sent with the first row
+func TestExport(t *testing.T) {
+	testcases := []struct {
+		desc               string
+		target             string
+		opts               *models.ExportOptions
+		body               string
+		err                error
+		expectedStatusCode int
+		contentType        string
+	}{
+		{desc: "csv by default", target: "/export", opts: &models.ExportOptions{Format: models.ExportCSV},
+			body: "bookID\n1\n", expectedStatusCode: http.StatusOK, contentType: "text/csv; charset=utf-8"},
+		{desc: "ndjson with fields and filters",
+			target: "/export?format=ndjson&fields=bookID,title&authorID=1&publication=Penguin&title=Girl",
+			opts: &models.ExportOptions{Format: models.ExportNDJSON, Fields: []string{"bookID", "title"},
+				Filter: models.BookFilter{AuthorID: 1, Publication: "Penguin", Title: "Girl"}},
+			body: "{\"bookID\":1}\n", expectedStatusCode: http.StatusOK, contentType: "application/x-ndjson"},
+		{desc: "unsupported format", target: "/export?format=xml", expectedStatusCode: http.StatusBadRequest},
+		{desc: "invalid authorID", target: "/export?authorID=a", expectedStatusCode: http.StatusBadRequest},
+		{desc: "error before writing", target: "/export?fields=isbn",
+			opts: &models.ExportOptions{Format: models.ExportCSV, Fields: []string{"isbn"}},
+			err:  errors.New("unknown field isbn"), expectedStatusCode: http.StatusBadRequest},
+		{desc: "error after writing", target: "/export?format=json", opts: &models.ExportOptions{Format: models.ExportJSON},
+			body: "[", err: errors.New("connection reset"), expectedStatusCode: http.StatusOK,
+			contentType: "application/json"},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockExport := service.NewMockExport(ctr)
+		delivery := New(mockExport)
+
+		if v.opts != nil {
+			body, err := v.body, v.err
+
+			mockExport.EXPECT().Export(gomock.Any(), gomock.Any(), *v.opts).DoAndReturn(
+				func(_ context.Context, w io.Writer, _ models.ExportOptions) error {
+					if body != "" {
+						if _, err := w.Write([]byte(body)); err != nil {
+							return err
+						}
+					}
+
+					return err
+				})
+		}
+
+		req := httptest.NewRequest(http.MethodGet, v.target, nil)
+		w := httptest.NewRecorder()
+
+		delivery.Export(w, req)
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.StatusCode, v.expectedStatusCode)
+		}
+
+		if res.Header.Get("Content-Type") != v.contentType && v.contentType != "" {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.Header.Get("Content-Type"),
+				v.contentType)
+		}
+
+		if got, _ := io.ReadAll(res.Body); v.body != "" && string(got) != v.body {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, string(got), v.body)
+		}
+
+		res.Body.Close()
+		ctr.Finish()
+	}
+}
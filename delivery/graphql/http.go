@@ -0,0 +1,206 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"Three-Layer-Architecture/service"
+)
+
+// Limits reject costly queries before they run, see measure for how complexity is counted
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+type Delivery struct {
+	book   service.Book
+	author service.Author
+	graph  service.GraphQL
+	rbac   service.RBAC
+	limits Limits
+	schema graphql.Schema
+}
+
+// New builds the schema, reads go through graph in batches and mutations through book and author services.
+// rbac checks the permission of each mutation and of author queries.
+func New(book service.Book, author service.Author, graph service.GraphQL, rbac service.RBAC, limits Limits) (Delivery, error) {
+	a := Delivery{book: book, author: author, graph: graph, rbac: rbac, limits: limits}
+
+	schema, err := a.newSchema()
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	a.schema = schema
+
+	return a, nil
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Handle method is to answer a query from query string of GET or a json or application/graphql body of POST.
+// Errors of the query are part of the json response, which is sent with status 200. Mutations need POST.
+func (a Delivery) Handle(w http.ResponseWriter, r *http.Request) {
+	req, err := readRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	doc, result := a.prepare(req)
+
+	if result == nil {
+		op := operation(doc, req.OperationName)
+
+		if op != nil && op.Operation != ast.OperationTypeQuery && r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			writeError(errors.New("mutations need POST"), w)
+
+			return
+		}
+
+		result = graphql.Execute(graphql.ExecuteParams{Schema: a.schema, AST: doc, OperationName: req.OperationName,
+			Args: req.Variables, Context: a.withLoaders(r.Context())})
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		writeError(err, w)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err = w.Write(body); err != nil {
+		slog.ErrorContext(r.Context(), "could not write response", "error", err)
+	}
+
+	if result.HasErrors() {
+		slog.WarnContext(r.Context(), "graphql query has errors", "operation", req.OperationName, "error", result.Errors[0].Message)
+
+		return
+	}
+
+	slog.InfoContext(r.Context(), "graphql query answered", "operation", req.OperationName)
+}
+
+// prepare parses and validates the query and checks its limits, result has the errors when it must not run
+func (a Delivery) prepare(req request) (*ast.Document, *graphql.Result) {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query),
+		Name: "GraphQL request"})})
+	if err != nil {
+		return nil, &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	if validation := graphql.ValidateDocument(&a.schema, doc, nil); !validation.IsValid {
+		return nil, &graphql.Result{Errors: validation.Errors}
+	}
+
+	// a missing operation is reported by execution
+	op := operation(doc, req.OperationName)
+	if op == nil {
+		return doc, nil
+	}
+
+	depth, complexity := measure(a.schema, doc, op)
+
+	switch {
+	case a.limits.MaxDepth > 0 && depth > a.limits.MaxDepth:
+		err = fmt.Errorf("query depth %d is over the limit of %d", depth, a.limits.MaxDepth)
+	case a.limits.MaxComplexity > 0 && complexity > a.limits.MaxComplexity:
+		err = fmt.Errorf("query complexity %d is over the limit of %d", complexity, a.limits.MaxComplexity)
+	}
+
+	if err != nil {
+		return nil, &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	return doc, nil
+}
+
+// operation finds the operation of name, or the only operation when name is empty
+func operation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		if name == "" && found != nil {
+			return nil
+		}
+
+		if name == "" || (op.Name != nil && op.Name.Value == name) {
+			found = op
+		}
+	}
+
+	return found
+}
+
+func readRequest(r *http.Request) (request, error) {
+	if r.Method == http.MethodGet {
+		req := request{Query: r.URL.Query().Get("query"), OperationName: r.URL.Query().Get("operationName")}
+
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return request{}, errors.New("invalid variables")
+			}
+		}
+
+		return req, nil
+	}
+
+	media, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return request{}, err
+	}
+
+	if media == "application/graphql" {
+		return request{Query: string(body)}, nil
+	}
+
+	var req request
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		return request{}, errors.New("invalid request body")
+	}
+
+	return req, nil
+}
+
+func writeError(err error, w http.ResponseWriter) {
+	_, errs := w.Write([]byte(err.Error()))
+	if errs != nil {
+		slog.Error("could not write response", "error", errs)
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+}
@@ -0,0 +1,193 @@
+package graphql
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+	"Three-Layer-Architecture/service/rbac"
+)
+
+type mocks struct {
+	book   *service.MockBook
+	author *service.MockAuthor
+	graph  *service.MockGraphQL
+	rbac   *service.MockRBAC
+}
+
+var (
+	bhagat = models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}
+	states = models.Book{BookID: 1, AuthorID: 1, Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}
+	girl   = models.Book{BookID: 3, AuthorID: 1, Title: "One Indian Girl", Publication: "Penguin", PublishedDate: "01/10/2016"}
+	godan  = models.Book{BookID: 2, AuthorID: 2, Title: "Godan", Publication: "Penguin", PublishedDate: "01/01/1936"}
+)
+
+func setup(t *testing.T) (mocks, Delivery) {
+	ctr := gomock.NewController(t)
+	m := mocks{book: service.NewMockBook(ctr), author: service.NewMockAuthor(ctr), graph: service.NewMockGraphQL(ctr),
+		rbac: service.NewMockRBAC(ctr)}
+
+	delivery, err := New(m.book, m.author, m.graph, m.rbac, Limits{MaxDepth: 4, MaxComplexity: 200})
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+
+	return m, delivery
+}
+
+func serve(delivery Delivery, req *http.Request) (int, string) {
+	w := httptest.NewRecorder()
+
+	delivery.Handle(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(res.Body)
+
+	return res.StatusCode, string(body)
+}
+
+func post(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	return req
+}
+
+// TestHandle function is to test queries and mutations are answered with authors loaded once per request
+func TestHandle(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		req      *http.Request
+		mock     func(m mocks)
+		status   int
+		contains []string
+	}{
+		{desc: "book with author and the author's books",
+			req: post(`{"query":"query($id: Int!) { book(id: $id) { title author { penName books { id title } } } }",` +
+				`"variables":{"id":1}}`),
+			mock: func(m mocks) {
+				m.rbac.EXPECT().Authorize(gomock.Any(), models.PermBookRead).Return(nil)
+				m.graph.EXPECT().BooksByID(gomock.Any(), []int{1}).Return(map[int]models.Book{1: states}, nil)
+				m.graph.EXPECT().Authors(gomock.Any(), []int{1}).Return(map[int]models.Author{1: bhagat}, nil)
+				m.graph.EXPECT().BooksByAuthor(gomock.Any(), []int{1}).
+					Return(map[int][]models.Book{1: {states, girl}}, nil)
+			}, status: http.StatusOK,
+			contains: []string{`{"data":{"book":{"author":{"books":[{"id":1,"title":"2 States"},` +
+				`{"id":3,"title":"One Indian Girl"}],"penName":"Chetan"},"title":"2 States"}}}`}},
+		{desc: "books of an author are paged",
+			req: post(`{"query":"{ book(id: 1) { author { first: books(first: 1) { id } next: books(after: 1) { id } } } }"}`),
+			mock: func(m mocks) {
+				m.rbac.EXPECT().Authorize(gomock.Any(), models.PermBookRead).Return(nil).Times(2)
+				m.graph.EXPECT().BooksByID(gomock.Any(), []int{1}).Return(map[int]models.Book{1: states}, nil)
+				m.graph.EXPECT().Authors(gomock.Any(), []int{1}).Return(map[int]models.Author{1: bhagat}, nil)
+				m.graph.EXPECT().BooksByAuthor(gomock.Any(), []int{1}).
+					Return(map[int][]models.Book{1: {states, girl}}, nil)
+			}, status: http.StatusOK,
+			contains: []string{`{"data":{"book":{"author":{"first":[{"id":1}],"next":[{"id":3}]}}}}`}},
+		{desc: "books of an author need book:read",
+			req: post(`{"query":"mutation { createAuthor(input: {id: 1, firstName: \"Chetan\", lastName: \"Bhagat\", ` +
+				`dob: \"06/04/2001\", penName: \"Chetan\"}) { id books { title } } }"}`),
+			mock: func(m mocks) {
+				m.rbac.EXPECT().Authorize(gomock.Any(), models.PermAuthorCreate).Return(nil)
+				m.author.EXPECT().Post(gomock.Any(), bhagat).Return(bhagat, nil)
+				m.rbac.EXPECT().Authorize(gomock.Any(), models.PermBookRead).Return(rbac.ErrForbidden)
+			}, status: http.StatusOK, contains: []string{`"message":"missing permission book:read"`}},
+		{desc: "page of more than 100 books", req: post(`{"query":"{ author(id: 1) { books(first: 101) { id } } }"}`),
+			mock: func(m mocks) {
+				m.rbac.EXPECT().Authorize(gomock.Any(), models.PermAuthorRead).Return(nil)
+				m.rbac.EXPECT().Authorize(gomock.Any(), models.PermBookRead).Return(nil)
+				m.graph.EXPECT().Authors(gomock.Any(), []int{1}).Return(map[int]models.Author{1: bhagat}, nil)
+			}, status: http.StatusOK, contains: []string{`"message":"first has to be between 1 and 100"`}},
+		{desc: "authors of a list are loaded by one batch",
+			req: httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape("{ books { title author { lastName } } }"), nil),
+			mock: func(m mocks) {
+				m.graph.EXPECT().Books(gomock.Any()).Return([]models.Book{states, godan, girl}, nil)
+				m.graph.EXPECT().Authors(gomock.Any(), []int{1, 2}).
+					Return(map[int]models.Author{1: bhagat, 2: {AuthID: 2, LastName: "Premchand"}}, nil).Times(1)
+			}, status: http.StatusOK,
+			contains: []string{`{"author":{"lastName":"Premchand"},"title":"Godan"}`,
+				`{"author":{"lastName":"Bhagat"},"title":"One Indian Girl"}`}},
+		{desc: "missing book is null", req: post(`{"query":"{ book(id: 9) { title } }"}`),
+			mock: func(m mocks) {
+				m.graph.EXPECT().BooksByID(gomock.Any(), []int{9}).Return(map[int]models.Book{}, nil)
+			}, status: http.StatusOK, contains: []string{`{"data":{"book":null}}`}},
+		{desc: "author needs author:read", req: post(`{"query":"{ author(id: 1) { lastName } }"}`),
+			mock: func(m mocks) {
+				m.rbac.EXPECT().Authorize(gomock.Any(), models.PermAuthorRead).Return(rbac.ErrForbidden)
+			}, status: http.StatusOK, contains: []string{`"message":"missing permission author:read"`}},
+		{desc: "create book", req: post(`{"query":"mutation { createBook(input: {id: 1, title: \"2 States\", ` +
+			`publication: \"Scholastic\", publishedDate: \"16/03/2016\", author: {id: 1, firstName: \"Chetan\", ` +
+			`lastName: \"Bhagat\", dob: \"06/04/2001\", penName: \"Chetan\"}}) { id author { firstName } } }"}`),
+			mock: func(m mocks) {
+				book := states
+				book.Auth = bhagat
+
+				m.rbac.EXPECT().Authorize(gomock.Any(), models.PermBookCreate).Return(nil)
+				m.book.EXPECT().Post(gomock.Any(), &book).Return(book, nil)
+				m.graph.EXPECT().Authors(gomock.Any(), []int{1}).Return(map[int]models.Author{1: bhagat}, nil)
+			}, status: http.StatusOK, contains: []string{`{"data":{"createBook":{"author":{"firstName":"Chetan"},"id":1}}}`}},
+		{desc: "update book keeps isbn", req: post(`{"query":"mutation { updateBook(id: 1, input: {id: 1, ` +
+			`title: \"2 States\", publication: \"Scholastic\", publishedDate: \"16/03/2016\", isbn: \"8129115301\", ` +
+			`author: {id: 1, firstName: \"Chetan\", lastName: \"Bhagat\", dob: \"06/04/2001\", penName: \"Chetan\"}}) ` +
+			`{ id isbn } }"}`),
+			mock: func(m mocks) {
+				book := states
+				book.Auth, book.ISBN = bhagat, "8129115301"
+
+				m.rbac.EXPECT().Authorize(gomock.Any(), models.PermBookUpdate).Return(nil)
+				m.book.EXPECT().Update(gomock.Any(), "1", &book).Return(book, nil)
+			}, status: http.StatusOK, contains: []string{`{"data":{"updateBook":{"id":1,"isbn":"8129115301"}}}`}},
+		{desc: "delete book without permission", req: post(`{"query":"mutation { deleteBook(id: 1) }"}`),
+			mock: func(m mocks) {
+				m.rbac.EXPECT().Authorize(gomock.Any(), models.PermBookDelete).Return(rbac.ErrForbidden)
+			}, status: http.StatusOK, contains: []string{`"message":"missing permission book:delete"`}},
+		{desc: "restore author", req: post(`{"query":"mutation { restoreAuthor(id: 1) }"}`),
+			mock: func(m mocks) {
+				m.rbac.EXPECT().Authorize(gomock.Any(), models.PermAuthorRestore).Return(nil)
+				m.author.EXPECT().Restore(gomock.Any(), "1").Return(1, nil)
+			}, status: http.StatusOK, contains: []string{`{"data":{"restoreAuthor":true}}`}},
+		{desc: "mutation over GET",
+			req:    httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape("mutation { deleteBook(id: 1) }"), nil),
+			status: http.StatusMethodNotAllowed, contains: []string{"mutations need POST"}},
+		{desc: "too deep", req: post(`{"query":"{ books { author { books { author { lastName } } } } }"}`),
+			status: http.StatusOK, contains: []string{"query depth 5 is over the limit of 4"}},
+		{desc: "too complex", req: post(`{"query":"{ books { author { books { title publication } } } }"}`),
+			status: http.StatusOK, contains: []string{"query complexity 221 is over the limit of 200"}},
+		{desc: "fragments are counted", req: post(`{"query":"{ books { ...deep } } fragment deep on Book ` +
+			`{ author { books { author { id } } } }"}`),
+			status: http.StatusOK, contains: []string{"query depth 5 is over the limit of 4"}},
+		{desc: "unknown field", req: post(`{"query":"{ books { barcode } }"}`),
+			status: http.StatusOK, contains: []string{`Cannot query field \"barcode\" on type \"Book\".`}},
+		{desc: "syntax error", req: post(`{"query":"{ books { "}`),
+			status: http.StatusOK, contains: []string{`"errors":[{"message":"Syntax Error`}},
+		{desc: "invalid body", req: post(`{"query":`), status: http.StatusBadRequest, contains: []string{"invalid request body"}},
+	}
+
+	for i, v := range testcases {
+		m, delivery := setup(t)
+		if v.mock != nil {
+			v.mock(m)
+		}
+
+		status, body := serve(delivery, v.req)
+
+		if status != v.status {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, status, v.status)
+		}
+
+		for _, c := range v.contains {
+			if !strings.Contains(body, c) {
+				t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, body, c)
+			}
+		}
+	}
+}
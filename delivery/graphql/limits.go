@@ -0,0 +1,112 @@
+package graphql
+
+import (
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// listFactor is the number of items a list field is counted with, so nested lists cost more than nested objects
+const listFactor = 10
+
+// measure gives the depth of the deepest field of op and its complexity, every field costs 1 and the selections of a
+// list field cost listFactor times. Introspection fields are not counted.
+func measure(schema graphql.Schema, doc *ast.Document, op *ast.OperationDefinition) (depth, complexity int) {
+	fragments := map[string]*ast.FragmentDefinition{}
+
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	var root graphql.Type = schema.QueryType()
+	if op.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	m := measurer{schema: schema, fragments: fragments}
+
+	return m.selections(op.SelectionSet, root, 1)
+}
+
+type measurer struct {
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+}
+
+// selections measures the fields of set, which are at depth level of the operation
+func (m measurer) selections(set *ast.SelectionSet, parent graphql.Type, level int) (depth, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, c int
+
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+
+			fieldType := typeOf(parent, s.Name.Value)
+			named, _ := graphql.GetNamed(fieldType).(graphql.Type)
+
+			d, c = m.selections(s.SelectionSet, named, level+1)
+			if isList(fieldType) {
+				c *= listFactor
+			}
+
+			d, c = max(d, level), c+1
+		case *ast.InlineFragment:
+			d, c = m.selections(s.SelectionSet, m.condition(s.TypeCondition, parent), level)
+		case *ast.FragmentSpread:
+			if fragment, ok := m.fragments[s.Name.Value]; ok {
+				d, c = m.selections(fragment.SelectionSet, m.condition(fragment.TypeCondition, parent), level)
+			}
+		}
+
+		depth, complexity = max(depth, d), complexity+c
+	}
+
+	return depth, complexity
+}
+
+func (m measurer) condition(name *ast.Named, parent graphql.Type) graphql.Type {
+	if name == nil {
+		return parent
+	}
+
+	return m.schema.Type(name.Name.Value)
+}
+
+// fielded is an object or interface type
+type fielded interface {
+	Fields() graphql.FieldDefinitionMap
+}
+
+// typeOf is the type of field name of parent, or nil when it has no such field
+func typeOf(parent graphql.Type, name string) graphql.Type {
+	object, ok := parent.(fielded)
+	if !ok {
+		return nil
+	}
+
+	if field, ok := object.Fields()[name]; ok {
+		return field.Type
+	}
+
+	return nil
+}
+
+func isList(t graphql.Type) bool {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType
+	}
+
+	_, ok := t.(*graphql.List)
+
+	return ok
+}
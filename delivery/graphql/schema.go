@@ -0,0 +1,332 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+
+	"Three-Layer-Architecture/dataloader"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service/rbac"
+)
+
+// maxBooks is the longest page of the books of an author
+const maxBooks = 100
+
+type loadersKey struct{}
+
+// loaders batch the lookups of one request so a list of books reads their authors with one query
+type loaders struct {
+	books         *dataloader.Loader[int, models.Book]
+	authors       *dataloader.Loader[int, models.Author]
+	booksByAuthor *dataloader.Loader[int, []models.Book]
+}
+
+func (a Delivery) withLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		books:         dataloader.New(a.graph.BooksByID),
+		authors:       dataloader.New(a.graph.Authors),
+		booksByAuthor: dataloader.New(a.graph.BooksByAuthor),
+	})
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// schema has books and authors with their relationships and mutations mapping onto the book and author services
+func (a Delivery) newSchema() (graphql.Schema, error) {
+	id := &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: resolveID}
+	text := &graphql.Field{Type: graphql.NewNonNull(graphql.String)}
+
+	authorType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Author",
+		Fields: graphql.Fields{
+			"id": id, "firstName": text, "lastName": text, "dob": text, "penName": text,
+		},
+	})
+
+	bookType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Book",
+		Fields: graphql.Fields{
+			"id": id, "title": text, "publication": text, "publishedDate": text,
+			"isbn":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"author": &graphql.Field{Type: authorType, Resolve: a.bookAuthor},
+		},
+	})
+
+	books := graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bookType)))
+
+	authorType.AddFieldConfig("books", &graphql.Field{Type: books, Resolve: a.authorBooks,
+		Args: graphql.FieldConfigArgument{
+			"first": {Type: graphql.Int, DefaultValue: maxBooks},
+			"after": {Type: graphql.Int, DefaultValue: 0},
+		}})
+
+	authorInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "AuthorInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"id":        {Type: graphql.NewNonNull(graphql.Int)},
+			"firstName": {Type: graphql.NewNonNull(graphql.String)},
+			"lastName":  {Type: graphql.NewNonNull(graphql.String)},
+			"dob":       {Type: graphql.NewNonNull(graphql.String)},
+			"penName":   {Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	bookInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "BookInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"id":            {Type: graphql.NewNonNull(graphql.Int)},
+			"title":         {Type: graphql.NewNonNull(graphql.String)},
+			"publication":   {Type: graphql.NewNonNull(graphql.String)},
+			"publishedDate": {Type: graphql.NewNonNull(graphql.String)},
+			"isbn":          {Type: graphql.String, DefaultValue: ""},
+			"author":        {Type: graphql.NewNonNull(authorInput)},
+		},
+	})
+
+	byID := graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.Int)}}
+	done := graphql.NewNonNull(graphql.Boolean)
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"book":   &graphql.Field{Type: bookType, Args: byID, Resolve: a.queryBook},
+			"books":  &graphql.Field{Type: books, Resolve: a.queryBooks},
+			"author": &graphql.Field{Type: authorType, Args: byID, Resolve: a.queryAuthor},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createBook": &graphql.Field{Type: graphql.NewNonNull(bookType), Resolve: a.createBook,
+				Args: graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(bookInput)}}},
+			"updateBook": &graphql.Field{Type: graphql.NewNonNull(bookType), Resolve: a.updateBook,
+				Args: graphql.FieldConfigArgument{"id": byID["id"], "input": {Type: graphql.NewNonNull(bookInput)}}},
+			"deleteBook":  &graphql.Field{Type: done, Args: byID, Resolve: a.deleteBook},
+			"restoreBook": &graphql.Field{Type: done, Args: byID, Resolve: a.restoreBook},
+			"createAuthor": &graphql.Field{Type: graphql.NewNonNull(authorType), Resolve: a.createAuthor,
+				Args: graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(authorInput)}}},
+			"updateAuthor": &graphql.Field{Type: graphql.NewNonNull(authorType), Resolve: a.updateAuthor,
+				Args: graphql.FieldConfigArgument{"id": byID["id"], "input": {Type: graphql.NewNonNull(authorInput)}}},
+			"deleteAuthor":  &graphql.Field{Type: done, Args: byID, Resolve: a.deleteAuthor},
+			"restoreAuthor": &graphql.Field{Type: done, Args: byID, Resolve: a.restoreAuthor},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+func resolveID(p graphql.ResolveParams) (interface{}, error) {
+	switch v := p.Source.(type) {
+	case models.Book:
+		return v.BookID, nil
+	case models.Author:
+		return v.AuthID, nil
+	default:
+		return nil, errors.New("unknown type of id")
+	}
+}
+
+func (a Delivery) queryBook(p graphql.ResolveParams) (interface{}, error) {
+	load := loadersFrom(p.Context).books.Load(p.Context, p.Args["id"].(int))
+
+	return func() (interface{}, error) {
+		book, ok, err := load()
+		if err != nil || !ok {
+			return nil, err
+		}
+
+		return book, nil
+	}, nil
+}
+
+func (a Delivery) queryBooks(p graphql.ResolveParams) (interface{}, error) {
+	return a.graph.Books(p.Context)
+}
+
+func (a Delivery) queryAuthor(p graphql.ResolveParams) (interface{}, error) {
+	if err := a.authorize(p.Context, models.PermAuthorRead); err != nil {
+		return nil, err
+	}
+
+	return a.loadAuthor(p.Context, p.Args["id"].(int)), nil
+}
+
+func (a Delivery) bookAuthor(p graphql.ResolveParams) (interface{}, error) {
+	return a.loadAuthor(p.Context, p.Source.(models.Book).AuthorID), nil
+}
+
+func (a Delivery) loadAuthor(ctx context.Context, id int) func() (interface{}, error) {
+	load := loadersFrom(ctx).authors.Load(ctx, id)
+
+	return func() (interface{}, error) {
+		author, ok, err := load()
+		if err != nil || !ok {
+			return nil, err
+		}
+
+		return author, nil
+	}
+}
+
+// authorBooks pages the books of an author in id order, after is the id of the last book of the previous page
+func (a Delivery) authorBooks(p graphql.ResolveParams) (interface{}, error) {
+	if err := a.authorize(p.Context, models.PermBookRead); err != nil {
+		return nil, err
+	}
+
+	first, after := intOf(p.Args["first"]), intOf(p.Args["after"])
+	if first < 1 || first > maxBooks {
+		return nil, fmt.Errorf("first has to be between 1 and %d", maxBooks)
+	}
+
+	load := loadersFrom(p.Context).booksByAuthor.Load(p.Context, p.Source.(models.Author).AuthID)
+
+	return func() (interface{}, error) {
+		books, _, err := load()
+		if err != nil {
+			return nil, err
+		}
+
+		page := make([]models.Book, 0, first)
+
+		for _, book := range books {
+			if book.BookID > after && len(page) < first {
+				page = append(page, book)
+			}
+		}
+
+		return page, nil
+	}, nil
+}
+
+func (a Delivery) createBook(p graphql.ResolveParams) (interface{}, error) {
+	if err := a.authorize(p.Context, models.PermBookCreate); err != nil {
+		return nil, err
+	}
+
+	book := bookOf(p.Args["input"])
+
+	return a.book.Post(p.Context, &book)
+}
+
+func (a Delivery) updateBook(p graphql.ResolveParams) (interface{}, error) {
+	if err := a.authorize(p.Context, models.PermBookUpdate); err != nil {
+		return nil, err
+	}
+
+	book := bookOf(p.Args["input"])
+
+	return a.book.Update(p.Context, strconv.Itoa(p.Args["id"].(int)), &book)
+}
+
+func (a Delivery) deleteBook(p graphql.ResolveParams) (interface{}, error) {
+	if err := a.authorize(p.Context, models.PermBookDelete); err != nil {
+		return nil, err
+	}
+
+	_, err := a.book.Delete(p.Context, strconv.Itoa(p.Args["id"].(int)))
+
+	return err == nil, err
+}
+
+func (a Delivery) restoreBook(p graphql.ResolveParams) (interface{}, error) {
+	if err := a.authorize(p.Context, models.PermBookRestore); err != nil {
+		return nil, err
+	}
+
+	_, err := a.book.Restore(p.Context, strconv.Itoa(p.Args["id"].(int)))
+
+	return err == nil, err
+}
+
+func (a Delivery) createAuthor(p graphql.ResolveParams) (interface{}, error) {
+	if err := a.authorize(p.Context, models.PermAuthorCreate); err != nil {
+		return nil, err
+	}
+
+	return a.author.Post(p.Context, authorOf(p.Args["input"]))
+}
+
+func (a Delivery) updateAuthor(p graphql.ResolveParams) (interface{}, error) {
+	if err := a.authorize(p.Context, models.PermAuthorUpdate); err != nil {
+		return nil, err
+	}
+
+	return a.author.Update(p.Context, strconv.Itoa(p.Args["id"].(int)), authorOf(p.Args["input"]))
+}
+
+func (a Delivery) deleteAuthor(p graphql.ResolveParams) (interface{}, error) {
+	if err := a.authorize(p.Context, models.PermAuthorDelete); err != nil {
+		return nil, err
+	}
+
+	_, err := a.author.Delete(p.Context, strconv.Itoa(p.Args["id"].(int)))
+
+	return err == nil, err
+}
+
+func (a Delivery) restoreAuthor(p graphql.ResolveParams) (interface{}, error) {
+	if err := a.authorize(p.Context, models.PermAuthorRestore); err != nil {
+		return nil, err
+	}
+
+	_, err := a.author.Restore(p.Context, strconv.Itoa(p.Args["id"].(int)))
+
+	return err == nil, err
+}
+
+// authorize checks the caller holds permission, the route only asks for book:read
+func (a Delivery) authorize(ctx context.Context, permission string) error {
+	err := a.rbac.Authorize(ctx, permission)
+	if errors.Is(err, rbac.ErrForbidden) {
+		return errors.New("missing permission " + permission)
+	}
+
+	return err
+}
+
+func bookOf(input interface{}) models.Book {
+	fields, _ := input.(map[string]interface{})
+	author := authorOf(fields["author"])
+
+	return models.Book{
+		BookID:        intOf(fields["id"]),
+		AuthorID:      author.AuthID,
+		Auth:          author,
+		Title:         stringOf(fields["title"]),
+		Publication:   stringOf(fields["publication"]),
+		PublishedDate: stringOf(fields["publishedDate"]),
+		ISBN:          stringOf(fields["isbn"]),
+	}
+}
+
+func authorOf(input interface{}) models.Author {
+	fields, _ := input.(map[string]interface{})
+
+	return models.Author{
+		AuthID:    intOf(fields["id"]),
+		FirstName: stringOf(fields["firstName"]),
+		LastName:  stringOf(fields["lastName"]),
+		Dob:       stringOf(fields["dob"]),
+		PenName:   stringOf(fields["penName"]),
+	}
+}
+
+func intOf(v interface{}) int {
+	n, _ := v.(int)
+
+	return n
+}
+
+func stringOf(v interface{}) string {
+	s, _ := v.(string)
+
+	return s
+}
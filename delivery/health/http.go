@@ -0,0 +1,44 @@
+package health
+
+import (
+	"log/slog"
+	"net/http"
+
+	"Three-Layer-Architecture/service"
+)
+
+type Delivery struct {
+	service service.Health
+}
+
+func New(health service.Health) Delivery {
+	return Delivery{service: health}
+}
+
+// Live method is to tell the process is up, it does not check dependencies
+func (a Delivery) Live(w http.ResponseWriter, r *http.Request) {
+	write(w, http.StatusOK, "ok")
+}
+
+// Ready method is to tell the server can take requests
+func (a Delivery) Ready(w http.ResponseWriter, r *http.Request) {
+	if err := a.service.Ready(r.Context()); err != nil {
+		slog.WarnContext(r.Context(), "not ready", "error", err)
+
+		write(w, http.StatusServiceUnavailable, err.Error())
+
+		return
+	}
+
+	write(w, http.StatusOK, "ok")
+}
+
+func write(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	if _, err := w.Write([]byte(body)); err != nil {
+		slog.Error("could not write response", "error", err)
+	}
+}
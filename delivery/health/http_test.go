@@ -0,0 +1,52 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/service"
+)
+
+// TestReady function is to test readiness status code
+func TestReady(t *testing.T) {
+	testcases := []struct {
+		desc               string
+		err                error
+		expectedStatusCode int
+	}{
+		{desc: "ready", expectedStatusCode: http.StatusOK},
+		{desc: "database down", err: errors.New("connection refused"), expectedStatusCode: http.StatusServiceUnavailable},
+	}
+
+	ctr := gomock.NewController(t)
+	mockHealth := service.NewMockHealth(ctr)
+	delivery := New(mockHealth)
+
+	for i, v := range testcases {
+		mockHealth.EXPECT().Ready(gomock.Any()).Return(v.err)
+
+		w := httptest.NewRecorder()
+
+		delivery.Ready(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.StatusCode, v.expectedStatusCode)
+		}
+
+		res.Body.Close()
+	}
+
+	w := httptest.NewRecorder()
+
+	delivery.Live(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("desc : live ,[TEST%d]Failed. Got %v\tExpected %v\n", len(testcases)+1, w.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// maxBody is the largest file accepted by one import
+const maxBody = 64 << 20
+
+// formats maps content types to import formats
+var formats = map[string]string{
+	"text/csv":                models.ImportCSV,
+	"application/x-ndjson":    models.ImportNDJSON,
+	"application/jsonl":       models.ImportNDJSON,
+	"application/marc":        models.ImportMARC,
+	"application/marcxml+xml": models.ImportMARCXML,
+}
+
+type Delivery struct {
+	service service.Import
+}
+
+func New(importer service.Import) Delivery {
+	return Delivery{service: importer}
+}
+
+// Import method is to store books and authors of a CSV or NDJSON body.
+// Format is taken from format query or Content-Type, dryRun query only validates rows and batchSize query sets rows per transaction.
+func (a Delivery) Import(w http.ResponseWriter, r *http.Request) {
+	opts, err := options(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	result, err := a.service.Import(r.Context(), http.MaxBytesReader(w, r.Body, maxBody), opts)
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		writeError(err, w)
+
+		return
+	}
+
+	if err != nil {
+		// earlier batches are stored, the result tells which rows
+		slog.ErrorContext(r.Context(), "import stopped", "rows", result.Rows, "imported", result.Imported, "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		writeError(err, w)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err = w.Write(body); err != nil {
+		slog.ErrorContext(r.Context(), "could not write response", "error", err)
+	}
+}
+
+func options(r *http.Request) (models.ImportOptions, error) {
+	query := r.URL.Query()
+
+	opts := models.ImportOptions{Format: query.Get("format")}
+
+	if opts.Format == "" {
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			return models.ImportOptions{}, errors.New("format query or Content-Type is needed")
+		}
+
+		var ok bool
+		if opts.Format, ok = formats[mediaType]; !ok {
+			return models.ImportOptions{}, errors.New("unsupported Content-Type " + mediaType)
+		}
+	}
+
+	if dryRun := query.Get("dryRun"); dryRun != "" {
+		var err error
+		if opts.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			return models.ImportOptions{}, errors.New("invalid dryRun")
+		}
+	}
+
+	if batchSize := query.Get("batchSize"); batchSize != "" {
+		var err error
+		if opts.BatchSize, err = strconv.Atoi(batchSize); err != nil || opts.BatchSize <= 0 {
+			return models.ImportOptions{}, errors.New("invalid batchSize")
+		}
+	}
+
+	return opts, nil
+}
+
+func writeError(err error, w http.ResponseWriter) {
+	_, errs := w.Write([]byte(err.Error()))
+	if errs != nil {
+		slog.Error("could not write response", "error", errs)
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+}
@@ -0,0 +1,88 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// TestImport function is to test format and options are read from the request
+func TestImport(t *testing.T) {
+	result := models.ImportResult{Rows: 2, Valid: 1, Imported: 1, Failed: 1,
+		Errors: []models.ImportError{{Row: 2, BookID: 2, Error: "invalid publication"}}}
+
+	testcases := []struct {
+		desc               string
+		target             string
+		contentType        string
+		opts               *models.ImportOptions
+		resp               models.ImportResult
+		err                error
+		expectedStatusCode int
+	}{
+		{desc: "csv", target: "/import", contentType: "text/csv; charset=utf-8",
+			opts: &models.ImportOptions{Format: models.ImportCSV}, resp: result, expectedStatusCode: http.StatusOK},
+		{desc: "ndjson dry run", target: "/import?dryRun=true&batchSize=50", contentType: "application/x-ndjson",
+			opts: &models.ImportOptions{Format: models.ImportNDJSON, DryRun: true, BatchSize: 50}, resp: result,
+			expectedStatusCode: http.StatusOK},
+		{desc: "format query", target: "/import?format=csv", opts: &models.ImportOptions{Format: models.ImportCSV},
+			resp: result, expectedStatusCode: http.StatusOK},
+		{desc: "missing format", target: "/import", expectedStatusCode: http.StatusBadRequest},
+		{desc: "unsupported content type", target: "/import", contentType: "application/xml",
+			expectedStatusCode: http.StatusBadRequest},
+		{desc: "invalid batch size", target: "/import?batchSize=0", contentType: "text/csv",
+			expectedStatusCode: http.StatusBadRequest},
+		{desc: "invalid dry run", target: "/import?dryRun=maybe", contentType: "text/csv",
+			expectedStatusCode: http.StatusBadRequest},
+		{desc: "error from svc", target: "/import", contentType: "text/csv",
+			opts: &models.ImportOptions{Format: models.ImportCSV}, err: errors.New("missing header"),
+			expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockImport := service.NewMockImport(ctr)
+		delivery := New(mockImport)
+
+		if v.opts != nil {
+			mockImport.EXPECT().Import(gomock.Any(), gomock.Any(), *v.opts).Return(v.resp, v.err)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, v.target, strings.NewReader("rows"))
+		if v.contentType != "" {
+			req.Header.Set("Content-Type", v.contentType)
+		}
+
+		w := httptest.NewRecorder()
+
+		delivery.Import(w, req)
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.StatusCode, v.expectedStatusCode)
+		}
+
+		if v.expectedStatusCode == http.StatusOK {
+			body, _ := io.ReadAll(res.Body)
+
+			var resp models.ImportResult
+			if err := json.Unmarshal(body, &resp); err != nil || !reflect.DeepEqual(resp, v.resp) {
+				t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+			}
+		}
+
+		res.Body.Close()
+		ctr.Finish()
+	}
+}
@@ -0,0 +1,324 @@
+package oai
+
+import (
+	"encoding/xml"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"Three-Layer-Architecture/citation"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+const (
+	namespace      = "http://www.openarchives.org/OAI/2.0/"
+	schemaLocation = namespace + " http://www.openarchives.org/OAI/2.0/OAI-PMH.xsd"
+	datestamp      = "2006-01-02T15:04:05Z"
+)
+
+// arguments each verb accepts, true when required
+var arguments = map[string]map[string]bool{
+	"Identify":            {},
+	"ListMetadataFormats": {"identifier": false},
+	"ListSets":            {"resumptionToken": false},
+	"GetRecord":           {"identifier": true, "metadataPrefix": true},
+	"ListIdentifiers":     {"metadataPrefix": true, "from": false, "until": false, "set": false, "resumptionToken": false},
+	"ListRecords":         {"metadataPrefix": true, "from": false, "until": false, "set": false, "resumptionToken": false},
+}
+
+type Delivery struct {
+	service service.OAI
+	baseURL string
+	now     func() time.Time
+}
+
+// New returns the handler of the repository at baseURL, it is echoed in every response
+func New(oai service.OAI, baseURL string) Delivery {
+	return Delivery{service: oai, baseURL: baseURL, now: time.Now}
+}
+
+type response struct {
+	XMLName        xml.Name `xml:"OAI-PMH"`
+	Xmlns          string   `xml:"xmlns,attr"`
+	XmlnsXSI       string   `xml:"xmlns:xsi,attr"`
+	SchemaLocation string   `xml:"xsi:schemaLocation,attr"`
+	ResponseDate   string   `xml:"responseDate"`
+	Request        request  `xml:"request"`
+	Errors         []oaiError
+
+	Identify            *identify        `xml:"Identify,omitempty"`
+	ListMetadataFormats *metadataFormats `xml:"ListMetadataFormats,omitempty"`
+	GetRecord           *records         `xml:"GetRecord,omitempty"`
+	ListIdentifiers     *identifiers     `xml:"ListIdentifiers,omitempty"`
+	ListRecords         *records         `xml:"ListRecords,omitempty"`
+}
+
+type request struct {
+	Attrs   []xml.Attr `xml:",any,attr"`
+	BaseURL string     `xml:",chardata"`
+}
+
+type oaiError struct {
+	XMLName xml.Name `xml:"error"`
+	Code    string   `xml:"code,attr"`
+	Message string   `xml:",chardata"`
+}
+
+type identify struct {
+	RepositoryName    string `xml:"repositoryName"`
+	BaseURL           string `xml:"baseURL"`
+	ProtocolVersion   string `xml:"protocolVersion"`
+	AdminEmail        string `xml:"adminEmail"`
+	EarliestDatestamp string `xml:"earliestDatestamp"`
+	DeletedRecord     string `xml:"deletedRecord"`
+	Granularity       string `xml:"granularity"`
+}
+
+type metadataFormats struct {
+	Formats []metadataFormat `xml:"metadataFormat"`
+}
+
+type metadataFormat struct {
+	Prefix    string `xml:"metadataPrefix"`
+	Schema    string `xml:"schema"`
+	Namespace string `xml:"metadataNamespace"`
+}
+
+type header struct {
+	Status     string `xml:"status,attr,omitempty"`
+	Identifier string `xml:"identifier"`
+	Datestamp  string `xml:"datestamp"`
+}
+
+type record struct {
+	Header   header    `xml:"header"`
+	Metadata *metadata `xml:"metadata,omitempty"`
+}
+
+type metadata struct {
+	DC citation.DC
+}
+
+type resumptionToken struct {
+	Cursor int    `xml:"cursor,attr"`
+	Token  string `xml:",chardata"`
+}
+
+type identifiers struct {
+	Headers []header         `xml:"header"`
+	Token   *resumptionToken `xml:"resumptionToken,omitempty"`
+}
+
+type records struct {
+	Records []record         `xml:"record"`
+	Token   *resumptionToken `xml:"resumptionToken,omitempty"`
+}
+
+// Handle method is to answer OAI-PMH requests, arguments come from query or an urlencoded POST body.
+// Protocol errors are part of the XML response, which is always sent with status 200.
+func (a Delivery) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resp := response{Xmlns: namespace, XmlnsXSI: "http://www.w3.org/2001/XMLSchema-instance", SchemaLocation: schemaLocation,
+		ResponseDate: a.now().UTC().Format(datestamp), Request: request{BaseURL: a.baseURL}}
+
+	args, err := readArguments(r)
+	if err == nil {
+		// request echoes the arguments only when they are valid
+		resp.Request.Attrs = attrs(args)
+		err = a.answer(r, args, &resp)
+	}
+
+	var oaiErr models.OAIError
+
+	switch {
+	case errors.As(err, &oaiErr):
+		resp.Errors = []oaiError{{Code: oaiErr.Code, Message: oaiErr.Message}}
+		if oaiErr.Code == models.OAIBadVerb || oaiErr.Code == models.OAIBadArgument {
+			resp.Request.Attrs = nil
+		}
+	case err != nil:
+		slog.ErrorContext(ctx, "oai request failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		writeError(errors.New("could not answer request"), w)
+
+		return
+	}
+
+	body, err := xml.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		writeError(err, w)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err = w.Write(append([]byte(xml.Header), body...)); err != nil {
+		slog.ErrorContext(ctx, "could not write response", "error", err)
+	}
+}
+
+func (a Delivery) answer(r *http.Request, args map[string]string, resp *response) error {
+	ctx := r.Context()
+
+	switch args["verb"] {
+	case "Identify":
+		repo, err := a.service.Identify(ctx)
+		if err != nil {
+			return err
+		}
+
+		resp.Identify = &identify{RepositoryName: repo.Name, BaseURL: repo.BaseURL, ProtocolVersion: "2.0",
+			AdminEmail: repo.AdminEmail, EarliestDatestamp: repo.EarliestDatestamp.UTC().Format(datestamp),
+			DeletedRecord: "transient", Granularity: "YYYY-MM-DDThh:mm:ssZ"}
+	case "ListMetadataFormats":
+		formats, err := a.service.MetadataFormats(ctx, args["identifier"])
+		if err != nil {
+			return err
+		}
+
+		resp.ListMetadataFormats = &metadataFormats{}
+		for _, f := range formats {
+			resp.ListMetadataFormats.Formats = append(resp.ListMetadataFormats.Formats, metadataFormat(f))
+		}
+	case "ListSets":
+		if args["resumptionToken"] != "" {
+			return models.OAIError{Code: models.OAIBadResumptionToken, Message: "invalid resumptionToken"}
+		}
+
+		return a.service.Sets(ctx)
+	case "GetRecord":
+		rec, err := a.service.GetRecord(ctx, args["identifier"], args["metadataPrefix"])
+		if err != nil {
+			return err
+		}
+
+		resp.GetRecord = &records{Records: []record{a.record(rec)}}
+	default:
+		page, err := a.service.List(ctx, models.OAIList{MetadataPrefix: args["metadataPrefix"], From: args["from"],
+			Until: args["until"], Set: args["set"], Token: args["resumptionToken"]})
+		if err != nil {
+			return err
+		}
+
+		var token *resumptionToken
+		if page.Token != "" || page.Resumed {
+			token = &resumptionToken{Cursor: page.Cursor, Token: page.Token}
+		}
+
+		if args["verb"] == "ListIdentifiers" {
+			resp.ListIdentifiers = &identifiers{Token: token}
+			for _, rec := range page.Records {
+				resp.ListIdentifiers.Headers = append(resp.ListIdentifiers.Headers, a.header(rec))
+			}
+
+			return nil
+		}
+
+		resp.ListRecords = &records{Token: token}
+		for _, rec := range page.Records {
+			resp.ListRecords.Records = append(resp.ListRecords.Records, a.record(rec))
+		}
+	}
+
+	return nil
+}
+
+func (a Delivery) header(rec models.OAIRecord) header {
+	h := header{Identifier: a.service.Identifier(rec.Book.BookID), Datestamp: rec.Datestamp.UTC().Format(datestamp)}
+	if rec.Deleted {
+		h.Status = "deleted"
+	}
+
+	return h
+}
+
+// record has no metadata when the book is deleted
+func (a Delivery) record(rec models.OAIRecord) record {
+	out := record{Header: a.header(rec)}
+	if !rec.Deleted {
+		out.Metadata = &metadata{DC: citation.DublinCore(rec.Book)}
+	}
+
+	return out
+}
+
+// readArguments checks the verb and its arguments, a resumptionToken excludes every other argument
+func readArguments(r *http.Request) (map[string]string, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, models.OAIError{Code: models.OAIBadArgument, Message: err.Error()}
+	}
+
+	verbs := r.Form["verb"]
+	if len(verbs) != 1 {
+		return nil, models.OAIError{Code: models.OAIBadVerb, Message: "exactly one verb is required"}
+	}
+
+	allowed, ok := arguments[verbs[0]]
+	if !ok {
+		return nil, models.OAIError{Code: models.OAIBadVerb, Message: "unknown verb " + verbs[0]}
+	}
+
+	args := map[string]string{}
+
+	for name, values := range r.Form {
+		if _, ok := allowed[name]; !ok && name != "verb" {
+			return nil, models.OAIError{Code: models.OAIBadArgument, Message: "illegal argument " + name}
+		}
+
+		if len(values) != 1 {
+			return nil, models.OAIError{Code: models.OAIBadArgument, Message: "repeated argument " + name}
+		}
+
+		args[name] = values[0]
+	}
+
+	if _, ok := args["resumptionToken"]; ok {
+		if len(args) != 2 {
+			return nil, models.OAIError{Code: models.OAIBadArgument, Message: "resumptionToken is exclusive"}
+		}
+
+		return args, nil
+	}
+
+	for name, required := range allowed {
+		if _, ok := args[name]; required && !ok {
+			return nil, models.OAIError{Code: models.OAIBadArgument, Message: "missing argument " + name}
+		}
+	}
+
+	return args, nil
+}
+
+// attrs gives arguments in a stable order
+func attrs(args map[string]string) []xml.Attr {
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	out := make([]xml.Attr, 0, len(names))
+	for _, name := range names {
+		out = append(out, xml.Attr{Name: xml.Name{Local: name}, Value: args[name]})
+	}
+
+	return out
+}
+
+func writeError(err error, w http.ResponseWriter) {
+	_, errs := w.Write([]byte(err.Error()))
+	if errs != nil {
+		slog.Error("could not write response", "error", errs)
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+}
@@ -0,0 +1,142 @@
+package oai
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+const baseURL = "http://localhost:8000/oai"
+
+func setup(t *testing.T) (*service.MockOAI, Delivery) {
+	ctr := gomock.NewController(t)
+	mockOAI := service.NewMockOAI(ctr)
+	mockOAI.EXPECT().Identifier(gomock.Any()).DoAndReturn(func(id int) string {
+		return "oai:localhost:book/" + strconv.Itoa(id)
+	}).AnyTimes()
+
+	delivery := New(mockOAI, baseURL)
+	delivery.now = func() time.Time { return time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC) }
+
+	return mockOAI, delivery
+}
+
+func serve(delivery Delivery, req *http.Request) (int, string) {
+	w := httptest.NewRecorder()
+
+	delivery.Handle(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(res.Body)
+
+	return res.StatusCode, string(body)
+}
+
+// TestHandle function is to test each verb is answered and arguments are echoed
+func TestHandle(t *testing.T) {
+	stamp := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	book := models.Book{BookID: 1, AuthorID: 1,
+		Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
+		Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}
+
+	testcases := []struct {
+		desc     string
+		query    string
+		mock     func(m *service.MockOAI)
+		contains []string
+	}{
+		{desc: "identify", query: "verb=Identify", mock: func(m *service.MockOAI) {
+			m.EXPECT().Identify(gomock.Any()).Return(models.OAIRepository{Name: "Library", BaseURL: baseURL,
+				AdminEmail: "admin@localhost", EarliestDatestamp: stamp}, nil)
+		}, contains: []string{`<request verb="Identify">` + baseURL + `</request>`, "<repositoryName>Library</repositoryName>",
+			"<earliestDatestamp>2024-05-01T10:00:00Z</earliestDatestamp>", "<deletedRecord>transient</deletedRecord>",
+			"<responseDate>2024-06-01T12:00:00Z</responseDate>"}},
+		{desc: "metadata formats", query: "verb=ListMetadataFormats", mock: func(m *service.MockOAI) {
+			m.EXPECT().MetadataFormats(gomock.Any(), "").Return([]models.OAIMetadataFormat{{Prefix: "oai_dc"}}, nil)
+		}, contains: []string{"<metadataPrefix>oai_dc</metadataPrefix>"}},
+		{desc: "sets", query: "verb=ListSets", mock: func(m *service.MockOAI) {
+			m.EXPECT().Sets(gomock.Any()).Return(models.OAIError{Code: models.OAINoSetHierarchy, Message: "sets are not supported"})
+		}, contains: []string{`<error code="noSetHierarchy">sets are not supported</error>`}},
+		{desc: "get record", query: "verb=GetRecord&identifier=oai:localhost:book/1&metadataPrefix=oai_dc",
+			mock: func(m *service.MockOAI) {
+				m.EXPECT().GetRecord(gomock.Any(), "oai:localhost:book/1", "oai_dc").
+					Return(models.OAIRecord{Book: book, Datestamp: stamp}, nil)
+			}, contains: []string{`<request identifier="oai:localhost:book/1" metadataPrefix="oai_dc" verb="GetRecord">`,
+				"<identifier>oai:localhost:book/1</identifier><datestamp>2024-05-01T10:00:00Z</datestamp>",
+				"<dc:title>2 States</dc:title>"}},
+		{desc: "list records with token", query: "verb=ListRecords&metadataPrefix=oai_dc&from=2024-01-01",
+			mock: func(m *service.MockOAI) {
+				m.EXPECT().List(gomock.Any(), models.OAIList{MetadataPrefix: "oai_dc", From: "2024-01-01"}).
+					Return(models.OAIPage{Records: []models.OAIRecord{{Book: book, Datestamp: stamp},
+						{Book: models.Book{BookID: 2}, Datestamp: stamp, Deleted: true}}, Token: "next"}, nil)
+			}, contains: []string{`<header status="deleted"><identifier>oai:localhost:book/2</identifier>`,
+				`<resumptionToken cursor="0">next</resumptionToken>`, "<dc:creator>Bhagat, Chetan</dc:creator>"}},
+		{desc: "last page of identifiers", query: "verb=ListIdentifiers&resumptionToken=next",
+			mock: func(m *service.MockOAI) {
+				m.EXPECT().List(gomock.Any(), models.OAIList{Token: "next"}).Return(models.OAIPage{
+					Records: []models.OAIRecord{{Book: book, Datestamp: stamp}}, Cursor: 2, Resumed: true}, nil)
+			}, contains: []string{"<ListIdentifiers><header><identifier>oai:localhost:book/1</identifier>",
+				`<resumptionToken cursor="2"></resumptionToken>`}},
+		{desc: "error of service", query: "verb=ListRecords&metadataPrefix=marc21", mock: func(m *service.MockOAI) {
+			m.EXPECT().List(gomock.Any(), gomock.Any()).Return(models.OAIPage{},
+				models.OAIError{Code: models.OAICannotDisseminateFormat, Message: "unsupported metadataPrefix marc21"})
+		}, contains: []string{`<request metadataPrefix="marc21" verb="ListRecords">`,
+			`<error code="cannotDisseminateFormat">`}},
+		{desc: "bad verb", query: "verb=Harvest", contains: []string{"<request>" + baseURL + "</request>",
+			`<error code="badVerb">unknown verb Harvest</error>`}},
+		{desc: "missing verb", query: "", contains: []string{`<error code="badVerb">`}},
+		{desc: "illegal argument", query: "verb=Identify&set=a", contains: []string{`<error code="badArgument">illegal argument set</error>`}},
+		{desc: "repeated argument", query: "verb=GetRecord&identifier=a&identifier=b&metadataPrefix=oai_dc",
+			contains: []string{`<error code="badArgument">repeated argument identifier</error>`}},
+		{desc: "missing argument", query: "verb=GetRecord&identifier=a",
+			contains: []string{`<error code="badArgument">missing argument metadataPrefix</error>`}},
+		{desc: "token is exclusive", query: "verb=ListRecords&resumptionToken=next&metadataPrefix=oai_dc",
+			contains: []string{`<error code="badArgument">resumptionToken is exclusive</error>`}},
+	}
+
+	for i, v := range testcases {
+		mockOAI, delivery := setup(t)
+		if v.mock != nil {
+			v.mock(mockOAI)
+		}
+
+		status, body := serve(delivery, httptest.NewRequest(http.MethodGet, "/oai?"+v.query, nil))
+
+		if status != http.StatusOK {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, status, http.StatusOK)
+		}
+
+		for _, c := range v.contains {
+			if !strings.Contains(body, c) {
+				t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, body, c)
+			}
+		}
+	}
+}
+
+// TestHandle_Post function is to test arguments of urlencoded POST bodies
+func TestHandle_Post(t *testing.T) {
+	mockOAI, delivery := setup(t)
+	mockOAI.EXPECT().MetadataFormats(gomock.Any(), "oai:localhost:book/1").
+		Return([]models.OAIMetadataFormat{{Prefix: "oai_dc"}}, nil)
+
+	form := url.Values{"verb": {"ListMetadataFormats"}, "identifier": {"oai:localhost:book/1"}}
+	req := httptest.NewRequest(http.MethodPost, "/oai", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if _, body := serve(delivery, req); !strings.Contains(body, "<metadataPrefix>oai_dc</metadataPrefix>") {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "post", 1, body, "oai_dc")
+	}
+}
@@ -0,0 +1,104 @@
+package rbac
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"Three-Layer-Architecture/service"
+)
+
+type Delivery struct {
+	service service.RBAC
+}
+
+func New(rbac service.RBAC) Delivery {
+	return Delivery{service: rbac}
+}
+
+// GetRoles method is to list roles with their permissions
+func (a Delivery) GetRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := a.service.GetRoles(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, roles)
+}
+
+// GetAssignments method is to list roles assigned to a principal
+func (a Delivery) GetAssignments(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	roles, err := a.service.GetAssignments(r.Context(), vars["principal"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, roles)
+}
+
+// Assign method is to give a role to a principal
+func (a Delivery) Assign(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := a.service.Assign(r.Context(), vars["principal"], vars["role"]); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	slog.InfoContext(r.Context(), "role assigned")
+}
+
+// Unassign method is to take a role away from a principal
+func (a Delivery) Unassign(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := a.service.Unassign(r.Context(), vars["principal"], vars["role"]); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	slog.InfoContext(r.Context(), "role unassigned")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		writeError(err, w)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if _, err = w.Write(body); err != nil {
+		slog.Error("could not write response", "error", err)
+	}
+}
+
+func writeError(err error, w http.ResponseWriter) {
+	_, errs := w.Write([]byte(err.Error()))
+	if errs != nil {
+		slog.Error("could not write response", "error", errs)
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+}
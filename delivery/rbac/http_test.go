@@ -0,0 +1,48 @@
+package rbac
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+
+	"Three-Layer-Architecture/service"
+)
+
+// TestAssignRole function is to test assigning roles
+func TestAssignRole(t *testing.T) {
+	testcases := []struct {
+		desc               string
+		role               string
+		expectedStatusCode int
+		err                error
+	}{
+		{desc: "valid", role: "cataloguer", expectedStatusCode: http.StatusNoContent},
+		{desc: "error from svc", role: "janitor", expectedStatusCode: http.StatusBadRequest, err: errors.New("invalid role")},
+	}
+
+	ctr := gomock.NewController(t)
+	mockRBAC := service.NewMockRBAC(ctr)
+	delivery := New(mockRBAC)
+
+	for i, v := range testcases {
+		req := httptest.NewRequest(http.MethodPut, "/principals/u-2/roles/"+v.role, nil)
+		req = mux.SetURLVars(req, map[string]string{"principal": "u-2", "role": v.role})
+		w := httptest.NewRecorder()
+
+		mockRBAC.EXPECT().Assign(gomock.Any(), "u-2", v.role).Return(v.err)
+
+		delivery.Assign(w, req)
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.StatusCode, v.expectedStatusCode)
+		}
+
+		res.Body.Close()
+	}
+}
@@ -0,0 +1,572 @@
+// Package sip2 serves self-service kiosks over SIP2 on TCP, each connection logs in as a user with loan:manage
+package sip2
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/principal"
+	"Three-Layer-Architecture/service"
+	serviceloan "Three-Layer-Architecture/service/loan"
+	serviceuser "Three-Layer-Architecture/service/user"
+	"Three-Layer-Architecture/sip2"
+)
+
+// supported are the messages of the ACS status BX field in protocol order, from patron status to renew all
+const supported = "YYYNYYYYYNYNNNYN"
+
+// maxMessage is the longest message read, longer ones are answered with a resend request and end the connection
+const maxMessage = 4096
+
+var (
+	// ErrNotLoggedIn ends connections which send other messages before a successful login
+	ErrNotLoggedIn = errors.New("sip2 login required")
+	// ErrMessageTooLong ends connections which send a message over maxMessage bytes
+	ErrMessageTooLong = errors.New("sip2 message too long")
+
+	// errUnsupported is answered with a resend request, the connection stays open
+	errUnsupported = errors.New("unsupported sip2 message")
+)
+
+// Config describes the library to kiosks
+type Config struct {
+	InstitutionID string
+	LibraryName   string
+	// Location is the permanent location of books
+	Location string
+	// Timeout closes connections idle for longer, zero keeps them open
+	Timeout time.Duration
+}
+
+type Server struct {
+	user   service.User
+	rbac   service.RBAC
+	book   service.Book
+	loan   service.Loan
+	config Config
+	now    func() time.Time
+}
+
+func New(user service.User, rbac service.RBAC, book service.Book, loan service.Loan, config Config) Server {
+	return Server{user: user, rbac: rbac, book: book, loan: loan, config: config, now: time.Now}
+}
+
+// session is the state of one kiosk connection
+type session struct {
+	// ctx carries the kiosk account after login
+	ctx  context.Context
+	last string
+}
+
+// Serve method is to accept kiosk connections until the listener is closed
+func (s Server) Serve(ctx context.Context, l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			defer conn.Close()
+
+			if err := s.Session(ctx, conn); err != nil {
+				slog.WarnContext(ctx, "sip2 connection closed", "remote", conn.RemoteAddr().String(), "error", err)
+			}
+		}()
+	}
+}
+
+// Session method is to answer messages read from rw until it is closed, a message is answered before the next is read
+func (s Server) Session(ctx context.Context, rw io.ReadWriter) error {
+	r := bufio.NewReaderSize(rw, maxMessage)
+	sess := &session{}
+
+	for {
+		if d, ok := rw.(interface{ SetReadDeadline(time.Time) error }); ok && s.config.Timeout > 0 {
+			if err := d.SetReadDeadline(s.now().Add(s.config.Timeout)); err != nil {
+				return err
+			}
+		}
+
+		raw, err := r.ReadSlice('\r')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			// the rest of the message is not read, the connection can not be resynchronised
+			_, _ = io.WriteString(rw, sip2.Message{Code: sip2.RequestSCResend, Seq: -1}.Encode())
+
+			return fmt.Errorf("%w : over %d bytes", ErrMessageTooLong, maxMessage)
+		}
+
+		line := string(raw)
+		if errors.Is(err, io.EOF) && strings.TrimSpace(line) == "" {
+			return nil
+		}
+
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		// some kiosks end messages with CR LF
+		if line = strings.Trim(line, "\r\n"); line == "" {
+			continue
+		}
+
+		out, err := s.reply(ctx, sess, line)
+		if err != nil {
+			return err
+		}
+
+		if _, err = io.WriteString(rw, out); err != nil {
+			return err
+		}
+	}
+}
+
+func (s Server) reply(ctx context.Context, sess *session, line string) (string, error) {
+	req, err := sip2.Parse(line)
+	if err != nil {
+		slog.WarnContext(ctx, "sip2 message rejected", "error", err)
+
+		return sip2.Message{Code: sip2.RequestSCResend, Seq: -1}.Encode(), nil
+	}
+
+	// the last response is sent again as it was
+	if req.Code == sip2.RequestACSResend && sess.last != "" {
+		return sess.last, nil
+	}
+
+	resp, err := s.answer(ctx, sess, req)
+	if errors.Is(err, errUnsupported) {
+		slog.WarnContext(ctx, "sip2 message rejected", "error", err)
+
+		return sip2.Message{Code: sip2.RequestSCResend, Seq: -1}.Encode(), nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	resp.Seq = req.Seq
+	sess.last = resp.Encode()
+
+	return sess.last, nil
+}
+
+func (s Server) answer(ctx context.Context, sess *session, req sip2.Message) (sip2.Message, error) {
+	if req.Code == sip2.Login {
+		return s.login(ctx, sess, req)
+	}
+
+	if sess.ctx == nil {
+		return sip2.Message{}, fmt.Errorf("%w : got message %v", ErrNotLoggedIn, req.Code)
+	}
+
+	switch req.Code {
+	case sip2.SCStatus:
+		return s.status(), nil
+	case sip2.PatronStatus, sip2.PatronInformation:
+		return s.patron(sess.ctx, req)
+	case sip2.ItemInformation:
+		return s.item(sess.ctx, req)
+	case sip2.Checkout:
+		return s.checkout(sess.ctx, req)
+	case sip2.Checkin:
+		return s.checkin(sess.ctx, req)
+	case sip2.Renew:
+		return s.renew(sess.ctx, req)
+	case sip2.EndSession:
+		return sip2.Message{Code: sip2.EndSessionResponse, Fixed: "Y" + sip2.Time(s.now()),
+			Fields: []sip2.Field{{ID: "AO", Value: s.config.InstitutionID}, {ID: "AA", Value: req.Get("AA")}}}, nil
+	default:
+		return sip2.Message{}, fmt.Errorf("%w %v", errUnsupported, req.Code)
+	}
+}
+
+// login checks the kiosk account, later messages are served on its behalf
+func (s Server) login(ctx context.Context, sess *session, req sip2.Message) (sip2.Message, error) {
+	resp := sip2.Message{Code: sip2.LoginResponse, Fixed: sip2.Flag(false)}
+
+	user, err := s.user.Verify(ctx, req.Get("CN"), req.Get("CO"))
+	if errors.Is(err, serviceuser.ErrInvalidCredentials) || errors.Is(err, serviceuser.ErrLocked) {
+		slog.WarnContext(ctx, "sip2 login failed", "user", req.Get("CN"), "error", err)
+
+		return resp, nil
+	}
+
+	if err != nil {
+		return sip2.Message{}, err
+	}
+
+	kiosk := principal.NewContext(ctx, models.Principal{ID: models.PrincipalID(models.PrincipalUser, user.Username),
+		Name: user.Username, Kind: models.PrincipalUser})
+
+	if err = s.rbac.Authorize(kiosk, models.PermLoanManage); err != nil {
+		slog.WarnContext(ctx, "sip2 login failed", "user", user.Username, "error", err)
+
+		return resp, nil
+	}
+
+	sess.ctx = kiosk
+	resp.Fixed = sip2.Flag(true)
+
+	return resp, nil
+}
+
+func (s Server) status() sip2.Message {
+	// online, checkin, checkout and renewal allowed, no status update or offline use, timeout and retries
+	return sip2.Message{Code: sip2.ACSStatus, Fixed: "YYYYNN" + "030" + "003" + sip2.Time(s.now()) + "2.00",
+		Fields: []sip2.Field{{ID: "AO", Value: s.config.InstitutionID}, {ID: "AM", Value: s.config.LibraryName},
+			{ID: "BX", Value: supported}}}
+}
+
+// patronAccount is a patron of a request with the loans the status is computed from
+type patronAccount struct {
+	user models.User
+	// found is false for unknown patrons, password is Y or N when a password was sent
+	found    bool
+	password string
+	loans    []models.Loan
+	overdue  []models.Loan
+}
+
+func (s Server) lookupPatron(ctx context.Context, req sip2.Message) (patronAccount, error) {
+	var p patronAccount
+
+	user, err := s.user.GetByUsername(ctx, req.Get("AA"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return p, nil
+	}
+
+	if err != nil {
+		return p, err
+	}
+
+	p.user, p.found = user, true
+
+	if pwd, ok := req.Lookup("AD"); ok {
+		_, err = s.user.Verify(ctx, user.Username, pwd)
+
+		switch {
+		case err == nil:
+			p.password = "Y"
+		case errors.Is(err, serviceuser.ErrInvalidCredentials) || errors.Is(err, serviceuser.ErrLocked):
+			p.password = "N"
+		default:
+			return p, err
+		}
+	}
+
+	if p.loans, err = s.loan.Loans(ctx, user.ID); err != nil {
+		return p, err
+	}
+
+	now := s.now()
+
+	for _, l := range p.loans {
+		if l.DueAt.Before(now) {
+			p.overdue = append(p.overdue, l)
+		}
+	}
+
+	return p, nil
+}
+
+// valid tells if the patron may borrow on this request
+func (p patronAccount) valid() bool {
+	return p.found && p.password != "N"
+}
+
+// patronStatus is the 14 character patron status, Y marks charge and renewal denied, too many items and overdue items
+func (s Server) patronStatus(p patronAccount) string {
+	status := []byte(strings.Repeat(" ", 14))
+
+	if !p.found || p.user.LockedUntil != nil && s.now().Before(*p.user.LockedUntil) {
+		status[0], status[1] = 'Y', 'Y'
+	}
+
+	if len(p.loans) >= s.loan.Policy().MaxLoans {
+		status[0], status[5] = 'Y', 'Y'
+	}
+
+	if len(p.overdue) > 0 {
+		status[0], status[6] = 'Y', 'Y'
+	}
+
+	return string(status)
+}
+
+// patron answers patron status and patron information requests
+func (s Server) patron(ctx context.Context, req sip2.Message) (sip2.Message, error) {
+	p, err := s.lookupPatron(ctx, req)
+	if err != nil {
+		return sip2.Message{}, err
+	}
+
+	language := req.Fixed[:3]
+
+	fields := []sip2.Field{{ID: "AO", Value: s.config.InstitutionID}, {ID: "AA", Value: req.Get("AA")},
+		{ID: "AE", Value: p.user.Username}, {ID: "BL", Value: sip2.YN(p.found)}}
+	if p.password != "" {
+		fields = append(fields, sip2.Field{ID: "CQ", Value: p.password})
+	}
+
+	if req.Code == sip2.PatronStatus {
+		return sip2.Message{Code: sip2.PatronStatusResponse, Fixed: s.patronStatus(p) + language + sip2.Time(s.now()),
+			Fields: fields}, nil
+	}
+
+	counts := fmt.Sprintf("%04d%04d%04d%04d%04d%04d", 0, len(p.overdue), len(p.loans), 0, 0, 0)
+	fields = append(fields, sip2.Field{ID: "CB", Value: fmt.Sprintf("%04d", s.loan.Policy().MaxLoans)})
+
+	// the summary asks for one list, overdue items at position 2 and charged items at position 3
+	summary := req.Fixed[21:31]
+
+	switch {
+	case summary[1] == 'Y':
+		fields = append(fields, items("AT", p.overdue, req)...)
+	case summary[2] == 'Y':
+		fields = append(fields, items("AU", p.loans, req)...)
+	}
+
+	if p.found {
+		fields = append(fields, sip2.Field{ID: "BE", Value: p.user.Email})
+	}
+
+	return sip2.Message{Code: sip2.PatronInfoResponse,
+		Fixed: s.patronStatus(p) + language + sip2.Time(s.now()) + counts, Fields: fields}, nil
+}
+
+// items lists book ids of loans between the 1-based BP and BQ positions
+func items(id string, loans []models.Loan, req sip2.Message) []sip2.Field {
+	start, end := 1, len(loans)
+
+	if n, err := strconv.Atoi(req.Get("BP")); err == nil && n > start {
+		start = n
+	}
+
+	if n, err := strconv.Atoi(req.Get("BQ")); err == nil && n < end {
+		end = n
+	}
+
+	var fields []sip2.Field
+
+	for i := start; i <= end; i++ {
+		fields = append(fields, sip2.Field{ID: id, Value: strconv.Itoa(loans[i-1].BookID)})
+	}
+
+	return fields
+}
+
+// lookupBook finds the book of the AB field, found is false for unknown or malformed ids
+func (s Server) lookupBook(ctx context.Context, req sip2.Message) (models.Book, bool, error) {
+	id, err := strconv.Atoi(req.Get("AB"))
+	if err != nil || id <= 0 {
+		return models.Book{}, false, nil
+	}
+
+	book, err := s.book.Getbyid(ctx, strconv.Itoa(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Book{}, false, nil
+	}
+
+	return book, err == nil, err
+}
+
+func (s Server) item(ctx context.Context, req sip2.Message) (sip2.Message, error) {
+	book, found, err := s.lookupBook(ctx, req)
+	if err != nil {
+		return sip2.Message{}, err
+	}
+
+	// other, security marker none, fee type other
+	circulation := "01"
+	fields := []sip2.Field{{ID: "AB", Value: req.Get("AB")}, {ID: "AJ", Value: book.Title}}
+
+	if found {
+		loan, err := s.loan.Current(ctx, book.BookID)
+
+		switch {
+		case err == nil:
+			circulation = "04"
+			fields = append(fields, sip2.Field{ID: "AH", Value: sip2.Time(loan.DueAt)})
+		case errors.Is(err, serviceloan.ErrNotOnLoan):
+			circulation = "03"
+		default:
+			return sip2.Message{}, err
+		}
+
+		fields = append(fields, sip2.Field{ID: "CK", Value: "001"}, sip2.Field{ID: "AQ", Value: s.config.Location})
+	} else {
+		fields = append(fields, sip2.Field{ID: "AF", Value: "Item not found"})
+	}
+
+	return sip2.Message{Code: sip2.ItemResponse, Fixed: circulation + "00" + "01" + sip2.Time(s.now()),
+		Fields: fields}, nil
+}
+
+// refusals are screen messages of loan errors a patron can act on
+var refusals = map[error]string{
+	serviceloan.ErrOnLoan:       "Item is already checked out",
+	serviceloan.ErrNotOnLoan:    "Item is not checked out",
+	serviceloan.ErrNotBorrower:  "Item is checked out to another patron",
+	serviceloan.ErrRenewalLimit: "Item can not be renewed again",
+	serviceloan.ErrLoanLimit:    "Too many items checked out",
+	serviceloan.ErrOverdue:      "Please return overdue items first",
+}
+
+// refusal returns the screen message of err, errors without one are returned
+func refusal(err error) (string, error) {
+	for e, msg := range refusals {
+		if errors.Is(err, e) {
+			return msg, nil
+		}
+	}
+
+	return "", err
+}
+
+// transaction carries what checkout, checkin and renew responses share
+type transaction struct {
+	ok      bool
+	renewal bool
+	book    models.Book
+	due     time.Time
+	message string
+}
+
+func (s Server) checkout(ctx context.Context, req sip2.Message) (sip2.Message, error) {
+	t, err := s.lend(ctx, req, req.Fixed[0] == 'Y')
+	if err != nil {
+		return sip2.Message{}, err
+	}
+
+	return s.loanResponse(sip2.CheckoutResponse, sip2.YN(t.ok && t.renewal), sip2.YN(t.ok), req, t), nil
+}
+
+func (s Server) renew(ctx context.Context, req sip2.Message) (sip2.Message, error) {
+	t, err := s.lend(ctx, req, true)
+	if err != nil {
+		return sip2.Message{}, err
+	}
+
+	return s.loanResponse(sip2.RenewResponse, sip2.YN(t.ok), "U", req, t), nil
+}
+
+// lend checks a book out to the patron, a book the patron already has is renewed when renewals are allowed
+func (s Server) lend(ctx context.Context, req sip2.Message, renewals bool) (transaction, error) {
+	var t transaction
+
+	p, err := s.lookupPatron(ctx, req)
+	if err != nil {
+		return t, err
+	}
+
+	if !p.valid() {
+		t.message = "Invalid patron or password"
+
+		return t, nil
+	}
+
+	book, found, err := s.lookupBook(ctx, req)
+	if err != nil || !found {
+		t.message = "Item not found"
+
+		return t, err
+	}
+
+	t.book = book
+
+	current, err := s.loan.Current(ctx, book.BookID)
+	if err != nil && !errors.Is(err, serviceloan.ErrNotOnLoan) {
+		return t, err
+	}
+
+	var loan models.Loan
+
+	switch {
+	case err == nil && current.UserID == p.user.ID && renewals:
+		t.renewal = true
+		loan, err = s.loan.Renew(ctx, book.BookID, p.user.ID)
+	case req.Code == sip2.Renew && err == nil:
+		err = serviceloan.ErrNotBorrower
+	case req.Code == sip2.Renew:
+		// renew requests never check out
+		err = serviceloan.ErrNotOnLoan
+	default:
+		loan, err = s.loan.Checkout(ctx, book.BookID, p.user.ID)
+	}
+
+	if err != nil {
+		t.message, err = refusal(err)
+
+		return t, err
+	}
+
+	t.ok, t.due = true, loan.DueAt
+
+	return t, nil
+}
+
+func (s Server) loanResponse(code, renewal, desensitize string, req sip2.Message, t transaction) sip2.Message {
+	if !t.ok {
+		desensitize = "N"
+	}
+
+	fields := []sip2.Field{{ID: "AO", Value: s.config.InstitutionID}, {ID: "AA", Value: req.Get("AA")},
+		{ID: "AB", Value: req.Get("AB")}, {ID: "AJ", Value: t.book.Title}}
+	if t.ok {
+		fields = append(fields, sip2.Field{ID: "AH", Value: sip2.Time(t.due)})
+	}
+
+	if t.message != "" {
+		fields = append(fields, sip2.Field{ID: "AF", Value: t.message})
+	}
+
+	// magnetic media is unknown
+	return sip2.Message{Code: code, Fixed: sip2.Flag(t.ok) + renewal + "U" + desensitize + sip2.Time(s.now()),
+		Fields: fields}
+}
+
+func (s Server) checkin(ctx context.Context, req sip2.Message) (sip2.Message, error) {
+	book, found, err := s.lookupBook(ctx, req)
+	if err != nil {
+		return sip2.Message{}, err
+	}
+
+	ok := found
+
+	var message string
+
+	if !found {
+		message = "Item not found"
+	} else if _, err = s.loan.Checkin(ctx, book.BookID); err != nil {
+		if message, err = refusal(err); err != nil {
+			return sip2.Message{}, err
+		}
+
+		ok = false
+	}
+
+	fields := []sip2.Field{{ID: "AO", Value: s.config.InstitutionID}, {ID: "AB", Value: req.Get("AB")},
+		{ID: "AQ", Value: s.config.Location}, {ID: "AJ", Value: book.Title}}
+	if message != "" {
+		fields = append(fields, sip2.Field{ID: "AF", Value: message})
+	}
+
+	// resensitize returned books, alert staff about books that were not out
+	return sip2.Message{Code: sip2.CheckinResponse,
+		Fixed: sip2.Flag(ok) + sip2.YN(ok) + "U" + sip2.YN(found && !ok) + sip2.Time(s.now()), Fields: fields}, nil
+}
@@ -0,0 +1,264 @@
+package sip2
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/principal"
+	"Three-Layer-Architecture/service"
+	serviceloan "Three-Layer-Architecture/service/loan"
+	serviceuser "Three-Layer-Architecture/service/user"
+)
+
+var now = time.Date(2024, 6, 3, 9, 30, 0, 0, time.UTC)
+
+var config = Config{InstitutionID: "MAIN", LibraryName: "Library", Location: "Stacks"}
+
+var passwords = map[string]string{"kiosk": "kiosk password", "reader": "reader password",
+	"alice": "alice password", "bob": "bob password"}
+
+var users = map[string]models.User{"kiosk": {ID: 1, Username: "kiosk"}, "reader": {ID: 4, Username: "reader"},
+	"alice": {ID: 2, Username: "alice", Email: "alice@library.test"}, "bob": {ID: 3, Username: "bob", Email: "bob@library.test"}}
+
+var books = map[string]models.Book{"1": {BookID: 1, Title: "2 States"}, "2": {BookID: 2, Title: "One Indian Girl"},
+	"3": {BookID: 3, Title: "To Kill a Mockingbird"}, "4": {BookID: 4, Title: "Five Point Someone"}}
+
+// loans is an in-memory loan service with the rules of the real one and a fixed clock
+type loans struct {
+	policy models.LoanPolicy
+	active map[int]models.Loan
+}
+
+func (l *loans) Policy() models.LoanPolicy {
+	return l.policy
+}
+
+func (l *loans) Checkout(_ context.Context, bookID, userID int) (models.Loan, error) {
+	if _, ok := l.active[bookID]; ok {
+		return models.Loan{}, serviceloan.ErrOnLoan
+	}
+
+	mine, _ := l.Loans(context.Background(), userID)
+	if len(mine) >= l.policy.MaxLoans {
+		return models.Loan{}, serviceloan.ErrLoanLimit
+	}
+
+	for _, loan := range mine {
+		if loan.DueAt.Before(now) {
+			return models.Loan{}, serviceloan.ErrOverdue
+		}
+	}
+
+	loan := models.Loan{BookID: bookID, UserID: userID, CheckedOutAt: now, DueAt: now.Add(l.policy.Period)}
+	l.active[bookID] = loan
+
+	return loan, nil
+}
+
+func (l *loans) Checkin(_ context.Context, bookID int) (models.Loan, error) {
+	loan, ok := l.active[bookID]
+	if !ok {
+		return models.Loan{}, serviceloan.ErrNotOnLoan
+	}
+
+	delete(l.active, bookID)
+
+	return loan, nil
+}
+
+func (l *loans) Renew(_ context.Context, bookID, userID int) (models.Loan, error) {
+	loan, ok := l.active[bookID]
+
+	switch {
+	case !ok:
+		return models.Loan{}, serviceloan.ErrNotOnLoan
+	case loan.UserID != userID:
+		return models.Loan{}, serviceloan.ErrNotBorrower
+	case loan.Renewals >= l.policy.MaxRenewals:
+		return models.Loan{}, serviceloan.ErrRenewalLimit
+	}
+
+	loan.Renewals++
+	loan.DueAt = now.Add(l.policy.Period)
+	l.active[bookID] = loan
+
+	return loan, nil
+}
+
+func (l *loans) Current(_ context.Context, bookID int) (models.Loan, error) {
+	loan, ok := l.active[bookID]
+	if !ok {
+		return models.Loan{}, serviceloan.ErrNotOnLoan
+	}
+
+	return loan, nil
+}
+
+func (l *loans) Loans(_ context.Context, userID int) ([]models.Loan, error) {
+	var out []models.Loan
+
+	for id := 1; id <= len(books); id++ {
+		if loan, ok := l.active[id]; ok && loan.UserID == userID {
+			out = append(out, loan)
+		}
+	}
+
+	return out, nil
+}
+
+// library returns a server over the fixtures, alice has book 2 and bob has book 3 overdue
+func library(t *testing.T) Server {
+	ctr := gomock.NewController(t)
+	mockUser := service.NewMockUser(ctr)
+	mockRBAC := service.NewMockRBAC(ctr)
+	mockBook := service.NewMockBook(ctr)
+
+	mockUser.EXPECT().GetByUsername(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, username string) (models.User, error) {
+			if u, ok := users[username]; ok {
+				return u, nil
+			}
+
+			return models.User{}, sql.ErrNoRows
+		}).AnyTimes()
+	mockUser.EXPECT().Verify(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, username, password string) (models.User, error) {
+			if pwd, ok := passwords[username]; ok && pwd == password {
+				return users[username], nil
+			}
+
+			return models.User{}, serviceuser.ErrInvalidCredentials
+		}).AnyTimes()
+	mockRBAC.EXPECT().Authorize(gomock.Any(), models.PermLoanManage).DoAndReturn(
+		func(ctx context.Context, _ string) error {
+			if principal.FromContext(ctx).ID != "user:kiosk" {
+				return errors.New("forbidden")
+			}
+
+			return nil
+		}).AnyTimes()
+	mockBook.EXPECT().Getbyid(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, id string) (models.Book, error) {
+			if b, ok := books[id]; ok {
+				return b, nil
+			}
+
+			return models.Book{}, sql.ErrNoRows
+		}).AnyTimes()
+
+	loans := &loans{policy: models.LoanPolicy{Period: 21 * 24 * time.Hour, MaxRenewals: 1, MaxLoans: 3},
+		active: map[int]models.Loan{
+			2: {BookID: 2, UserID: 2, CheckedOutAt: now.Add(-16 * 24 * time.Hour), DueAt: now.Add(5 * 24 * time.Hour)},
+			3: {BookID: 3, UserID: 3, CheckedOutAt: now.Add(-23 * 24 * time.Hour), DueAt: now.Add(-2 * 24 * time.Hour)},
+		}}
+
+	server := New(mockUser, mockRBAC, mockBook, loans, config)
+	server.now = func() time.Time { return now }
+
+	return server
+}
+
+// TestSession_Recorded function is to replay kiosk sessions of testdata, lines starting with > are sent by the kiosk
+// and lines starting with < are the expected answers
+func TestSession_Recorded(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.sip"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "recordings", 1, files, err, "sessions")
+	}
+
+	for i, file := range files {
+		replay(t, i+1, file)
+	}
+}
+
+func replay(t *testing.T, n int, file string) {
+	recording, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", file, n, err, nil)
+	}
+
+	kiosk, acs := net.Pipe()
+	done := make(chan error, 1)
+
+	go func() {
+		done <- library(t).Session(context.Background(), acs)
+		acs.Close()
+	}()
+
+	r := bufio.NewReader(kiosk)
+
+	for no, line := range strings.Split(string(recording), "\n") {
+		switch {
+		case strings.HasPrefix(line, "> "):
+			if _, err = kiosk.Write([]byte(line[2:] + "\r")); err != nil {
+				t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v at line %d\n", file, n, err, no+1)
+			}
+		case strings.HasPrefix(line, "< "):
+			got, err := r.ReadString('\r')
+			if got = strings.TrimSuffix(got, "\r"); err != nil || got != line[2:] {
+				t.Errorf("desc : %v ,[TEST%d]Failed at line %d. Got %q %v\tExpected %q\n", file, n, no+1, got, err, line[2:])
+			}
+		}
+	}
+
+	kiosk.Close()
+
+	if err = <-done; err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", file, n, err, nil)
+	}
+}
+
+// TestSession_NotLoggedIn function is to test connections are closed when the kiosk does not log in first
+func TestSession_NotLoggedIn(t *testing.T) {
+	kiosk, acs := net.Pipe()
+	done := make(chan error, 1)
+
+	go func() {
+		done <- library(t).Session(context.Background(), acs)
+		acs.Close()
+	}()
+
+	if _, err := kiosk.Write([]byte("9900302.00\r")); err != nil {
+		t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "write", 1, err, nil)
+	}
+
+	if err := <-done; !errors.Is(err, ErrNotLoggedIn) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "status before login", 2, err, ErrNotLoggedIn)
+	}
+}
+
+// TestSession_TooLong function is to test messages over maxMessage bytes get a resend request and close the connection
+func TestSession_TooLong(t *testing.T) {
+	kiosk, acs := net.Pipe()
+	done := make(chan error, 1)
+
+	go func() {
+		done <- library(t).Session(context.Background(), acs)
+		acs.Close()
+	}()
+
+	// the write is left blocked on the unread rest of the message until the connection is closed
+	go func() {
+		_, _ = kiosk.Write([]byte("9300CN" + strings.Repeat("k", 2*maxMessage) + "\r"))
+	}()
+
+	resp, err := bufio.NewReader(kiosk).ReadString('\r')
+	if err != nil || resp != "96\r" {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %q, %v\tExpected %q\n", "response", 1, resp, err, "96\r")
+	}
+
+	if err := <-done; !errors.Is(err, ErrMessageTooLong) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "long message", 2, err, ErrMessageTooLong)
+	}
+}
@@ -0,0 +1,368 @@
+package sru
+
+import (
+	"encoding/xml"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"Three-Layer-Architecture/citation"
+	"Three-Layer-Architecture/marc"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+const (
+	namespace           = "http://www.loc.gov/zing/srw/"
+	diagnosticNamespace = "http://www.loc.gov/zing/srw/diagnostic/"
+	explainNamespace    = "http://explain.z3950.org/dtd/2.0/"
+	version             = "1.2"
+
+	// defaultRecords is the page size when maximumRecords is missing
+	defaultRecords = 10
+)
+
+// Record schemas, by short name and identifier
+const (
+	schemaDC      = "info:srw/schema/1/dc-v1.1"
+	schemaMARCXML = "info:srw/schema/1/marcxml-v1.1"
+)
+
+var schemas = map[string]string{"dc": schemaDC, schemaDC: schemaDC, "marcxml": schemaMARCXML,
+	schemaMARCXML: schemaMARCXML}
+
+// parameters of SRU 1.2 requests, x- extensions are ignored
+var parameters = map[string]bool{"operation": true, "version": true, "query": true, "startRecord": true,
+	"maximumRecords": true, "recordPacking": true, "recordSchema": true, "recordXPath": true, "resultSetTTL": true,
+	"sortKeys": true, "stylesheet": true}
+
+// messages of the diagnostics this server reports
+var messages = map[int]string{
+	models.SRUGeneralError:                "General system error",
+	models.SRUUnsupportedOperation:        "Unsupported operation",
+	models.SRUUnsupportedVersion:          "Unsupported version",
+	models.SRUUnsupportedParameterValue:   "Unsupported parameter value",
+	models.SRUMandatoryParameter:          "Mandatory parameter not supplied",
+	models.SRUUnsupportedParameter:        "Unsupported parameter",
+	models.SRUQuerySyntax:                 "Query syntax error",
+	models.SRUUnsupportedIndex:            "Unsupported index",
+	models.SRUUnsupportedRelation:         "Unsupported relation",
+	models.SRUUnsupportedRelationModifier: "Unsupported relation modifier",
+	models.SRUEmptyTerm:                   "Empty term unsupported",
+	models.SRUInvalidTerm:                 "Term in invalid format for index or relation",
+	models.SRUUnsupportedBoolean:          "Unsupported boolean operator",
+	models.SRUProximity:                   "Proximity not supported",
+	models.SRUUnsupportedBooleanModifier:  "Unsupported boolean modifier",
+	models.SRUFirstRecordOutOfRange:       "First record position out of range",
+	models.SRUUnknownSchema:               "Unknown schema for retrieval",
+	models.SRUUnsupportedPacking:          "Unsupported record packing",
+	models.SRUXPath:                       "XPath retrieval unsupported",
+	models.SRUSort:                        "Sort not supported",
+	models.SRUStylesheet:                  "Stylesheets not supported",
+}
+
+type Delivery struct {
+	service service.SRU
+	name    string
+}
+
+// New returns the handler of the database called name in explain records
+func New(sru service.SRU, name string) Delivery {
+	return Delivery{service: sru, name: name}
+}
+
+type searchResponse struct {
+	XMLName         xml.Name     `xml:"searchRetrieveResponse"`
+	Xmlns           string       `xml:"xmlns,attr"`
+	Version         string       `xml:"version"`
+	NumberOfRecords int          `xml:"numberOfRecords"`
+	Records         *records     `xml:"records,omitempty"`
+	NextPosition    int          `xml:"nextRecordPosition,omitempty"`
+	Diagnostics     *diagnostics `xml:"diagnostics,omitempty"`
+}
+
+type explainResponse struct {
+	XMLName     xml.Name     `xml:"explainResponse"`
+	Xmlns       string       `xml:"xmlns,attr"`
+	Version     string       `xml:"version"`
+	Record      *record      `xml:"record,omitempty"`
+	Diagnostics *diagnostics `xml:"diagnostics,omitempty"`
+}
+
+type records struct {
+	Records []record `xml:"record"`
+}
+
+type record struct {
+	Schema   string     `xml:"recordSchema"`
+	Packing  string     `xml:"recordPacking"`
+	Data     recordData `xml:"recordData"`
+	Position int        `xml:"recordPosition,omitempty"`
+}
+
+// recordData holds the record as XML, or escaped as text with string packing
+type recordData struct {
+	XML  string `xml:",innerxml"`
+	Text string `xml:",chardata"`
+}
+
+type diagnostics struct {
+	Diagnostics []diagnostic
+}
+
+type diagnostic struct {
+	XMLName xml.Name `xml:"http://www.loc.gov/zing/srw/diagnostic/ diagnostic"`
+	URI     string   `xml:"uri"`
+	Details string   `xml:"details,omitempty"`
+	Message string   `xml:"message"`
+}
+
+// srwDC is the Dublin Core record of the SRU dc schema
+type srwDC struct {
+	XMLName    xml.Name `xml:"srw_dc:dc"`
+	XmlnsSRWDC string   `xml:"xmlns:srw_dc,attr"`
+	XmlnsDC    string   `xml:"xmlns:dc,attr"`
+	Title      string   `xml:"dc:title"`
+	Creator    string   `xml:"dc:creator"`
+	Publisher  string   `xml:"dc:publisher,omitempty"`
+	Date       string   `xml:"dc:date,omitempty"`
+	Type       string   `xml:"dc:type"`
+	Identifier string   `xml:"dc:identifier"`
+}
+
+// Handle method is to answer SRU 1.2 searchRetrieve and explain requests.
+// Diagnostics are part of the XML response, which is always sent with status 200.
+func (a Delivery) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	args, err := readArguments(r)
+
+	var resp any
+
+	if err == nil && args["operation"] == "searchRetrieve" {
+		resp, err = a.search(r, args)
+	} else {
+		resp = a.explain(r)
+	}
+
+	var diag models.SRUDiagnostic
+
+	switch {
+	case errors.As(err, &diag):
+		diags := &diagnostics{Diagnostics: []diagnostic{{URI: "info:srw/diagnostic/1/" + strconv.Itoa(diag.Code),
+			Details: diag.Details, Message: messages[diag.Code]}}}
+
+		// fatal diagnostics come without records
+		if args["operation"] == "searchRetrieve" {
+			resp = searchResponse{Xmlns: namespace, Version: version, Diagnostics: diags}
+		} else {
+			resp = explainResponse{Xmlns: namespace, Version: version, Diagnostics: diags}
+		}
+	case err != nil:
+		slog.ErrorContext(ctx, "sru request failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		writeError(errors.New("could not answer request"), w)
+
+		return
+	}
+
+	body, err := xml.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		writeError(err, w)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err = w.Write(append([]byte(xml.Header), body...)); err != nil {
+		slog.ErrorContext(ctx, "could not write response", "error", err)
+	}
+}
+
+func (a Delivery) search(r *http.Request, args map[string]string) (searchResponse, error) {
+	query, ok := args["query"]
+	if !ok {
+		return searchResponse{}, models.SRUDiagnostic{Code: models.SRUMandatoryParameter, Details: "query"}
+	}
+
+	start, err := number(args, "startRecord", 1)
+	if err != nil {
+		return searchResponse{}, err
+	}
+
+	maximum, err := number(args, "maximumRecords", defaultRecords)
+	if err != nil {
+		return searchResponse{}, err
+	}
+
+	schema, ok := schemas[args["recordSchema"]]
+	if !ok && args["recordSchema"] != "" {
+		return searchResponse{}, models.SRUDiagnostic{Code: models.SRUUnknownSchema, Details: args["recordSchema"]}
+	} else if !ok {
+		schema = schemaDC
+	}
+
+	packing := args["recordPacking"]
+	if packing == "" {
+		packing = "xml"
+	}
+
+	if packing != "xml" && packing != "string" {
+		return searchResponse{}, models.SRUDiagnostic{Code: models.SRUUnsupportedPacking, Details: packing}
+	}
+
+	result, err := a.service.Search(r.Context(), query, start, maximum)
+	if err != nil {
+		return searchResponse{}, err
+	}
+
+	resp := searchResponse{Xmlns: namespace, Version: version, NumberOfRecords: result.Total,
+		NextPosition: result.Next}
+
+	if len(result.Books) == 0 {
+		return resp, nil
+	}
+
+	resp.Records = &records{}
+
+	for i, book := range result.Books {
+		data, err := encode(book, schema)
+		if err != nil {
+			return searchResponse{}, err
+		}
+
+		rec := record{Schema: schema, Packing: packing, Position: start + i}
+		if packing == "xml" {
+			rec.Data.XML = string(data)
+		} else {
+			rec.Data.Text = string(data)
+		}
+
+		resp.Records.Records = append(resp.Records.Records, rec)
+	}
+
+	return resp, nil
+}
+
+func encode(book models.Book, schema string) ([]byte, error) {
+	if schema == schemaMARCXML {
+		return marc.EncodeXML(marc.FromBook(book))
+	}
+
+	dc := citation.DublinCore(book)
+
+	return xml.Marshal(srwDC{XmlnsSRWDC: "info:srw/schema/1/dc-schema", XmlnsDC: citation.NamespaceDC, Title: dc.Title,
+		Creator: dc.Creator, Publisher: dc.Publisher, Date: dc.Date, Type: dc.Type, Identifier: dc.Identifier})
+}
+
+// explain describes the server as ZeeRex record, host and port are the ones the request was sent to
+func (a Delivery) explain(r *http.Request) explainResponse {
+	host, port, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host, port = r.Host, "80"
+	}
+
+	var b strings.Builder
+
+	b.WriteString(`<explain xmlns="` + explainNamespace + `">`)
+	b.WriteString(`<serverInfo protocol="SRU" version="` + version + `"><host>` + escape(host) + `</host><port>` +
+		escape(port) + `</port><database>sru</database></serverInfo>`)
+	b.WriteString(`<databaseInfo><title>` + escape(a.name) + `</title></databaseInfo>`)
+	b.WriteString(`<indexInfo><set name="dc" identifier="info:srw/cql-context-set/1/dc-v1.1"/>` +
+		`<set name="cql" identifier="info:srw/cql-context-set/1/cql-v1.2"/>`)
+
+	for _, index := range []string{"title", "creator", "publisher", "date"} {
+		b.WriteString(`<index><title>` + index + `</title><map><name set="dc">` + index + `</name></map></index>`)
+	}
+
+	b.WriteString(`</indexInfo><schemaInfo>` +
+		`<schema identifier="` + schemaDC + `" name="dc"><title>Dublin Core</title></schema>` +
+		`<schema identifier="` + schemaMARCXML + `" name="marcxml"><title>MARCXML</title></schema></schemaInfo>`)
+	b.WriteString(`<configInfo><default type="numberOfRecords">` + strconv.Itoa(defaultRecords) + `</default>` +
+		`</configInfo></explain>`)
+
+	return explainResponse{Xmlns: namespace, Version: version, Record: &record{Schema: explainNamespace,
+		Packing: "xml", Data: recordData{XML: b.String()}}}
+}
+
+func escape(s string) string {
+	var b strings.Builder
+
+	_ = xml.EscapeText(&b, []byte(s))
+
+	return b.String()
+}
+
+// readArguments checks the operation, version and parameters the server does not support.
+// Requests without operation are explain requests, the operation is kept when another argument is wrong.
+func readArguments(r *http.Request) (map[string]string, error) {
+	args := map[string]string{}
+
+	var unsupported string
+
+	for name, values := range r.URL.Query() {
+		switch {
+		case strings.HasPrefix(name, "x-"):
+		case !parameters[name]:
+			unsupported = name
+		default:
+			args[name] = values[0]
+		}
+	}
+
+	switch args["operation"] {
+	case "searchRetrieve", "explain":
+	case "":
+		args["operation"] = "explain"
+	default:
+		return args, models.SRUDiagnostic{Code: models.SRUUnsupportedOperation, Details: args["operation"]}
+	}
+
+	if v, ok := args["version"]; ok && v != "1.1" && v != version {
+		return args, models.SRUDiagnostic{Code: models.SRUUnsupportedVersion, Details: version}
+	}
+
+	switch {
+	case unsupported != "":
+		return args, models.SRUDiagnostic{Code: models.SRUUnsupportedParameter, Details: unsupported}
+	case args["recordXPath"] != "":
+		return args, models.SRUDiagnostic{Code: models.SRUXPath, Details: args["recordXPath"]}
+	case args["sortKeys"] != "":
+		return args, models.SRUDiagnostic{Code: models.SRUSort, Details: args["sortKeys"]}
+	case args["stylesheet"] != "":
+		return args, models.SRUDiagnostic{Code: models.SRUStylesheet, Details: args["stylesheet"]}
+	}
+
+	return args, nil
+}
+
+// number reads a non negative integer parameter, def when it is missing
+func number(args map[string]string, name string, def int) (int, error) {
+	v, ok := args[name]
+	if !ok {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, models.SRUDiagnostic{Code: models.SRUUnsupportedParameterValue, Details: name}
+	}
+
+	return n, nil
+}
+
+func writeError(err error, w http.ResponseWriter) {
+	_, errs := w.Write([]byte(err.Error()))
+	if errs != nil {
+		slog.Error("could not write response", "error", errs)
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+}
@@ -0,0 +1,126 @@
+package sru
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+func serve(delivery Delivery, req *http.Request) (int, string) {
+	w := httptest.NewRecorder()
+
+	delivery.Handle(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(res.Body)
+
+	return res.StatusCode, string(body)
+}
+
+// TestHandle function is to test searches, explain and diagnostics
+func TestHandle(t *testing.T) {
+	book := models.Book{BookID: 1, AuthorID: 1,
+		Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
+		Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}
+
+	testcases := []struct {
+		desc     string
+		query    string
+		mock     func(m *service.MockSRU)
+		contains []string
+	}{
+		{desc: "dublin core", query: "operation=searchRetrieve&version=1.2&query=title%3Dstates&maximumRecords=1",
+			mock: func(m *service.MockSRU) {
+				m.EXPECT().Search(gomock.Any(), "title=states", 1, 1).
+					Return(models.SRUResult{Books: []models.Book{book}, Total: 3, Next: 2}, nil)
+			}, contains: []string{`<searchRetrieveResponse xmlns="http://www.loc.gov/zing/srw/"><version>1.2</version>`,
+				"<numberOfRecords>3</numberOfRecords>", "<recordSchema>info:srw/schema/1/dc-v1.1</recordSchema>",
+				`<recordData><srw_dc:dc xmlns:srw_dc="info:srw/schema/1/dc-schema"`, "<dc:title>2 States</dc:title>",
+				"<recordPosition>1</recordPosition>", "<nextRecordPosition>2</nextRecordPosition>"}},
+		{desc: "marcxml", query: "operation=searchRetrieve&query=bhagat&recordSchema=marcxml&startRecord=3",
+			mock: func(m *service.MockSRU) {
+				m.EXPECT().Search(gomock.Any(), "bhagat", 3, defaultRecords).
+					Return(models.SRUResult{Books: []models.Book{book}, Total: 3}, nil)
+			}, contains: []string{"<recordSchema>info:srw/schema/1/marcxml-v1.1</recordSchema>",
+				`<recordData><record xmlns="http://www.loc.gov/MARC21/slim">`, "<recordPosition>3</recordPosition>"}},
+		{desc: "string packing", query: "operation=searchRetrieve&query=bhagat&recordPacking=string",
+			mock: func(m *service.MockSRU) {
+				m.EXPECT().Search(gomock.Any(), "bhagat", 1, defaultRecords).
+					Return(models.SRUResult{Books: []models.Book{book}, Total: 1}, nil)
+			}, contains: []string{"<recordPacking>string</recordPacking>", "<recordData>&lt;srw_dc:dc"}},
+		{desc: "no records", query: "operation=searchRetrieve&query=nothing", mock: func(m *service.MockSRU) {
+			m.EXPECT().Search(gomock.Any(), "nothing", 1, defaultRecords).Return(models.SRUResult{}, nil)
+		}, contains: []string{"<numberOfRecords>0</numberOfRecords></searchRetrieveResponse>"}},
+		{desc: "diagnostic of service", query: "operation=searchRetrieve&query=isbn%3D1", mock: func(m *service.MockSRU) {
+			m.EXPECT().Search(gomock.Any(), "isbn=1", 1, defaultRecords).Return(models.SRUResult{},
+				models.SRUDiagnostic{Code: models.SRUUnsupportedIndex, Details: "isbn"})
+		}, contains: []string{`<diagnostic xmlns="http://www.loc.gov/zing/srw/diagnostic/">` +
+			"<uri>info:srw/diagnostic/1/16</uri><details>isbn</details><message>Unsupported index</message>"}},
+		{desc: "explain", query: "operation=explain", contains: []string{`<explainResponse xmlns="http://www.loc.gov/zing/srw/">`,
+			`<explain xmlns="http://explain.z3950.org/dtd/2.0/">`, "<host>example.com</host><port>80</port>",
+			"<title>Library</title>", `<name set="dc">creator</name>`}},
+		{desc: "explain by default", query: "", contains: []string{"<explainResponse"}},
+		{desc: "missing query", query: "operation=searchRetrieve",
+			contains: []string{"<searchRetrieveResponse", "<uri>info:srw/diagnostic/1/7</uri><details>query</details>"}},
+		{desc: "bad start", query: "operation=searchRetrieve&query=a&startRecord=first",
+			contains: []string{"<uri>info:srw/diagnostic/1/6</uri><details>startRecord</details>"}},
+		{desc: "unknown schema", query: "operation=searchRetrieve&query=a&recordSchema=mods",
+			contains: []string{"<uri>info:srw/diagnostic/1/66</uri><details>mods</details>"}},
+		{desc: "unknown packing", query: "operation=searchRetrieve&query=a&recordPacking=json",
+			contains: []string{"<uri>info:srw/diagnostic/1/71</uri>"}},
+		{desc: "sorting", query: "operation=searchRetrieve&query=a&sortKeys=title",
+			contains: []string{"<uri>info:srw/diagnostic/1/80</uri>"}},
+		{desc: "unknown parameter", query: "operation=searchRetrieve&query=a&x-debug=1&limit=5",
+			contains: []string{"<searchRetrieveResponse", "<uri>info:srw/diagnostic/1/8</uri><details>limit</details>"}},
+		{desc: "unsupported version", query: "operation=searchRetrieve&version=2.0&query=a",
+			contains: []string{"<uri>info:srw/diagnostic/1/5</uri><details>1.2</details>"}},
+		{desc: "unsupported operation", query: "operation=scan&scanClause=a",
+			contains: []string{"<explainResponse", "<uri>info:srw/diagnostic/1/4</uri><details>scan</details>"}},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockSRU := service.NewMockSRU(ctr)
+
+		if v.mock != nil {
+			v.mock(mockSRU)
+		}
+
+		status, body := serve(New(mockSRU, "Library"), httptest.NewRequest(http.MethodGet, "/sru?"+v.query, nil))
+
+		if status != http.StatusOK {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, status, http.StatusOK)
+		}
+
+		for _, c := range v.contains {
+			if !strings.Contains(body, c) {
+				t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, body, c)
+			}
+		}
+	}
+}
+
+// TestHandle_Error function is to test errors other than diagnostics are answered with 500
+func TestHandle_Error(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockSRU := service.NewMockSRU(ctr)
+	mockSRU.EXPECT().Search(gomock.Any(), "a", 1, defaultRecords).Return(models.SRUResult{}, errors.New("connection lost"))
+
+	req := httptest.NewRequest(http.MethodGet, "/sru?operation=searchRetrieve&query=a", nil)
+
+	if status, body := serve(New(mockSRU, "Library"), req); status != http.StatusInternalServerError ||
+		body != "could not answer request" {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "service error", 1, status, body,
+			http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,57 @@
+package trash
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"Three-Layer-Architecture/service"
+)
+
+type Delivery struct {
+	service service.Trash
+}
+
+func New(trash service.Trash) Delivery {
+	return Delivery{service: trash}
+}
+
+// GetAll method is to list deleted Books and Authors
+func (a Delivery) GetAll(w http.ResponseWriter, r *http.Request) {
+	trash, err := a.service.GetAll(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	body, err := json.Marshal(trash)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		writeError(err, w)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err = w.Write(body); err != nil {
+		slog.ErrorContext(r.Context(), "could not write response", "error", err)
+
+		return
+	}
+
+	slog.InfoContext(r.Context(), "trash listed")
+}
+
+func writeError(err error, w http.ResponseWriter) {
+	_, errs := w.Write([]byte(err.Error()))
+	if errs != nil {
+		slog.Error("could not write response", "error", errs)
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+}
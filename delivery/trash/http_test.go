@@ -0,0 +1,61 @@
+package trash
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// TestGetAllTrash function is to test listing of deleted books and authors
+func TestGetAllTrash(t *testing.T) {
+	testcases := []struct {
+		desc               string
+		resp               models.Trash
+		expectedStatusCode int
+		err                error
+	}{
+		{desc: "valid", resp: models.Trash{Books: []models.Book{{BookID: 1, Title: "Journey"}},
+			Authors: []models.Author{{AuthID: 1, FirstName: "Rajan"}}}, expectedStatusCode: http.StatusOK},
+		{desc: "error from svc", expectedStatusCode: http.StatusBadRequest, err: errors.New("connection refused")},
+	}
+
+	ctr := gomock.NewController(t)
+	mockTrash := service.NewMockTrash(ctr)
+	delivery := New(mockTrash)
+
+	for i, v := range testcases {
+		req := httptest.NewRequest(http.MethodGet, "/trash", nil)
+		w := httptest.NewRecorder()
+
+		mockTrash.EXPECT().GetAll(gomock.Any()).Return(v.resp, v.err)
+
+		delivery.GetAll(w, req)
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.StatusCode, v.expectedStatusCode)
+		}
+
+		if v.err == nil {
+			body, _ := io.ReadAll(res.Body)
+
+			var trash models.Trash
+
+			if err := json.Unmarshal(body, &trash); err != nil || !reflect.DeepEqual(trash, v.resp) {
+				t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, trash, v.resp)
+			}
+		}
+
+		res.Body.Close()
+	}
+}
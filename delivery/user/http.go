@@ -0,0 +1,239 @@
+package user
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"Three-Layer-Architecture/middleware"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+	serviceuser "Three-Layer-Architecture/service/user"
+)
+
+type Delivery struct {
+	service service.User
+}
+
+func New(user service.User) Delivery {
+	return Delivery{service: user}
+}
+
+// Login method is to check the password and start a session, the token is set as cookie and returned in body
+func (a Delivery) Login(w http.ResponseWriter, r *http.Request) {
+	var credentials models.Credentials
+
+	if err := decode(r, &credentials); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	token, session, err := a.service.Login(r.Context(), credentials.Username, credentials.Password)
+
+	switch {
+	case errors.Is(err, serviceuser.ErrLocked):
+		w.WriteHeader(http.StatusLocked)
+		writeError(err, w)
+
+		return
+	case errors.Is(err, serviceuser.ErrInvalidCredentials):
+		w.WriteHeader(http.StatusUnauthorized)
+		writeError(err, w)
+
+		return
+	case err != nil:
+		// the cause may name tables or hosts, it is only logged
+		slog.ErrorContext(r.Context(), "login failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		writeError(errors.New("could not log in"), w)
+
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.SessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	writeJSON(w, http.StatusOK, models.Login{Token: token, ExpiresAt: session.ExpiresAt})
+	slog.InfoContext(r.Context(), "user logged in")
+}
+
+// Logout method is to end the session of the request
+func (a Delivery) Logout(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("X-Session-Token")
+
+	if c, err := r.Cookie(middleware.SessionCookie); token == "" && err == nil {
+		token = c.Value
+	}
+
+	if err := a.service.Logout(r.Context(), token); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.SessionCookie,
+		Path:     "/",
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+	slog.InfoContext(r.Context(), "user logged out")
+}
+
+// Register method is to create a user account
+func (a Delivery) Register(w http.ResponseWriter, r *http.Request) {
+	var registration models.Registration
+
+	if err := decode(r, &registration); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	user, err := a.service.Register(r.Context(), registration.Username, registration.Email, registration.Password)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, user)
+	slog.InfoContext(r.Context(), "user registered")
+}
+
+// Current method is to get the account of the caller
+func (a Delivery) Current(w http.ResponseWriter, r *http.Request) {
+	user, err := a.service.Current(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+// RevokeSessions method is to end every session of a user
+func (a Delivery) RevokeSessions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(errors.New("invalid id"), w)
+
+		return
+	}
+
+	if _, err = a.service.RevokeSessions(r.Context(), id); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	slog.InfoContext(r.Context(), "sessions revoked")
+}
+
+// ForgotPassword method is to send a reset token, it is accepted even for unknown users
+func (a Delivery) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var credentials models.Credentials
+
+	if err := decode(r, &credentials); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	if err := a.service.RequestPasswordReset(r.Context(), credentials.Username); err != nil {
+		slog.ErrorContext(r.Context(), "password reset request failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		writeError(errors.New("could not send password reset"), w)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ResetPassword method is to set a new password with the token sent to the user
+func (a Delivery) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var change models.PasswordChange
+
+	if err := decode(r, &change); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	if err := a.service.ResetPassword(r.Context(), change.Token, change.Password); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	slog.InfoContext(r.Context(), "password reset")
+}
+
+func decode(r *http.Request, v any) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		writeError(err, w)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if _, err = w.Write(body); err != nil {
+		slog.Error("could not write response", "error", err)
+	}
+}
+
+func writeError(err error, w http.ResponseWriter) {
+	_, errs := w.Write([]byte(err.Error()))
+	if errs != nil {
+		slog.Error("could not write response", "error", errs)
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+}
@@ -0,0 +1,167 @@
+package user
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+	serviceuser "Three-Layer-Architecture/service/user"
+)
+
+// TestLogin function is to test session cookie is set only for valid credentials
+func TestLogin(t *testing.T) {
+	expires := time.Date(2022, 5, 1, 11, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc               string
+		body               string
+		err                error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{desc: "valid", body: `{"username":"alice","password":"correct horse battery"}`, expectedStatusCode: http.StatusOK},
+		{desc: "wrong password", body: `{"username":"alice","password":"wrong"}`, err: serviceuser.ErrInvalidCredentials,
+			expectedStatusCode: http.StatusUnauthorized},
+		{desc: "locked", body: `{"username":"alice","password":"wrong"}`, err: serviceuser.ErrLocked,
+			expectedStatusCode: http.StatusLocked},
+		{desc: "malformed", body: `{"username":`, expectedStatusCode: http.StatusBadRequest},
+		{desc: "database down", body: `{"username":"alice","password":"wrong"}`,
+			err: errors.New("dial tcp 10.0.0.5:3306: connection refused"), expectedStatusCode: http.StatusInternalServerError,
+			expectedBody: "could not log in"},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockUser := service.NewMockUser(ctr)
+		delivery := New(mockUser)
+
+		if v.desc != "malformed" {
+			mockUser.EXPECT().Login(gomock.Any(), "alice", gomock.Any()).Return("tok", models.Session{ExpiresAt: expires}, v.err)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader([]byte(v.body)))
+		w := httptest.NewRecorder()
+
+		delivery.Login(w, req)
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.StatusCode, v.expectedStatusCode)
+		}
+
+		if body, _ := io.ReadAll(res.Body); v.expectedBody != "" && string(body) != v.expectedBody {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %q\tExpected %q\n", v.desc, i+1, body, v.expectedBody)
+		}
+
+		cookies := res.Cookies()
+		if v.err == nil && res.StatusCode == http.StatusOK && (len(cookies) != 1 || cookies[0].Value != "tok" || !cookies[0].HttpOnly) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got cookies %v\n", v.desc, i+1, cookies)
+		}
+
+		if res.StatusCode != http.StatusOK && len(cookies) != 0 {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got cookies %v\tExpected none\n", v.desc, i+1, cookies)
+		}
+
+		res.Body.Close()
+		ctr.Finish()
+	}
+}
+
+// TestLogout function is to test session of the cookie is ended
+func TestLogout(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockUser := service.NewMockUser(ctr)
+	delivery := New(mockUser)
+
+	mockUser.EXPECT().Logout(gomock.Any(), "tok").Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "tok"})
+
+	w := httptest.NewRecorder()
+
+	delivery.Logout(w, req)
+
+	res := w.Result()
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("[TEST1]Failed. Got %v\tExpected %v\n", res.StatusCode, http.StatusNoContent)
+	}
+
+	if cookies := res.Cookies(); len(cookies) != 1 || cookies[0].MaxAge >= 0 {
+		t.Errorf("[TEST1]Failed. Got cookies %v\tExpected cleared cookie\n", cookies)
+	}
+
+	res.Body.Close()
+}
+
+// TestForgotPassword function is to test request is accepted without telling if the user exists
+func TestForgotPassword(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockUser := service.NewMockUser(ctr)
+	delivery := New(mockUser)
+
+	mockUser.EXPECT().RequestPasswordReset(gomock.Any(), "nobody").Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/password/forgot", bytes.NewReader([]byte(`{"username":"nobody"}`)))
+	w := httptest.NewRecorder()
+
+	delivery.ForgotPassword(w, req)
+
+	res := w.Result()
+
+	if res.StatusCode != http.StatusAccepted {
+		t.Errorf("[TEST1]Failed. Got %v\tExpected %v\n", res.StatusCode, http.StatusAccepted)
+	}
+
+	res.Body.Close()
+}
+
+// TestRevokeSessions function is to test sessions of a user are ended
+func TestRevokeSessions(t *testing.T) {
+	testcases := []struct {
+		desc               string
+		id                 string
+		err                error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{desc: "valid", id: "3", expectedStatusCode: http.StatusNoContent},
+		{desc: "invalid id", id: "abc", expectedStatusCode: http.StatusBadRequest},
+		{desc: "error from svc", id: "-1", err: errors.New("invalid id"), expectedStatusCode: http.StatusBadRequest},
+	}
+
+	ctr := gomock.NewController(t)
+	mockUser := service.NewMockUser(ctr)
+	delivery := New(mockUser)
+
+	mockUser.EXPECT().RevokeSessions(gomock.Any(), 3).Return(2, nil)
+	mockUser.EXPECT().RevokeSessions(gomock.Any(), -1).Return(0, errors.New("invalid id"))
+
+	for i, v := range testcases {
+		req := httptest.NewRequest(http.MethodDelete, "/users/"+v.id+"/sessions", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": v.id})
+
+		w := httptest.NewRecorder()
+
+		delivery.RevokeSessions(w, req)
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.StatusCode, v.expectedStatusCode)
+		}
+
+		res.Body.Close()
+	}
+}
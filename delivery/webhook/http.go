@@ -0,0 +1,168 @@
+package webhook
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+type Delivery struct {
+	service service.Webhook
+}
+
+func New(webhook service.Webhook) Delivery {
+	return Delivery{service: webhook}
+}
+
+// Post method is to subscribe an URL to events, the response has the secret which is not shown again
+func (a Delivery) Post(w http.ResponseWriter, r *http.Request) {
+	var webhook models.Webhook
+
+	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	webhook, err := a.service.Create(r.Context(), webhook)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, webhook)
+	slog.InfoContext(r.Context(), "webhook created", "webhook", webhook.ID)
+}
+
+// GetAll method is to list webhooks
+func (a Delivery) GetAll(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := a.service.GetAll(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, webhooks)
+}
+
+// Get method is to get a webhook by id
+func (a Delivery) Get(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	webhook, err := a.service.Get(r.Context(), vars["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, webhook)
+}
+
+// Update method is to change url, events and active of a webhook
+func (a Delivery) Update(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var webhook models.Webhook
+
+	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	webhook, err := a.service.Update(r.Context(), vars["id"], webhook)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, webhook)
+	slog.InfoContext(r.Context(), "webhook updated", "webhook", webhook.ID)
+}
+
+// Delete method is to remove a webhook with its deliveries
+func (a Delivery) Delete(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if _, err := a.service.Delete(r.Context(), vars["id"]); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	slog.InfoContext(r.Context(), "webhook deleted", "webhook", vars["id"])
+}
+
+// Deliveries method is to list the latest deliveries of a webhook
+func (a Delivery) Deliveries(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	deliveries, err := a.service.Deliveries(r.Context(), vars["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+// Redeliver method is to queue a delivery again, it is sent by the dispatcher
+func (a Delivery) Redeliver(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := a.service.Redeliver(r.Context(), vars["id"], vars["delivery"]); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeError(err, w)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	slog.InfoContext(r.Context(), "webhook delivery queued again", "webhook", vars["id"], "delivery", vars["delivery"])
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		writeError(err, w)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if _, err = w.Write(body); err != nil {
+		slog.Error("could not write response", "error", err)
+	}
+}
+
+func writeError(err error, w http.ResponseWriter) {
+	_, errs := w.Write([]byte(err.Error()))
+	if errs != nil {
+		slog.Error("could not write response", "error", errs)
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+}
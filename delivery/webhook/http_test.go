@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// TestPost function is to test the secret of a created webhook is in the response
+func TestPost(t *testing.T) {
+	webhook := models.Webhook{URL: "https://example.com/hook", Events: []string{"book.*"}}
+	created := models.Webhook{ID: 1, URL: webhook.URL, Events: webhook.Events, Secret: "s", Active: true}
+
+	testcases := []struct {
+		desc               string
+		body               string
+		resp               models.Webhook
+		err                error
+		expectedStatusCode int
+	}{
+		{desc: "valid", body: `{"url":"https://example.com/hook","events":["book.*"]}`, resp: created,
+			expectedStatusCode: http.StatusCreated},
+		{desc: "unmarshal error", body: `[]`, expectedStatusCode: http.StatusBadRequest},
+		{desc: "error from svc", body: `{"url":"https://example.com/hook","events":["book.*"]}`,
+			err: errors.New("invalid event"), expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockWebhook := service.NewMockWebhook(ctr)
+
+		mockWebhook.EXPECT().Create(gomock.Any(), webhook).Return(v.resp, v.err).AnyTimes()
+
+		w := httptest.NewRecorder()
+
+		New(mockWebhook).Post(w, httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(v.body)))
+
+		res := w.Result()
+
+		var resp models.Webhook
+
+		if res.StatusCode == http.StatusCreated {
+			_ = json.NewDecoder(res.Body).Decode(&resp)
+		}
+
+		if res.StatusCode != v.expectedStatusCode || !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", v.desc, i+1, res.StatusCode, resp,
+				v.expectedStatusCode, v.resp)
+		}
+
+		res.Body.Close()
+	}
+}
+
+// TestRedeliver function is to test a delivery is queued again under its webhook
+func TestRedeliver(t *testing.T) {
+	testcases := []struct {
+		desc               string
+		delivery           string
+		err                error
+		expectedStatusCode int
+	}{
+		{desc: "queued", delivery: "7", expectedStatusCode: http.StatusAccepted},
+		{desc: "delivery of another webhook", delivery: "8", err: sql.ErrNoRows, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	ctr := gomock.NewController(t)
+	mockWebhook := service.NewMockWebhook(ctr)
+	delivery := New(mockWebhook)
+
+	for i, v := range testcases {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/1/deliveries/"+v.delivery+"/redeliver", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "1", "delivery": v.delivery})
+		w := httptest.NewRecorder()
+
+		mockWebhook.EXPECT().Redeliver(gomock.Any(), "1", v.delivery).Return(v.err)
+
+		delivery.Redeliver(w, req)
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.StatusCode, v.expectedStatusCode)
+		}
+
+		res.Body.Close()
+	}
+}
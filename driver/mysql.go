@@ -2,29 +2,33 @@ package driver
 
 import (
 	"database/sql"
-	"fmt"
-	"log"
+	"log/slog"
+
+	"github.com/XSAM/otelsql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 
 	// package sql-driver
 	_ "github.com/go-sql-driver/mysql"
 )
 
 func ConnectDB() (*sql.DB, error) {
-	// Open the driver to datasource
-	db, err := sql.Open("mysql", "root:root@123@tcp(localhost:3306)/library")
+	// Open the driver to datasource, parseTime is needed to scan DATETIME columns
+	// every query gets a span as child of the span in its context
+	db, err := otelsql.Open("mysql", "root:root@123@tcp(localhost:3306)/library?parseTime=true",
+		otelsql.WithAttributes(semconv.DBSystemMySQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{DisableErrSkip: true, OmitConnResetSession: true}))
 	if err != nil {
-		fmt.Println("Failed during Connection")
-		log.Fatal(err)
+		return nil, err
 	}
 
 	// Checking Connection To DB
-	err = db.Ping()
-	if err != nil {
-		fmt.Println("Ping Giving error ")
-		log.Fatal(err)
+	if err = db.Ping(); err != nil {
+		db.Close()
+
+		return nil, err
 	}
 
-	fmt.Println("Connection Established..!!")
+	slog.Info("connection established")
 
-	return db, err
+	return db, nil
 }
@@ -0,0 +1,130 @@
+// Package librarypb has the protobuf messages and gRPC services of the catalogue, library.proto is the source of
+// the generated files and this file converts the messages to and from models
+package librarypb
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"Three-Layer-Architecture/models"
+)
+
+// FromAuthor converts an author to its message
+func FromAuthor(author models.Author) *Author {
+	return &Author{
+		Id:        int32(author.AuthID),
+		FirstName: author.FirstName,
+		LastName:  author.LastName,
+		Dob:       author.Dob,
+		PenName:   author.PenName,
+		DeletedAt: timestamp(author.DeletedAt),
+	}
+}
+
+// Model converts the message to an author, nil gives an empty author
+func (x *Author) Model() models.Author {
+	if x == nil {
+		return models.Author{}
+	}
+
+	return models.Author{
+		AuthID:    int(x.Id),
+		FirstName: x.FirstName,
+		LastName:  x.LastName,
+		Dob:       x.Dob,
+		PenName:   x.PenName,
+		DeletedAt: timeOf(x.DeletedAt),
+	}
+}
+
+// FromBook converts a book and its author to a message
+func FromBook(book models.Book) *Book {
+	return &Book{
+		Id:            int32(book.BookID),
+		AuthorId:      int32(book.AuthorID),
+		Author:        FromAuthor(book.Auth),
+		Title:         book.Title,
+		Publication:   book.Publication,
+		PublishedDate: book.PublishedDate,
+		Isbn:          book.ISBN,
+		DeletedAt:     timestamp(book.DeletedAt),
+	}
+}
+
+// Model converts the message to a book, nil gives an empty book
+func (x *Book) Model() models.Book {
+	if x == nil {
+		return models.Book{}
+	}
+
+	return models.Book{
+		BookID:        int(x.Id),
+		AuthorID:      int(x.AuthorId),
+		Auth:          x.Author.Model(),
+		Title:         x.Title,
+		Publication:   x.Publication,
+		PublishedDate: x.PublishedDate,
+		ISBN:          x.Isbn,
+		DeletedAt:     timeOf(x.DeletedAt),
+	}
+}
+
+// FromRevisions converts revisions to a list response
+func FromRevisions(revisions []models.Revision) *ListRevisionsResponse {
+	resp := &ListRevisionsResponse{Revisions: make([]*Revision, 0, len(revisions))}
+
+	for _, rev := range revisions {
+		resp.Revisions = append(resp.Revisions, &Revision{
+			Entity:    rev.Entity,
+			EntityId:  int32(rev.EntityID),
+			Rev:       int32(rev.Rev),
+			Operation: rev.Operation,
+			Actor:     rev.Actor,
+			CreatedAt: timestamppb.New(rev.CreatedAt),
+			Data:      rev.Data,
+		})
+	}
+
+	return resp
+}
+
+// FromRevisionDiff converts a diff, values of fields are the ones decoded from revision JSON
+func FromRevisionDiff(diff models.RevisionDiff) (*RevisionDiff, error) {
+	resp := &RevisionDiff{From: int32(diff.From), To: int32(diff.To), Changes: make([]*FieldChange, 0, len(diff.Changes))}
+
+	for _, change := range diff.Changes {
+		from, err := structpb.NewValue(change.From)
+		if err != nil {
+			return nil, err
+		}
+
+		to, err := structpb.NewValue(change.To)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Changes = append(resp.Changes, &FieldChange{Field: change.Field, From: from, To: to})
+	}
+
+	return resp, nil
+}
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
+
+func timeOf(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+
+	t := ts.AsTime()
+
+	return &t
+}
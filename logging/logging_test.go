@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"Three-Layer-Architecture/requestid"
+)
+
+// TestNew function is to test records carry request id of the context and respect level
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := New(&buf, ParseLevel("info")).With("component", "test")
+
+	logger.DebugContext(context.Background(), "hidden")
+	logger.InfoContext(requestid.NewContext(context.Background(), "req-1"), "book deleted", "id", 7)
+
+	var record map[string]any
+
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("[TEST1]Failed. Got %v for %q\n", err, buf.String())
+	}
+
+	expected := map[string]any{"msg": "book deleted", "level": "INFO", "request_id": "req-1", "id": float64(7), "component": "test"}
+
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("[TEST1]Failed. Got %v=%v\tExpected %v\n", key, record[key], value)
+		}
+	}
+}
+
+// TestParseLevel function is to test level names
+func TestParseLevel(t *testing.T) {
+	testcases := []struct {
+		in       string
+		expected slog.Level
+	}{
+		{in: "debug", expected: slog.LevelDebug},
+		{in: "WARN", expected: slog.LevelWarn},
+		{in: "error", expected: slog.LevelError},
+		{in: "", expected: slog.LevelInfo},
+		{in: "loud", expected: slog.LevelInfo},
+	}
+
+	for i, v := range testcases {
+		if got := ParseLevel(v.in); got != v.expected {
+			t.Errorf("[TEST%d]Failed. Got %v\tExpected %v\n", i+1, got, v.expected)
+		}
+	}
+}
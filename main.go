@@ -1,51 +1,596 @@
 package main
 
 import (
-	"fmt"
-	"log"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/smtp"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+	"google.golang.org/grpc"
 
+	"Three-Layer-Architecture/cache"
+	datastoreapikey "Three-Layer-Architecture/datastore/apikey"
+	datastoreaudit "Three-Layer-Architecture/datastore/audit"
 	datastoreauthor "Three-Layer-Architecture/datastore/author"
 	datastorebook "Three-Layer-Architecture/datastore/book"
+	datastoreexport "Three-Layer-Architecture/datastore/export"
+	datastoregraphql "Three-Layer-Architecture/datastore/graphql"
+	datastorehealth "Three-Layer-Architecture/datastore/health"
+	datastoreimporter "Three-Layer-Architecture/datastore/importer"
+	datastoreloan "Three-Layer-Architecture/datastore/loan"
+	datastoreoai "Three-Layer-Architecture/datastore/oai"
+	datastoreoutbox "Three-Layer-Architecture/datastore/outbox"
+	datastorerbac "Three-Layer-Architecture/datastore/rbac"
+	datastorerevision "Three-Layer-Architecture/datastore/revision"
+	datastoresession "Three-Layer-Architecture/datastore/session"
+	datastoresru "Three-Layer-Architecture/datastore/sru"
+	datastoreuser "Three-Layer-Architecture/datastore/user"
+	datastorewebhook "Three-Layer-Architecture/datastore/webhook"
+	deliveryaudit "Three-Layer-Architecture/delivery/audit"
 	deliveryauthor "Three-Layer-Architecture/delivery/author"
 	deliverybook "Three-Layer-Architecture/delivery/book"
+	deliveryevents "Three-Layer-Architecture/delivery/events"
+	deliveryexport "Three-Layer-Architecture/delivery/export"
+	deliverygraphql "Three-Layer-Architecture/delivery/graphql"
+	deliveryhealth "Three-Layer-Architecture/delivery/health"
+	deliveryimporter "Three-Layer-Architecture/delivery/importer"
+	deliveryoai "Three-Layer-Architecture/delivery/oai"
+	deliveryrbac "Three-Layer-Architecture/delivery/rbac"
+	deliverysip2 "Three-Layer-Architecture/delivery/sip2"
+	deliverysru "Three-Layer-Architecture/delivery/sru"
+	deliverytrash "Three-Layer-Architecture/delivery/trash"
+	deliveryuser "Three-Layer-Architecture/delivery/user"
+	deliverywebhook "Three-Layer-Architecture/delivery/webhook"
 	"Three-Layer-Architecture/driver"
+	"Three-Layer-Architecture/librarypb"
+	"Three-Layer-Architecture/logging"
+	"Three-Layer-Architecture/metrics"
+	"Three-Layer-Architecture/middleware"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/notifier"
+	"Three-Layer-Architecture/ratelimit"
+	"Three-Layer-Architecture/service"
+	serviceaudit "Three-Layer-Architecture/service/audit"
+	serviceauth "Three-Layer-Architecture/service/auth"
 	serviceauthor "Three-Layer-Architecture/service/author"
 	servicebook "Three-Layer-Architecture/service/book"
+	serviceevents "Three-Layer-Architecture/service/events"
+	serviceexport "Three-Layer-Architecture/service/export"
+	servicegraphql "Three-Layer-Architecture/service/graphql"
+	servicehealth "Three-Layer-Architecture/service/health"
+	serviceimporter "Three-Layer-Architecture/service/importer"
+	serviceloan "Three-Layer-Architecture/service/loan"
+	serviceoai "Three-Layer-Architecture/service/oai"
+	serviceoutbox "Three-Layer-Architecture/service/outbox"
+	servicerbac "Three-Layer-Architecture/service/rbac"
+	servicerevision "Three-Layer-Architecture/service/revision"
+	servicesru "Three-Layer-Architecture/service/sru"
+	servicetrash "Three-Layer-Architecture/service/trash"
+	serviceuser "Three-Layer-Architecture/service/user"
+	servicewebhook "Three-Layer-Architecture/service/webhook"
+	"Three-Layer-Architecture/sink"
+	"Three-Layer-Architecture/tracing"
 )
 
 func main() {
+	slog.SetDefault(logging.New(os.Stdout, logging.ParseLevel(os.Getenv("LOG_LEVEL"))))
+
+	// Spans are exported by OTEL_TRACES_EXPORTER, otlp or stdout, and dropped by default
+	shutdownTracing, err := tracing.Setup(context.Background(), os.Getenv("OTEL_TRACES_EXPORTER"))
+	if err != nil {
+		slog.Error("could not set up tracing", "error", err)
+
+		return
+	}
+
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			slog.Error("could not flush spans", "error", err)
+		}
+	}()
+
 	db, err := driver.ConnectDB()
 	if err != nil {
-		log.Println("could not connect to sql, err:", err)
+		slog.Error("could not connect to sql", "error", err)
 
 		return
 	}
 
-	authorDatastore := datastoreauthor.New(db)
-	authorService := serviceauthor.New(authorDatastore)
+	defer db.Close()
+
+	// Stops on SIGTERM from orchestrator or Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	// Every change of books and authors is recorded in audit log
+	auditService := serviceaudit.New(datastoreaudit.New(db))
+	auditHandler := deliveryaudit.New(auditService)
+
+	// and every version of them is kept as revision
+	revisionService := servicerevision.New(datastorerevision.New(db))
+
+	// Loans and catalogue events from outbox are queued for webhooks and sent in the background with retries
+	webhookService := servicewebhook.New(datastorewebhook.New(db),
+		&http.Client{Timeout: durationFromEnv("WEBHOOK_TIMEOUT", 10*time.Second)}, servicewebhook.Config{
+			MaxAttempts: intFromEnv("WEBHOOK_MAX_ATTEMPTS", 8),
+			Backoff:     durationFromEnv("WEBHOOK_BACKOFF", 30*time.Second),
+			MaxBackoff:  durationFromEnv("WEBHOOK_MAX_BACKOFF", time.Hour),
+			BatchSize:   100,
+		})
+	webhookHandler := deliverywebhook.New(webhookService)
+
+	go webhookService.Schedule(ctx, durationFromEnv("WEBHOOK_POLL_INTERVAL", 10*time.Second))
+
+	// Book and author changes store their event in the same transaction, dispatcher hands them to sinks in order
+	sinks := []service.Sink{sink.NewWebhook(webhookService)}
+
+	if path := os.Getenv("OUTBOX_FILE"); path != "" {
+		fileSink, err := sink.NewFile(path)
+		if err != nil {
+			slog.Error("could not open outbox file", "error", err)
+
+			return
+		}
+
+		defer fileSink.Close()
+
+		sinks = append(sinks, fileSink)
+	}
+
+	// Dashboards follow changes over server-sent events, resuming from the latest events kept in memory
+	eventBus := sink.NewBus(intFromEnv("EVENTS_REPLAY_SIZE", 1000))
+	eventsHandler := deliveryevents.New(serviceevents.New(eventBus, 64),
+		durationFromEnv("EVENTS_HEARTBEAT", 15*time.Second))
+
+	// Catalogue reads are cached, writes drop the entries they change and changes of other replicas or of librarian
+	// command are dropped when their events come out of the outbox
+	catalogueCache := cache.NewMemory(intFromEnv("CACHE_SIZE", 10000))
+	cacheTTL := durationFromEnv("CACHE_TTL", 5*time.Minute)
+
+	authorDatastore := datastoreauthor.NewCached(datastoreauthor.New(db), catalogueCache, cacheTTL)
+	authorService := serviceauthor.NewTraced(serviceauthor.New(authorDatastore, revisionService))
 	authorHandler := deliveryauthor.New(authorService)
 
-	bookDatastore := datastorebook.New(db)
-	bookService := servicebook.New(bookDatastore)
+	bookDatastore := datastorebook.NewCached(datastorebook.New(db), catalogueCache, cacheTTL)
+	bookService := servicebook.NewTraced(servicebook.New(bookDatastore, revisionService))
 	bookHandler := deliverybook.New(bookService)
 
-	r := mux.NewRouter()
+	outboxService := serviceoutbox.New(datastoreoutbox.New(db), serviceoutbox.Config{
+		BatchSize: intFromEnv("OUTBOX_BATCH_SIZE", 100),
+		Retention: durationFromEnv("OUTBOX_RETENTION", 7*24*time.Hour),
+	}, sinks, []service.Sink{eventBus, sink.NewCache(bookDatastore, authorDatastore)})
+
+	go outboxService.Schedule(ctx, durationFromEnv("OUTBOX_POLL_INTERVAL", time.Second))
+
+	// Imported rows are checked by book and author services and stored in batches
+	importDatastore := datastoreimporter.NewCached(datastoreimporter.New(db), catalogueCache)
+	importService := serviceimporter.New(bookService, authorService, importDatastore)
+	importHandler := deliveryimporter.New(importService)
+
+	// Exports read books from a cursor and skip the cache
+	exportHandler := deliveryexport.New(serviceexport.New(datastoreexport.New(db)))
+
+	// Harvesters page through the catalogue over OAI-PMH
+	oaiConfig := serviceoai.Config{
+		Name:         envOr("OAI_REPOSITORY_NAME", "Library"),
+		BaseURL:      envOr("OAI_BASE_URL", "http://localhost:8000/oai"),
+		AdminEmail:   envOr("OAI_ADMIN_EMAIL", "admin@localhost"),
+		RepositoryID: envOr("OAI_REPOSITORY_ID", "localhost"),
+		PageSize:     intFromEnv("OAI_PAGE_SIZE", 100),
+	}
+	oaiHandler := deliveryoai.New(serviceoai.New(datastoreoai.New(db), oaiConfig), oaiConfig.BaseURL)
+
+	// Other catalogues search books with CQL over SRU
+	sruHandler := deliverysru.New(servicesru.New(datastoresru.New(db), intFromEnv("SRU_MAX_RECORDS", 100)), oaiConfig.Name)
+
+	// Deleted rows stay in trash for retention period and then purged
+	trashService := servicetrash.New(bookDatastore, authorDatastore, durationFromEnv("TRASH_RETENTION", 30*24*time.Hour))
+	trashHandler := deliverytrash.New(trashService)
+
+	go trashService.Schedule(ctx, durationFromEnv("TRASH_PURGE_INTERVAL", time.Hour))
+
+	jwtConfig, err := jwtConfigFromEnv()
+	if err != nil {
+		slog.Error("invalid jwt configuration", "error", err)
+
+		return
+	}
+
+	// Users log in with password, reset links are sent by the notifier
+	userService := serviceuser.New(datastoreuser.New(db), datastoresession.New(db), notifierFromEnv(), serviceuser.Config{
+		SessionTTL:  durationFromEnv("SESSION_TTL", 24*time.Hour),
+		ResetTTL:    durationFromEnv("PASSWORD_RESET_TTL", time.Hour),
+		MaxFailures: 5,
+		LockoutFor:  durationFromEnv("LOCKOUT_DURATION", 15*time.Minute),
+		ResetURL:    os.Getenv("PASSWORD_RESET_URL"),
+	})
+	userHandler := deliveryuser.New(userService)
+
+	authService := serviceauth.New(datastoreapikey.New(db), jwtConfig, userService)
+
+	// Requests are limited per client address before authentication and per caller after it
+	limiter := ratelimit.NewMemory()
+	limit := func(group string, def models.RateLimit, key func(*http.Request) string) func(http.Handler) http.Handler {
+		def.Group = group
+
+		return middleware.RateLimit(limiter, rateLimitFromEnv("RATE_LIMIT_"+strings.ToUpper(group), def), key)
+	}
+
+	healthService := servicehealth.New(datastorehealth.New(db), durationFromEnv("READY_TIMEOUT", 2*time.Second))
+	healthHandler := deliveryhealth.New(healthService)
+
+	// Requests of every route are counted, /metrics also has connection pool stats
+	metrics.RegisterDB(db)
+
+	router := mux.NewRouter()
+	router.Use(otelmux.Middleware(tracing.ServiceName), middleware.RequestID, middleware.AccessLog, middleware.Metrics)
+
+	// Health and metrics endpoints for probes and scrapers, they are neither limited nor authenticated
+	router.HandleFunc("/healthz", healthHandler.Live).Methods(http.MethodGet)
+	router.HandleFunc("/readyz", healthHandler.Ready).Methods(http.MethodGet)
+	router.Handle("/metrics", metrics.Handler()).Methods(http.MethodGet)
+
+	public := router.PathPrefix("/").Subrouter()
+	public.Use(limit("ip", models.RateLimit{Requests: 600, Per: time.Minute}, middleware.ClientIP))
+
+	// Public endpoints, password guessing is slowed down by a tight limit
+	login := limit("login", models.RateLimit{Requests: 10, Per: time.Minute}, middleware.ClientIP)
+
+	public.Handle("/login", login(http.HandlerFunc(userHandler.Login))).Methods(http.MethodPost)
+	public.Handle("/password/forgot", login(http.HandlerFunc(userHandler.ForgotPassword))).Methods(http.MethodPost)
+	public.Handle("/password/reset", login(http.HandlerFunc(userHandler.ResetPassword))).Methods(http.MethodPost)
+
+	// Every other endpoint needs an api key, a bearer token or a session
+	r := public.PathPrefix("/").Subrouter()
+	r.Use(middleware.Authenticate(authService))
+	r.Use(middleware.ReadWrite(
+		limit("read", models.RateLimit{Requests: 300, Per: time.Minute}, middleware.Caller),
+		limit("write", models.RateLimit{Requests: 60, Per: time.Minute}, middleware.Caller),
+	))
+
+	// Every handler checks the caller holds the permission for its action
+	rbacService := servicerbac.New(datastorerbac.New(db))
+	rbacHandler := deliveryrbac.New(rbacService)
+
+	can := func(permission string, handler http.HandlerFunc) http.Handler {
+		return middleware.Authorize(rbacService, permission)(handler)
+	}
+
+	// Front-ends fetch books with their authors in one round trip over GraphQL, mutations check their own permission
+	graphqlHandler, err := deliverygraphql.New(bookService, authorService, servicegraphql.New(datastoregraphql.New(db)),
+		rbacService, deliverygraphql.Limits{
+			MaxDepth:      intFromEnv("GRAPHQL_MAX_DEPTH", 7),
+			MaxComplexity: intFromEnv("GRAPHQL_MAX_COMPLEXITY", 1000),
+		})
+	if err != nil {
+		slog.Error("invalid graphql schema", "error", err)
+
+		return
+	}
 
 	// Author endpoints
-	r.HandleFunc("/author", authorHandler.Post).Methods(http.MethodPost)
-	r.HandleFunc("/author/{id}", authorHandler.Update).Methods(http.MethodPut)
-	r.HandleFunc("/author/{id}", authorHandler.Delete).Methods(http.MethodDelete)
+	r.Handle("/author", can(models.PermAuthorCreate, authorHandler.Post)).Methods(http.MethodPost)
+	r.Handle("/author/{id}", can(models.PermAuthorUpdate, authorHandler.Update)).Methods(http.MethodPut)
+	r.Handle("/author/{id}", can(models.PermAuthorDelete, authorHandler.Delete)).Methods(http.MethodDelete)
+	r.Handle("/author/{id}/restore", can(models.PermAuthorRestore, authorHandler.Restore)).Methods(http.MethodPost)
+	r.Handle("/author/{id}/revisions", can(models.PermAuthorRead, authorHandler.GetRevisions)).Methods(http.MethodGet)
 
 	// Book endpoints
-	r.HandleFunc("/books", bookHandler.GetAll).Methods(http.MethodGet)
-	r.HandleFunc("/book/{id}", bookHandler.Getbyid).Methods(http.MethodGet)
-	r.HandleFunc("/book", bookHandler.Post).Methods(http.MethodPost)
-	r.HandleFunc("/book/{id}", bookHandler.Update).Methods(http.MethodPut)
-	r.HandleFunc("/book/{id}", bookHandler.Delete).Methods(http.MethodDelete)
-
-	fmt.Println("Server Started And Listening..!!")
-	log.Fatal(http.ListenAndServe(":8000", r))
+	r.Handle("/books", can(models.PermBookRead, bookHandler.GetAll)).Methods(http.MethodGet)
+	r.Handle("/book/{id}", can(models.PermBookRead, bookHandler.Getbyid)).Methods(http.MethodGet)
+	r.Handle("/book", can(models.PermBookCreate, bookHandler.Post)).Methods(http.MethodPost)
+	r.Handle("/book/{id}", can(models.PermBookUpdate, bookHandler.Update)).Methods(http.MethodPut)
+	r.Handle("/book/{id}", can(models.PermBookDelete, bookHandler.Delete)).Methods(http.MethodDelete)
+	r.Handle("/book/{id}/restore", can(models.PermBookRestore, bookHandler.Restore)).Methods(http.MethodPost)
+	r.Handle("/book/{id}/cite", can(models.PermBookRead, bookHandler.Cite)).Methods(http.MethodGet)
+	r.Handle("/book/{id}/revisions", can(models.PermBookRead, bookHandler.GetRevisions)).Methods(http.MethodGet)
+	r.Handle("/book/{id}/revisions/diff", can(models.PermBookRead, bookHandler.DiffRevisions)).Methods(http.MethodGet)
+	r.Handle("/book/{id}/revert/{rev}", can(models.PermBookUpdate, bookHandler.Revert)).Methods(http.MethodPost)
+
+	// Import and export endpoints
+	r.Handle("/import", can(models.PermBookImport, importHandler.Import)).Methods(http.MethodPost)
+	r.Handle("/export", can(models.PermBookExport, exportHandler.Export)).Methods(http.MethodGet)
+
+	// OAI-PMH endpoint
+	r.Handle("/oai", can(models.PermBookRead, oaiHandler.Handle)).Methods(http.MethodGet, http.MethodPost)
+
+	// SRU endpoint
+	r.Handle("/sru", can(models.PermBookRead, sruHandler.Handle)).Methods(http.MethodGet)
+
+	// GraphQL endpoint
+	r.Handle("/graphql", can(models.PermBookRead, graphqlHandler.Handle)).Methods(http.MethodGet, http.MethodPost)
+
+	// Trash endpoints
+	r.Handle("/trash", can(models.PermTrashRead, trashHandler.GetAll)).Methods(http.MethodGet)
+
+	// Audit endpoints
+	r.Handle("/audit", can(models.PermAuditRead, auditHandler.Get)).Methods(http.MethodGet)
+
+	// Role endpoints
+	r.Handle("/roles", can(models.PermRoleManage, rbacHandler.GetRoles)).Methods(http.MethodGet)
+	r.Handle("/principals/{principal}/roles", can(models.PermRoleManage, rbacHandler.GetAssignments)).Methods(http.MethodGet)
+	r.Handle("/principals/{principal}/roles/{role}", can(models.PermRoleManage, rbacHandler.Assign)).Methods(http.MethodPut)
+	r.Handle("/principals/{principal}/roles/{role}", can(models.PermRoleManage, rbacHandler.Unassign)).Methods(http.MethodDelete)
+
+	// Webhook endpoints
+	r.Handle("/webhooks", can(models.PermWebhookManage, webhookHandler.Post)).Methods(http.MethodPost)
+	r.Handle("/webhooks", can(models.PermWebhookManage, webhookHandler.GetAll)).Methods(http.MethodGet)
+	r.Handle("/webhooks/{id}", can(models.PermWebhookManage, webhookHandler.Get)).Methods(http.MethodGet)
+	r.Handle("/webhooks/{id}", can(models.PermWebhookManage, webhookHandler.Update)).Methods(http.MethodPut)
+	r.Handle("/webhooks/{id}", can(models.PermWebhookManage, webhookHandler.Delete)).Methods(http.MethodDelete)
+	r.Handle("/webhooks/{id}/deliveries", can(models.PermWebhookManage, webhookHandler.Deliveries)).Methods(http.MethodGet)
+	r.Handle("/webhooks/{id}/deliveries/{delivery}/redeliver",
+		can(models.PermWebhookManage, webhookHandler.Redeliver)).Methods(http.MethodPost)
+
+	// Live catalogue changes
+	r.Handle("/events", can(models.PermBookRead, eventsHandler.Stream)).Methods(http.MethodGet)
+
+	// User endpoints
+	r.HandleFunc("/logout", userHandler.Logout).Methods(http.MethodPost)
+	r.HandleFunc("/users/me", userHandler.Current).Methods(http.MethodGet)
+	r.Handle("/users", can(models.PermUserManage, userHandler.Register)).Methods(http.MethodPost)
+	r.Handle("/users/{id}/sessions", can(models.PermUserManage, userHandler.RevokeSessions)).Methods(http.MethodDelete)
+
+	server := &http.Server{
+		Addr:              ":8000",
+		Handler:           router,
+		ReadHeaderTimeout: durationFromEnv("READ_HEADER_TIMEOUT", 5*time.Second),
+		ReadTimeout:       durationFromEnv("READ_TIMEOUT", 30*time.Second),
+		WriteTimeout:      durationFromEnv("WRITE_TIMEOUT", 60*time.Second),
+		IdleTimeout:       durationFromEnv("IDLE_TIMEOUT", 2*time.Minute),
+	}
+
+	// event streams never go idle, ending them lets shutdown drain the other requests
+	server.RegisterOnShutdown(eventBus.Close)
+
+	go func() {
+		slog.Info("server started", "addr", server.Addr)
+
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("server failed", "error", err)
+			stop()
+		}
+	}()
+
+	// Internal services call books and authors over gRPC on a port of its own, with the same callers and permissions
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(middleware.GRPCUnary(authService, rbacService, grpcPermissions)),
+		grpc.ChainStreamInterceptor(middleware.GRPCStream(authService, rbacService, grpcPermissions)),
+	)
+	librarypb.RegisterBookServiceServer(grpcServer, deliverybook.NewServer(bookService))
+	librarypb.RegisterAuthorServiceServer(grpcServer, deliveryauthor.NewServer(authorService))
+
+	grpcAddr := envOr("GRPC_ADDR", ":9000")
+
+	grpcListener, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		slog.Error("could not listen for grpc", "addr", grpcAddr, "error", err)
+
+		return
+	}
+
+	go func() {
+		slog.Info("grpc server started", "addr", grpcAddr)
+
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			slog.Error("grpc server failed", "error", err)
+			stop()
+		}
+	}()
+
+	// Self-service kiosks check books out and in over SIP2 when SIP2_ADDR is set
+	if addr := os.Getenv("SIP2_ADDR"); addr != "" {
+		loanService := serviceloan.New(datastoreloan.New(db), bookService, models.LoanPolicy{
+			Period:      durationFromEnv("LOAN_PERIOD", 21*24*time.Hour),
+			MaxRenewals: intFromEnv("LOAN_MAX_RENEWALS", 2),
+			MaxLoans:    intFromEnv("LOAN_MAX_ITEMS", 10),
+		})
+		sip2Server := deliverysip2.New(userService, rbacService, bookService, loanService, deliverysip2.Config{
+			InstitutionID: envOr("SIP2_INSTITUTION_ID", "library"),
+			LibraryName:   envOr("OAI_REPOSITORY_NAME", "Library"),
+			Location:      envOr("SIP2_LOCATION", "Stacks"),
+			Timeout:       durationFromEnv("SIP2_IDLE_TIMEOUT", 5*time.Minute),
+		})
+
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			slog.Error("could not listen for sip2", "addr", addr, "error", err)
+
+			return
+		}
+
+		go func() {
+			slog.Info("sip2 server started", "addr", addr)
+
+			if err := sip2Server.Serve(ctx, listener); err != nil {
+				slog.Error("sip2 server failed", "error", err)
+			}
+		}()
+
+		defer listener.Close()
+	}
+
+	<-ctx.Done()
+
+	// Probes see the server as not ready while in-flight requests finish, new requests are still served until load
+	// balancers have seen it
+	healthService.Drain()
+	time.Sleep(durationFromEnv("DRAIN_DELAY", 5*time.Second))
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), durationFromEnv("SHUTDOWN_TIMEOUT", 30*time.Second))
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("could not drain connections", "error", err)
+	}
+
+	// running calls finish unless the shutdown timeout is over
+	stopped := make(chan struct{})
+
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		grpcServer.Stop()
+	}
+
+	slog.Info("server stopped")
+}
+
+// grpcPermissions is the permission each gRPC method needs, like the routes of the same action
+var grpcPermissions = map[string]string{
+	librarypb.BookService_GetBook_FullMethodName:           models.PermBookRead,
+	librarypb.BookService_ListBooks_FullMethodName:         models.PermBookRead,
+	librarypb.BookService_CreateBook_FullMethodName:        models.PermBookCreate,
+	librarypb.BookService_ValidateBook_FullMethodName:      models.PermBookCreate,
+	librarypb.BookService_UpdateBook_FullMethodName:        models.PermBookUpdate,
+	librarypb.BookService_DeleteBook_FullMethodName:        models.PermBookDelete,
+	librarypb.BookService_RestoreBook_FullMethodName:       models.PermBookRestore,
+	librarypb.BookService_ListBookRevisions_FullMethodName: models.PermBookRead,
+	librarypb.BookService_DiffBookRevisions_FullMethodName: models.PermBookRead,
+	librarypb.BookService_RevertBook_FullMethodName:        models.PermBookUpdate,
+
+	librarypb.AuthorService_CreateAuthor_FullMethodName:        models.PermAuthorCreate,
+	librarypb.AuthorService_ValidateAuthor_FullMethodName:      models.PermAuthorCreate,
+	librarypb.AuthorService_UpdateAuthor_FullMethodName:        models.PermAuthorUpdate,
+	librarypb.AuthorService_DeleteAuthor_FullMethodName:        models.PermAuthorDelete,
+	librarypb.AuthorService_RestoreAuthor_FullMethodName:       models.PermAuthorRestore,
+	librarypb.AuthorService_ListAuthorRevisions_FullMethodName: models.PermAuthorRead,
+}
+
+// durationFromEnv reads a duration like "720h" from environment, def is used when it is unset or invalid
+func durationFromEnv(key string, def time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Warn("invalid duration, using default", "key", key, "error", err, "default", def)
+
+		return def
+	}
+
+	return d
+}
+
+// envOr returns the value of key, or def when it is not set
+func envOr(key, def string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+
+	return def
+}
+
+// intFromEnv reads a positive number from environment, def is used when it is unset or invalid
+func intFromEnv(key string, def int) int {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		slog.Warn("invalid number, using default", "key", key, "value", value, "default", def)
+
+		return def
+	}
+
+	return n
+}
+
+// jwtConfigFromEnv reads accepted bearer tokens, JWT_HS256_SECRET and/or JWT_RS256_PUBLIC_KEY (path of PEM file)
+func jwtConfigFromEnv() (serviceauth.JWTConfig, error) {
+	config := serviceauth.JWTConfig{
+		HMACSecret: []byte(os.Getenv("JWT_HS256_SECRET")),
+		Issuer:     os.Getenv("JWT_ISSUER"),
+		Audience:   os.Getenv("JWT_AUDIENCE"),
+	}
+
+	if path := os.Getenv("JWT_RS256_PUBLIC_KEY"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return serviceauth.JWTConfig{}, err
+		}
+
+		if config.RSAPublicKey, err = serviceauth.ParseRSAPublicKey(data); err != nil {
+			return serviceauth.JWTConfig{}, err
+		}
+	}
+
+	return config, nil
+}
+
+// notifierFromEnv sends mail through SMTP_ADDR when it is set, otherwise messages are only logged
+func notifierFromEnv() service.Notifier {
+	addr := os.Getenv("SMTP_ADDR")
+	if addr == "" {
+		return notifier.Log{}
+	}
+
+	var auth smtp.Auth
+
+	if username := os.Getenv("SMTP_USERNAME"); username != "" {
+		host, _, _ := strings.Cut(addr, ":")
+		auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+	}
+
+	return notifier.SMTP{Addr: addr, From: os.Getenv("SMTP_FROM"), Auth: auth}
+}
+
+// rateLimitFromEnv reads a limit like "300/1m" or "300/1m/50" with burst of 50, def is used when it is unset or invalid
+func rateLimitFromEnv(key string, def models.RateLimit) models.RateLimit {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+
+	parts := strings.Split(value, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		slog.Warn("invalid rate limit, using default", "key", key, "value", value, "default", def)
+
+		return def
+	}
+
+	limit := models.RateLimit{Group: def.Group}
+
+	var err error
+
+	if limit.Requests, err = strconv.Atoi(parts[0]); err != nil || limit.Requests <= 0 {
+		slog.Warn("invalid rate limit, using default", "key", key, "value", value, "default", def)
+
+		return def
+	}
+
+	if limit.Per, err = time.ParseDuration(parts[1]); err != nil || limit.Per <= 0 {
+		slog.Warn("invalid rate limit, using default", "key", key, "value", value, "default", def)
+
+		return def
+	}
+
+	if len(parts) == 3 {
+		if limit.Burst, err = strconv.Atoi(parts[2]); err != nil {
+			slog.Warn("invalid rate limit, using default", "key", key, "value", value, "default", def)
+
+			return def
+		}
+	}
+
+	return limit
 }
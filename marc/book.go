@@ -0,0 +1,146 @@
+package marc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"Three-Layer-Architecture/models"
+)
+
+// Fields mapped to books.
+// 001 is the book id, 020 $a the ISBN, 046 $k the published date as yyyymmdd, 100 the author with $a "last, first",
+// $c pen name, $d date of birth and $0 author id, 245 $a the title and 264 $b, $c the publication and year.
+// Records of other catalogues without 046 get the first day of the year of 264 $c, or of 260 $c in older records.
+const (
+	TagControlNumber = "001"
+	TagISBN          = "020"
+	TagDates         = "046"
+	TagMainEntry     = "100"
+	TagTitle         = "245"
+	TagImprint       = "260"
+	TagPublication   = "264"
+)
+
+// dateLayout is the published date of books, codedLayout the same date in 046 $k
+const (
+	dateLayout  = "02/01/2006"
+	codedLayout = "20060102"
+)
+
+// FromBook returns the MARC record of a book and its author
+func FromBook(book models.Book) Record {
+	author := book.Auth
+
+	var data []DataField
+
+	// 020 is left out of books without an ISBN
+	if book.ISBN != "" {
+		data = append(data, DataField{Tag: TagISBN, Ind1: ' ', Ind2: ' ',
+			Subfields: []Subfield{{Code: 'a', Value: book.ISBN}}})
+	}
+
+	// 264 $c only has the year, the day and month are kept in 046 so they come back on import
+	year := book.PublishedDate
+
+	if date, err := time.Parse(dateLayout, book.PublishedDate); err == nil {
+		year = strconv.Itoa(date.Year())
+		data = append(data, DataField{Tag: TagDates, Ind1: ' ', Ind2: ' ',
+			Subfields: []Subfield{{Code: 'k', Value: date.Format(codedLayout)}}})
+	}
+
+	return Record{
+		Leader:  leader,
+		Control: []ControlField{{Tag: TagControlNumber, Value: strconv.Itoa(book.BookID)}},
+		Data: append(data,
+			DataField{Tag: TagMainEntry, Ind1: '1', Ind2: ' ', Subfields: []Subfield{
+				{Code: 'a', Value: author.LastName + ", " + author.FirstName},
+				{Code: 'c', Value: author.PenName},
+				{Code: 'd', Value: author.Dob},
+				{Code: '0', Value: strconv.Itoa(book.AuthorID)},
+			}},
+			DataField{Tag: TagTitle, Ind1: '1', Ind2: '0', Subfields: []Subfield{{Code: 'a', Value: book.Title}}},
+			DataField{Tag: TagPublication, Ind1: ' ', Ind2: '1', Subfields: []Subfield{
+				{Code: 'b', Value: book.Publication},
+				{Code: 'c', Value: year},
+			}},
+		),
+	}
+}
+
+// ToBook returns the book and author of a MARC record, fields are not validated beyond their ids.
+// The catalogue has no ids of its own for records of other systems, so 001 has to be the book id and 100 $0 the
+// author id, records with other control numbers like OCLC ones are refused with the reason.
+func ToBook(rec Record) (models.Book, error) {
+	var (
+		book models.Book
+		err  error
+	)
+
+	control := strings.TrimSpace(rec.ControlValue(TagControlNumber))
+	if control == "" {
+		return models.Book{}, fmt.Errorf("missing control number %v, it has to be the book id", TagControlNumber)
+	}
+
+	if book.BookID, err = strconv.Atoi(control); err != nil {
+		return models.Book{}, fmt.Errorf("control number %q of %v is not a book id", control, TagControlNumber)
+	}
+
+	authorID := strings.TrimSpace(rec.Subfield(TagMainEntry, '0'))
+	if authorID == "" {
+		return models.Book{}, fmt.Errorf("missing author id in %v $0", TagMainEntry)
+	}
+
+	if book.AuthorID, err = strconv.Atoi(authorID); err != nil {
+		return models.Book{}, fmt.Errorf("author id %q of %v $0 is not a number", authorID, TagMainEntry)
+	}
+
+	last, first, _ := strings.Cut(trimISBD(rec.Subfield(TagMainEntry, 'a')), ",")
+
+	book.Auth = models.Author{AuthID: book.AuthorID, FirstName: strings.TrimSpace(first), LastName: strings.TrimSpace(last),
+		Dob: strings.TrimSpace(rec.Subfield(TagMainEntry, 'd')), PenName: strings.TrimSpace(rec.Subfield(TagMainEntry, 'c'))}
+
+	// cataloguing rules end title and publisher with ISBD punctuation
+	book.Title = trimISBD(rec.Subfield(TagTitle, 'a'))
+
+	publication := TagPublication
+	if !rec.has(TagPublication) {
+		publication = TagImprint
+	}
+
+	book.Publication = trimISBD(rec.Subfield(publication, 'b'))
+	book.PublishedDate = publishedDate(rec.Subfield(TagDates, 'k'), rec.Subfield(publication, 'c'))
+
+	// $a may carry a qualifier after the number like "8129115301 (pbk.)" and hyphens are not kept
+	isbn, _, _ := strings.Cut(strings.TrimSpace(rec.Subfield(TagISBN, 'a')), " ")
+	book.ISBN = strings.ReplaceAll(isbn, "-", "")
+
+	return book, nil
+}
+
+// publishedDate gives the dd/mm/yyyy date of 046 $k, else of $c when it is a full date, else the first day of the
+// year in $c like "c2016." or "[2016?]", empty when there is no year
+func publishedDate(coded, field string) string {
+	if date, err := time.Parse(codedLayout, strings.TrimSpace(coded)); err == nil {
+		return date.Format(dateLayout)
+	}
+
+	field = trimISBD(field)
+
+	if _, err := time.Parse(dateLayout, field); err == nil {
+		return field
+	}
+
+	for i := 0; i+4 <= len(field); i++ {
+		if _, ok := number(field[i : i+4]); ok {
+			return "01/01/" + field[i:i+4]
+		}
+	}
+
+	return ""
+}
+
+func trimISBD(s string) string {
+	return strings.TrimSpace(strings.TrimRight(strings.TrimSpace(s), " /:;,."))
+}
@@ -0,0 +1,243 @@
+package marc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ISO 2709 delimiters
+const (
+	subfieldDelimiter = 0x1F
+	fieldTerminator   = 0x1E
+	recordTerminator  = 0x1D
+)
+
+// the directory has 4 digits for the length of a field and 5 for the length of a record
+const (
+	leaderLength   = 24
+	entryLength    = 12
+	maxFieldLength = 9999
+	maxLength      = 99999
+)
+
+var ErrInvalidRecord = errors.New("invalid MARC record")
+
+// Reader reads ISO 2709 records one after another
+type Reader struct {
+	r *bufio.Reader
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read returns the next record, io.EOF after the last one
+func (m *Reader) Read() (Record, error) {
+	// line breaks between records are left by some exporting systems
+	for {
+		b, err := m.r.Peek(1)
+		if err != nil {
+			return Record{}, err
+		}
+
+		if b[0] != '\n' && b[0] != '\r' {
+			break
+		}
+
+		if _, err = m.r.Discard(1); err != nil {
+			return Record{}, err
+		}
+	}
+
+	head := make([]byte, 5)
+	if _, err := io.ReadFull(m.r, head); err != nil {
+		return Record{}, fmt.Errorf("%w : %v", ErrInvalidRecord, err)
+	}
+
+	length, err := strconv.Atoi(string(head))
+	if err != nil || length <= leaderLength {
+		return Record{}, fmt.Errorf("%w : record length %q", ErrInvalidRecord, head)
+	}
+
+	raw := make([]byte, length)
+	copy(raw, head)
+
+	if _, err = io.ReadFull(m.r, raw[5:]); err != nil {
+		return Record{}, fmt.Errorf("%w : %v", ErrInvalidRecord, err)
+	}
+
+	return Decode(raw)
+}
+
+// Decode parses one ISO 2709 record
+func Decode(raw []byte) (Record, error) {
+	if len(raw) <= leaderLength || raw[len(raw)-1] != recordTerminator {
+		return Record{}, fmt.Errorf("%w : missing record terminator", ErrInvalidRecord)
+	}
+
+	rec := Record{Leader: string(raw[:leaderLength])}
+
+	if length, ok := number(rec.Leader[:5]); !ok || length != len(raw) {
+		return Record{}, fmt.Errorf("%w : record length %q", ErrInvalidRecord, rec.Leader[:5])
+	}
+
+	// directory and its terminator come before the fields, the record terminator after them
+	base, ok := number(rec.Leader[12:17])
+	if !ok || base <= leaderLength || base >= len(raw) {
+		return Record{}, fmt.Errorf("%w : base address %q", ErrInvalidRecord, rec.Leader[12:17])
+	}
+
+	directory := raw[leaderLength : base-1]
+	if len(directory)%entryLength != 0 {
+		return Record{}, fmt.Errorf("%w : directory length %d", ErrInvalidRecord, len(directory))
+	}
+
+	for i := 0; i < len(directory); i += entryLength {
+		entry := string(directory[i : i+entryLength])
+		tag := entry[:3]
+
+		length, ok1 := number(entry[3:7])
+		start, ok2 := number(entry[7:12])
+
+		if !ok1 || !ok2 || length < 1 || base+start+length > len(raw)-1 {
+			return Record{}, fmt.Errorf("%w : directory entry %q", ErrInvalidRecord, entry)
+		}
+
+		// field terminator is part of the length
+		field := raw[base+start : base+start+length-1]
+
+		if tag < "010" {
+			rec.Control = append(rec.Control, ControlField{Tag: tag, Value: string(field)})
+
+			continue
+		}
+
+		if len(field) < 2 {
+			return Record{}, fmt.Errorf("%w : field %v without indicators", ErrInvalidRecord, tag)
+		}
+
+		df := DataField{Tag: tag, Ind1: field[0], Ind2: field[1]}
+
+		for _, sf := range strings.Split(string(field[2:]), string(rune(subfieldDelimiter))) {
+			if sf == "" {
+				continue
+			}
+
+			df.Subfields = append(df.Subfields, Subfield{Code: sf[0], Value: sf[1:]})
+		}
+
+		rec.Data = append(rec.Data, df)
+	}
+
+	return rec, nil
+}
+
+// number parses the digits of a leader or directory field, signs and spaces are refused so offsets are never negative
+func number(s string) (int, bool) {
+	n := 0
+
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+
+		n = n*10 + int(s[i]-'0')
+	}
+
+	return n, s != ""
+}
+
+// Writer writes ISO 2709 records one after another
+type Writer struct {
+	w io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (m *Writer) Write(rec Record) error {
+	raw, err := Encode(rec)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.w.Write(raw)
+
+	return err
+}
+
+// Encode gives the ISO 2709 form of a record, record length and base address of the leader are computed
+func Encode(rec Record) ([]byte, error) {
+	var directory, fields strings.Builder
+
+	add := func(tag, field string) error {
+		if len(field)+1 > maxFieldLength {
+			return fmt.Errorf("%w : field %v longer than %d bytes", ErrInvalidRecord, tag, maxFieldLength)
+		}
+
+		fmt.Fprintf(&directory, "%3s%04d%05d", tag, len(field)+1, fields.Len())
+		fields.WriteString(field)
+		fields.WriteByte(fieldTerminator)
+
+		return nil
+	}
+
+	for _, f := range rec.Control {
+		if err := add(f.Tag, f.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range rec.Data {
+		var b strings.Builder
+
+		b.WriteByte(indicator(f.Ind1))
+		b.WriteByte(indicator(f.Ind2))
+
+		for _, s := range f.Subfields {
+			b.WriteByte(subfieldDelimiter)
+			b.WriteByte(s.Code)
+			b.WriteString(s.Value)
+		}
+
+		if err := add(f.Tag, b.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	directory.WriteByte(fieldTerminator)
+
+	base := leaderLength + directory.Len()
+	length := base + fields.Len() + 1
+
+	if length > maxLength {
+		return nil, fmt.Errorf("%w : record longer than %d bytes", ErrInvalidRecord, maxLength)
+	}
+
+	head := rec.Leader
+	if len(head) != leaderLength {
+		head = leader
+	}
+
+	raw := make([]byte, 0, length)
+	raw = fmt.Appendf(raw, "%05d%s%05d%s", length, head[5:12], base, head[17:])
+	raw = append(raw, directory.String()...)
+	raw = append(raw, fields.String()...)
+	raw = append(raw, recordTerminator)
+
+	return raw, nil
+}
+
+// indicator writes blank for unset indicators
+func indicator(b byte) byte {
+	if b == 0 {
+		return ' '
+	}
+
+	return b
+}
@@ -0,0 +1,208 @@
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"Three-Layer-Architecture/models"
+)
+
+var (
+	states = models.Book{BookID: 1, AuthorID: 1,
+		Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
+		Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}
+	mockingbird = models.Book{BookID: 2, AuthorID: 2,
+		Auth:  models.Author{AuthID: 2, FirstName: "Nelle Harper", LastName: "Lee", Dob: "28/04/1926", PenName: "Harper Lee"},
+		Title: "To Kill a Mockingbird – 50th Anniversary", Publication: "Penguin", PublishedDate: "11/07/1960",
+		ISBN: "9780060935467"}
+)
+
+// statesRecord is the ISO 2709 form of states, which has no ISBN and so no 020, 264 $c has the year and 046 the date
+const statesRecord = "00177nam a2200085 i 4500001000200000046001300002100004200015245001300057264002100070\x1e1\x1e" +
+	"  \x1fk20160316\x1e1 \x1faBhagat, Chetan\x1fcChetan\x1fd06/04/2001\x1f01\x1e10\x1fa2 States\x1e" +
+	" 1\x1fbScholastic\x1fc2016\x1e\x1d"
+
+// TestEncode function is to test leader, directory and fields of a written record
+func TestEncode(t *testing.T) {
+	raw, err := Encode(FromBook(states))
+	if err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "encode", 1, err, nil)
+	}
+
+	if string(raw) != statesRecord {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %q\tExpected %q\n", "encode", 1, raw, statesRecord)
+	}
+}
+
+// TestReader_RoundTrip function is to test books written as ISO 2709 are read back the same
+func TestReader_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := NewWriter(&buf)
+
+	for _, b := range []models.Book{states, mockingbird} {
+		if err := w.Write(FromBook(b)); err != nil {
+			t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "write", 1, err, nil)
+		}
+	}
+
+	// a line break between records is skipped
+	buf.WriteString("\n")
+
+	r := NewReader(&buf)
+
+	for i, expected := range []models.Book{states, mockingbird} {
+		rec, err := r.Read()
+		if err != nil {
+			t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "read", i+1, err, nil)
+		}
+
+		book, err := ToBook(rec)
+		if err != nil || !reflect.DeepEqual(book, expected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "round trip", i+1, book, expected)
+		}
+	}
+
+	if _, err := r.Read(); !errors.Is(err, io.EOF) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "end", 3, err, io.EOF)
+	}
+}
+
+// TestToBook function is to test records of other systems, with ISBN and ISBD punctuation, are mapped
+func TestToBook(t *testing.T) {
+	rec := Record{
+		Control: []ControlField{{Tag: "001", Value: "7"}, {Tag: "008", Value: "160316s2016    ii            000 1 eng d"}},
+		Data: []DataField{
+			{Tag: "020", Ind1: ' ', Ind2: ' ', Subfields: []Subfield{{Code: 'a', Value: "978-81-291-3552-0 (pbk.)"}}},
+			{Tag: "100", Ind1: '1', Ind2: ' ', Subfields: []Subfield{{Code: 'a', Value: "Bhagat, Chetan,"},
+				{Code: 'c', Value: "Chetan"}, {Code: 'd', Value: "06/04/2001"}, {Code: '0', Value: "1"}}},
+			{Tag: "245", Ind1: '1', Ind2: '0', Subfields: []Subfield{{Code: 'a', Value: "2 States /"},
+				{Code: 'c', Value: "Chetan Bhagat."}}},
+			{Tag: "264", Ind1: ' ', Ind2: '1', Subfields: []Subfield{{Code: 'a', Value: "New Delhi :"},
+				{Code: 'b', Value: "Scholastic,"}, {Code: 'c', Value: "16/03/2016."}}},
+		},
+	}
+
+	raw, err := Encode(rec)
+	if err != nil {
+		t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "encode", 1, err, nil)
+	}
+
+	decoded, err := Decode(raw)
+	if err != nil {
+		t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "decode", 2, err, nil)
+	}
+
+	if decoded.Subfield(TagTitle, 'c') != "Chetan Bhagat." || decoded.Subfield("020", 'a') != "978-81-291-3552-0 (pbk.)" {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "decode", 2, decoded, rec)
+	}
+
+	book, err := ToBook(decoded)
+
+	expected := states
+	expected.BookID = 7
+	expected.ISBN = "9788129135520"
+
+	if err != nil || !reflect.DeepEqual(book, expected) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "to book", 3, book, expected)
+	}
+}
+
+// TestToBook_OtherCatalogues function is to test years of 264 and 260 $c are mapped and missing ids are reported
+func TestToBook_OtherCatalogues(t *testing.T) {
+	author := DataField{Tag: "100", Ind1: '1',
+		Subfields: []Subfield{{Code: 'a', Value: "Bhagat, Chetan,"}, {Code: '0', Value: "1"}}}
+	control := []ControlField{{Tag: "001", Value: "7"}}
+
+	testcases := []struct {
+		desc    string
+		control []ControlField
+		data    []DataField
+		date    string
+		err     error
+	}{
+		{desc: "copyright year", control: control, date: "01/01/2016", data: []DataField{author,
+			{Tag: "264", Ind2: '4', Subfields: []Subfield{{Code: 'b', Value: "Rupa,"}, {Code: 'c', Value: "c2016."}}}}},
+		{desc: "260 of older records", control: control, date: "01/01/1936", data: []DataField{author,
+			{Tag: "260", Subfields: []Subfield{{Code: 'b', Value: "Rupa,"}, {Code: 'c', Value: "[1936?]"}}}}},
+		{desc: "046 over 264", control: control, date: "16/03/2016", data: []DataField{
+			{Tag: "046", Subfields: []Subfield{{Code: 'k', Value: "20160316"}}}, author,
+			{Tag: "264", Ind2: '1', Subfields: []Subfield{{Code: 'b', Value: "Rupa,"}, {Code: 'c', Value: "2016."}}}}},
+		{desc: "no year", control: control, data: []DataField{author,
+			{Tag: "264", Ind2: '1', Subfields: []Subfield{{Code: 'b', Value: "Rupa,"}, {Code: 'c', Value: "[n.d.]"}}}}},
+		{desc: "missing control number", data: []DataField{author},
+			err: errors.New("missing control number 001, it has to be the book id")},
+		{desc: "oclc number", control: []ControlField{{Tag: "001", Value: "ocm12345678"}}, data: []DataField{author},
+			err: errors.New(`control number "ocm12345678" of 001 is not a book id`)},
+		{desc: "missing author id", control: control, data: []DataField{{Tag: "100", Ind1: '1',
+			Subfields: []Subfield{{Code: 'a', Value: "Bhagat, Chetan,"}}}}, err: errors.New("missing author id in 100 $0")},
+	}
+
+	for i, v := range testcases {
+		book, err := ToBook(Record{Control: v.control, Data: v.data})
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		if v.err == nil && (book.PublishedDate != v.date || book.Publication != "Rupa") {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, book, v.date)
+		}
+	}
+}
+
+// TestEncode_TooLong function is to test fields and records over the lengths of the directory are refused
+func TestEncode_TooLong(t *testing.T) {
+	long := strings.Repeat("a", 9000)
+
+	testcases := []struct {
+		desc string
+		rec  Record
+	}{
+		{desc: "field over 9999 bytes", rec: Record{Data: []DataField{{Tag: "245",
+			Subfields: []Subfield{{Code: 'a', Value: strings.Repeat("a", 9997)}}}}}},
+		{desc: "record over 99999 bytes", rec: Record{Data: make([]DataField, 12)}},
+	}
+
+	for i := range testcases[1].rec.Data {
+		testcases[1].rec.Data[i] = DataField{Tag: "500", Subfields: []Subfield{{Code: 'a', Value: long}}}
+	}
+
+	for i, v := range testcases {
+		if _, err := Encode(v.rec); !errors.Is(err, ErrInvalidRecord) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, ErrInvalidRecord)
+		}
+	}
+}
+
+// TestDecode_Invalid function is to test broken records are refused
+func TestDecode_Invalid(t *testing.T) {
+	testcases := []struct {
+		desc string
+		raw  string
+	}{
+		{desc: "missing terminator", raw: statesRecord[:len(statesRecord)-1]},
+		{desc: "bad base address", raw: statesRecord[:12] + "abcde" + statesRecord[17:]},
+		{desc: "entry past end", raw: statesRecord[:24] + "001999900000" + statesRecord[36:]},
+		{desc: "short leader", raw: "00010\x1d"},
+		{desc: "negative start", raw: statesRecord[:24] + "0010002-9999" + statesRecord[36:]},
+		{desc: "signed length", raw: statesRecord[:24] + "001+00200000" + statesRecord[36:]},
+		{desc: "entry over record terminator", raw: statesRecord[:24] + "001000200090" + statesRecord[36:]},
+		{desc: "length not in leader", raw: "00999" + statesRecord[5:]},
+		{desc: "base address past end", raw: statesRecord[:12] + "00177" + statesRecord[17:]},
+	}
+
+	for i, v := range testcases {
+		if _, err := Decode([]byte(v.raw)); !errors.Is(err, ErrInvalidRecord) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, ErrInvalidRecord)
+		}
+	}
+
+	if _, err := NewReader(bytes.NewBufferString("abcde")).Read(); !errors.Is(err, ErrInvalidRecord) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "bad length", len(testcases)+1, err, ErrInvalidRecord)
+	}
+}
@@ -0,0 +1,71 @@
+// Package marc reads and writes MARC 21 bibliographic records as ISO 2709 and MARCXML
+package marc
+
+// ControlField is a field 001 to 009, it has no indicators or subfields
+type ControlField struct {
+	Tag   string
+	Value string
+}
+
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+type DataField struct {
+	Tag       string
+	Ind1      byte
+	Ind2      byte
+	Subfields []Subfield
+}
+
+// Record is one MARC record, Leader is 24 characters and its length and base address are set when written
+type Record struct {
+	Leader  string
+	Control []ControlField
+	Data    []DataField
+}
+
+// ControlValue returns the value of the first control field with tag
+func (r Record) ControlValue(tag string) string {
+	for _, f := range r.Control {
+		if f.Tag == tag {
+			return f.Value
+		}
+	}
+
+	return ""
+}
+
+// Subfield returns the first subfield code of the first data field with tag
+func (r Record) Subfield(tag string, code byte) string {
+	for _, f := range r.Data {
+		if f.Tag != tag {
+			continue
+		}
+
+		for _, s := range f.Subfields {
+			if s.Code == code {
+				return s.Value
+			}
+		}
+
+		return ""
+	}
+
+	return ""
+}
+
+// has tells if the record has a data field with tag
+func (r Record) has(tag string) bool {
+	for _, f := range r.Data {
+		if f.Tag == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// leader is used for written records without one, a unicode monograph
+const leader = "00000nam a2200000 i 4500"
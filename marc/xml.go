@@ -0,0 +1,177 @@
+package marc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Namespace of MARCXML documents
+const Namespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlRecord struct {
+	XMLName xml.Name     `xml:"record"`
+	Xmlns   string       `xml:"xmlns,attr,omitempty"`
+	Leader  string       `xml:"leader"`
+	Control []xmlControl `xml:"controlfield"`
+	Data    []xmlData    `xml:"datafield"`
+}
+
+type xmlControl struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlData struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// XMLReader reads record elements of a MARCXML document, a collection or a single record
+type XMLReader struct {
+	d *xml.Decoder
+}
+
+func NewXMLReader(r io.Reader) *XMLReader {
+	return &XMLReader{d: xml.NewDecoder(r)}
+}
+
+// Read returns the next record, io.EOF after the last one
+func (m *XMLReader) Read() (Record, error) {
+	for {
+		token, err := m.d.Token()
+		if err != nil {
+			return Record{}, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		var x xmlRecord
+		if err = m.d.DecodeElement(&x, &start); err != nil {
+			return Record{}, fmt.Errorf("%w : %v", ErrInvalidRecord, err)
+		}
+
+		return fromXML(x)
+	}
+}
+
+func fromXML(x xmlRecord) (Record, error) {
+	rec := Record{Leader: x.Leader}
+
+	for _, c := range x.Control {
+		rec.Control = append(rec.Control, ControlField(c))
+	}
+
+	for _, d := range x.Data {
+		df := DataField{Tag: d.Tag, Ind1: ' ', Ind2: ' '}
+
+		if len(d.Ind1) == 1 {
+			df.Ind1 = d.Ind1[0]
+		}
+
+		if len(d.Ind2) == 1 {
+			df.Ind2 = d.Ind2[0]
+		}
+
+		for _, s := range d.Subfields {
+			if len(s.Code) != 1 {
+				return Record{}, fmt.Errorf("%w : subfield code %q of field %v", ErrInvalidRecord, s.Code, d.Tag)
+			}
+
+			df.Subfields = append(df.Subfields, Subfield{Code: s.Code[0], Value: s.Value})
+		}
+
+		rec.Data = append(rec.Data, df)
+	}
+
+	return rec, nil
+}
+
+// XMLWriter writes records into a collection element, Close ends the document
+type XMLWriter struct {
+	w       io.Writer
+	e       *xml.Encoder
+	started bool
+}
+
+func NewXMLWriter(w io.Writer) *XMLWriter {
+	return &XMLWriter{w: w, e: xml.NewEncoder(w)}
+}
+
+func (m *XMLWriter) Write(rec Record) error {
+	if err := m.start(); err != nil {
+		return err
+	}
+
+	if err := m.e.Encode(toXML(rec)); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(m.w, "\n")
+
+	return err
+}
+
+// Close writes the end of the collection, a collection without records is written as well
+func (m *XMLWriter) Close() error {
+	if err := m.start(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(m.w, "</collection>\n")
+
+	return err
+}
+
+func (m *XMLWriter) start() error {
+	if m.started {
+		return nil
+	}
+
+	m.started = true
+
+	_, err := io.WriteString(m.w, xml.Header+`<collection xmlns="`+Namespace+`">`+"\n")
+
+	return err
+}
+
+// EncodeXML returns a single record element with the MARCXML namespace, for records embedded in other documents
+func EncodeXML(rec Record) ([]byte, error) {
+	x := toXML(rec)
+	x.Xmlns = Namespace
+
+	return xml.Marshal(x)
+}
+
+func toXML(rec Record) xmlRecord {
+	x := xmlRecord{Leader: rec.Leader}
+	if len(x.Leader) != leaderLength {
+		x.Leader = leader
+	}
+
+	for _, c := range rec.Control {
+		x.Control = append(x.Control, xmlControl(c))
+	}
+
+	for _, d := range rec.Data {
+		xd := xmlData{Tag: d.Tag, Ind1: string(indicator(d.Ind1)), Ind2: string(indicator(d.Ind2))}
+
+		for _, s := range d.Subfields {
+			xd.Subfields = append(xd.Subfields, xmlSubfield{Code: string(s.Code), Value: s.Value})
+		}
+
+		x.Data = append(x.Data, xd)
+	}
+
+	return x
+}
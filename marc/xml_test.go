@@ -0,0 +1,130 @@
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"Three-Layer-Architecture/models"
+)
+
+// sampleXML is a record as other systems send it, with a namespace prefix and fields the catalogue does not keep
+const sampleXML = `<?xml version="1.0" encoding="UTF-8"?>
+<marc:collection xmlns:marc="http://www.loc.gov/MARC21/slim">
+  <marc:record>
+    <marc:leader>00000nam a2200000 i 4500</marc:leader>
+    <marc:controlfield tag="001">2</marc:controlfield>
+    <marc:datafield tag="020" ind1=" " ind2=" ">
+      <marc:subfield code="a">9780060935467</marc:subfield>
+    </marc:datafield>
+    <marc:datafield tag="100" ind1="1" ind2=" ">
+      <marc:subfield code="a">Lee, Nelle Harper,</marc:subfield>
+      <marc:subfield code="c">Harper Lee</marc:subfield>
+      <marc:subfield code="d">28/04/1926</marc:subfield>
+      <marc:subfield code="0">2</marc:subfield>
+    </marc:datafield>
+    <marc:datafield tag="245" ind1="1" ind2="0">
+      <marc:subfield code="a">To Kill a Mockingbird – 50th Anniversary /</marc:subfield>
+    </marc:datafield>
+    <marc:datafield tag="264" ind1=" " ind2="1">
+      <marc:subfield code="a">London :</marc:subfield>
+      <marc:subfield code="b">Penguin,</marc:subfield>
+      <marc:subfield code="c">11/07/1960.</marc:subfield>
+    </marc:datafield>
+  </marc:record>
+</marc:collection>`
+
+// TestXMLReader function is to test MARCXML of other systems is mapped to books
+func TestXMLReader(t *testing.T) {
+	r := NewXMLReader(strings.NewReader(sampleXML))
+
+	rec, err := r.Read()
+	if err != nil {
+		t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "read", 1, err, nil)
+	}
+
+	book, err := ToBook(rec)
+	if err != nil || !reflect.DeepEqual(book, mockingbird) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "to book", 2, book, mockingbird)
+	}
+
+	if _, err = r.Read(); !errors.Is(err, io.EOF) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "end", 3, err, io.EOF)
+	}
+}
+
+// TestXMLWriter_RoundTrip function is to test books written as MARCXML are read back the same
+func TestXMLWriter_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := NewXMLWriter(&buf)
+
+	for _, b := range []models.Book{states, mockingbird} {
+		if err := w.Write(FromBook(b)); err != nil {
+			t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "write", 1, err, nil)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "close", 1, err, nil)
+	}
+
+	if !strings.Contains(buf.String(), `<collection xmlns="`+Namespace+`">`) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "namespace", 2, buf.String(), Namespace)
+	}
+
+	r := NewXMLReader(&buf)
+
+	for i, expected := range []models.Book{states, mockingbird} {
+		rec, err := r.Read()
+		if err != nil {
+			t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "read", i+3, err, nil)
+		}
+
+		book, err := ToBook(rec)
+		if err != nil || !reflect.DeepEqual(book, expected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "round trip", i+3, book, expected)
+		}
+	}
+
+	if _, err := r.Read(); !errors.Is(err, io.EOF) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "end", 5, err, io.EOF)
+	}
+}
+
+// TestXMLWriter_Empty function is to test a collection without records is still a document
+func TestXMLWriter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := NewXMLWriter(&buf).Close(); err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "close", 1, err, nil)
+	}
+
+	if _, err := NewXMLReader(&buf).Read(); !errors.Is(err, io.EOF) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "empty", 2, err, io.EOF)
+	}
+}
+
+// TestEncodeXML function is to test a single record carries its namespace and reads back the same
+func TestEncodeXML(t *testing.T) {
+	body, err := EncodeXML(FromBook(states))
+	if err != nil {
+		t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "encode", 1, err, nil)
+	}
+
+	if !strings.HasPrefix(string(body), `<record xmlns="`+Namespace+`">`) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "namespace", 2, string(body), Namespace)
+	}
+
+	rec, err := NewXMLReader(bytes.NewReader(body)).Read()
+	if err != nil {
+		t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "read", 3, err, nil)
+	}
+
+	if book, err := ToBook(rec); err != nil || !reflect.DeepEqual(book, states) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "round trip", 4, book, states)
+	}
+}
@@ -0,0 +1,94 @@
+// Package metrics has the prometheus collectors of http, service and database layers
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// Requests counts served requests by route template, method and status code
+	Requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "library",
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	// Latency observes time to serve requests by route template, method and status code
+	Latency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "library",
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// ValidationFailures counts requests rejected by a service before reaching the database
+	ValidationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "library",
+		Name:      "service_validation_failures_total",
+		Help:      "Requests rejected by service validation by service and reason.",
+	}, []string{"service", "reason"})
+
+	// CacheRequests counts lookups of caching datastores by result, hit or miss
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "library",
+		Name:      "cache_requests_total",
+		Help:      "Cache lookups by cache and result.",
+	}, []string{"cache", "result"})
+
+	// WebhookDeliveries counts attempts to send webhook events by event and result, succeeded, retried or failed
+	WebhookDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "library",
+		Name:      "webhook_deliveries_total",
+		Help:      "Webhook delivery attempts by event and result.",
+	}, []string{"event", "result"})
+)
+
+// Registry holds every collector exposed on /metrics
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		Requests,
+		Latency,
+		ValidationFailures,
+		CacheRequests,
+		WebhookDeliveries,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// RegisterDB exposes connection pool stats of db as library_db_* gauges
+func RegisterDB(db *sql.DB) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, "library"))
+}
+
+// Handler serves the collectors in prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ValidationFailure counts a rejected request, reason must be one of a fixed set of messages
+func ValidationFailure(service, reason string) {
+	ValidationFailures.WithLabelValues(service, reason).Inc()
+}
+
+// WebhookDelivery counts an attempt to send an event to a webhook
+func WebhookDelivery(event, result string) {
+	WebhookDeliveries.WithLabelValues(event, result).Inc()
+}
+
+// CacheLookup counts a hit or miss of cache
+func CacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	CacheRequests.WithLabelValues(cache, result).Inc()
+}
@@ -0,0 +1,84 @@
+// Package middleware has the http middlewares wrapped around the router and the gRPC interceptors of the server in main
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/principal"
+	"Three-Layer-Architecture/service"
+)
+
+// SessionCookie is the cookie set at login
+const SessionCookie = "session"
+
+var errMissingCredentials = errors.New("missing credentials")
+
+// Authenticate rejects requests without a valid api key, bearer token or session and attaches the caller to the context.
+// Api key is read from X-API-Key header, bearer token from Authorization header and session from X-Session-Token
+// header or session cookie.
+func Authenticate(auth service.Auth) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session := r.Header.Get("X-Session-Token")
+			if session == "" {
+				session = sessionCookie(r)
+			}
+
+			p, err := authenticate(r.Context(), auth, r.Header.Get("X-API-Key"), r.Header.Get("Authorization"), session)
+			if errors.Is(err, errMissingCredentials) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="library"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				writeError("missing credentials", w)
+
+				return
+			}
+
+			if err != nil {
+				slog.WarnContext(r.Context(), "authentication failed", "error", err)
+
+				w.Header().Set("WWW-Authenticate", `Bearer realm="library", error="invalid_token"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				writeError("invalid credentials", w)
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(principal.NewContext(r.Context(), p)))
+		})
+	}
+}
+
+// authenticate checks the first credentials present in order of api key, bearer token and session,
+// it is shared by http and gRPC so both accept the same callers
+func authenticate(ctx context.Context, auth service.Auth, apiKey, authorization, session string) (models.Principal, error) {
+	switch {
+	case apiKey != "":
+		return auth.AuthenticateAPIKey(ctx, apiKey)
+	case strings.HasPrefix(authorization, "Bearer "):
+		return auth.AuthenticateToken(ctx, strings.TrimPrefix(authorization, "Bearer "))
+	case session != "":
+		return auth.AuthenticateSession(ctx, session)
+	default:
+		return models.Principal{}, errMissingCredentials
+	}
+}
+
+func sessionCookie(r *http.Request) string {
+	c, err := r.Cookie(SessionCookie)
+	if err != nil {
+		return ""
+	}
+
+	return c.Value
+}
+
+func writeError(msg string, w http.ResponseWriter) {
+	if _, err := w.Write([]byte(msg)); err != nil {
+		slog.Error("could not write response", "error", err)
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/principal"
+	"Three-Layer-Architecture/service"
+)
+
+// TestAuthenticate function is to test only authenticated callers reach the handler
+func TestAuthenticate(t *testing.T) {
+	testcases := []struct {
+		desc               string
+		header             string
+		value              string
+		expectedStatusCode int
+		expectedCaller     string
+	}{
+		{desc: "api key", header: "X-API-Key", value: "lib_ab12_good", expectedStatusCode: http.StatusOK, expectedCaller: "svc-discovery"},
+		{desc: "bearer token", header: "Authorization", value: "Bearer good", expectedStatusCode: http.StatusOK, expectedCaller: "u-1"},
+		{desc: "session header", header: "X-Session-Token", value: "good", expectedStatusCode: http.StatusOK, expectedCaller: "alice"},
+		{desc: "session cookie", header: "Cookie", value: "session=good", expectedStatusCode: http.StatusOK, expectedCaller: "alice"},
+		{desc: "expired session", header: "Cookie", value: "session=bad", expectedStatusCode: http.StatusUnauthorized},
+		{desc: "bad api key", header: "X-API-Key", value: "lib_ab12_bad", expectedStatusCode: http.StatusUnauthorized},
+		{desc: "bad token", header: "Authorization", value: "Bearer bad", expectedStatusCode: http.StatusUnauthorized},
+		{desc: "basic auth", header: "Authorization", value: "Basic YTpi", expectedStatusCode: http.StatusUnauthorized},
+		{desc: "no credentials", expectedStatusCode: http.StatusUnauthorized},
+	}
+
+	ctr := gomock.NewController(t)
+	mockAuth := service.NewMockAuth(ctr)
+
+	mockAuth.EXPECT().AuthenticateAPIKey(gomock.Any(), "lib_ab12_good").
+		Return(models.Principal{ID: "svc-discovery", Kind: models.PrincipalAPIKey}, nil).AnyTimes()
+	mockAuth.EXPECT().AuthenticateAPIKey(gomock.Any(), "lib_ab12_bad").Return(models.Principal{}, errors.New("unauthenticated")).AnyTimes()
+	mockAuth.EXPECT().AuthenticateToken(gomock.Any(), "good").Return(models.Principal{ID: "u-1", Kind: models.PrincipalJWT}, nil).AnyTimes()
+	mockAuth.EXPECT().AuthenticateToken(gomock.Any(), "bad").Return(models.Principal{}, errors.New("unauthenticated")).AnyTimes()
+
+	mockAuth.EXPECT().AuthenticateSession(gomock.Any(), "good").Return(models.Principal{ID: "alice", Kind: models.PrincipalUser}, nil).AnyTimes()
+	mockAuth.EXPECT().AuthenticateSession(gomock.Any(), "bad").Return(models.Principal{}, errors.New("unauthenticated")).AnyTimes()
+
+	for i, v := range testcases {
+		var caller string
+
+		handler := Authenticate(mockAuth)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			caller = principal.FromContext(r.Context()).ID
+		}))
+
+		req := httptest.NewRequest(http.MethodDelete, "/author/1", nil)
+		if v.header != "" {
+			req.Header.Set(v.header, v.value)
+		}
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.StatusCode, v.expectedStatusCode)
+		}
+
+		if caller != v.expectedCaller {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got caller %v\tExpected %v\n", v.desc, i+1, caller, v.expectedCaller)
+		}
+
+		if res.StatusCode == http.StatusUnauthorized && res.Header.Get("WWW-Authenticate") == "" {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Missing WWW-Authenticate header\n", v.desc, i+1)
+		}
+
+		res.Body.Close()
+	}
+}
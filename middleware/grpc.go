@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"Three-Layer-Architecture/principal"
+	"Three-Layer-Architecture/requestid"
+	"Three-Layer-Architecture/service"
+	"Three-Layer-Architecture/service/rbac"
+)
+
+// GRPCUnary does for unary calls what RequestID, AccessLog, Authenticate and Authorize do for http requests, and maps
+// errors of the service layer to status codes. permissions has the permission needed by each full method name,
+// methods missing from it are denied.
+func GRPCUnary(auth service.Auth, authorizer service.RBAC, permissions map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		ctx, err := guard(ctx, info.FullMethod, auth, authorizer, permissions)
+
+		var resp any
+		if err == nil {
+			resp, err = handler(ctx, req)
+		}
+
+		err = GRPCStatus(ctx, err)
+		logCall(ctx, info.FullMethod, err, start)
+
+		return resp, err
+	}
+}
+
+// GRPCStream is GRPCUnary for streaming calls
+func GRPCStream(auth service.Auth, authorizer service.RBAC, permissions map[string]string) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		ctx, err := guard(stream.Context(), info.FullMethod, auth, authorizer, permissions)
+		if err == nil {
+			err = handler(srv, serverStream{ServerStream: stream, ctx: ctx})
+		}
+
+		err = GRPCStatus(ctx, err)
+		logCall(ctx, info.FullMethod, err, start)
+
+		return err
+	}
+}
+
+// GRPCStatus maps an error of the service layer to a status, missing rows are NotFound and validation errors of the
+// service, or ids which are not numbers, are InvalidArgument like the 400 of the http handlers. Other errors are
+// Internal and only logged, their text may tell about the database.
+func GRPCStatus(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var (
+		invalid service.ValidationError
+		number  *strconv.NumError
+	)
+
+	switch {
+	case errors.As(err, &invalid), errors.As(err, &number):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, sql.ErrNoRows):
+		return status.Error(codes.NotFound, "not found")
+	case errors.Is(err, rbac.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		slog.ErrorContext(ctx, "call failed", "error", err)
+
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// guard attaches request id and caller to ctx and checks the caller holds the permission of method.
+// Credentials are read from x-api-key, authorization and x-session-token metadata.
+func guard(ctx context.Context, method string, auth service.Auth, authorizer service.RBAC,
+	permissions map[string]string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	id := first(md, "x-request-id")
+	if !validRequestID(id) {
+		id = requestid.New()
+	}
+
+	ctx = requestid.NewContext(ctx, id)
+
+	if err := grpc.SetHeader(ctx, metadata.Pairs("x-request-id", id)); err != nil {
+		slog.WarnContext(ctx, "could not set request id header", "error", err)
+	}
+
+	p, err := authenticate(ctx, auth, first(md, "x-api-key"), first(md, "authorization"), first(md, "x-session-token"))
+	if errors.Is(err, errMissingCredentials) {
+		return ctx, status.Error(codes.Unauthenticated, "missing credentials")
+	}
+
+	if err != nil {
+		slog.WarnContext(ctx, "authentication failed", "error", err)
+
+		return ctx, status.Error(codes.Unauthenticated, "invalid credentials")
+	}
+
+	ctx = principal.NewContext(ctx, p)
+
+	permission, ok := permissions[method]
+	if !ok {
+		return ctx, status.Error(codes.PermissionDenied, "no permission for method "+method)
+	}
+
+	err = authorizer.Authorize(ctx, permission)
+
+	switch {
+	case err == nil:
+		return ctx, nil
+	case errors.Is(err, rbac.ErrForbidden):
+		slog.WarnContext(ctx, "permission denied", "principal", p.ID, "permission", permission)
+
+		return ctx, status.Error(codes.PermissionDenied, "missing permission "+permission)
+	default:
+		slog.ErrorContext(ctx, "authorization failed", "error", err)
+
+		return ctx, status.Error(codes.Internal, "authorization failed")
+	}
+}
+
+func logCall(ctx context.Context, method string, err error, start time.Time) {
+	code := status.Code(err)
+
+	level := slog.LevelWarn
+
+	switch code {
+	case codes.OK:
+		level = slog.LevelInfo
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		level = slog.LevelError
+	}
+
+	slog.Log(ctx, level, "call served", "method", method, "code", code.String(),
+		"duration_ms", time.Since(start).Milliseconds())
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// serverStream carries the context with caller and request id to the handler
+type serverStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"Three-Layer-Architecture/librarypb"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/principal"
+	"Three-Layer-Architecture/requestid"
+	"Three-Layer-Architecture/service"
+	"Three-Layer-Architecture/service/rbac"
+)
+
+// books answers with the caller as title, or with err
+type books struct {
+	librarypb.UnimplementedBookServiceServer
+
+	err error
+}
+
+func (b books) GetBook(ctx context.Context, _ *librarypb.GetBookRequest) (*librarypb.Book, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return &librarypb.Book{Title: principal.FromContext(ctx).ID, Publication: requestid.FromContext(ctx)}, nil
+}
+
+func (b books) ListBooks(_ *librarypb.ListBooksRequest, stream librarypb.BookService_ListBooksServer) error {
+	if b.err != nil {
+		return b.err
+	}
+
+	return stream.Send(&librarypb.Book{Title: principal.FromContext(stream.Context()).ID})
+}
+
+func dialBooks(t *testing.T, auth service.Auth, authorizer service.RBAC, server books) librarypb.BookServiceClient {
+	permissions := map[string]string{
+		librarypb.BookService_GetBook_FullMethodName:   models.PermBookRead,
+		librarypb.BookService_ListBooks_FullMethodName: models.PermBookRead,
+	}
+
+	listener := bufconn.Listen(1 << 20)
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(GRPCUnary(auth, authorizer, permissions)),
+		grpc.ChainStreamInterceptor(GRPCStream(auth, authorizer, permissions)),
+	)
+	librarypb.RegisterBookServiceServer(s, server)
+
+	go func() {
+		if err := s.Serve(listener); err != nil {
+			t.Errorf("serve: %v", err)
+		}
+	}()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		s.Stop()
+	})
+
+	return librarypb.NewBookServiceClient(conn)
+}
+
+// TestGRPC function is to test calls are authenticated, authorized and their errors mapped to status codes
+func TestGRPC(t *testing.T) {
+	testcases := []struct {
+		desc      string
+		md        []string
+		forbidden error
+		err       error
+		code      codes.Code
+		caller    string
+	}{
+		{desc: "api key", md: []string{"x-api-key", "lib_ab12_good"}, code: codes.OK, caller: "svc-discovery"},
+		{desc: "bearer token", md: []string{"authorization", "Bearer good"}, code: codes.OK, caller: "u-1"},
+		{desc: "session", md: []string{"x-session-token", "good"}, code: codes.OK, caller: "alice"},
+		{desc: "no credentials", code: codes.Unauthenticated},
+		{desc: "bad api key", md: []string{"x-api-key", "lib_ab12_bad"}, code: codes.Unauthenticated},
+		{desc: "forbidden", md: []string{"x-api-key", "lib_ab12_good"}, forbidden: rbac.ErrForbidden, code: codes.PermissionDenied},
+		{desc: "rbac down", md: []string{"x-api-key", "lib_ab12_good"}, forbidden: errors.New("connection refused"),
+			code: codes.Internal},
+		{desc: "missing book", md: []string{"x-api-key", "lib_ab12_good"}, err: sql.ErrNoRows, code: codes.NotFound},
+		{desc: "invalid book", md: []string{"x-api-key", "lib_ab12_good"}, err: service.ValidationError("invalid id"),
+			code: codes.InvalidArgument},
+		{desc: "database down", md: []string{"x-api-key", "lib_ab12_good"}, err: errors.New("dial tcp: connection refused"),
+			code: codes.Internal},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockAuth := service.NewMockAuth(ctr)
+		mockRBAC := service.NewMockRBAC(ctr)
+
+		mockAuth.EXPECT().AuthenticateAPIKey(gomock.Any(), "lib_ab12_good").
+			Return(models.Principal{ID: "svc-discovery", Kind: models.PrincipalAPIKey}, nil).AnyTimes()
+		mockAuth.EXPECT().AuthenticateAPIKey(gomock.Any(), "lib_ab12_bad").
+			Return(models.Principal{}, errors.New("unauthenticated")).AnyTimes()
+		mockAuth.EXPECT().AuthenticateToken(gomock.Any(), "good").
+			Return(models.Principal{ID: "u-1", Kind: models.PrincipalJWT}, nil).AnyTimes()
+		mockAuth.EXPECT().AuthenticateSession(gomock.Any(), "good").
+			Return(models.Principal{ID: "alice", Kind: models.PrincipalUser}, nil).AnyTimes()
+		mockRBAC.EXPECT().Authorize(gomock.Any(), models.PermBookRead).Return(v.forbidden).AnyTimes()
+
+		client := dialBooks(t, mockAuth, mockRBAC, books{err: v.err})
+		ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs(v.md...))
+
+		var header metadata.MD
+
+		book, err := client.GetBook(ctx, &librarypb.GetBookRequest{Id: "1"}, grpc.Header(&header))
+		if status.Code(err) != v.code || book.GetTitle() != v.caller {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", v.desc, i+1, status.Code(err), book.GetTitle(),
+				v.code, v.caller)
+		}
+
+		if ids := header.Get("x-request-id"); len(ids) != 1 || (err == nil && ids[0] != book.GetPublication()) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got request id %v\tExpected %v\n", v.desc, i+1, ids, book.GetPublication())
+		}
+
+		stream, err := client.ListBooks(ctx, &librarypb.ListBooksRequest{})
+		if err == nil {
+			book, err = stream.Recv()
+		}
+
+		if status.Code(err) != v.code || book.GetTitle() != v.caller {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got stream %v %v\tExpected %v %v\n", v.desc, i+1, status.Code(err),
+				book.GetTitle(), v.code, v.caller)
+		}
+
+		if err == nil {
+			if _, err = stream.Recv(); err != io.EOF {
+				t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, io.EOF)
+			}
+		}
+
+		ctr.Finish()
+	}
+}
+
+// TestGRPCStatus function is to test only mistakes of the caller are told to the caller
+func TestGRPCStatus(t *testing.T) {
+	_, number := strconv.Atoi("abc")
+
+	testcases := []struct {
+		desc    string
+		err     error
+		code    codes.Code
+		message string
+	}{
+		{desc: "validation", err: service.ValidationError("invalid publication"), code: codes.InvalidArgument,
+			message: "invalid publication"},
+		{desc: "wrapped validation", err: fmt.Errorf("book 3 : %w", service.ValidationError("missing id")),
+			code: codes.InvalidArgument, message: "book 3 : missing id"},
+		{desc: "id not a number", err: number, code: codes.InvalidArgument, message: number.Error()},
+		{desc: "missing row", err: sql.ErrNoRows, code: codes.NotFound, message: "not found"},
+		{desc: "driver error", err: errors.New("Error 1146: Table 'library.Book' doesn't exist"), code: codes.Internal,
+			message: "internal error"},
+		{desc: "status", err: status.Error(codes.Unauthenticated, "missing credentials"), code: codes.Unauthenticated,
+			message: "missing credentials"},
+	}
+
+	for i, v := range testcases {
+		got, _ := status.FromError(GRPCStatus(context.Background(), v.err))
+		if got.Code() != v.code || got.Message() != v.message {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", v.desc, i+1, got.Code(), got.Message(),
+				v.code, v.message)
+		}
+	}
+}
+
+// TestGRPC_UnknownMethod function is to test methods without a permission are denied
+func TestGRPC_UnknownMethod(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockAuth := service.NewMockAuth(ctr)
+
+	mockAuth.EXPECT().AuthenticateAPIKey(gomock.Any(), "lib_ab12_good").
+		Return(models.Principal{ID: "svc-discovery", Kind: models.PrincipalAPIKey}, nil)
+
+	client := dialBooks(t, mockAuth, service.NewMockRBAC(ctr), books{})
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("x-api-key", "lib_ab12_good"))
+
+	_, err := client.DeleteBook(ctx, &librarypb.DeleteBookRequest{Id: "1"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "unknown method", 1, err, codes.PermissionDenied)
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"Three-Layer-Architecture/metrics"
+)
+
+// TestMetrics function is to test requests are counted by route template and status
+func TestMetrics(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(Metrics)
+
+	api := router.PathPrefix("/").Subrouter()
+	api.HandleFunc("/book/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodGet)
+	api.HandleFunc("/books", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("[]"))
+	}).Methods(http.MethodGet)
+
+	testcases := []struct {
+		desc   string
+		target string
+		route  string
+		status string
+	}{
+		{desc: "first status is kept", target: "/book/1", route: "/book/{id}", status: "404"},
+		{desc: "same route other id", target: "/book/2", route: "/book/{id}", status: "404"},
+		{desc: "implicit ok", target: "/books", route: "/books", status: "200"},
+	}
+
+	for i, v := range testcases {
+		before := testutil.ToFloat64(metrics.Requests.WithLabelValues(v.route, http.MethodGet, v.status))
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, v.target, nil))
+
+		after := testutil.ToFloat64(metrics.Requests.WithLabelValues(v.route, http.MethodGet, v.status))
+		if after != before+1 {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, after, before+1)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/principal"
+	"Three-Layer-Architecture/service"
+)
+
+// RateLimit rejects requests over limit with 429, each value returned by key has its own bucket.
+// Requests are let through when the limiter fails, an outage of a shared store must not take the api down.
+func RateLimit(limiter service.Limiter, limit models.RateLimit, key func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := limiter.Allow(r.Context(), limit.Group+":"+key(r), limit)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "rate limit failed", "error", err)
+
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("RateLimit-Reset", ceilSeconds(result.Reset))
+
+			if !result.Allowed {
+				slog.WarnContext(r.Context(), "rate limit exceeded", "group", limit.Group, "key", key(r))
+
+				w.Header().Set("Retry-After", ceilSeconds(result.RetryAfter))
+				w.WriteHeader(http.StatusTooManyRequests)
+				writeError("too many requests", w)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ReadWrite sends GET and HEAD requests through read and every other request through write
+func ReadWrite(read, write func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		reads, writes := read(next), write(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				reads.ServeHTTP(w, r)
+
+				return
+			}
+
+			writes.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIP is the rate limit key of the connecting address
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// Caller is the rate limit key of the authenticated principal, it must run after Authenticate
+func Caller(r *http.Request) string {
+	p := principal.FromContext(r.Context())
+
+	return p.Kind + ":" + p.ID
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
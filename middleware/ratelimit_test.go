@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// TestRateLimit function is to test requests over the limit get 429 with headers
+func TestRateLimit(t *testing.T) {
+	limit := models.RateLimit{Group: "read", Requests: 300, Per: time.Minute}
+
+	testcases := []struct {
+		desc               string
+		result             models.RateLimitResult
+		err                error
+		expectedStatusCode int
+		expectedHeaders    map[string]string
+	}{
+		{desc: "allowed", result: models.RateLimitResult{Allowed: true, Limit: 300, Remaining: 299, Reset: 200 * time.Millisecond},
+			expectedStatusCode: http.StatusOK, expectedHeaders: map[string]string{"RateLimit-Limit": "300",
+				"RateLimit-Remaining": "299", "RateLimit-Reset": "1", "Retry-After": ""}},
+		{desc: "limited", result: models.RateLimitResult{Limit: 300, Reset: time.Minute, RetryAfter: 1500 * time.Millisecond},
+			expectedStatusCode: http.StatusTooManyRequests, expectedHeaders: map[string]string{"RateLimit-Limit": "300",
+				"RateLimit-Remaining": "0", "RateLimit-Reset": "60", "Retry-After": "2"}},
+		{desc: "limiter down", err: errors.New("connection refused"), expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{"RateLimit-Limit": "", "Retry-After": ""}},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockLimiter := service.NewMockLimiter(ctr)
+
+		mockLimiter.EXPECT().Allow(gomock.Any(), "read:192.0.2.1", limit).Return(v.result, v.err)
+
+		handler := RateLimit(mockLimiter, limit, ClientIP)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		req := httptest.NewRequest(http.MethodGet, "/books", nil)
+		req.RemoteAddr = "192.0.2.1:40000"
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, res.StatusCode, v.expectedStatusCode)
+		}
+
+		for header, value := range v.expectedHeaders {
+			if res.Header.Get(header) != value {
+				t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", v.desc, i+1, header, res.Header.Get(header), value)
+			}
+		}
+
+		res.Body.Close()
+		ctr.Finish()
+	}
+}
+
+// TestReadWrite function is to test reads and writes go to their own limits
+func TestReadWrite(t *testing.T) {
+	var got string
+
+	mark := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = name
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	handler := ReadWrite(mark("read"), mark("write"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for i, v := range []struct{ method, expected string }{
+		{http.MethodGet, "read"}, {http.MethodHead, "read"}, {http.MethodPost, "write"}, {http.MethodDelete, "write"},
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(v.method, "/books", nil))
+
+		if got != v.expected {
+			t.Errorf("[TEST%d]Failed. Got %v\tExpected %v\n", i+1, got, v.expected)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"Three-Layer-Architecture/principal"
+	"Three-Layer-Architecture/service"
+	"Three-Layer-Architecture/service/rbac"
+)
+
+// Authorize lets the request through only if the caller holds permission, it must run after Authenticate
+func Authorize(authorizer service.RBAC, permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := authorizer.Authorize(r.Context(), permission)
+
+			switch {
+			case err == nil:
+				next.ServeHTTP(w, r)
+			case errors.Is(err, rbac.ErrForbidden):
+				slog.WarnContext(r.Context(), "permission denied", "principal", principal.FromContext(r.Context()).ID, "permission", permission)
+
+				w.WriteHeader(http.StatusForbidden)
+				writeError("missing permission "+permission, w)
+			default:
+				slog.ErrorContext(r.Context(), "authorization failed", "error", err)
+
+				w.WriteHeader(http.StatusInternalServerError)
+				writeError("authorization failed", w)
+			}
+		})
+	}
+}
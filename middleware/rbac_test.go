@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+	"Three-Layer-Architecture/service/rbac"
+)
+
+// TestAuthorize function is to test handler is reached only with permission
+func TestAuthorize(t *testing.T) {
+	testcases := []struct {
+		desc               string
+		err                error
+		expectedStatusCode int
+		reached            bool
+	}{
+		{desc: "granted", expectedStatusCode: http.StatusOK, reached: true},
+		{desc: "forbidden", err: rbac.ErrForbidden, expectedStatusCode: http.StatusForbidden},
+		{desc: "db down", err: errors.New("connection refused"), expectedStatusCode: http.StatusInternalServerError},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockRBAC := service.NewMockRBAC(ctr)
+
+		mockRBAC.EXPECT().Authorize(gomock.Any(), models.PermBookDelete).Return(v.err)
+
+		reached := false
+		handler := Authorize(mockRBAC, models.PermBookDelete)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reached = true
+		}))
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/book/1", nil))
+
+		res := w.Result()
+
+		if res.StatusCode != v.expectedStatusCode || reached != v.reached {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v, reached %v\tExpected %v, %v\n", v.desc, i+1, res.StatusCode, reached,
+				v.expectedStatusCode, v.reached)
+		}
+
+		res.Body.Close()
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"Three-Layer-Architecture/requestid"
+)
+
+// TestRequestID function is to test request id is propagated or generated and echoed
+func TestRequestID(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		header   string
+		expected string
+	}{
+		{desc: "propagated", header: "abc-123", expected: "abc-123"},
+		{desc: "generated", header: ""},
+		{desc: "too long", header: strings.Repeat("a", 129)},
+		{desc: "unsafe characters", header: "abc\" injected=1"},
+	}
+
+	for i, v := range testcases {
+		var got string
+
+		handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = requestid.FromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/books", nil)
+		if v.header != "" {
+			req.Header.Set("X-Request-ID", v.header)
+		}
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if v.expected != "" && got != v.expected {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, got, v.expected)
+		}
+
+		if v.expected == "" && (len(got) != 32 || got == v.header) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected generated id\n", v.desc, i+1, got)
+		}
+
+		if w.Header().Get("X-Request-ID") != got {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got header %v\tExpected %v\n", v.desc, i+1, w.Header().Get("X-Request-ID"), got)
+		}
+	}
+}
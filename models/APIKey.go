@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// APIKey lets a machine call the api as Principal, only the hash of the key is stored
+type APIKey struct {
+	ID        int        `json:"id"`
+	Prefix    string     `json:"prefix"`
+	Hash      string     `json:"-"`
+	Name      string     `json:"name"`
+	Principal string     `json:"principal"`
+	CreatedAt time.Time  `json:"createdAt"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audit is one entry of the append-only change log of books and authors
+type Audit struct {
+	ID        int64           `json:"id"`
+	Actor     string          `json:"actor"`
+	Timestamp time.Time       `json:"timestamp"`
+	Operation string          `json:"operation"`
+	Entity    string          `json:"entity"`
+	EntityID  int             `json:"entityID"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditFilter narrows down audit entries, zero values are not applied
+type AuditFilter struct {
+	Entity   string
+	EntityID int
+	From     time.Time
+	To       time.Time
+}
+
+// Audit operations
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+)
+
+// Audited entities
+const (
+	EntityBook   = "book"
+	EntityAuthor = "author"
+)
@@ -1,9 +1,12 @@
 package models
 
+import "time"
+
 type Author struct {
-	AuthID    int    `json:"authID"`
-	FirstName string `json:"firstName"`
-	LastName  string `json:"lastName"`
-	Dob       string `json:"dob"`
-	PenName   string `json:"penName"`
+	AuthID    int        `json:"authID"`
+	FirstName string     `json:"firstName"`
+	LastName  string     `json:"lastName"`
+	Dob       string     `json:"dob"`
+	PenName   string     `json:"penName"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
@@ -1,10 +1,14 @@
 package models
 
+import "time"
+
 type Book struct {
-	BookID        int    `json:"bookID"`
-	AuthorID      int    `json:"authorID"`
-	Auth          Author `json:"auth"`
-	Title         string `json:"title"`
-	Publication   string `json:"publication"`
-	PublishedDate string `json:"publishedDate"`
+	BookID        int        `json:"bookID"`
+	AuthorID      int        `json:"authorID"`
+	Auth          Author     `json:"auth"`
+	Title         string     `json:"title"`
+	Publication   string     `json:"publication"`
+	PublishedDate string     `json:"publishedDate"`
+	ISBN          string     `json:"isbn,omitempty"`
+	DeletedAt     *time.Time `json:"deletedAt,omitempty"`
 }
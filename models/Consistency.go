@@ -0,0 +1,9 @@
+package models
+
+// Inconsistency is a row breaking a rule the schema does not enforce, Check names the rule like book.trashed_author
+type Inconsistency struct {
+	Check    string `json:"check"`
+	Entity   string `json:"entity"`
+	EntityID int    `json:"entityID"`
+	Detail   string `json:"detail"`
+}
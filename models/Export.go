@@ -0,0 +1,34 @@
+package models
+
+// Export formats
+const (
+	ExportCSV     = "csv"
+	ExportNDJSON  = "ndjson"
+	ExportJSON    = "json"
+	ExportMARC    = "marc"
+	ExportMARCXML = "marcxml"
+	ExportBibTeX  = "bibtex"
+	ExportRIS     = "ris"
+	ExportCSLJSON = "csljson"
+	ExportDC      = "dc"
+)
+
+// ExportFields are the columns of an exported book, they match the import CSV header
+var ExportFields = []string{"bookID", "title", "publication", "publishedDate", "authorID", "firstName", "lastName", "dob",
+	"penName", "isbn"}
+
+// BookFilter narrows down books, zero values are not applied
+type BookFilter struct {
+	AuthorID    int
+	Publication string
+	// Title matches books whose title contains it
+	Title string
+}
+
+// ExportOptions controls a catalogue export, every field is written when Fields is empty.
+// MARC records and citations always have every field.
+type ExportOptions struct {
+	Format string
+	Fields []string
+	Filter BookFilter
+}
@@ -0,0 +1,35 @@
+package models
+
+// Import formats
+const (
+	ImportCSV     = "csv"
+	ImportNDJSON  = "ndjson"
+	ImportMARC    = "marc"
+	ImportMARCXML = "marcxml"
+)
+
+// ImportOptions controls a bulk import
+type ImportOptions struct {
+	Format string
+	// DryRun validates every row without storing any
+	DryRun bool
+	// BatchSize is the count of rows stored in one transaction
+	BatchSize int
+}
+
+// ImportResult reports a bulk import, Errors has one entry per rejected row
+type ImportResult struct {
+	DryRun   bool          `json:"dryRun"`
+	Rows     int           `json:"rows"`
+	Valid    int           `json:"valid"`
+	Imported int           `json:"imported"`
+	Failed   int           `json:"failed"`
+	Errors   []ImportError `json:"errors"`
+}
+
+// ImportError is the reason a row was rejected, Row counts data rows from 1
+type ImportError struct {
+	Row    int    `json:"row"`
+	BookID int    `json:"bookID,omitempty"`
+	Error  string `json:"error"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Loan is a book checked out by a user, it is active until ReturnedAt is set
+type Loan struct {
+	ID           int        `json:"id"`
+	BookID       int        `json:"bookID"`
+	UserID       int        `json:"userID"`
+	CheckedOutAt time.Time  `json:"checkedOutAt"`
+	DueAt        time.Time  `json:"dueAt"`
+	Renewals     int        `json:"renewals"`
+	ReturnedAt   *time.Time `json:"returnedAt,omitempty"`
+}
+
+// EntityLoan is the entity of loan events in the outbox
+const EntityLoan = "loan"
+
+// LoanPolicy limits loans of a user
+type LoanPolicy struct {
+	Period      time.Duration
+	MaxRenewals int
+	MaxLoans    int
+}
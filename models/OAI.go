@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// OAIRecord is a book as OAI-PMH item, Datestamp is the last change of the book or its author
+type OAIRecord struct {
+	Book      Book
+	Datestamp time.Time
+	// Deleted books are reported while they are in trash
+	Deleted bool
+}
+
+// OAIFilter selects records by datestamp, From is inclusive and Before exclusive, zero values are not applied
+type OAIFilter struct {
+	From   time.Time
+	Before time.Time
+	// After is the last book id of the previous page
+	After int
+	Limit int
+}
+
+// OAIList asks for a page of records, Token continues an earlier list and excludes the other fields
+type OAIList struct {
+	MetadataPrefix string
+	From           string
+	Until          string
+	Set            string
+	Token          string
+}
+
+// OAIPage is a page of a list, Token is empty on the last page
+type OAIPage struct {
+	Records []OAIRecord
+	Token   string
+	Cursor  int
+	// Resumed is set when the page continues an earlier one, its last page then has an empty token
+	Resumed bool
+}
+
+// OAIRepository is the description given by Identify
+type OAIRepository struct {
+	Name              string
+	BaseURL           string
+	AdminEmail        string
+	EarliestDatestamp time.Time
+}
+
+// OAIMetadataFormat is a format records can be disseminated in
+type OAIMetadataFormat struct {
+	Prefix    string
+	Schema    string
+	Namespace string
+}
+
+// OAIError is an OAI-PMH error condition, it is reported in the response rather than as HTTP status
+type OAIError struct {
+	Code    string
+	Message string
+}
+
+func (e OAIError) Error() string {
+	return e.Code + " : " + e.Message
+}
+
+// OAI-PMH error codes
+const (
+	OAIBadArgument             = "badArgument"
+	OAIBadResumptionToken      = "badResumptionToken"
+	OAIBadVerb                 = "badVerb"
+	OAICannotDisseminateFormat = "cannotDisseminateFormat"
+	OAIIDDoesNotExist          = "idDoesNotExist"
+	OAINoRecordsMatch          = "noRecordsMatch"
+	OAINoSetHierarchy          = "noSetHierarchy"
+)
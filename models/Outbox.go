@@ -0,0 +1,25 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent is a change stored in the same transaction as the change, event names are the ones of webhooks like
+// book.created and data is the record after the change, or before it for deletes
+type OutboxEvent struct {
+	ID        int64           `json:"id"`
+	Event     string          `json:"event"`
+	Entity    string          `json:"entity"`
+	EntityID  int             `json:"entityID"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// Subscription is a stream of events, Complete is false when events after the requested id are lost and the client
+// has to read the records again. Cancel ends it and closes Events.
+type Subscription struct {
+	Events   <-chan OutboxEvent
+	Complete bool
+	Cancel   func()
+}
@@ -0,0 +1,39 @@
+package models
+
+import "strings"
+
+// Principal is the caller on whose behalf a request is served
+type Principal struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Kind tells how the caller was authenticated, apikey, jwt or user, or cli for the librarian command
+	Kind string `json:"kind"`
+}
+
+// Principal kinds
+const (
+	PrincipalAPIKey = "apikey"
+	PrincipalJWT    = "jwt"
+	PrincipalUser   = "user"
+	PrincipalCLI    = "cli"
+)
+
+// PrincipalID is the id of a principal, ids of different kinds may be equal so the kind comes first, like cli:asha
+func PrincipalID(kind, id string) string {
+	return kind + ":" + id
+}
+
+// IsPrincipalID tells if id starts with a known kind
+func IsPrincipalID(id string) bool {
+	kind, rest, ok := strings.Cut(id, ":")
+	if !ok || rest == "" {
+		return false
+	}
+
+	switch kind {
+	case PrincipalAPIKey, PrincipalJWT, PrincipalUser, PrincipalCLI:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// RateLimit is a token bucket which refills Requests tokens every Per and holds at most Burst tokens
+type RateLimit struct {
+	// Group separates buckets of route groups, a caller has one bucket per group
+	Group    string
+	Requests int
+	Per      time.Duration
+	// Burst defaults to Requests
+	Burst int
+}
+
+// RateLimitResult is the state of a bucket after taking a request from it
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until next request is allowed, zero when Allowed
+	RetryAfter time.Duration
+}
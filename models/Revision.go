@@ -0,0 +1,31 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Revision is a snapshot of a book or author after each change, numbered from 1 per record
+type Revision struct {
+	Entity    string          `json:"entity"`
+	EntityID  int             `json:"entityID"`
+	Rev       int             `json:"rev"`
+	Operation string          `json:"operation"`
+	Actor     string          `json:"actor"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// RevisionDiff lists the fields which differ between two revisions
+type RevisionDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// FieldChange is one changed field, nested fields are joined by dot like auth.firstName
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
@@ -0,0 +1,29 @@
+package models
+
+// Role groups permissions, principals get permissions through assigned roles
+type Role struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// Permissions are <resource>:<action>, a role may hold <resource>:* or * as wildcard
+const (
+	PermBookRead      = "book:read"
+	PermBookCreate    = "book:create"
+	PermBookUpdate    = "book:update"
+	PermBookDelete    = "book:delete"
+	PermBookRestore   = "book:restore"
+	PermBookImport    = "book:import"
+	PermBookExport    = "book:export"
+	PermAuthorRead    = "author:read"
+	PermAuthorCreate  = "author:create"
+	PermAuthorUpdate  = "author:update"
+	PermAuthorDelete  = "author:delete"
+	PermAuthorRestore = "author:restore"
+	PermTrashRead     = "trash:read"
+	PermAuditRead     = "audit:read"
+	PermRoleManage    = "role:manage"
+	PermUserManage    = "user:manage"
+	PermLoanManage    = "loan:manage"
+	PermWebhookManage = "webhook:manage"
+)
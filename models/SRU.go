@@ -0,0 +1,83 @@
+package models
+
+import "strconv"
+
+// BookQuery is a search over books, either a condition of Field, Relation and Term or Op joining Left and Right.
+// Op not is Left and not Right, a not without Left negates Right alone.
+type BookQuery struct {
+	Field    string
+	Relation string
+	Term     string
+
+	Op    string
+	Left  *BookQuery
+	Right *BookQuery
+}
+
+// Fields books are searched by, SearchAll matches every book
+const (
+	SearchTitle     = "title"
+	SearchAuthor    = "author"
+	SearchPublisher = "publisher"
+	SearchDate      = "date"
+	SearchAll       = "all"
+)
+
+// Relations of a condition, text fields take a LIKE pattern and dates a year or yyyy-mm-dd
+const (
+	RelationLike         = "like"
+	RelationEqual        = "="
+	RelationLess         = "<"
+	RelationLessEqual    = "<="
+	RelationGreater      = ">"
+	RelationGreaterEqual = ">="
+)
+
+// Operators joining conditions
+const (
+	OpAnd = "and"
+	OpOr  = "or"
+	OpNot = "not"
+)
+
+// SRUResult is a page of search results, Next is the position of the record after the page and 0 on the last page
+type SRUResult struct {
+	Books []Book
+	Total int
+	Next  int
+}
+
+// SRUDiagnostic is an SRU diagnostic, it is reported in the response rather than as HTTP status
+type SRUDiagnostic struct {
+	Code    int
+	Details string
+}
+
+func (d SRUDiagnostic) Error() string {
+	return "diagnostic " + strconv.Itoa(d.Code) + " : " + d.Details
+}
+
+// SRU diagnostic codes of info:srw/diagnostic/1/
+const (
+	SRUGeneralError                = 1
+	SRUUnsupportedOperation        = 4
+	SRUUnsupportedVersion          = 5
+	SRUUnsupportedParameterValue   = 6
+	SRUMandatoryParameter          = 7
+	SRUUnsupportedParameter        = 8
+	SRUQuerySyntax                 = 10
+	SRUUnsupportedIndex            = 16
+	SRUUnsupportedRelation         = 19
+	SRUUnsupportedRelationModifier = 20
+	SRUEmptyTerm                   = 27
+	SRUInvalidTerm                 = 36
+	SRUUnsupportedBoolean          = 37
+	SRUProximity                   = 39
+	SRUUnsupportedBooleanModifier  = 46
+	SRUFirstRecordOutOfRange       = 61
+	SRUUnknownSchema               = 66
+	SRUUnsupportedPacking          = 71
+	SRUXPath                       = 72
+	SRUSort                        = 80
+	SRUStylesheet                  = 110
+)
@@ -0,0 +1,7 @@
+package models
+
+// Trash holds soft deleted Books and Authors until they are restored or purged
+type Trash struct {
+	Books   []Book   `json:"books"`
+	Authors []Author `json:"authors"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// User is a staff or patron account which logs in with a password
+type User struct {
+	ID           int        `json:"id"`
+	Username     string     `json:"username"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"-"`
+	FailedLogins int        `json:"-"`
+	LockedUntil  *time.Time `json:"lockedUntil,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// Session is a login of a User, only the hash of its token is stored
+type Session struct {
+	ID        int       `json:"id"`
+	TokenHash string    `json:"-"`
+	UserID    int       `json:"userID"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// PasswordReset is a one time token sent to the user to choose a new password
+type PasswordReset struct {
+	TokenHash string
+	UserID    int
+	ExpiresAt time.Time
+}
+
+// Credentials is the body of login request
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Registration is the body of request creating a user
+type Registration struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// PasswordChange is the body of password reset request with the token sent to the user
+type PasswordChange struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// Login is returned after a successful login, Token is shown only once
+type Login struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
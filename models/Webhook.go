@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Webhook is a subscription of an URL to events, the secret signs every payload sent to it
+type Webhook struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Matches reports whether the webhook is subscribed to event
+func (w Webhook) Matches(event string) bool {
+	return MatchEvent(w.Events, event)
+}
+
+// MatchEvent reports whether any of filters selects event, filters are event names, <entity>.* or *
+func MatchEvent(filters []string, event string) bool {
+	entity, _, _ := strings.Cut(event, ".")
+
+	for _, filter := range filters {
+		if filter == "*" || filter == event || filter == entity+".*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsEventFilter reports whether filter selects any of the known events
+func IsEventFilter(filter string) bool {
+	for _, event := range Events {
+		if MatchEvent([]string{filter}, event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WebhookEvent is the payload POSTed to webhooks, data is the record after the change, or before it for deletes
+type WebhookEvent struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// WebhookDelivery is one event sent to one webhook with the result of its last attempt
+type WebhookDelivery struct {
+	ID            int             `json:"id"`
+	WebhookID     int             `json:"webhookID"`
+	EventID       string          `json:"eventID"`
+	Event         string          `json:"event"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	ResponseCode  int             `json:"responseCode,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	NextAttemptAt *time.Time      `json:"nextAttemptAt,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	DeliveredAt   *time.Time      `json:"deliveredAt,omitempty"`
+}
+
+// Status of webhook deliveries
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Events sent to webhooks
+const (
+	EventBookCreated    = "book.created"
+	EventBookUpdated    = "book.updated"
+	EventBookDeleted    = "book.deleted"
+	EventBookRestored   = "book.restored"
+	EventAuthorCreated  = "author.created"
+	EventAuthorUpdated  = "author.updated"
+	EventAuthorDeleted  = "author.deleted"
+	EventAuthorRestored = "author.restored"
+	EventLoanCheckedOut = "loan.checked_out"
+	EventLoanCheckedIn  = "loan.checked_in"
+	EventLoanRenewed    = "loan.renewed"
+)
+
+// Events lists every event a webhook can subscribe to
+var Events = []string{EventBookCreated, EventBookUpdated, EventBookDeleted, EventBookRestored, EventAuthorCreated,
+	EventAuthorUpdated, EventAuthorDeleted, EventAuthorRestored, EventLoanCheckedOut, EventLoanCheckedIn, EventLoanRenewed}
@@ -0,0 +1,42 @@
+// Package notifier delivers messages like password reset links to users
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/smtp"
+	"strings"
+)
+
+// Log writes messages to the server log, it is meant for development
+type Log struct{}
+
+// Notify logs the message
+func (Log) Notify(ctx context.Context, to, subject, body string) error {
+	slog.InfoContext(ctx, "notification", "to", to, "subject", subject, "body", body)
+
+	return nil
+}
+
+// SMTP sends messages as plain text mail
+type SMTP struct {
+	Addr string
+	From string
+	Auth smtp.Auth
+}
+
+// Notify sends the message to address to
+func (s SMTP) Notify(ctx context.Context, to, subject, body string) error {
+	// header injection through the subject or recipient
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return fmt.Errorf("invalid mail header")
+	}
+
+	msg := "From: " + s.From + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\n" + body
+
+	return smtp.SendMail(s.Addr, s.Auth, s.From, []string{to}, []byte(msg))
+}
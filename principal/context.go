@@ -0,0 +1,28 @@
+// Package principal carries the caller of a request through context.Context
+package principal
+
+import (
+	"context"
+
+	"Three-Layer-Architecture/models"
+)
+
+// Anonymous is used when no caller is attached to the context
+var Anonymous = models.Principal{ID: "anonymous", Name: "anonymous"}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying p
+func NewContext(ctx context.Context, p models.Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the caller attached to ctx, or Anonymous
+func FromContext(ctx context.Context) models.Principal {
+	p, ok := ctx.Value(contextKey{}).(models.Principal)
+	if !ok {
+		return Anonymous
+	}
+
+	return p
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Three-Layer-Architecture/models"
+)
+
+// TestMemory_Allow function is to test bucket empties at burst and refills over time
+func TestMemory_Allow(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	limit := models.RateLimit{Group: "read", Requests: 2, Per: time.Second}
+
+	testcases := []struct {
+		desc       string
+		after      time.Duration
+		key        string
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+	}{
+		{desc: "first", key: "a", allowed: true, remaining: 1},
+		{desc: "second", key: "a", allowed: true, remaining: 0},
+		{desc: "empty", key: "a", allowed: false, remaining: 0, retryAfter: 500 * time.Millisecond},
+		{desc: "other key", key: "b", allowed: true, remaining: 1},
+		{desc: "refilled one", after: 500 * time.Millisecond, key: "a", allowed: true, remaining: 0},
+		{desc: "refilled full", after: 10 * time.Second, key: "a", allowed: true, remaining: 1},
+	}
+
+	limiter := NewMemory()
+
+	for i, v := range testcases {
+		now = now.Add(v.after)
+		limiter.now = func() time.Time { return now }
+
+		result, err := limiter.Allow(context.Background(), v.key, limit)
+		if err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected <nil>\n", v.desc, i+1, err)
+		}
+
+		if result.Allowed != v.allowed || result.Remaining != v.remaining || result.RetryAfter != v.retryAfter {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %+v\tExpected allowed %v remaining %v retry after %v\n",
+				v.desc, i+1, result, v.allowed, v.remaining, v.retryAfter)
+		}
+	}
+}
+
+// TestMemory_Sweep function is to test full buckets are dropped
+func TestMemory_Sweep(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	limit := models.RateLimit{Requests: 10, Per: time.Second}
+
+	limiter := NewMemory()
+	limiter.now = func() time.Time { return now }
+
+	for _, key := range []string{"a", "b", "c"} {
+		if _, err := limiter.Allow(context.Background(), key, limit); err != nil {
+			t.Fatalf("[TEST1]Failed. Got %v\tExpected <nil>\n", err)
+		}
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if _, err := limiter.Allow(context.Background(), "a", limit); err != nil {
+		t.Fatalf("[TEST1]Failed. Got %v\tExpected <nil>\n", err)
+	}
+
+	if len(limiter.buckets) != 1 {
+		t.Errorf("[TEST1]Failed. Got %v buckets\tExpected 1\n", len(limiter.buckets))
+	}
+}
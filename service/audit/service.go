@@ -0,0 +1,39 @@
+package audit
+
+import (
+	"context"
+	"errors"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+type Service struct {
+	datastore datastore.Audit
+}
+
+func New(audit datastore.Audit) Service {
+	return Service{datastore: audit}
+}
+
+// Get method is to read audit entries by entity and time range
+func (s Service) Get(ctx context.Context, filter models.AuditFilter) ([]models.Audit, error) {
+	if filter.Entity != "" && filter.Entity != models.EntityBook && filter.Entity != models.EntityAuthor {
+		return nil, errors.New("invalid entity")
+	}
+
+	if filter.EntityID < 0 {
+		return nil, errors.New("invalid id")
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, errors.New("invalid time range")
+	}
+
+	audits, err := s.datastore.Get(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return audits, nil
+}
@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+// TestAudit_Get function is to test filter validation
+func TestAudit_Get(t *testing.T) {
+	at := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc   string
+		filter models.AuditFilter
+		resp   []models.Audit
+		err    error
+	}{
+		{desc: "valid", filter: models.AuditFilter{Entity: models.EntityBook, EntityID: 1, From: at, To: at.Add(time.Hour)},
+			resp: []models.Audit{{ID: 1, Entity: models.EntityBook, EntityID: 1}}},
+		{desc: "invalid entity", filter: models.AuditFilter{Entity: "patron"}, err: errors.New("invalid entity")},
+		{desc: "invalid id", filter: models.AuditFilter{EntityID: -1}, err: errors.New("invalid id")},
+		{desc: "invalid range", filter: models.AuditFilter{From: at, To: at}, err: errors.New("invalid time range")},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockAudit := datastore.NewMockAudit(ctr)
+		service := New(mockAudit)
+
+		mockAudit.EXPECT().Get(gomock.Any(), v.filter).Return(v.resp, nil).AnyTimes()
+
+		resp, err := service.Get(context.TODO(), v.filter)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
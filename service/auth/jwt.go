@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"time"
+
+	"Three-Layer-Architecture/models"
+)
+
+// leeway tolerates clock skew between token issuer and this server
+const leeway = time.Minute
+
+// JWTConfig configures accepted bearer tokens, a token is accepted if signed by any configured key
+type JWTConfig struct {
+	// HMACSecret enables HS256 tokens
+	HMACSecret []byte
+	// RSAPublicKey enables RS256 tokens
+	RSAPublicKey *rsa.PublicKey
+	// Issuer and Audience are checked when not empty
+	Issuer   string
+	Audience string
+}
+
+// ParseRSAPublicKey reads a PEM encoded PKIX or PKCS1 public key
+func ParseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM")
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("not an RSA public key")
+	}
+
+	return key, nil
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+type claims struct {
+	Subject   string   `json:"sub"`
+	Name      string   `json:"name"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+}
+
+// audience claim is either a string or a list of strings
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+
+	*a = list
+
+	return nil
+}
+
+func (a audience) contains(aud string) bool {
+	for _, v := range a {
+		if v == aud {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verifyJWT checks signature and registered claims of token and returns its subject
+func verifyJWT(config JWTConfig, token string, now time.Time) (models.Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return models.Principal{}, errors.New("malformed token")
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return models.Principal{}, errors.New("malformed token header")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return models.Principal{}, errors.New("malformed token signature")
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	digest := sha256.Sum256(signed)
+
+	// algorithm is only accepted when its key is configured, this rejects none and key confusion
+	switch {
+	case h.Alg == "HS256" && len(config.HMACSecret) > 0:
+		mac := hmac.New(sha256.New, config.HMACSecret)
+		mac.Write(signed)
+
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return models.Principal{}, errors.New("invalid token signature")
+		}
+	case h.Alg == "RS256" && config.RSAPublicKey != nil:
+		if err = rsa.VerifyPKCS1v15(config.RSAPublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			return models.Principal{}, errors.New("invalid token signature")
+		}
+	default:
+		return models.Principal{}, errors.New("unsupported token algorithm")
+	}
+
+	var c claims
+	if err = decodeSegment(parts[1], &c); err != nil {
+		return models.Principal{}, errors.New("malformed token claims")
+	}
+
+	if c.ExpiresAt == 0 || now.After(time.Unix(c.ExpiresAt, 0).Add(leeway)) {
+		return models.Principal{}, errors.New("token expired")
+	}
+
+	if c.NotBefore != 0 && now.Add(leeway).Before(time.Unix(c.NotBefore, 0)) {
+		return models.Principal{}, errors.New("token not valid yet")
+	}
+
+	if config.Issuer != "" && c.Issuer != config.Issuer {
+		return models.Principal{}, errors.New("invalid token issuer")
+	}
+
+	if config.Audience != "" && !c.Audience.contains(config.Audience) {
+		return models.Principal{}, errors.New("invalid token audience")
+	}
+
+	if c.Subject == "" {
+		return models.Principal{}, errors.New("missing token subject")
+	}
+
+	name := c.Name
+	if name == "" {
+		name = c.Subject
+	}
+
+	return models.Principal{ID: models.PrincipalID(models.PrincipalJWT, c.Subject), Name: name, Kind: models.PrincipalJWT}, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"Three-Layer-Architecture/models"
+)
+
+func sign(t *testing.T, alg string, key any, claims map[string]any) string {
+	t.Helper()
+
+	h, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	c, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+
+	var signature []byte
+
+	switch alg {
+	case "HS256":
+		mac := hmac.New(sha256.New, key.([]byte))
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case "RS256":
+		digest := sha256.Sum256([]byte(signed))
+
+		var err error
+
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key.(*rsa.PrivateKey), crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// TestVerifyJWT function is to test signature and claim checks of bearer tokens
+func TestVerifyJWT(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	secret := []byte("s3cret")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := JWTConfig{HMACSecret: secret, RSAPublicKey: &rsaKey.PublicKey, Issuer: "https://sso.library.test", Audience: "catalogue"}
+	valid := map[string]any{"sub": "u-1", "name": "Asha", "iss": "https://sso.library.test", "aud": "catalogue",
+		"exp": now.Add(time.Hour).Unix()}
+
+	with := func(key string, value any) map[string]any {
+		c := map[string]any{}
+		for k, v := range valid {
+			c[k] = v
+		}
+
+		c[key] = value
+
+		return c
+	}
+
+	testcases := []struct {
+		desc  string
+		token string
+		resp  models.Principal
+		err   error
+	}{
+		{desc: "valid HS256", token: sign(t, "HS256", secret, valid),
+			resp: models.Principal{ID: "jwt:u-1", Name: "Asha", Kind: models.PrincipalJWT}},
+		{desc: "valid RS256 with audience list", token: sign(t, "RS256", rsaKey, with("aud", []string{"opac", "catalogue"})),
+			resp: models.Principal{ID: "jwt:u-1", Name: "Asha", Kind: models.PrincipalJWT}},
+		{desc: "wrong secret", token: sign(t, "HS256", []byte("guess"), valid), err: errors.New("invalid token signature")},
+		{desc: "alg none", token: sign(t, "none", nil, valid), err: errors.New("unsupported token algorithm")},
+		{desc: "expired", token: sign(t, "HS256", secret, with("exp", now.Add(-time.Hour).Unix())), err: errors.New("token expired")},
+		{desc: "missing exp", token: sign(t, "HS256", secret, with("exp", 0)), err: errors.New("token expired")},
+		{desc: "not yet valid", token: sign(t, "HS256", secret, with("nbf", now.Add(time.Hour).Unix())),
+			err: errors.New("token not valid yet")},
+		{desc: "wrong issuer", token: sign(t, "HS256", secret, with("iss", "https://evil.test")), err: errors.New("invalid token issuer")},
+		{desc: "wrong audience", token: sign(t, "HS256", secret, with("aud", "billing")), err: errors.New("invalid token audience")},
+		{desc: "malformed", token: "abc.def", err: errors.New("malformed token")},
+	}
+
+	for i, v := range testcases {
+		resp, err := verifyJWT(config, v.token, now)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+
+	// RS256 only config must not accept HS256 signed with the public key
+	der := x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: der})
+
+	parsed, err := ParseRSAPublicKey(pemKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = verifyJWT(JWTConfig{RSAPublicKey: parsed}, sign(t, "HS256", pemKey, valid), now)
+	if !reflect.DeepEqual(err, errors.New("unsupported token algorithm")) {
+		t.Errorf("desc : key confusion ,Failed. Got %v\tExpected unsupported token algorithm\n", err)
+	}
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// api keys look like lib_<prefix>_<secret>, prefix is stored in clear to find the key
+const keyPrefix = "lib_"
+
+// ErrUnauthenticated is returned for every credential which is not accepted
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+type Service struct {
+	apiKey  datastore.APIKey
+	jwt     JWTConfig
+	session service.Session
+	now     func() time.Time
+}
+
+func New(apiKey datastore.APIKey, jwt JWTConfig, session service.Session) Service {
+	return Service{apiKey: apiKey, jwt: jwt, session: session, now: time.Now}
+}
+
+// AuthenticateAPIKey method is to find the principal of an api key
+func (s Service) AuthenticateAPIKey(ctx context.Context, key string) (models.Principal, error) {
+	prefix, ok := parseKey(key)
+	if !ok {
+		return models.Principal{}, ErrUnauthenticated
+	}
+
+	stored, err := s.apiKey.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return models.Principal{}, ErrUnauthenticated
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashKey(key)), []byte(stored.Hash)) != 1 {
+		return models.Principal{}, ErrUnauthenticated
+	}
+
+	return models.Principal{ID: models.PrincipalID(models.PrincipalAPIKey, stored.Principal), Name: stored.Name,
+		Kind: models.PrincipalAPIKey}, nil
+}
+
+// AuthenticateToken method is to verify a JWT bearer token
+func (s Service) AuthenticateToken(ctx context.Context, token string) (models.Principal, error) {
+	p, err := verifyJWT(s.jwt, token, s.now())
+	if err != nil {
+		return models.Principal{}, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+
+	return p, nil
+}
+
+// AuthenticateSession method is to find the user of a session token issued at login
+func (s Service) AuthenticateSession(ctx context.Context, token string) (models.Principal, error) {
+	p, err := s.session.AuthenticateSession(ctx, token)
+	if err != nil {
+		return models.Principal{}, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+
+	return p, nil
+}
+
+// CreateAPIKey method is to issue a new key for principal, the key is only returned here and never stored
+func (s Service) CreateAPIKey(ctx context.Context, name, principal string) (string, models.APIKey, error) {
+	if name == "" || principal == "" {
+		return "", models.APIKey{}, errors.New("missing fields")
+	}
+
+	prefix, err := randomHex(4)
+	if err != nil {
+		return "", models.APIKey{}, err
+	}
+
+	secret, err := randomHex(24)
+	if err != nil {
+		return "", models.APIKey{}, err
+	}
+
+	key := keyPrefix + prefix + "_" + secret
+
+	stored, err := s.apiKey.Post(ctx, models.APIKey{
+		Prefix:    prefix,
+		Hash:      hashKey(key),
+		Name:      name,
+		Principal: principal,
+		CreatedAt: s.now().UTC(),
+	})
+	if err != nil {
+		return "", models.APIKey{}, err
+	}
+
+	return key, stored, nil
+}
+
+// RevokeAPIKey method is to disable a key by its id
+func (s Service) RevokeAPIKey(ctx context.Context, id string) (int, error) {
+	if id == "" {
+		return 0, errors.New("missing id")
+	}
+
+	return s.apiKey.Revoke(ctx, id)
+}
+
+func parseKey(key string) (string, bool) {
+	if !strings.HasPrefix(key, keyPrefix) {
+		return "", false
+	}
+
+	prefix, secret, ok := strings.Cut(strings.TrimPrefix(key, keyPrefix), "_")
+	if !ok || prefix == "" || secret == "" {
+		return "", false
+	}
+
+	return prefix, true
+}
+
+// hashKey uses plain SHA-256, keys are random so a slow hash is not needed
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// TestAuth_APIKey function is to test issuing and checking api keys
+func TestAuth_APIKey(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockAPIKey := datastore.NewMockAPIKey(ctr)
+	service := New(mockAPIKey, JWTConfig{}, service.NewMockSession(ctr))
+
+	var stored models.APIKey
+
+	mockAPIKey.EXPECT().Post(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key models.APIKey) (models.APIKey, error) {
+		key.ID = 1
+		stored = key
+
+		return key, nil
+	})
+
+	key, created, err := service.CreateAPIKey(context.TODO(), "discovery layer", "svc-discovery")
+	if err != nil {
+		t.Fatalf("[TEST1]Failed. Got %v\tExpected <nil>\n", err)
+	}
+
+	if !strings.HasPrefix(key, "lib_"+created.Prefix+"_") || created.Hash == "" || strings.Contains(created.Hash, key) {
+		t.Errorf("[TEST1]Failed. Got key %v for %v", key, created)
+	}
+
+	testcases := []struct {
+		desc   string
+		key    string
+		prefix string
+		found  error
+		resp   models.Principal
+		err    error
+	}{
+		{desc: "valid", key: key, prefix: created.Prefix,
+			resp: models.Principal{ID: "apikey:svc-discovery", Name: "discovery layer", Kind: models.PrincipalAPIKey}},
+		{desc: "wrong secret", key: "lib_" + created.Prefix + "_guess", prefix: created.Prefix, err: ErrUnauthenticated},
+		{desc: "revoked or unknown", key: "lib_abcd_guess", prefix: "abcd", found: errors.New("sql: no rows in result set"),
+			err: ErrUnauthenticated},
+		{desc: "malformed", key: "guess", err: ErrUnauthenticated},
+	}
+
+	for i, v := range testcases {
+		mockAPIKey.EXPECT().GetByPrefix(gomock.Any(), v.prefix).Return(stored, v.found).AnyTimes()
+
+		resp, err := service.AuthenticateAPIKey(context.TODO(), v.key)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+2, resp, v.resp)
+		}
+
+		if !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+2, err, v.err)
+		}
+	}
+}
+
+// TestAuth_Token function is to test bearer token errors are unauthenticated
+func TestAuth_Token(t *testing.T) {
+	ctr := gomock.NewController(t)
+	service := New(datastore.NewMockAPIKey(ctr), JWTConfig{HMACSecret: []byte("s3cret")}, service.NewMockSession(ctr))
+
+	_, err := service.AuthenticateToken(context.TODO(), "abc.def.ghi")
+	if !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("[TEST1]Failed. Got %v\tExpected %v\n", err, ErrUnauthenticated)
+	}
+}
@@ -2,43 +2,68 @@ package author
 
 import (
 	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/metrics"
 	"Three-Layer-Architecture/models"
-	"errors"
-	"fmt"
+	"Three-Layer-Architecture/service"
+	"context"
 	"strconv"
 )
 
 type Service struct {
 	datastore datastore.Author
+	revision  service.Revision
 }
 
-func New(author datastore.Author) Service {
-	return Service{author}
+func New(author datastore.Author, revision service.Revision) Service {
+	return Service{datastore: author, revision: revision}
 }
 
 // Post Author details
-func (a Service) Post(auth models.Author) (models.Author, error) {
+func (a Service) Post(ctx context.Context, auth models.Author) (models.Author, error) {
+	if err := a.Validate(ctx, auth); err != nil {
+		return models.Author{}, err
+	}
+
+	return a.datastore.Post(ctx, auth)
+}
+
+// Validate method is to check a new author without storing it
+func (a Service) Validate(ctx context.Context, auth models.Author) error {
 	// Checking for invalid id
 	if auth.AuthID <= 0 {
-		return models.Author{}, errors.New("invalid id")
+		return invalid("invalid id")
 	}
 
 	if isMissingFields(auth) {
-		return models.Author{}, fmt.Errorf("missing fields")
+		return invalid("missing fields")
 	}
 
-	author, err := a.datastore.Post(auth)
+	return nil
+}
+
+// Getbyid method is to get Author details by id
+func (a Service) Getbyid(ctx context.Context, id string) (models.Author, error) {
+	if id == "" {
+		return models.Author{}, invalid("missing id")
+	}
+
+	// converting string to integer to check for invalid id
+	iD, err := strconv.Atoi(id)
 	if err != nil {
 		return models.Author{}, err
 	}
 
-	return author, nil
+	if iD <= 0 {
+		return models.Author{}, invalid("invalid id")
+	}
+
+	return a.datastore.Getbyid(ctx, id)
 }
 
 // Update Author details
-func (a Service) Update(id string, auth models.Author) (models.Author, error) {
+func (a Service) Update(ctx context.Context, id string, auth models.Author) (models.Author, error) {
 	if id == "" {
-		return models.Author{}, errors.New("missing id")
+		return models.Author{}, invalid("missing id")
 	}
 
 	// converting string to integer
@@ -49,26 +74,42 @@ func (a Service) Update(id string, auth models.Author) (models.Author, error) {
 
 	// Checking invalid id
 	if iD <= 0 {
-		return models.Author{}, errors.New("invalid id")
+		return models.Author{}, invalid("invalid id")
 	}
 
 	if isMissingFields(auth) {
-		return models.Author{}, errors.New("missing fields")
+		return models.Author{}, invalid("missing fields")
 	}
 
-	author, err := a.datastore.Update(id, auth)
+	return a.datastore.Update(ctx, id, auth)
+}
+
+// Delete Author by its ID
+func (a Service) Delete(ctx context.Context, id string) (int, error) {
+	// Checking for missing id
+	if id == "" {
+		return 0, invalid("missing id")
+	}
+
+	// converting string to integer
+	iD, err := strconv.Atoi(id)
 	if err != nil {
-		return models.Author{}, err
+		return 0, err
 	}
 
-	return author, nil
+	// Checking for invalid id
+	if iD <= 0 {
+		return 0, invalid("invalid id")
+	}
+
+	return a.datastore.Delete(ctx, id)
 }
 
-// Delete Author by its ID
-func (a Service) Delete(id string) (int, error) {
+// Restore Author from trash by its ID
+func (a Service) Restore(ctx context.Context, id string) (int, error) {
 	// Checking for missing id
 	if id == "" {
-		return 0, errors.New("missing id")
+		return 0, invalid("missing id")
 	}
 
 	// converting string to integer
@@ -79,15 +120,28 @@ func (a Service) Delete(id string) (int, error) {
 
 	// Checking for invalid id
 	if iD <= 0 {
-		return 0, fmt.Errorf("invalid id")
+		return 0, invalid("invalid id")
 	}
 
-	rowaffected, err := a.datastore.Delete(id)
+	return a.datastore.Restore(ctx, id)
+}
+
+// GetRevisions method is to get history of an Author
+func (a Service) GetRevisions(ctx context.Context, id string) ([]models.Revision, error) {
+	if id == "" {
+		return nil, invalid("missing id")
+	}
+
+	iD, err := strconv.Atoi(id)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	if iD <= 0 {
+		return nil, invalid("invalid id")
 	}
 
-	return rowaffected, nil
+	return a.revision.GetAll(ctx, models.EntityAuthor, iD)
 }
 
 func isMissingFields(auth models.Author) bool {
@@ -97,3 +151,10 @@ func isMissingFields(auth models.Author) bool {
 
 	return false
 }
+
+// invalid counts the validation failure and returns reason as error
+func invalid(reason string) error {
+	metrics.ValidationFailure("author", reason)
+
+	return service.ValidationError(reason)
+}
@@ -1,8 +1,7 @@
 package author
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"reflect"
 	"testing"
 
@@ -10,6 +9,7 @@ import (
 
 	"Three-Layer-Architecture/datastore"
 	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
 )
 
 // TestStorer_Post function is to test post author details for valid conditions
@@ -23,25 +23,26 @@ func TestAuthor_Post(t *testing.T) {
 		{desc: "valid details", req: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
 			response: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}, err: nil},
 		{desc: "missing first name", req: models.Author{AuthID: 1, LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			err: fmt.Errorf("missing fields")},
+			err: service.ValidationError("missing fields")},
 		{desc: "invalid id", req: models.Author{AuthID: -11, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			err: fmt.Errorf("invalid id")},
+			err: service.ValidationError("invalid id")},
 		{desc: "missing last name", req: models.Author{AuthID: 1, FirstName: "Chetan", Dob: "06/04/2001", PenName: "Chetan"},
-			err: fmt.Errorf("missing fields")},
+			err: service.ValidationError("missing fields")},
 		{desc: "missing dob", req: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", PenName: "Chetan"},
-			err: fmt.Errorf("missing fields")},
+			err: service.ValidationError("missing fields")},
 		{desc: "missing penname", req: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001"},
-			err: fmt.Errorf("missing fields")},
+			err: service.ValidationError("missing fields")},
 	}
 
 	ctr := gomock.NewController(t)
 	mockAuthor := datastore.NewMockAuthor(ctr)
-	service := New(mockAuthor)
+	mockRevision := service.NewMockRevision(ctr)
+	service := New(mockAuthor, mockRevision)
 
 	for i, v := range testcases {
-		mockAuthor.EXPECT().Post(v.req).Return(v.response, v.err).AnyTimes()
+		mockAuthor.EXPECT().Post(gomock.Any(), v.req).Return(v.response, v.err).AnyTimes()
 
-		resp, err := service.Post(v.req)
+		resp, err := service.Post(context.TODO(), v.req)
 
 		if !reflect.DeepEqual(resp, v.response) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.response)
@@ -53,6 +54,39 @@ func TestAuthor_Post(t *testing.T) {
 	}
 }
 
+// TestAuthor_Getbyid function is to test for get an author
+func TestAuthor_Getbyid(t *testing.T) {
+	testcases := []struct {
+		desc string
+		id   string
+		resp models.Author
+		err  error
+	}{
+		{desc: "valid detail", id: "1",
+			resp: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}},
+		{desc: "invalid id", id: "-11", err: service.ValidationError("invalid id")},
+		{desc: "missing id", err: service.ValidationError("missing id")},
+	}
+
+	ctr := gomock.NewController(t)
+	mockAuthor := datastore.NewMockAuthor(ctr)
+	service := New(mockAuthor, service.NewMockRevision(ctr))
+
+	for i, v := range testcases {
+		mockAuthor.EXPECT().Getbyid(gomock.Any(), v.id).Return(v.resp, v.err).AnyTimes()
+
+		resp, err := service.Getbyid(context.TODO(), v.id)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
 // TestAuthor_Put function is to test update valid author details
 func TestAuthor_Put(t *testing.T) {
 	testcases := []struct {
@@ -63,18 +97,19 @@ func TestAuthor_Put(t *testing.T) {
 	}{
 		{desc: "valid", id: "1", req: models.Author{AuthID: 1, FirstName: "Rajan", LastName: "Sharma",
 			Dob: "26/04/2001", PenName: "Rajan"}},
-		{desc: "invalid id", id: "-11", err: errors.New("invalid id")},
-		{desc: "missing id", err: errors.New("missing id")},
+		{desc: "invalid id", id: "-11", err: service.ValidationError("invalid id")},
+		{desc: "missing id", err: service.ValidationError("missing id")},
 	}
 
 	ctr := gomock.NewController(t)
 	mockAuthor := datastore.NewMockAuthor(ctr)
-	service := New(mockAuthor)
+	mockRevision := service.NewMockRevision(ctr)
+	service := New(mockAuthor, mockRevision)
 
 	for i, v := range testcases {
-		mockAuthor.EXPECT().Update(v.id, v.req).Return(v.req, v.err).AnyTimes()
+		mockAuthor.EXPECT().Update(gomock.Any(), v.id, v.req).Return(v.req, v.err).AnyTimes()
 
-		resp, err := service.Update(v.id, v.req)
+		resp, err := service.Update(context.TODO(), v.id, v.req)
 
 		if !reflect.DeepEqual(resp, v.req) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.req)
@@ -95,18 +130,52 @@ func TestAuthor_Delete(t *testing.T) {
 		err         error
 	}{
 		{desc: "valid", id: "1", rowaffected: 1, err: nil},
-		{desc: "invalid id", id: "-11", err: fmt.Errorf("invalid id")},
-		{desc: "missing id", err: fmt.Errorf("missing id")},
+		{desc: "invalid id", id: "-11", err: service.ValidationError("invalid id")},
+		{desc: "missing id", err: service.ValidationError("missing id")},
+	}
+
+	ctr := gomock.NewController(t)
+	mockAuthor := datastore.NewMockAuthor(ctr)
+	mockRevision := service.NewMockRevision(ctr)
+	service := New(mockAuthor, mockRevision)
+
+	for i, v := range testcases {
+		mockAuthor.EXPECT().Delete(gomock.Any(), v.id).Return(v.rowaffected, v.err).AnyTimes()
+
+		resp, err := service.Delete(context.TODO(), v.id)
+
+		if !reflect.DeepEqual(resp, v.rowaffected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.rowaffected)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestAuthor_Restore function is to test restoring author from trash
+func TestAuthor_Restore(t *testing.T) {
+	testcases := []struct {
+		desc        string
+		id          string
+		rowaffected int
+		err         error
+	}{
+		{desc: "valid", id: "1", rowaffected: 1, err: nil},
+		{desc: "invalid id", id: "-11", err: service.ValidationError("invalid id")},
+		{desc: "missing id", err: service.ValidationError("missing id")},
 	}
 
 	ctr := gomock.NewController(t)
 	mockAuthor := datastore.NewMockAuthor(ctr)
-	service := New(mockAuthor)
+	mockRevision := service.NewMockRevision(ctr)
+	service := New(mockAuthor, mockRevision)
 
 	for i, v := range testcases {
-		mockAuthor.EXPECT().Delete(v.id).Return(v.rowaffected, v.err).AnyTimes()
+		mockAuthor.EXPECT().Restore(gomock.Any(), v.id).Return(v.rowaffected, v.err).AnyTimes()
 
-		resp, err := service.Delete(v.id)
+		resp, err := service.Restore(context.TODO(), v.id)
 
 		if !reflect.DeepEqual(resp, v.rowaffected) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.rowaffected)
@@ -0,0 +1,90 @@
+package author
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+	"Three-Layer-Architecture/tracing"
+)
+
+// Traced wraps an author service with a span around every method
+type Traced struct {
+	next service.Author
+}
+
+func NewTraced(next service.Author) Traced {
+	return Traced{next: next}
+}
+
+func (t Traced) Post(ctx context.Context, author models.Author) (models.Author, error) {
+	ctx, span := tracing.Start(ctx, "author.Post")
+	span.SetAttributes(attribute.Int("author.id", author.AuthID))
+
+	resp, err := t.next.Post(ctx, author)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) Getbyid(ctx context.Context, id string) (models.Author, error) {
+	ctx, span := tracing.Start(ctx, "author.Getbyid")
+	span.SetAttributes(attribute.String("author.id", id))
+
+	resp, err := t.next.Getbyid(ctx, id)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) Update(ctx context.Context, id string, author models.Author) (models.Author, error) {
+	ctx, span := tracing.Start(ctx, "author.Update")
+	span.SetAttributes(attribute.String("author.id", id))
+
+	resp, err := t.next.Update(ctx, id, author)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) Delete(ctx context.Context, id string) (int, error) {
+	ctx, span := tracing.Start(ctx, "author.Delete")
+	span.SetAttributes(attribute.String("author.id", id))
+
+	resp, err := t.next.Delete(ctx, id)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) Restore(ctx context.Context, id string) (int, error) {
+	ctx, span := tracing.Start(ctx, "author.Restore")
+	span.SetAttributes(attribute.String("author.id", id))
+
+	resp, err := t.next.Restore(ctx, id)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) GetRevisions(ctx context.Context, id string) ([]models.Revision, error) {
+	ctx, span := tracing.Start(ctx, "author.GetRevisions")
+	span.SetAttributes(attribute.String("author.id", id))
+
+	resp, err := t.next.GetRevisions(ctx, id)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) Validate(ctx context.Context, author models.Author) error {
+	ctx, span := tracing.Start(ctx, "author.Validate")
+	span.SetAttributes(attribute.Int("author.id", author.AuthID))
+
+	err := t.next.Validate(ctx, author)
+	tracing.End(span, err)
+
+	return err
+}
@@ -2,59 +2,73 @@ package book
 
 import (
 	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/metrics"
 	"Three-Layer-Architecture/models"
-	"errors"
-	"fmt"
-	"log"
+	"Three-Layer-Architecture/service"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Service struct {
 	datastore datastore.Book
+	revision  service.Revision
 }
 
-func New(book datastore.Book) Service {
-	return Service{book}
+func New(book datastore.Book, revision service.Revision) Service {
+	return Service{datastore: book, revision: revision}
 }
 
 // Post method is to post Book details
-func (a Service) Post(book *models.Book) (models.Book, error) {
+func (a Service) Post(ctx context.Context, book *models.Book) (models.Book, error) {
+	if err := a.Validate(ctx, book); err != nil {
+		return models.Book{}, err
+	}
+
+	return a.datastore.Post(ctx, book)
+}
+
+// Validate method is to check a new book without storing it
+func (a Service) Validate(ctx context.Context, book *models.Book) error {
 	if book.BookID <= 0 {
-		return models.Book{}, errors.New("invalid id")
+		return invalid("invalid id")
 	}
 
 	// missing book fields
 	if isBookFieldsMissing(book) {
-		return models.Book{}, errors.New("missing book fields")
+		return invalid("missing book fields")
 	}
 
 	// missing author fields
 	if isAuthorFieldsMissing(book.Auth) {
-		return models.Book{}, errors.New("missing author fields")
+		return invalid("missing author fields")
 	}
 
 	if !isValidPublishedDate(book.PublishedDate) {
-		return models.Book{}, errors.New("invalid publishedDate")
+		return invalid("invalid publishedDate")
 	}
 
 	if !isValidPublication(book.Publication) {
-		return models.Book{}, errors.New("invalid publication")
+		return invalid("invalid publication")
 	}
 
-	_, err := a.datastore.Post(book)
-	if err != nil {
-		return models.Book{}, err
+	book.ISBN = strings.ReplaceAll(book.ISBN, "-", "")
+	if !isValidISBN(book.ISBN) {
+		return invalid("invalid isbn")
 	}
 
-	return *book, nil
+	return nil
 }
 
 // Getbyid method is to get Book details by id
-func (a Service) Getbyid(id string) (models.Book, error) {
+func (a Service) Getbyid(ctx context.Context, id string) (models.Book, error) {
 	// checking missing id
 	if id == "" {
-		return models.Book{}, errors.New("missing id")
+		return models.Book{}, invalid("missing id")
 	}
 
 	// converting string to integer to check for invalid id
@@ -65,10 +79,10 @@ func (a Service) Getbyid(id string) (models.Book, error) {
 
 	// Checking invalid id
 	if iD <= 0 {
-		return models.Book{}, errors.New("invalid id")
+		return models.Book{}, invalid("invalid id")
 	}
 
-	book, err := a.datastore.Getbyid(id)
+	book, err := a.datastore.Getbyid(ctx, id)
 	if err != nil {
 		return models.Book{}, err
 	}
@@ -77,49 +91,49 @@ func (a Service) Getbyid(id string) (models.Book, error) {
 }
 
 // Update method is to update Book details
-func (a Service) Update(id string, book *models.Book) (models.Book, error) {
+func (a Service) Update(ctx context.Context, id string, book *models.Book) (models.Book, error) {
 	// checking missing id
 	if id == "" {
-		return models.Book{}, errors.New("missing id")
+		return models.Book{}, invalid("missing id")
 	}
 
 	// missing author fields
 	if isAuthorFieldsMissing(book.Auth) {
-		return models.Book{}, errors.New("missing author fields")
+		return models.Book{}, invalid("missing author fields")
 	}
 
 	// missing book fields
 	if isBookFieldsMissing(book) {
-		return models.Book{}, errors.New("missing book fields")
+		return models.Book{}, invalid("missing book fields")
 	}
 
 	if !isValidPublishedDate(book.PublishedDate) {
-		return models.Book{}, errors.New("invalid publishedDate")
+		return models.Book{}, invalid("invalid publishedDate")
 	}
 
 	if !isValidPublication(book.Publication) {
-		return models.Book{}, errors.New("invalid publication")
+		return models.Book{}, invalid("invalid publication")
+	}
+
+	book.ISBN = strings.ReplaceAll(book.ISBN, "-", "")
+	if !isValidISBN(book.ISBN) {
+		return models.Book{}, invalid("invalid isbn")
 	}
 
 	// converting string to integer to check for invalid id
 	iD, _ := strconv.Atoi(id)
 	if iD <= 0 {
-		return models.Book{}, fmt.Errorf("invalid id")
-	}
-
-	bk, err := a.datastore.Update(id, book)
-	if err != nil {
-		return models.Book{}, err
+		return models.Book{}, invalid("invalid id")
 	}
 
-	return bk, nil
+	return a.datastore.Update(ctx, id, book)
 }
 
 // Delete method is to delete Book details
-func (a Service) Delete(id string) (int, error) {
+func (a Service) Delete(ctx context.Context, id string) (int, error) {
 	// Checking missing id
 	if id == "" {
-		return 0, errors.New("missing id")
+		return 0, invalid("missing id")
 	}
 
 	// converting string to integer to check for invalid id
@@ -130,25 +144,41 @@ func (a Service) Delete(id string) (int, error) {
 
 	// Checking invalid id
 	if iD <= 0 {
-		return 0, errors.New("invalid id")
+		return 0, invalid("invalid id")
 	}
 
-	rowAffected, err := a.datastore.Delete(id)
+	return a.datastore.Delete(ctx, id)
+}
+
+// Restore method is to bring back deleted Book details
+func (a Service) Restore(ctx context.Context, id string) (int, error) {
+	// Checking missing id
+	if id == "" {
+		return 0, invalid("missing id")
+	}
+
+	// converting string to integer to check for invalid id
+	iD, err := strconv.Atoi(id)
 	if err != nil {
 		return 0, err
 	}
 
-	return rowAffected, nil
+	// Checking invalid id
+	if iD <= 0 {
+		return 0, invalid("invalid id")
+	}
+
+	return a.datastore.Restore(ctx, id)
 }
 
 // GetAll method is to details of book
-func (a Service) GetAll() ([]models.Book, error) {
+func (a Service) GetAll(ctx context.Context) ([]models.Book, error) {
 	// To store book details
 	var book []models.Book
 
 	var err error
 
-	book, err = a.datastore.GetAll()
+	book, err = a.datastore.GetAll(ctx)
 	if err != nil {
 		return []models.Book{}, err
 	}
@@ -156,15 +186,121 @@ func (a Service) GetAll() ([]models.Book, error) {
 	return book, nil
 }
 
+// GetRevisions method is to get history of a Book
+func (a Service) GetRevisions(ctx context.Context, id string) ([]models.Revision, error) {
+	iD, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.revision.GetAll(ctx, models.EntityBook, iD)
+}
+
+// DiffRevisions method is to compare two revisions of a Book
+func (a Service) DiffRevisions(ctx context.Context, id, from, to string) (models.RevisionDiff, error) {
+	iD, err := parseID(id)
+	if err != nil {
+		return models.RevisionDiff{}, err
+	}
+
+	fromRev, err := strconv.Atoi(from)
+	if err != nil {
+		return models.RevisionDiff{}, invalid("invalid revision")
+	}
+
+	toRev, err := strconv.Atoi(to)
+	if err != nil {
+		return models.RevisionDiff{}, invalid("invalid revision")
+	}
+
+	return a.revision.Diff(ctx, models.EntityBook, iD, fromRev, toRev)
+}
+
+// GetAsOf method is to get Book as it was at given time
+func (a Service) GetAsOf(ctx context.Context, id string, at time.Time) (models.Book, error) {
+	iD, err := parseID(id)
+	if err != nil {
+		return models.Book{}, err
+	}
+
+	rev, err := a.revision.AsOf(ctx, models.EntityBook, iD, at)
+	if err != nil {
+		return models.Book{}, err
+	}
+
+	// book was in trash at that time
+	if rev.Operation == models.AuditDelete {
+		return models.Book{}, sql.ErrNoRows
+	}
+
+	var book models.Book
+
+	if err = json.Unmarshal(rev.Data, &book); err != nil {
+		return models.Book{}, err
+	}
+
+	return book, nil
+}
+
+// Revert method is to update Book back to the state of given revision, it is recorded as a new revision
+func (a Service) Revert(ctx context.Context, id, rev string) (models.Book, error) {
+	iD, err := parseID(id)
+	if err != nil {
+		return models.Book{}, err
+	}
+
+	revNo, err := strconv.Atoi(rev)
+	if err != nil {
+		return models.Book{}, invalid("invalid revision")
+	}
+
+	revision, err := a.revision.Get(ctx, models.EntityBook, iD, revNo)
+	if err != nil {
+		return models.Book{}, err
+	}
+
+	if revision.Operation == models.AuditDelete {
+		return models.Book{}, invalid("cannot revert to a deleted revision")
+	}
+
+	var book models.Book
+
+	if err = json.Unmarshal(revision.Data, &book); err != nil {
+		return models.Book{}, err
+	}
+
+	return a.Update(ctx, id, &book)
+}
+
+func parseID(id string) (int, error) {
+	if id == "" {
+		return 0, invalid("missing id")
+	}
+
+	iD, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, err
+	}
+
+	if iD <= 0 {
+		return 0, invalid("invalid id")
+	}
+
+	return iD, nil
+}
+
 func isValidPublishedDate(date string) bool {
 	split := strings.Split(date, "/")
+	if len(split) != 3 {
+		return false
+	}
 
 	yearInstr := split[2]
 
 	yearInint, err := strconv.Atoi(yearInstr)
 
 	if err != nil {
-		log.Printf("Cannot convert dob in integer : %v", yearInint)
+		slog.Debug("invalid published year", "year", yearInstr)
 	}
 
 	if yearInint < 2022 && yearInint > 1880 {
@@ -182,6 +318,43 @@ func isValidPublication(pub string) bool {
 	return false
 }
 
+// isValidISBN checks the check digit of an ISBN-10 or ISBN-13, a book without ISBN is valid
+func isValidISBN(isbn string) bool {
+	sum := 0
+
+	switch len(isbn) {
+	case 0:
+		return true
+	case 10:
+		for i, c := range isbn {
+			digit := int(c - '0')
+
+			// X stands for 10 as the check digit only
+			if c == 'X' && i == 9 {
+				digit = 10
+			} else if c < '0' || c > '9' {
+				return false
+			}
+
+			sum += (10 - i) * digit
+		}
+
+		return sum%11 == 0
+	case 13:
+		for i, c := range isbn {
+			if c < '0' || c > '9' {
+				return false
+			}
+
+			sum += int(c-'0') * (1 + 2*(i%2))
+		}
+
+		return sum%10 == 0
+	default:
+		return false
+	}
+}
+
 func isAuthorFieldsMissing(auth models.Author) bool {
 	if auth.FirstName == "" || auth.LastName == "" || auth.PenName == "" || auth.Dob == "" {
 		return true
@@ -197,3 +370,10 @@ func isBookFieldsMissing(book *models.Book) bool {
 
 	return false
 }
+
+// invalid counts the validation failure and returns reason as error
+func invalid(reason string) error {
+	metrics.ValidationFailure("book", reason)
+
+	return service.ValidationError(reason)
+}
@@ -1,14 +1,19 @@
 package book
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
 	"Three-Layer-Architecture/datastore"
 	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
 )
 
 // TestBook_Post function is to test post author details
@@ -37,37 +42,54 @@ func TestBook_Post(t *testing.T) {
 			response: models.Book{BookID: 3, AuthorID: 1,
 				Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
 				Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}},
+		{desc: "isbn with hyphens", req: models.Book{BookID: 4, AuthorID: 1,
+			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016", ISBN: "978-81-291-3552-0"},
+			response: models.Book{BookID: 4, AuthorID: 1,
+				Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
+				Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016", ISBN: "9788129135520"}},
+		{desc: "isbn-10 with check digit X", req: models.Book{BookID: 5, AuthorID: 1,
+			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016", ISBN: "080442957X"},
+			response: models.Book{BookID: 5, AuthorID: 1,
+				Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
+				Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016", ISBN: "080442957X"}},
+		{desc: "invalid isbn", req: models.Book{BookID: 1, AuthorID: 1,
+			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016", ISBN: "9788129135521"},
+			err: service.ValidationError("invalid isbn")},
 		{desc: "invalid id", req: models.Book{BookID: -11, AuthorID: 1,
 			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}, err: errors.New("invalid id")},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}, err: service.ValidationError("invalid id")},
 		{desc: "invalid publication", req: models.Book{BookID: 1, AuthorID: 1,
 			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Lenin", PublishedDate: "16/03/2016"}, err: errors.New("invalid publication")},
+			Title: "2 States", Publication: "Lenin", PublishedDate: "16/03/2016"}, err: service.ValidationError("invalid publication")},
 		{desc: "invalid publishedDate", req: models.Book{BookID: 1, AuthorID: 1,
 			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2061"}, err: errors.New("invalid publishedDate")},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2061"}, err: service.ValidationError("invalid publishedDate")},
 		{desc: "missing title", req: models.Book{BookID: 1, AuthorID: 1,
 			Auth:        models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Publication: "Scholastic", PublishedDate: "16/03/2016"}, err: errors.New("missing book fields")},
+			Publication: "Scholastic", PublishedDate: "16/03/2016"}, err: service.ValidationError("missing book fields")},
 		{desc: "missing publication", req: models.Book{BookID: 1, AuthorID: 1,
 			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", PublishedDate: "16/03/2016"}, err: errors.New("missing book fields")},
+			Title: "2 States", PublishedDate: "16/03/2016"}, err: service.ValidationError("missing book fields")},
 		{desc: "missing publishedDate", req: models.Book{BookID: 1, AuthorID: 1,
 			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Scholastic"}, err: errors.New("missing book fields")},
+			Title: "2 States", Publication: "Scholastic"}, err: service.ValidationError("missing book fields")},
 		{desc: "missing Author fields", req: models.Book{BookID: 1, AuthorID: 1,
 			Auth:  models.Author{AuthID: 1, LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}, err: errors.New("missing author fields")},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}, err: service.ValidationError("missing author fields")},
 	}
 
 	for i, v := range testcases {
 		ctr := gomock.NewController(t)
 		mockBook := datastore.NewMockBook(ctr)
-		service := New(mockBook)
+		mockRevision := service.NewMockRevision(ctr)
+		service := New(mockBook, mockRevision)
 
-		mockBook.EXPECT().Post(&v.req).Return(v.response, v.err).AnyTimes()
+		mockBook.EXPECT().Post(gomock.Any(), &v.req).Return(v.response, v.err).AnyTimes()
 
-		resp, err := service.Post(&v.req)
+		resp, err := service.Post(context.TODO(), &v.req)
 
 		if !reflect.DeepEqual(resp, v.response) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.response)
@@ -95,11 +117,12 @@ func TestBook_GetAll(t *testing.T) {
 	for i, v := range testcases {
 		ctr := gomock.NewController(t)
 		mockBook := datastore.NewMockBook(ctr)
-		service := New(mockBook)
+		mockRevision := service.NewMockRevision(ctr)
+		service := New(mockBook, mockRevision)
 
-		mockBook.EXPECT().GetAll().Return(v.resp, v.err).AnyTimes()
+		mockBook.EXPECT().GetAll(gomock.Any()).Return(v.resp, v.err).AnyTimes()
 
-		resp, err := service.GetAll()
+		resp, err := service.GetAll(context.TODO())
 
 		if !reflect.DeepEqual(resp, v.resp) {
 			t.Errorf("Desc : %v,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
@@ -123,18 +146,19 @@ func TestBook_Getbyid(t *testing.T) {
 		{desc: "valid detail", id: "1", resp: models.Book{BookID: 1, AuthorID: 1,
 			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
 			Title: "States", Publication: "Scholastic", PublishedDate: "16/03/2016"}},
-		{desc: "invalid id", id: "-11", err: errors.New("invalid id")},
-		{desc: "missing id", err: errors.New("missing id")},
+		{desc: "invalid id", id: "-11", err: service.ValidationError("invalid id")},
+		{desc: "missing id", err: service.ValidationError("missing id")},
 	}
 
 	ctr := gomock.NewController(t)
 	mockBook := datastore.NewMockBook(ctr)
-	service := New(mockBook)
+	mockRevision := service.NewMockRevision(ctr)
+	service := New(mockBook, mockRevision)
 
 	for i, v := range testcases {
-		mockBook.EXPECT().Getbyid(v.id).Return(v.resp, v.err).AnyTimes()
+		mockBook.EXPECT().Getbyid(gomock.Any(), v.id).Return(v.resp, v.err).AnyTimes()
 
-		resp, err := service.Getbyid(v.id)
+		resp, err := service.Getbyid(context.TODO(), v.id)
 
 		if !reflect.DeepEqual(resp, v.resp) {
 			t.Errorf("Desc : %v,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
@@ -162,32 +186,37 @@ func TestBook_Put(t *testing.T) {
 			Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2016"}},
 		{desc: "missing id", req: models.Book{BookID: 1, AuthorID: 1,
 			Auth:  models.Author{AuthID: 1, FirstName: "Gaurav", LastName: "Singh", Dob: "07/04/2001", PenName: "Gaurav"},
-			Title: "300 Days", Publication: "lenin", PublishedDate: "17/03/2016"}, err: errors.New("missing id"), resp: models.Book{}},
+			Title: "300 Days", Publication: "lenin", PublishedDate: "17/03/2016"}, err: service.ValidationError("missing id"), resp: models.Book{}},
 		{desc: "invalid id", req: models.Book{BookID: 1, AuthorID: 1,
 			Auth:  models.Author{AuthID: 1, FirstName: "Gaurav", LastName: "Singh", Dob: "07/04/2001", PenName: "Gaurav"},
-			Title: "300 Days", Publication: "Arihant", PublishedDate: "17/03/2016"}, id: "-11", err: errors.New("invalid id")},
+			Title: "300 Days", Publication: "Arihant", PublishedDate: "17/03/2016"}, id: "-11", err: service.ValidationError("invalid id")},
 		{desc: "invalid publication", id: "1", req: models.Book{BookID: 1, AuthorID: 1,
 			Auth:  models.Author{AuthID: 1, FirstName: "Gaurav", LastName: "Singh", Dob: "07/04/2001", PenName: "Gaurav"},
-			Title: "300 Days", Publication: "lenin", PublishedDate: "17/03/2016"}, err: errors.New("invalid publication")},
+			Title: "300 Days", Publication: "lenin", PublishedDate: "17/03/2016"}, err: service.ValidationError("invalid publication")},
 		{desc: "invalid publishedDate", id: "1", req: models.Book{BookID: 1, AuthorID: 1,
 			Auth:  models.Author{AuthID: 1, FirstName: "Gaurav", LastName: "Singh", Dob: "07/04/2001", PenName: "Gaurav"},
-			Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2061"}, err: errors.New("invalid publishedDate")},
+			Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2061"}, err: service.ValidationError("invalid publishedDate")},
+		{desc: "invalid isbn", id: "1", req: models.Book{BookID: 1, AuthorID: 1,
+			Auth:  models.Author{AuthID: 1, FirstName: "Gaurav", LastName: "Singh", Dob: "07/04/2001", PenName: "Gaurav"},
+			Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2016", ISBN: "81291153X1"},
+			err: service.ValidationError("invalid isbn")},
 		{desc: "missing book fields", id: "1", req: models.Book{BookID: 1, AuthorID: 1,
 			Auth:        models.Author{AuthID: 1, FirstName: "Gaurav", LastName: "Singh", Dob: "07/04/2001", PenName: "Gaurav"},
-			Publication: "lenin", PublishedDate: "17/03/2016"}, err: errors.New("missing book fields")},
+			Publication: "lenin", PublishedDate: "17/03/2016"}, err: service.ValidationError("missing book fields")},
 		{desc: "missing author fields", id: "1", req: models.Book{BookID: 1, AuthorID: 1,
 			Auth:        models.Author{AuthID: 1, LastName: "Singh", Dob: "07/04/2001", PenName: "Gaurav"},
-			Publication: "Arihant", PublishedDate: "17/03/2016"}, err: errors.New("missing author fields")},
+			Publication: "Arihant", PublishedDate: "17/03/2016"}, err: service.ValidationError("missing author fields")},
 	}
 
 	for i, v := range testcases {
 		ctr := gomock.NewController(t)
 		mockBook := datastore.NewMockBook(ctr)
-		service := New(mockBook)
+		mockRevision := service.NewMockRevision(ctr)
+		service := New(mockBook, mockRevision)
 
-		mockBook.EXPECT().Update(v.id, &v.req).Return(v.resp, v.err).AnyTimes()
+		mockBook.EXPECT().Update(gomock.Any(), v.id, &v.req).Return(v.resp, v.err).AnyTimes()
 
-		resp, err := service.Update(v.id, &v.req)
+		resp, err := service.Update(context.TODO(), v.id, &v.req)
 
 		if !reflect.DeepEqual(resp, v.resp) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
@@ -208,18 +237,53 @@ func TestBook_Delete(t *testing.T) {
 		err         error
 	}{
 		{desc: "valid", id: "1", rowAffected: 1},
-		{desc: "missing id", err: errors.New("missing id")},
-		{desc: "invalid id", id: "-11", err: errors.New("invalid id")},
+		{desc: "missing id", err: service.ValidationError("missing id")},
+		{desc: "invalid id", id: "-11", err: service.ValidationError("invalid id")},
+	}
+
+	ctr := gomock.NewController(t)
+	mockBook := datastore.NewMockBook(ctr)
+	mockRevision := service.NewMockRevision(ctr)
+	service := New(mockBook, mockRevision)
+
+	for i, v := range testcases {
+		mockBook.EXPECT().Delete(gomock.Any(), v.id).Return(v.rowAffected, v.err).AnyTimes()
+
+		resp, err := service.Delete(context.TODO(), v.id)
+
+		if !reflect.DeepEqual(resp, v.rowAffected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.rowAffected)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestBook_Restore function is to test restoring book from trash
+func TestBook_Restore(t *testing.T) {
+	testcases := []struct {
+		desc        string
+		id          string
+		rowAffected int
+		err         error
+	}{
+		{desc: "valid", id: "1", rowAffected: 1},
+		{desc: "missing id", err: service.ValidationError("missing id")},
+		{desc: "invalid id", id: "-11", err: service.ValidationError("invalid id")},
+		{desc: "not in trash", id: "5", err: errors.New("sql: no rows in result set")},
 	}
 
 	ctr := gomock.NewController(t)
 	mockBook := datastore.NewMockBook(ctr)
-	service := New(mockBook)
+	mockRevision := service.NewMockRevision(ctr)
+	service := New(mockBook, mockRevision)
 
 	for i, v := range testcases {
-		mockBook.EXPECT().Delete(v.id).Return(v.rowAffected, v.err).AnyTimes()
+		mockBook.EXPECT().Restore(gomock.Any(), v.id).Return(v.rowAffected, v.err).AnyTimes()
 
-		resp, err := service.Delete(v.id)
+		resp, err := service.Restore(context.TODO(), v.id)
 
 		if !reflect.DeepEqual(resp, v.rowAffected) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.rowAffected)
@@ -230,3 +294,82 @@ func TestBook_Delete(t *testing.T) {
 		}
 	}
 }
+
+// TestBook_GetAsOf function is to test reading book as it was at a time
+func TestBook_GetAsOf(t *testing.T) {
+	at := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		desc string
+		id   string
+		rev  models.Revision
+		resp models.Book
+		err  error
+	}{
+		{desc: "valid", id: "1", rev: models.Revision{Operation: models.AuditUpdate, Data: json.RawMessage(`{"bookID":1,"title":"Journey"}`)},
+			resp: models.Book{BookID: 1, Title: "Journey"}},
+		{desc: "deleted at that time", id: "1", rev: models.Revision{Operation: models.AuditDelete, Data: json.RawMessage(`{"bookID":1}`)},
+			err: sql.ErrNoRows},
+		{desc: "invalid id", id: "-1", err: service.ValidationError("invalid id")},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockBook := datastore.NewMockBook(ctr)
+		mockRevision := service.NewMockRevision(ctr)
+		service := New(mockBook, mockRevision)
+
+		mockRevision.EXPECT().AsOf(gomock.Any(), models.EntityBook, 1, at).Return(v.rev, nil).AnyTimes()
+
+		resp, err := service.GetAsOf(context.TODO(), v.id, at)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestBook_Revert function is to test book goes back to an old revision through update
+func TestBook_Revert(t *testing.T) {
+	old := models.Book{BookID: 1, AuthorID: 1,
+		Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
+		Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}
+	data, _ := json.Marshal(old)
+
+	testcases := []struct {
+		desc string
+		rev  string
+		revs models.Revision
+		resp models.Book
+		err  error
+	}{
+		{desc: "valid", rev: "2", revs: models.Revision{Rev: 2, Operation: models.AuditUpdate, Data: data}, resp: old},
+		{desc: "deleted revision", rev: "3", revs: models.Revision{Rev: 3, Operation: models.AuditDelete, Data: data},
+			err: service.ValidationError("cannot revert to a deleted revision")},
+		{desc: "invalid revision", rev: "a", err: service.ValidationError("invalid revision")},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockBook := datastore.NewMockBook(ctr)
+		mockRevision := service.NewMockRevision(ctr)
+		service := New(mockBook, mockRevision)
+
+		mockRevision.EXPECT().Get(gomock.Any(), models.EntityBook, 1, v.revs.Rev).Return(v.revs, nil).AnyTimes()
+		mockBook.EXPECT().Update(gomock.Any(), "1", &old).Return(old, nil).AnyTimes()
+
+		resp, err := service.Revert(context.TODO(), "1", v.rev)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package book
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+	"Three-Layer-Architecture/tracing"
+)
+
+// Traced wraps a book service with a span around every method
+type Traced struct {
+	next service.Book
+}
+
+func NewTraced(next service.Book) Traced {
+	return Traced{next: next}
+}
+
+func (t Traced) Post(ctx context.Context, book *models.Book) (models.Book, error) {
+	ctx, span := tracing.Start(ctx, "book.Post")
+	span.SetAttributes(attribute.Int("book.id", book.BookID))
+
+	resp, err := t.next.Post(ctx, book)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) GetAll(ctx context.Context) ([]models.Book, error) {
+	ctx, span := tracing.Start(ctx, "book.GetAll")
+
+	resp, err := t.next.GetAll(ctx)
+	span.SetAttributes(attribute.Int("book.count", len(resp)))
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) Getbyid(ctx context.Context, id string) (models.Book, error) {
+	ctx, span := tracing.Start(ctx, "book.Getbyid")
+	span.SetAttributes(attribute.String("book.id", id))
+
+	resp, err := t.next.Getbyid(ctx, id)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) Update(ctx context.Context, id string, book *models.Book) (models.Book, error) {
+	ctx, span := tracing.Start(ctx, "book.Update")
+	span.SetAttributes(attribute.String("book.id", id))
+
+	resp, err := t.next.Update(ctx, id, book)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) Delete(ctx context.Context, id string) (int, error) {
+	ctx, span := tracing.Start(ctx, "book.Delete")
+	span.SetAttributes(attribute.String("book.id", id))
+
+	resp, err := t.next.Delete(ctx, id)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) Restore(ctx context.Context, id string) (int, error) {
+	ctx, span := tracing.Start(ctx, "book.Restore")
+	span.SetAttributes(attribute.String("book.id", id))
+
+	resp, err := t.next.Restore(ctx, id)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) GetRevisions(ctx context.Context, id string) ([]models.Revision, error) {
+	ctx, span := tracing.Start(ctx, "book.GetRevisions")
+	span.SetAttributes(attribute.String("book.id", id))
+
+	resp, err := t.next.GetRevisions(ctx, id)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) DiffRevisions(ctx context.Context, id, from, to string) (models.RevisionDiff, error) {
+	ctx, span := tracing.Start(ctx, "book.DiffRevisions")
+	span.SetAttributes(attribute.String("book.id", id), attribute.String("revision.from", from), attribute.String("revision.to", to))
+
+	resp, err := t.next.DiffRevisions(ctx, id, from, to)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) GetAsOf(ctx context.Context, id string, at time.Time) (models.Book, error) {
+	ctx, span := tracing.Start(ctx, "book.GetAsOf")
+	span.SetAttributes(attribute.String("book.id", id), attribute.String("book.as_of", at.Format(time.RFC3339)))
+
+	resp, err := t.next.GetAsOf(ctx, id, at)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) Revert(ctx context.Context, id, rev string) (models.Book, error) {
+	ctx, span := tracing.Start(ctx, "book.Revert")
+	span.SetAttributes(attribute.String("book.id", id), attribute.String("revision", rev))
+
+	resp, err := t.next.Revert(ctx, id, rev)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (t Traced) Validate(ctx context.Context, book *models.Book) error {
+	ctx, span := tracing.Start(ctx, "book.Validate")
+	span.SetAttributes(attribute.Int("book.id", book.BookID))
+
+	err := t.next.Validate(ctx, book)
+	tracing.End(span, err)
+
+	return err
+}
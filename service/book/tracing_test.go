@@ -0,0 +1,56 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// TestTraced_Getbyid function is to test a span is recorded with the book id and error status
+func TestTraced_Getbyid(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	testcases := []struct {
+		desc   string
+		id     string
+		err    error
+		status codes.Code
+	}{
+		{desc: "found", id: "1", status: codes.Unset},
+		{desc: "not found", id: "2", err: errors.New("sql: no rows in result set"), status: codes.Error},
+	}
+
+	ctr := gomock.NewController(t)
+	mockBook := service.NewMockBook(ctr)
+	traced := NewTraced(mockBook)
+
+	for i, v := range testcases {
+		mockBook.EXPECT().Getbyid(gomock.Any(), v.id).Return(models.Book{}, v.err)
+
+		if _, err := traced.Getbyid(context.Background(), v.id); !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		spans := recorder.Ended()
+		span := spans[len(spans)-1]
+
+		if span.Name() != "book.Getbyid" || span.Status().Code != v.status {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got span %v with status %v\tExpected book.Getbyid with %v\n",
+				v.desc, i+1, span.Name(), span.Status().Code, v.status)
+		}
+
+		if attrs := span.Attributes(); len(attrs) != 1 || attrs[0].Value.AsString() != v.id {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got attributes %v\tExpected book.id %v\n", v.desc, i+1, attrs, v.id)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Package consistency finds stored rows which break rules, for rows written around the services or before a rule
+// was added. Nothing is repaired, the report tells which rows to fix.
+package consistency
+
+import (
+	"context"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+type Service struct {
+	datastore datastore.Consistency
+	book      service.Book
+}
+
+func New(consistency datastore.Consistency, book service.Book) Service {
+	return Service{datastore: consistency, book: book}
+}
+
+// Check method is to report rows breaking the schema rules and books which the book service would not accept
+func (s Service) Check(ctx context.Context) ([]models.Inconsistency, error) {
+	found, err := s.datastore.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	books, err := s.book.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range books {
+		if err = s.book.Validate(ctx, &books[i]); err != nil {
+			found = append(found, models.Inconsistency{Check: "book.invalid", Entity: models.EntityBook,
+				EntityID: books[i].BookID, Detail: err.Error()})
+		}
+	}
+
+	return found, nil
+}
@@ -0,0 +1,38 @@
+package consistency
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// TestCheck function is to test books the book service rejects are reported with the schema rules
+func TestCheck(t *testing.T) {
+	trashed := models.Inconsistency{Check: "book.trashed_author", Entity: models.EntityBook, EntityID: 2,
+		Detail: "author 1 is in trash"}
+	books := []models.Book{{BookID: 1}, {BookID: 2}}
+
+	ctr := gomock.NewController(t)
+	mockConsistency := datastore.NewMockConsistency(ctr)
+	mockBook := service.NewMockBook(ctr)
+
+	mockConsistency.EXPECT().Check(gomock.Any()).Return([]models.Inconsistency{trashed}, nil)
+	mockBook.EXPECT().GetAll(gomock.Any()).Return(books, nil)
+	mockBook.EXPECT().Validate(gomock.Any(), &books[0]).Return(nil)
+	mockBook.EXPECT().Validate(gomock.Any(), &books[1]).Return(errors.New("invalid publishedDate"))
+
+	expected := []models.Inconsistency{trashed, {Check: "book.invalid", Entity: models.EntityBook, EntityID: 2,
+		Detail: "invalid publishedDate"}}
+
+	found, err := New(mockConsistency, mockBook).Check(context.Background())
+	if err != nil || !reflect.DeepEqual(found, expected) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "inconsistencies", 1, found, err, expected)
+	}
+}
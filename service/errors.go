@@ -0,0 +1,8 @@
+package service
+
+// ValidationError is a request a service refused, transports report it as a mistake of the caller
+type ValidationError string
+
+func (e ValidationError) Error() string {
+	return string(e)
+}
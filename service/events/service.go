@@ -0,0 +1,60 @@
+// Package events streams outbox events to clients as they are sent, clients filter them by topic and resume after
+// the last one they have seen while the broker still keeps it.
+package events
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"Three-Layer-Architecture/metrics"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+type Service struct {
+	broker service.Broker
+	buffer int
+}
+
+// New returns a service subscribing to broker, each subscriber holds up to buffer events it has not read
+func New(broker service.Broker, buffer int) Service {
+	return Service{broker: broker, buffer: buffer}
+}
+
+// Subscribe method is to stream events of topics after lastID, every event when there are no topics.
+// Topics are event names, <entity>.* or *, lastID is empty for new events only.
+func (s Service) Subscribe(_ context.Context, topics []string, lastID string) (models.Subscription, error) {
+	for _, topic := range topics {
+		if !models.IsEventFilter(topic) {
+			return models.Subscription{}, invalid("invalid topic")
+		}
+	}
+
+	if len(topics) == 0 {
+		topics = []string{"*"}
+	}
+
+	var after int64
+
+	if lastID != "" {
+		var err error
+
+		after, err = strconv.ParseInt(lastID, 10, 64)
+		if err != nil || after < 0 {
+			return models.Subscription{}, invalid("invalid last event id")
+		}
+	}
+
+	events, cancel, complete := s.broker.Subscribe(after, s.buffer, func(event string) bool {
+		return models.MatchEvent(topics, event)
+	})
+
+	return models.Subscription{Events: events, Complete: complete, Cancel: cancel}, nil
+}
+
+func invalid(reason string) error {
+	metrics.ValidationFailure("events", reason)
+
+	return errors.New(reason)
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// TestSubscribe function is to test topics and last event id are checked and handed to the broker
+func TestSubscribe(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		topics  []string
+		lastID  string
+		after   int64
+		matches map[string]bool
+		err     error
+	}{
+		{"every event", nil, "", 0, map[string]bool{"book.created": true, "loan.renewed": true}, nil},
+		{"entity topic resumed", []string{"book.*", "author.deleted"}, "42", 42,
+			map[string]bool{"book.updated": true, "author.deleted": true, "author.created": false}, nil},
+		{"unknown topic", []string{"shelf.*"}, "", 0, nil, invalid("invalid topic")},
+		{"invalid last event id", nil, "abc", 0, nil, invalid("invalid last event id")},
+		{"negative last event id", nil, "-1", 0, nil, invalid("invalid last event id")},
+	}
+
+	for i, tc := range tcs {
+		ctr := gomock.NewController(t)
+		mockBroker := service.NewMockBroker(ctr)
+
+		if tc.err == nil {
+			mockBroker.EXPECT().Subscribe(tc.after, 16, gomock.Any()).DoAndReturn(
+				func(_ int64, _ int, match func(string) bool) (<-chan models.OutboxEvent, func(), bool) {
+					for event, expected := range tc.matches {
+						if match(event) != expected {
+							t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", tc.desc, i+1, !expected, expected)
+						}
+					}
+
+					return make(chan models.OutboxEvent), func() {}, true
+				})
+		}
+
+		sub, err := New(mockBroker, 16).Subscribe(context.Background(), tc.topics, tc.lastID)
+		if tc.err != nil && (err == nil || err.Error() != tc.err.Error()) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", tc.desc, i+1, err, tc.err)
+		}
+
+		if tc.err == nil && (err != nil || !sub.Complete || sub.Events == nil) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", tc.desc, i+1, err, nil)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"Three-Layer-Architecture/citation"
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/marc"
+	"Three-Layer-Architecture/models"
+)
+
+// flushEvery is the count of books written before the buffered output is sent on
+const flushEvery = 100
+
+type Service struct {
+	datastore datastore.Export
+}
+
+func New(export datastore.Export) Service {
+	return Service{datastore: export}
+}
+
+// Export method is to write books matching the filter to w as they are read, with the selected fields only.
+// Options are checked before anything is written, so an error without output means a bad request.
+func (s Service) Export(ctx context.Context, w io.Writer, opts models.ExportOptions) error {
+	fields := opts.Fields
+
+	if isRecord(opts.Format) && len(fields) > 0 {
+		return fmt.Errorf("fields can not be selected for %v", opts.Format)
+	}
+
+	if len(fields) == 0 {
+		fields = models.ExportFields
+	}
+
+	for _, f := range fields {
+		if !isField(f) {
+			return fmt.Errorf("unknown field %v", f)
+		}
+	}
+
+	enc, err := encoder(opts.Format, fields)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(w)
+	count := 0
+
+	flush := func() error {
+		if err := out.Flush(); err != nil {
+			return err
+		}
+
+		// streamed responses are sent on instead of waiting for the handler to return
+		if f, ok := w.(interface{ Flush() }); ok {
+			f.Flush()
+		}
+
+		return nil
+	}
+
+	if err = enc.begin(out); err != nil {
+		return err
+	}
+
+	err = s.datastore.Stream(ctx, opts.Filter, func(book models.Book) error {
+		if err := enc.write(out, book); err != nil {
+			return err
+		}
+
+		if count++; count%flushEvery == 0 {
+			return flush()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("export stopped after %d books : %w", count, err)
+	}
+
+	if err = enc.end(out); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "catalogue exported", "format", opts.Format, "books", count)
+
+	return flush()
+}
+
+// isRecord tells if a format has a fixed layout of its own
+func isRecord(format string) bool {
+	switch format {
+	case models.ExportMARC, models.ExportMARCXML, models.ExportBibTeX, models.ExportRIS, models.ExportCSLJSON,
+		models.ExportDC:
+		return true
+	default:
+		return false
+	}
+}
+
+func isField(name string) bool {
+	for _, f := range models.ExportFields {
+		if f == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// values gives every export field of a book
+func values(book models.Book) map[string]any {
+	return map[string]any{
+		"bookID":        book.BookID,
+		"title":         book.Title,
+		"publication":   book.Publication,
+		"publishedDate": book.PublishedDate,
+		"authorID":      book.AuthorID,
+		"firstName":     book.Auth.FirstName,
+		"lastName":      book.Auth.LastName,
+		"dob":           book.Auth.Dob,
+		"penName":       book.Auth.PenName,
+		"isbn":          book.ISBN,
+	}
+}
+
+// format writes books of one export format
+type format interface {
+	begin(w *bufio.Writer) error
+	write(w *bufio.Writer, book models.Book) error
+	end(w *bufio.Writer) error
+}
+
+func encoder(name string, fields []string) (format, error) {
+	switch name {
+	case models.ExportCSV:
+		return &csvFormat{fields: fields}, nil
+	case models.ExportNDJSON:
+		return &jsonFormat{fields: fields}, nil
+	case models.ExportJSON:
+		return &jsonFormat{fields: fields, array: true}, nil
+	case models.ExportMARC:
+		return &marcFormat{}, nil
+	case models.ExportMARCXML:
+		return &marcFormat{xml: true}, nil
+	case models.ExportBibTeX, models.ExportRIS, models.ExportCSLJSON, models.ExportDC:
+		return &citationFormat{name: name}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", name)
+	}
+}
+
+type csvFormat struct {
+	fields []string
+	writer *csv.Writer
+	record []string
+}
+
+func (c *csvFormat) begin(w *bufio.Writer) error {
+	c.writer = csv.NewWriter(w)
+	c.record = make([]string, len(c.fields))
+
+	return c.flush(c.fields)
+}
+
+func (c *csvFormat) write(_ *bufio.Writer, book models.Book) error {
+	values := values(book)
+
+	for i, f := range c.fields {
+		switch v := values[f].(type) {
+		case int:
+			c.record[i] = strconv.Itoa(v)
+		case string:
+			c.record[i] = v
+		}
+	}
+
+	return c.flush(c.record)
+}
+
+func (c *csvFormat) end(_ *bufio.Writer) error {
+	return nil
+}
+
+// flush hands the record to the buffered writer, which decides when it is sent
+func (c *csvFormat) flush(record []string) error {
+	if err := c.writer.Write(record); err != nil {
+		return err
+	}
+
+	c.writer.Flush()
+
+	return c.writer.Error()
+}
+
+// jsonFormat writes one object per line, or one array when array is set
+type jsonFormat struct {
+	fields  []string
+	array   bool
+	written bool
+}
+
+func (j *jsonFormat) begin(w *bufio.Writer) error {
+	if j.array {
+		return w.WriteByte('[')
+	}
+
+	return nil
+}
+
+func (j *jsonFormat) write(w *bufio.Writer, book models.Book) error {
+	values := values(book)
+
+	var b strings.Builder
+
+	if j.array && j.written {
+		b.WriteByte(',')
+	}
+
+	j.written = true
+
+	// fields keep the requested order
+	b.WriteByte('{')
+
+	for i, f := range j.fields {
+		value, err := json.Marshal(values[f])
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		b.WriteString(strconv.Quote(f) + ":")
+		b.Write(value)
+	}
+
+	b.WriteByte('}')
+
+	if !j.array {
+		b.WriteByte('\n')
+	}
+
+	_, err := w.WriteString(b.String())
+
+	return err
+}
+
+func (j *jsonFormat) end(w *bufio.Writer) error {
+	if j.array {
+		_, err := w.WriteString("]\n")
+
+		return err
+	}
+
+	return nil
+}
+
+// marcFormat writes ISO 2709 records, or a MARCXML collection when xml is set
+type marcFormat struct {
+	xml    bool
+	writer interface{ Write(marc.Record) error }
+	closer *marc.XMLWriter
+}
+
+func (m *marcFormat) begin(w *bufio.Writer) error {
+	if !m.xml {
+		m.writer = marc.NewWriter(w)
+
+		return nil
+	}
+
+	m.closer = marc.NewXMLWriter(w)
+	m.writer = m.closer
+
+	return nil
+}
+
+func (m *marcFormat) write(_ *bufio.Writer, book models.Book) error {
+	return m.writer.Write(marc.FromBook(book))
+}
+
+func (m *marcFormat) end(_ *bufio.Writer) error {
+	if m.closer != nil {
+		return m.closer.Close()
+	}
+
+	return nil
+}
+
+// citationFormat writes bibliographies, CSL-JSON items in one array and Dublin Core records in one collection
+type citationFormat struct {
+	name    string
+	written bool
+}
+
+func (c *citationFormat) begin(w *bufio.Writer) error {
+	switch c.name {
+	case models.ExportCSLJSON:
+		return w.WriteByte('[')
+	case models.ExportDC:
+		_, err := w.WriteString(xml.Header + "<collection>\n")
+
+		return err
+	default:
+		return nil
+	}
+}
+
+func (c *citationFormat) write(w *bufio.Writer, book models.Book) error {
+	first := !c.written
+	c.written = true
+
+	switch c.name {
+	case models.ExportBibTeX:
+		if !first {
+			if err := w.WriteByte('\n'); err != nil {
+				return err
+			}
+		}
+
+		_, err := w.WriteString(citation.BibTeX(book))
+
+		return err
+	case models.ExportRIS:
+		_, err := w.WriteString(citation.RIS(book))
+
+		return err
+	case models.ExportCSLJSON:
+		body, err := json.Marshal(citation.CSLJSON(book))
+		if err != nil {
+			return err
+		}
+
+		if !first {
+			body = append([]byte{','}, body...)
+		}
+
+		_, err = w.Write(body)
+
+		return err
+	default:
+		body, err := xml.Marshal(citation.DublinCore(book))
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(append(body, '\n'))
+
+		return err
+	}
+}
+
+func (c *citationFormat) end(w *bufio.Writer) error {
+	switch c.name {
+	case models.ExportCSLJSON:
+		_, err := w.WriteString("]\n")
+
+		return err
+	case models.ExportDC:
+		_, err := w.WriteString("</collection>\n")
+
+		return err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,140 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/citation"
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/marc"
+	"Three-Layer-Architecture/models"
+)
+
+// TestExport function is to test every format writes the selected fields of streamed books
+func TestExport(t *testing.T) {
+	author := models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}
+	books := []models.Book{
+		{BookID: 1, AuthorID: 1, Auth: author, Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016",
+			ISBN: "8129115301"},
+		{BookID: 3, AuthorID: 1, Auth: author, Title: "One Indian Girl, Again", Publication: "Penguin",
+			PublishedDate: "01/10/2016"},
+	}
+	filter := models.BookFilter{AuthorID: 1}
+
+	testcases := []struct {
+		desc string
+		opts models.ExportOptions
+		resp string
+	}{
+		{desc: "csv with every field", opts: models.ExportOptions{Format: models.ExportCSV, Filter: filter},
+			resp: "bookID,title,publication,publishedDate,authorID,firstName,lastName,dob,penName,isbn\n" +
+				"1,2 States,Scholastic,16/03/2016,1,Chetan,Bhagat,06/04/2001,Chetan,8129115301\n" +
+				"3,\"One Indian Girl, Again\",Penguin,01/10/2016,1,Chetan,Bhagat,06/04/2001,Chetan,\n"},
+		{desc: "csv with selected fields",
+			opts: models.ExportOptions{Format: models.ExportCSV, Fields: []string{"title", "bookID"}, Filter: filter},
+			resp: "title,bookID\n2 States,1\n\"One Indian Girl, Again\",3\n"},
+		{desc: "ndjson", opts: models.ExportOptions{Format: models.ExportNDJSON, Fields: []string{"bookID", "penName"},
+			Filter: filter}, resp: "{\"bookID\":1,\"penName\":\"Chetan\"}\n{\"bookID\":3,\"penName\":\"Chetan\"}\n"},
+		{desc: "json", opts: models.ExportOptions{Format: models.ExportJSON, Fields: []string{"bookID", "title"},
+			Filter: filter}, resp: "[{\"bookID\":1,\"title\":\"2 States\"},{\"bookID\":3,\"title\":\"One Indian Girl, Again\"}]\n"},
+		{desc: "marc", opts: models.ExportOptions{Format: models.ExportMARC, Filter: filter}, resp: encode(t, books...)},
+		{desc: "bibtex", opts: models.ExportOptions{Format: models.ExportBibTeX, Filter: filter},
+			resp: citation.BibTeX(books[0]) + "\n" + citation.BibTeX(books[1])},
+		{desc: "ris", opts: models.ExportOptions{Format: models.ExportRIS, Filter: filter},
+			resp: citation.RIS(books[0]) + citation.RIS(books[1])},
+		{desc: "csl json", opts: models.ExportOptions{Format: models.ExportCSLJSON, Filter: filter},
+			resp: `[{"id":"1","type":"book","title":"2 States","author":[{"family":"Bhagat","given":"Chetan"}],` +
+				`"publisher":"Scholastic","issued":{"date-parts":[[2016,3,16]]}},{"id":"3","type":"book",` +
+				`"title":"One Indian Girl, Again","author":[{"family":"Bhagat","given":"Chetan"}],"publisher":"Penguin",` +
+				`"issued":{"date-parts":[[2016,10,1]]}}]` + "\n"},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockExport := datastore.NewMockExport(ctr)
+
+		mockExport.EXPECT().Stream(gomock.Any(), filter, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ models.BookFilter, fn func(models.Book) error) error {
+				for _, b := range books {
+					if err := fn(b); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			})
+
+		var out bytes.Buffer
+
+		err := New(mockExport).Export(context.Background(), &out, v.opts)
+		if err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, nil)
+		}
+
+		if out.String() != v.resp {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, out.String(), v.resp)
+		}
+
+		ctr.Finish()
+	}
+}
+
+// TestExport_Errors function is to test bad options are refused before writing and cursor errors are returned
+func TestExport_Errors(t *testing.T) {
+	testcases := []struct {
+		desc      string
+		opts      models.ExportOptions
+		streamErr error
+		err       error
+	}{
+		{desc: "unsupported format", opts: models.ExportOptions{Format: "xml"}, err: errors.New(`unsupported format "xml"`)},
+		{desc: "unknown field", opts: models.ExportOptions{Format: models.ExportCSV, Fields: []string{"barcode"}},
+			err: errors.New("unknown field barcode")},
+		{desc: "fields of marc", opts: models.ExportOptions{Format: models.ExportMARC, Fields: []string{"title"}},
+			err: errors.New("fields can not be selected for marc")},
+		{desc: "cursor error", opts: models.ExportOptions{Format: models.ExportNDJSON},
+			streamErr: errors.New("connection reset"), err: errors.New("export stopped after 0 books : connection reset")},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockExport := datastore.NewMockExport(ctr)
+
+		if v.streamErr != nil {
+			mockExport.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).Return(v.streamErr)
+		}
+
+		var out bytes.Buffer
+
+		err := New(mockExport).Export(context.Background(), &out, v.opts)
+		if err == nil || err.Error() != v.err.Error() {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		if out.Len() != 0 {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, out.String(), "")
+		}
+
+		ctr.Finish()
+	}
+}
+
+// encode gives the ISO 2709 records of books
+func encode(t *testing.T, books ...models.Book) string {
+	var out bytes.Buffer
+
+	for _, b := range books {
+		raw, err := marc.Encode(marc.FromBook(b))
+		if err != nil {
+			t.Fatalf("could not encode %v : %v", b.BookID, err)
+		}
+
+		out.Write(raw)
+	}
+
+	return out.String()
+}
@@ -0,0 +1,88 @@
+package graphql
+
+import (
+	"context"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+type Service struct {
+	datastore datastore.GraphQL
+}
+
+func New(graphql datastore.GraphQL) Service {
+	return Service{datastore: graphql}
+}
+
+// Books method is to get every book, without author
+func (s Service) Books(ctx context.Context) ([]models.Book, error) {
+	return s.datastore.Books(ctx)
+}
+
+// BooksByID method is to get books of ids by their id, without author
+func (s Service) BooksByID(ctx context.Context, ids []int) (map[int]models.Book, error) {
+	books, err := s.datastore.BooksByID(ctx, unique(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[int]models.Book, len(books))
+	for _, b := range books {
+		out[b.BookID] = b
+	}
+
+	return out, nil
+}
+
+// BooksByAuthor method is to get books of authors by author id, an author without books has an empty list
+func (s Service) BooksByAuthor(ctx context.Context, authorIDs []int) (map[int][]models.Book, error) {
+	ids := unique(authorIDs)
+
+	books, err := s.datastore.BooksByAuthor(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[int][]models.Book, len(ids))
+	for _, id := range ids {
+		out[id] = []models.Book{}
+	}
+
+	for _, b := range books {
+		out[b.AuthorID] = append(out[b.AuthorID], b)
+	}
+
+	return out, nil
+}
+
+// Authors method is to get authors of ids by their id
+func (s Service) Authors(ctx context.Context, ids []int) (map[int]models.Author, error) {
+	authors, err := s.datastore.Authors(ctx, unique(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[int]models.Author, len(authors))
+	for _, a := range authors {
+		out[a.AuthID] = a
+	}
+
+	return out, nil
+}
+
+// unique drops repeated ids keeping the order, a loader may ask for an id once per field
+func unique(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	out := make([]int, 0, len(ids))
+
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+
+			out = append(out, id)
+		}
+	}
+
+	return out
+}
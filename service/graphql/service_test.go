@@ -0,0 +1,63 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+// TestBooksByAuthor function is to test books are grouped by author and repeated ids are read once
+func TestBooksByAuthor(t *testing.T) {
+	testcases := []struct {
+		desc  string
+		ids   []int
+		query []int
+		books []models.Book
+		resp  map[int][]models.Book
+		err   error
+	}{
+		{desc: "grouped", ids: []int{1, 2, 1, 3}, query: []int{1, 2, 3},
+			books: []models.Book{{BookID: 1, AuthorID: 1}, {BookID: 2, AuthorID: 2}, {BookID: 3, AuthorID: 1}},
+			resp: map[int][]models.Book{1: {{BookID: 1, AuthorID: 1}, {BookID: 3, AuthorID: 1}},
+				2: {{BookID: 2, AuthorID: 2}}, 3: {}}},
+		{desc: "error of datastore", ids: []int{1}, query: []int{1}, err: errors.New("connection refused")},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockGraphQL := datastore.NewMockGraphQL(ctr)
+		mockGraphQL.EXPECT().BooksByAuthor(gomock.Any(), v.query).Return(v.books, v.err)
+
+		resp, err := New(mockGraphQL).BooksByAuthor(context.Background(), v.ids)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		ctr.Finish()
+	}
+}
+
+// TestAuthors function is to test authors are keyed by id and missing ids are left out
+func TestAuthors(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockGraphQL := datastore.NewMockGraphQL(ctr)
+
+	author := models.Author{AuthID: 1, FirstName: "Chetan"}
+	mockGraphQL.EXPECT().Authors(gomock.Any(), []int{1, 9}).Return([]models.Author{author}, nil)
+
+	resp, err := New(mockGraphQL).Authors(context.Background(), []int{1, 9, 1})
+	if err != nil || !reflect.DeepEqual(resp, map[int]models.Author{1: author}) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "one missing", 1, resp, err, author)
+	}
+}
@@ -0,0 +1,40 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"Three-Layer-Architecture/datastore"
+)
+
+// ErrDraining is returned by Ready once shutdown started, load balancers stop sending new requests
+var ErrDraining = errors.New("shutting down")
+
+type Service struct {
+	health   datastore.Health
+	timeout  time.Duration
+	draining *int32
+}
+
+func New(health datastore.Health, timeout time.Duration) Service {
+	return Service{health: health, timeout: timeout, draining: new(int32)}
+}
+
+// Ready method is to check the server can serve requests, the database must answer within timeout
+func (s Service) Ready(ctx context.Context) error {
+	if atomic.LoadInt32(s.draining) == 1 {
+		return ErrDraining
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return s.health.Ping(ctx)
+}
+
+// Drain method is to report not ready from now on
+func (s Service) Drain() {
+	atomic.StoreInt32(s.draining, 1)
+}
@@ -0,0 +1,53 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/datastore"
+)
+
+// TestHealth_Ready function is to test readiness follows the database and shutdown
+func TestHealth_Ready(t *testing.T) {
+	testcases := []struct {
+		desc  string
+		drain bool
+		ping  error
+		err   error
+	}{
+		{desc: "ready"},
+		{desc: "database down", ping: errors.New("connection refused"), err: errors.New("connection refused")},
+		{desc: "draining", drain: true, err: ErrDraining},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockHealth := datastore.NewMockHealth(ctr)
+		service := New(mockHealth, time.Second)
+
+		if v.drain {
+			service.Drain()
+		} else {
+			mockHealth.EXPECT().Ping(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+				if _, ok := ctx.Deadline(); !ok {
+					t.Errorf("desc : %v ,[TEST%d]Failed. Ping without timeout\n", v.desc, i+1)
+				}
+
+				return v.ping
+			})
+		}
+
+		err := service.Ready(context.Background())
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		ctr.Finish()
+	}
+}
@@ -0,0 +1,315 @@
+package importer
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/marc"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// Batch sizes, rows of a batch are stored in one transaction
+const (
+	DefaultBatchSize = 100
+	MaxBatchSize     = 1000
+)
+
+// maxLine is the longest NDJSON row accepted
+const maxLine = 1 << 20
+
+// columns are the CSV header, the order of columns in a file does not matter and isbn may be left out
+var columns = []string{"bookID", "title", "publication", "publishedDate", "authorID", "firstName", "lastName", "dob", "penName"}
+
+type Service struct {
+	book     service.Book
+	author   service.Author
+	importer datastore.Import
+}
+
+func New(book service.Book, author service.Author, importer datastore.Import) Service {
+	return Service{book: book, author: author, importer: importer}
+}
+
+// row is one book read from the file, err is set when it could not be read or is invalid
+type row struct {
+	number int
+	book   models.Book
+	err    error
+}
+
+// Import method is to store books and their authors read from r.
+// Every row is checked like a posted book and author, rejected rows are reported and the others are still stored.
+// An error is returned when the file can not be read or a batch can not be stored, earlier batches stay stored.
+func (s Service) Import(ctx context.Context, r io.Reader, opts models.ImportOptions) (models.ImportResult, error) {
+	batchSize := opts.BatchSize
+	if batchSize == 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	if batchSize < 0 || batchSize > MaxBatchSize {
+		return models.ImportResult{}, fmt.Errorf("batch size must be between 1 and %d", MaxBatchSize)
+	}
+
+	next, err := reader(opts.Format, r)
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+
+	result := models.ImportResult{DryRun: opts.DryRun, Errors: []models.ImportError{}}
+	batch := make([]row, 0, batchSize)
+
+	for {
+		rw, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return result, err
+		}
+
+		result.Rows++
+
+		if rw.err == nil {
+			rw.err = s.validate(ctx, &rw.book)
+		}
+
+		if rw.err != nil {
+			reject(&result, rw)
+
+			continue
+		}
+
+		result.Valid++
+
+		if opts.DryRun {
+			continue
+		}
+
+		if batch = append(batch, rw); len(batch) == batchSize {
+			if err = s.commit(ctx, batch, &result); err != nil {
+				return result, err
+			}
+
+			batch = batch[:0]
+		}
+	}
+
+	if len(batch) > 0 {
+		if err = s.commit(ctx, batch, &result); err != nil {
+			return result, err
+		}
+	}
+
+	slog.InfoContext(ctx, "books imported", "rows", result.Rows, "imported", result.Imported, "failed", result.Failed,
+		"dryRun", result.DryRun)
+
+	return result, nil
+}
+
+// validate runs the checks of posting a book and its author
+func (s Service) validate(ctx context.Context, book *models.Book) error {
+	// either id is enough to link the book to its author
+	if book.AuthorID == 0 {
+		book.AuthorID = book.Auth.AuthID
+	}
+
+	if book.Auth.AuthID == 0 {
+		book.Auth.AuthID = book.AuthorID
+	}
+
+	if book.AuthorID != book.Auth.AuthID {
+		return errors.New("authorID does not match author")
+	}
+
+	if err := s.book.Validate(ctx, book); err != nil {
+		return err
+	}
+
+	return s.author.Validate(ctx, book.Auth)
+}
+
+// commit stores a batch, a row rejected by the database does not stop the others
+func (s Service) commit(ctx context.Context, batch []row, result *models.ImportResult) error {
+	books := make([]models.Book, len(batch))
+	for i := range batch {
+		books[i] = batch[i].book
+	}
+
+	rowErrs, err := s.importer.Commit(ctx, books)
+	if err != nil {
+		return fmt.Errorf("batch from row %d : %w", batch[0].number, err)
+	}
+
+	for i := range batch {
+		if rowErrs[i] != nil {
+			batch[i].err = rowErrs[i]
+			reject(result, batch[i])
+
+			continue
+		}
+
+		result.Imported++
+	}
+
+	return nil
+}
+
+func reject(result *models.ImportResult, rw row) {
+	result.Failed++
+	result.Errors = append(result.Errors, models.ImportError{Row: rw.number, BookID: rw.book.BookID, Error: rw.err.Error()})
+}
+
+// reader returns a func giving rows of r one by one and io.EOF after the last
+func reader(format string, r io.Reader) (func() (row, error), error) {
+	switch format {
+	case models.ImportCSV:
+		return csvReader(r)
+	case models.ImportNDJSON:
+		return ndjsonReader(r), nil
+	case models.ImportMARC:
+		return marcReader(marc.NewReader(r)), nil
+	case models.ImportMARCXML:
+		return marcReader(marc.NewXMLReader(r)), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+func csvReader(r io.Reader) (func() (row, error), error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("missing header")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(name)] = i
+	}
+
+	for _, name := range columns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("missing column %v", name)
+		}
+	}
+
+	// header row is not counted
+	number := 0
+
+	return func() (row, error) {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return row{}, io.EOF
+		}
+
+		number++
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return row{number: number, err: parseErr.Err}, nil
+		}
+
+		if err != nil {
+			return row{}, err
+		}
+
+		field := func(name string) string {
+			return strings.TrimSpace(record[index[name]])
+		}
+
+		book := models.Book{Title: field("title"), Publication: field("publication"), PublishedDate: field("publishedDate"),
+			Auth: models.Author{FirstName: field("firstName"), LastName: field("lastName"), Dob: field("dob"),
+				PenName: field("penName")}}
+
+		if book.BookID, err = strconv.Atoi(field("bookID")); err != nil {
+			return row{number: number, err: errors.New("invalid bookID")}, nil
+		}
+
+		if book.AuthorID, err = strconv.Atoi(field("authorID")); err != nil {
+			return row{number: number, book: book, err: errors.New("invalid authorID")}, nil
+		}
+
+		book.Auth.AuthID = book.AuthorID
+
+		if i, ok := index["isbn"]; ok {
+			book.ISBN = strings.TrimSpace(record[i])
+		}
+
+		return row{number: number, book: book}, nil
+	}, nil
+}
+
+func ndjsonReader(r io.Reader) func() (row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+
+	number := 0
+
+	return func() (row, error) {
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+
+			// blank lines are not rows
+			if line == "" {
+				continue
+			}
+
+			number++
+
+			var book models.Book
+			if err := json.Unmarshal([]byte(line), &book); err != nil {
+				return row{number: number, err: err}, nil
+			}
+
+			return row{number: number, book: book}, nil
+		}
+
+		if err := scanner.Err(); err != nil {
+			return row{}, err
+		}
+
+		return row{}, io.EOF
+	}
+}
+
+// marcReader reads ISO 2709 or MARCXML records, a record that can not be parsed stops the import
+func marcReader(rd interface{ Read() (marc.Record, error) }) func() (row, error) {
+	number := 0
+
+	return func() (row, error) {
+		rec, err := rd.Read()
+		if errors.Is(err, io.EOF) {
+			return row{}, io.EOF
+		}
+
+		number++
+
+		if err != nil {
+			return row{}, fmt.Errorf("record %d : %w", number, err)
+		}
+
+		book, err := marc.ToBook(rec)
+		if err != nil {
+			return row{number: number, err: err}, nil
+		}
+
+		return row{number: number, book: book}, nil
+	}
+}
@@ -0,0 +1,164 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/marc"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+const header = "bookID,title,publication,publishedDate,authorID,firstName,lastName,dob,penName\n"
+
+// validator checks rows like book and author services, invalid publication is the only rejected field
+func validator(ctr *gomock.Controller) (*service.MockBook, *service.MockAuthor) {
+	mockBook := service.NewMockBook(ctr)
+	mockAuthor := service.NewMockAuthor(ctr)
+
+	mockBook.EXPECT().Validate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, book *models.Book) error {
+		if book.Publication == "Lenin" {
+			return errors.New("invalid publication")
+		}
+
+		return nil
+	}).AnyTimes()
+	mockAuthor.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	return mockBook, mockAuthor
+}
+
+// TestImport function is to test rows are validated, reported and stored in batches
+func TestImport(t *testing.T) {
+	author := models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}
+	states := models.Book{BookID: 1, AuthorID: 1, Auth: author, Title: "2 States", Publication: "Scholastic",
+		PublishedDate: "16/03/2016"}
+	girl := models.Book{BookID: 3, AuthorID: 1, Auth: author, Title: "One Indian Girl", Publication: "Penguin",
+		PublishedDate: "01/10/2016", ISBN: "9788129142146"}
+
+	// isbn is an optional column
+	csvBody := strings.TrimSuffix(header, "\n") + ",isbn\n" +
+		"1,2 States,Scholastic,16/03/2016,1,Chetan,Bhagat,06/04/2001,Chetan,\n" +
+		"2,Half Girlfriend,Lenin,01/10/2014,1,Chetan,Bhagat,06/04/2001,Chetan,\n" +
+		"x,Revolution,Penguin,01/10/2011,1,Chetan,Bhagat,06/04/2001,Chetan,\n" +
+		"3,One Indian Girl,Penguin,01/10/2016,1,Chetan,Bhagat,06/04/2001,Chetan,9788129142146\n"
+	ndjsonBody := `{"bookID":1,"title":"2 States","publication":"Scholastic","publishedDate":"16/03/2016",` +
+		`"auth":{"authID":1,"firstName":"Chetan","lastName":"Bhagat","dob":"06/04/2001","penName":"Chetan"}}` + "\n\n" +
+		`{"bookID":2,` + "\n" +
+		`{"bookID":3,"authorID":1,"title":"One Indian Girl","publication":"Penguin","publishedDate":"01/10/2016",` +
+		`"isbn":"9788129142146",` +
+		`"auth":{"firstName":"Chetan","lastName":"Bhagat","dob":"06/04/2001","penName":"Chetan"}}` + "\n"
+
+	var marcBody strings.Builder
+
+	for _, b := range []models.Book{states, {BookID: 2, AuthorID: 1, Auth: author, Title: "Half Girlfriend",
+		Publication: "Lenin", PublishedDate: "01/10/2014"}, girl} {
+		raw, err := marc.Encode(marc.FromBook(b))
+		if err != nil {
+			t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "marc", 0, err, nil)
+		}
+
+		marcBody.Write(raw)
+	}
+
+	testcases := []struct {
+		desc    string
+		body    string
+		opts    models.ImportOptions
+		batches [][]models.Book
+		resp    models.ImportResult
+	}{
+		{desc: "csv", body: csvBody, opts: models.ImportOptions{Format: models.ImportCSV},
+			batches: [][]models.Book{{states, girl}},
+			resp: models.ImportResult{Rows: 4, Valid: 2, Imported: 2, Failed: 2, Errors: []models.ImportError{
+				{Row: 2, BookID: 2, Error: "invalid publication"}, {Row: 3, Error: "invalid bookID"}}}},
+		{desc: "csv without isbn", body: header + "1,2 States,Scholastic,16/03/2016,1,Chetan,Bhagat,06/04/2001,Chetan\n",
+			opts: models.ImportOptions{Format: models.ImportCSV}, batches: [][]models.Book{{states}},
+			resp: models.ImportResult{Rows: 1, Valid: 1, Imported: 1, Errors: []models.ImportError{}}},
+		{desc: "csv in batches of one", body: csvBody, opts: models.ImportOptions{Format: models.ImportCSV, BatchSize: 1},
+			batches: [][]models.Book{{states}, {girl}},
+			resp: models.ImportResult{Rows: 4, Valid: 2, Imported: 2, Failed: 2, Errors: []models.ImportError{
+				{Row: 2, BookID: 2, Error: "invalid publication"}, {Row: 3, Error: "invalid bookID"}}}},
+		{desc: "dry run", body: csvBody, opts: models.ImportOptions{Format: models.ImportCSV, DryRun: true},
+			resp: models.ImportResult{DryRun: true, Rows: 4, Valid: 2, Failed: 2, Errors: []models.ImportError{
+				{Row: 2, BookID: 2, Error: "invalid publication"}, {Row: 3, Error: "invalid bookID"}}}},
+		{desc: "ndjson", body: ndjsonBody, opts: models.ImportOptions{Format: models.ImportNDJSON},
+			batches: [][]models.Book{{states, girl}},
+			resp: models.ImportResult{Rows: 3, Valid: 2, Imported: 2, Failed: 1, Errors: []models.ImportError{
+				{Row: 2, Error: "unexpected end of JSON input"}}}},
+		{desc: "marc", body: marcBody.String(), opts: models.ImportOptions{Format: models.ImportMARC},
+			batches: [][]models.Book{{states, girl}},
+			resp: models.ImportResult{Rows: 3, Valid: 2, Imported: 2, Failed: 1, Errors: []models.ImportError{
+				{Row: 2, BookID: 2, Error: "invalid publication"}}}},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockBook, mockAuthor := validator(ctr)
+		mockImport := datastore.NewMockImport(ctr)
+
+		for _, batch := range v.batches {
+			mockImport.EXPECT().Commit(gomock.Any(), batch).Return(make([]error, len(batch)), nil)
+		}
+
+		svc := New(mockBook, mockAuthor, mockImport)
+
+		resp, err := svc.Import(context.Background(), strings.NewReader(v.body), v.opts)
+		if err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, nil)
+		}
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		ctr.Finish()
+	}
+}
+
+// TestImport_Errors function is to test imports stopped by the file or the database
+func TestImport_Errors(t *testing.T) {
+	row := "1,2 States,Scholastic,16/03/2016,1,Chetan,Bhagat,06/04/2001,Chetan\n"
+
+	testcases := []struct {
+		desc      string
+		body      string
+		opts      models.ImportOptions
+		commitErr error
+		err       error
+	}{
+		{desc: "unsupported format", opts: models.ImportOptions{Format: "xml"}, err: errors.New(`unsupported format "xml"`)},
+		{desc: "batch too large", opts: models.ImportOptions{Format: models.ImportCSV, BatchSize: 1001},
+			err: errors.New("batch size must be between 1 and 1000")},
+		{desc: "empty csv", opts: models.ImportOptions{Format: models.ImportCSV}, err: errors.New("missing header")},
+		{desc: "missing column", body: "bookID,title\n", opts: models.ImportOptions{Format: models.ImportCSV},
+			err: errors.New("missing column publication")},
+		{desc: "commit error", body: header + row, opts: models.ImportOptions{Format: models.ImportCSV},
+			commitErr: errors.New("connection refused"), err: errors.New("batch from row 1 : connection refused")},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockBook, mockAuthor := validator(ctr)
+		mockImport := datastore.NewMockImport(ctr)
+
+		if v.commitErr != nil {
+			mockImport.EXPECT().Commit(gomock.Any(), gomock.Any()).Return(nil, v.commitErr)
+		}
+
+		svc := New(mockBook, mockAuthor, mockImport)
+
+		_, err := svc.Import(context.Background(), strings.NewReader(v.body), v.opts)
+		if err == nil || err.Error() != v.err.Error() {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		ctr.Finish()
+	}
+}
@@ -1,19 +1,174 @@
 package service
 
 import (
+	"context"
+	"io"
+	"time"
+
 	"Three-Layer-Architecture/models"
 )
 
 type Book interface {
-	Post(book *models.Book) (models.Book, error)
-	GetAll() ([]models.Book, error)
-	Getbyid(id string) (models.Book, error)
-	Update(id string, book *models.Book) (models.Book, error)
-	Delete(id string) (int, error)
+	Post(ctx context.Context, book *models.Book) (models.Book, error)
+	GetAll(ctx context.Context) ([]models.Book, error)
+	Getbyid(ctx context.Context, id string) (models.Book, error)
+	Update(ctx context.Context, id string, book *models.Book) (models.Book, error)
+	Delete(ctx context.Context, id string) (int, error)
+	Restore(ctx context.Context, id string) (int, error)
+	GetRevisions(ctx context.Context, id string) ([]models.Revision, error)
+	DiffRevisions(ctx context.Context, id, from, to string) (models.RevisionDiff, error)
+	GetAsOf(ctx context.Context, id string, at time.Time) (models.Book, error)
+	Revert(ctx context.Context, id, rev string) (models.Book, error)
+	Validate(ctx context.Context, book *models.Book) error
 }
 
 type Author interface {
-	Post(models.Author) (models.Author, error)
-	Update(id string, author models.Author) (models.Author, error)
-	Delete(id string) (int, error)
+	Post(ctx context.Context, author models.Author) (models.Author, error)
+	Getbyid(ctx context.Context, id string) (models.Author, error)
+	Update(ctx context.Context, id string, author models.Author) (models.Author, error)
+	Delete(ctx context.Context, id string) (int, error)
+	Restore(ctx context.Context, id string) (int, error)
+	GetRevisions(ctx context.Context, id string) ([]models.Revision, error)
+	Validate(ctx context.Context, author models.Author) error
+}
+
+type Import interface {
+	Import(ctx context.Context, r io.Reader, opts models.ImportOptions) (models.ImportResult, error)
+}
+
+type Export interface {
+	Export(ctx context.Context, w io.Writer, opts models.ExportOptions) error
+}
+
+type OAI interface {
+	Identify(ctx context.Context) (models.OAIRepository, error)
+	MetadataFormats(ctx context.Context, identifier string) ([]models.OAIMetadataFormat, error)
+	Sets(ctx context.Context) error
+	GetRecord(ctx context.Context, identifier, prefix string) (models.OAIRecord, error)
+	List(ctx context.Context, list models.OAIList) (models.OAIPage, error)
+	Identifier(bookID int) string
+}
+
+type SRU interface {
+	Search(ctx context.Context, query string, start, maximum int) (models.SRUResult, error)
+}
+
+// GraphQL loads books and authors in batches keyed by id, ids which are not found are missing from the maps
+type GraphQL interface {
+	Books(ctx context.Context) ([]models.Book, error)
+	BooksByID(ctx context.Context, ids []int) (map[int]models.Book, error)
+	BooksByAuthor(ctx context.Context, authorIDs []int) (map[int][]models.Book, error)
+	Authors(ctx context.Context, ids []int) (map[int]models.Author, error)
+}
+
+type Loan interface {
+	Policy() models.LoanPolicy
+	Checkout(ctx context.Context, bookID, userID int) (models.Loan, error)
+	Checkin(ctx context.Context, bookID int) (models.Loan, error)
+	Renew(ctx context.Context, bookID, userID int) (models.Loan, error)
+	Current(ctx context.Context, bookID int) (models.Loan, error)
+	Loans(ctx context.Context, userID int) ([]models.Loan, error)
+}
+
+// APIKey issues keys for services calling the api, a key is only returned when it is created
+type APIKey interface {
+	CreateAPIKey(ctx context.Context, name, principal string) (string, models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) (int, error)
+}
+
+// Consistency reports rows which break rules of the schema or of the services
+type Consistency interface {
+	Check(ctx context.Context) ([]models.Inconsistency, error)
+}
+
+type Trash interface {
+	GetAll(ctx context.Context) (models.Trash, error)
+	Purge(ctx context.Context) (int, error)
+}
+
+type Audit interface {
+	Get(ctx context.Context, filter models.AuditFilter) ([]models.Audit, error)
+}
+
+type Revision interface {
+	GetAll(ctx context.Context, entity string, entityID int) ([]models.Revision, error)
+	Get(ctx context.Context, entity string, entityID, rev int) (models.Revision, error)
+	AsOf(ctx context.Context, entity string, entityID int, at time.Time) (models.Revision, error)
+	Diff(ctx context.Context, entity string, entityID, from, to int) (models.RevisionDiff, error)
+}
+
+type Auth interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (models.Principal, error)
+	AuthenticateToken(ctx context.Context, token string) (models.Principal, error)
+	AuthenticateSession(ctx context.Context, token string) (models.Principal, error)
+}
+
+type RBAC interface {
+	Authorize(ctx context.Context, permission string) error
+	GetRoles(ctx context.Context) ([]models.Role, error)
+	GetAssignments(ctx context.Context, principal string) ([]string, error)
+	Assign(ctx context.Context, principal, role string) error
+	Unassign(ctx context.Context, principal, role string) error
+}
+
+type User interface {
+	Register(ctx context.Context, username, email, password string) (models.User, error)
+	Login(ctx context.Context, username, password string) (string, models.Session, error)
+	Verify(ctx context.Context, username, password string) (models.User, error)
+	GetByUsername(ctx context.Context, username string) (models.User, error)
+	Logout(ctx context.Context, token string) error
+	RevokeSessions(ctx context.Context, userID int) (int, error)
+	RequestPasswordReset(ctx context.Context, username string) error
+	ResetPassword(ctx context.Context, token, password string) error
+	Current(ctx context.Context) (models.User, error)
+}
+
+type Session interface {
+	AuthenticateSession(ctx context.Context, token string) (models.Principal, error)
+}
+
+// Publisher queues an event of the outbox for webhooks, the event keeps its id so receivers can tell it when it is
+// sent again.
+type Publisher interface {
+	PublishEvent(ctx context.Context, event models.WebhookEvent) error
+}
+
+// Sink receives events of the outbox in id order, except an event committed after a later one which comes late.
+// An event may be sent again after an error or a restart.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, event models.OutboxEvent) error
+}
+
+// Broker hands events after an id which match to a subscriber, see sink.Bus
+type Broker interface {
+	Subscribe(after int64, buffer int, match func(event string) bool) (<-chan models.OutboxEvent, func(), bool)
+}
+
+// Events streams changes to clients, lastID resumes after an event the client has seen
+type Events interface {
+	Subscribe(ctx context.Context, topics []string, lastID string) (models.Subscription, error)
+}
+
+type Webhook interface {
+	Create(ctx context.Context, webhook models.Webhook) (models.Webhook, error)
+	GetAll(ctx context.Context) ([]models.Webhook, error)
+	Get(ctx context.Context, id string) (models.Webhook, error)
+	Update(ctx context.Context, id string, webhook models.Webhook) (models.Webhook, error)
+	Delete(ctx context.Context, id string) (int, error)
+	Deliveries(ctx context.Context, id string) ([]models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, id, delivery string) error
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, to, subject, body string) error
+}
+
+// Limiter keeps token buckets, implementations may share them between replicas
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit models.RateLimit) (models.RateLimitResult, error)
+}
+
+type Health interface {
+	Ready(ctx context.Context) error
 }
@@ -0,0 +1,171 @@
+package loan
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+var (
+	// ErrOnLoan is returned when a book to check out is already out
+	ErrOnLoan = errors.New("book is on loan")
+	// ErrNotOnLoan is returned when a book to check in or renew is on the shelf
+	ErrNotOnLoan = errors.New("book is not on loan")
+	// ErrNotBorrower is returned when a user renews a book someone else has
+	ErrNotBorrower = errors.New("book is on loan to another user")
+	// ErrRenewalLimit is returned after the policy's renewals
+	ErrRenewalLimit = errors.New("renewal limit reached")
+	// ErrLoanLimit is returned when the user has as many books as the policy allows
+	ErrLoanLimit = errors.New("loan limit reached")
+	// ErrOverdue is returned when the user has overdue books
+	ErrOverdue = errors.New("user has overdue loans")
+)
+
+type Service struct {
+	loan   datastore.Loan
+	book   service.Book
+	policy models.LoanPolicy
+	now    func() time.Time
+}
+
+func New(loan datastore.Loan, book service.Book, policy models.LoanPolicy) Service {
+	return Service{loan: loan, book: book, policy: policy, now: time.Now}
+}
+
+// Policy method is to get the limits loans are checked against
+func (s Service) Policy() models.LoanPolicy {
+	return s.policy
+}
+
+// Checkout method is to lend a book to a user, it is due after the loan period
+func (s Service) Checkout(ctx context.Context, bookID, userID int) (models.Loan, error) {
+	if _, err := s.book.Getbyid(ctx, strconv.Itoa(bookID)); err != nil {
+		return models.Loan{}, err
+	}
+
+	if _, err := s.Current(ctx, bookID); !errors.Is(err, ErrNotOnLoan) {
+		if err == nil {
+			return models.Loan{}, ErrOnLoan
+		}
+
+		return models.Loan{}, err
+	}
+
+	loans, err := s.loan.GetByUser(ctx, userID)
+	if err != nil {
+		return models.Loan{}, err
+	}
+
+	if len(loans) >= s.policy.MaxLoans {
+		return models.Loan{}, ErrLoanLimit
+	}
+
+	now := s.now().UTC()
+
+	for _, l := range loans {
+		if l.DueAt.Before(now) {
+			return models.Loan{}, ErrOverdue
+		}
+	}
+
+	loan, err := s.loan.Post(ctx, models.Loan{BookID: bookID, UserID: userID, CheckedOutAt: now,
+		DueAt: now.Add(s.policy.Period)}, s.policy.MaxLoans)
+	// another checkout of the book or by the user got in since they were looked up
+	if errors.Is(err, datastore.ErrDuplicate) {
+		return models.Loan{}, ErrOnLoan
+	}
+
+	if errors.Is(err, datastore.ErrLimit) {
+		return models.Loan{}, ErrLoanLimit
+	}
+
+	if err != nil {
+		return models.Loan{}, err
+	}
+
+	slog.InfoContext(ctx, "book checked out", "book", bookID, "user", userID, "due", loan.DueAt)
+
+	return loan, nil
+}
+
+// Checkin method is to end the loan of a book, whoever has it
+func (s Service) Checkin(ctx context.Context, bookID int) (models.Loan, error) {
+	loan, err := s.Current(ctx, bookID)
+	if err != nil {
+		return models.Loan{}, err
+	}
+
+	now := s.now().UTC()
+	loan.ReturnedAt = &now
+
+	// another checkin of the book got in since it was looked up
+	err = s.loan.Return(ctx, loan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Loan{}, ErrNotOnLoan
+	}
+
+	if err != nil {
+		return models.Loan{}, err
+	}
+
+	slog.InfoContext(ctx, "book checked in", "book", bookID, "user", loan.UserID)
+
+	return loan, nil
+}
+
+// Renew method is to give the user another loan period from now
+func (s Service) Renew(ctx context.Context, bookID, userID int) (models.Loan, error) {
+	loan, err := s.Current(ctx, bookID)
+	if err != nil {
+		return models.Loan{}, err
+	}
+
+	if loan.UserID != userID {
+		return models.Loan{}, ErrNotBorrower
+	}
+
+	if loan.Renewals >= s.policy.MaxRenewals {
+		return models.Loan{}, ErrRenewalLimit
+	}
+
+	loan.DueAt = s.now().UTC().Add(s.policy.Period)
+	loan.Renewals++
+
+	// the book was checked in or renewed since it was looked up
+	err = s.loan.Renew(ctx, loan, s.policy.MaxRenewals)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Loan{}, ErrNotOnLoan
+	}
+
+	if errors.Is(err, datastore.ErrLimit) {
+		return models.Loan{}, ErrRenewalLimit
+	}
+
+	if err != nil {
+		return models.Loan{}, err
+	}
+
+	return loan, nil
+}
+
+// Current method is to get the active loan of a book, ErrNotOnLoan when it is on the shelf
+func (s Service) Current(ctx context.Context, bookID int) (models.Loan, error) {
+	loan, err := s.loan.GetActive(ctx, bookID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Loan{}, ErrNotOnLoan
+	}
+
+	return loan, err
+}
+
+// Loans method is to get the books a user has, soonest due first
+func (s Service) Loans(ctx context.Context, userID int) ([]models.Loan, error) {
+	return s.loan.GetByUser(ctx, userID)
+}
@@ -0,0 +1,159 @@
+package loan
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+var policy = models.LoanPolicy{Period: 21 * 24 * time.Hour, MaxRenewals: 2, MaxLoans: 2}
+
+var now = time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+func setup(t *testing.T) (*datastore.MockLoan, *service.MockBook, Service) {
+	ctr := gomock.NewController(t)
+	mockLoan := datastore.NewMockLoan(ctr)
+	mockBook := service.NewMockBook(ctr)
+	svc := New(mockLoan, mockBook, policy)
+	svc.now = func() time.Time { return now }
+
+	return mockLoan, mockBook, svc
+}
+
+// TestCheckout function is to test books are lent within the policy only
+func TestCheckout(t *testing.T) {
+	due := now.Add(policy.Period)
+	missing := errors.New("book not found")
+
+	testcases := []struct {
+		desc   string
+		book   error
+		active error
+		loans  []models.Loan
+		post   error
+		resp   models.Loan
+		err    error
+	}{
+		{desc: "lent", active: sql.ErrNoRows, loans: []models.Loan{{ID: 3, DueAt: due}},
+			resp: models.Loan{ID: 9, BookID: 1, UserID: 2, CheckedOutAt: now, DueAt: due}},
+		{desc: "lent meanwhile", active: sql.ErrNoRows, loans: []models.Loan{{ID: 3, DueAt: due}},
+			post: datastore.ErrDuplicate, err: ErrOnLoan},
+		{desc: "loan limit reached meanwhile", active: sql.ErrNoRows, loans: []models.Loan{{ID: 3, DueAt: due}},
+			post: datastore.ErrLimit, err: ErrLoanLimit},
+		{desc: "unknown book", book: missing, err: missing},
+		{desc: "on loan", err: ErrOnLoan},
+		{desc: "loan limit", active: sql.ErrNoRows, loans: []models.Loan{{ID: 3, DueAt: due}, {ID: 4, DueAt: due}},
+			err: ErrLoanLimit},
+		{desc: "overdue", active: sql.ErrNoRows, loans: []models.Loan{{ID: 3, DueAt: now.Add(-time.Hour)}}, err: ErrOverdue},
+	}
+
+	for i, v := range testcases {
+		mockLoan, mockBook, svc := setup(t)
+
+		mockBook.EXPECT().Getbyid(gomock.Any(), "1").Return(models.Book{BookID: 1}, v.book)
+
+		if v.book == nil {
+			mockLoan.EXPECT().GetActive(gomock.Any(), 1).Return(models.Loan{ID: 5}, v.active)
+		}
+
+		if v.active != nil {
+			mockLoan.EXPECT().GetByUser(gomock.Any(), 2).Return(v.loans, nil)
+		}
+
+		if v.err == nil || v.post != nil {
+			mockLoan.EXPECT().Post(gomock.Any(), models.Loan{BookID: 1, UserID: 2, CheckedOutAt: now, DueAt: due}, 2).
+				DoAndReturn(func(_ context.Context, l models.Loan, _ int) (models.Loan, error) {
+					if v.post != nil {
+						return models.Loan{}, v.post
+					}
+
+					l.ID = 9
+
+					return l, nil
+				})
+		}
+
+		resp, err := svc.Checkout(context.Background(), 1, 2)
+
+		if !reflect.DeepEqual(resp, v.resp) || !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", v.desc, i+1, resp, err, v.resp, v.err)
+		}
+	}
+}
+
+// TestCheckin function is to test the active loan of a book is ended
+func TestCheckin(t *testing.T) {
+	mockLoan, _, svc := setup(t)
+	returned := now
+
+	mockLoan.EXPECT().GetActive(gomock.Any(), 1).Return(models.Loan{ID: 5, BookID: 1, UserID: 2}, nil)
+	mockLoan.EXPECT().Return(gomock.Any(), models.Loan{ID: 5, BookID: 1, UserID: 2, ReturnedAt: &returned}).Return(nil)
+	mockLoan.EXPECT().GetActive(gomock.Any(), 3).Return(models.Loan{}, sql.ErrNoRows)
+	// another checkin ended the loan after it was looked up
+	mockLoan.EXPECT().GetActive(gomock.Any(), 4).Return(models.Loan{ID: 6, BookID: 4, UserID: 2}, nil)
+	mockLoan.EXPECT().Return(gomock.Any(), models.Loan{ID: 6, BookID: 4, UserID: 2, ReturnedAt: &returned}).
+		Return(sql.ErrNoRows)
+
+	resp, err := svc.Checkin(context.Background(), 1)
+	if err != nil || resp.ReturnedAt == nil || !resp.ReturnedAt.Equal(now) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "returned", 1, resp, err, now)
+	}
+
+	if _, err = svc.Checkin(context.Background(), 3); !errors.Is(err, ErrNotOnLoan) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "on shelf", 2, err, ErrNotOnLoan)
+	}
+
+	if _, err = svc.Checkin(context.Background(), 4); !errors.Is(err, ErrNotOnLoan) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "checked in meanwhile", 3, err, ErrNotOnLoan)
+	}
+}
+
+// TestRenew function is to test only the borrower renews, up to the policy's renewals
+func TestRenew(t *testing.T) {
+	due := now.Add(policy.Period)
+
+	testcases := []struct {
+		desc   string
+		loan   models.Loan
+		active error
+		renew  error
+		resp   models.Loan
+		err    error
+	}{
+		{desc: "renewed", loan: models.Loan{ID: 5, BookID: 1, UserID: 2, Renewals: 1, DueAt: now},
+			resp: models.Loan{ID: 5, BookID: 1, UserID: 2, Renewals: 2, DueAt: due}},
+		{desc: "checked in meanwhile", loan: models.Loan{ID: 5, BookID: 1, UserID: 2, Renewals: 1, DueAt: now},
+			renew: sql.ErrNoRows, err: ErrNotOnLoan},
+		{desc: "renewed meanwhile", loan: models.Loan{ID: 5, BookID: 1, UserID: 2, Renewals: 1, DueAt: now},
+			renew: datastore.ErrLimit, err: ErrRenewalLimit},
+		{desc: "on shelf", active: sql.ErrNoRows, err: ErrNotOnLoan},
+		{desc: "another borrower", loan: models.Loan{ID: 5, BookID: 1, UserID: 4}, err: ErrNotBorrower},
+		{desc: "renewal limit", loan: models.Loan{ID: 5, BookID: 1, UserID: 2, Renewals: 2}, err: ErrRenewalLimit},
+	}
+
+	for i, v := range testcases {
+		mockLoan, _, svc := setup(t)
+
+		mockLoan.EXPECT().GetActive(gomock.Any(), 1).Return(v.loan, v.active)
+
+		if v.err == nil || v.renew != nil {
+			mockLoan.EXPECT().Renew(gomock.Any(), models.Loan{ID: 5, BookID: 1, UserID: 2, Renewals: 2, DueAt: due}, 2).
+				Return(v.renew)
+		}
+
+		resp, err := svc.Renew(context.Background(), 1, 2)
+
+		if !reflect.DeepEqual(resp, v.resp) || !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", v.desc, i+1, resp, err, v.resp, v.err)
+		}
+	}
+}
@@ -6,172 +6,1770 @@ package service
 
 import (
 	models "Three-Layer-Architecture/models"
-	gomock "github.com/golang/mock/gomock"
+	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
 )
 
-// MockBook is a mock of Book interface
+// MockBook is a mock of Book interface.
 type MockBook struct {
 	ctrl     *gomock.Controller
 	recorder *MockBookMockRecorder
 }
 
-// MockBookMockRecorder is the mock recorder for MockBook
+// MockBookMockRecorder is the mock recorder for MockBook.
 type MockBookMockRecorder struct {
 	mock *MockBook
 }
 
-// NewMockBook creates a new mock instance
+// NewMockBook creates a new mock instance.
 func NewMockBook(ctrl *gomock.Controller) *MockBook {
 	mock := &MockBook{ctrl: ctrl}
 	mock.recorder = &MockBookMockRecorder{mock}
 	return mock
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
+// EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockBook) EXPECT() *MockBookMockRecorder {
 	return m.recorder
 }
 
-// Post mocks base method
-func (m *MockBook) Post(book *models.Book) (models.Book, error) {
+// Delete mocks base method.
+func (m *MockBook) Delete(ctx context.Context, id string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Post", book)
-	ret0, _ := ret[0].(models.Book)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBookMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBook)(nil).Delete), ctx, id)
+}
+
+// DiffRevisions mocks base method.
+func (m *MockBook) DiffRevisions(ctx context.Context, id, from, to string) (models.RevisionDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffRevisions", ctx, id, from, to)
+	ret0, _ := ret[0].(models.RevisionDiff)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Post indicates an expected call of Post
-func (mr *MockBookMockRecorder) Post(book interface{}) *gomock.Call {
+// DiffRevisions indicates an expected call of DiffRevisions.
+func (mr *MockBookMockRecorder) DiffRevisions(ctx, id, from, to interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockBook)(nil).Post), book)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffRevisions", reflect.TypeOf((*MockBook)(nil).DiffRevisions), ctx, id, from, to)
 }
 
-// GetAll mocks base method
-func (m *MockBook) GetAll() ([]models.Book, error) {
+// GetAll mocks base method.
+func (m *MockBook) GetAll(ctx context.Context) ([]models.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", ctx)
 	ret0, _ := ret[0].([]models.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAll indicates an expected call of GetAll
-func (mr *MockBookMockRecorder) GetAll() *gomock.Call {
+// GetAll indicates an expected call of GetAll.
+func (mr *MockBookMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBook)(nil).GetAll), ctx)
+}
+
+// GetAsOf mocks base method.
+func (m *MockBook) GetAsOf(ctx context.Context, id string, at time.Time) (models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAsOf", ctx, id, at)
+	ret0, _ := ret[0].(models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAsOf indicates an expected call of GetAsOf.
+func (mr *MockBookMockRecorder) GetAsOf(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAsOf", reflect.TypeOf((*MockBook)(nil).GetAsOf), ctx, id, at)
+}
+
+// GetRevisions mocks base method.
+func (m *MockBook) GetRevisions(ctx context.Context, id string) ([]models.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", ctx, id)
+	ret0, _ := ret[0].([]models.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockBookMockRecorder) GetRevisions(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBook)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockBook)(nil).GetRevisions), ctx, id)
 }
 
-// Getbyid mocks base method
-func (m *MockBook) Getbyid(id string) (models.Book, error) {
+// Getbyid mocks base method.
+func (m *MockBook) Getbyid(ctx context.Context, id string) (models.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Getbyid", id)
+	ret := m.ctrl.Call(m, "Getbyid", ctx, id)
 	ret0, _ := ret[0].(models.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Getbyid indicates an expected call of Getbyid
-func (mr *MockBookMockRecorder) Getbyid(id interface{}) *gomock.Call {
+// Getbyid indicates an expected call of Getbyid.
+func (mr *MockBookMockRecorder) Getbyid(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Getbyid", reflect.TypeOf((*MockBook)(nil).Getbyid), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Getbyid", reflect.TypeOf((*MockBook)(nil).Getbyid), ctx, id)
 }
 
-// Update mocks base method
-func (m *MockBook) Update(id string, book *models.Book) (models.Book, error) {
+// Post mocks base method.
+func (m *MockBook) Post(ctx context.Context, book *models.Book) (models.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", id, book)
+	ret := m.ctrl.Call(m, "Post", ctx, book)
 	ret0, _ := ret[0].(models.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Update indicates an expected call of Update
-func (mr *MockBookMockRecorder) Update(id, book interface{}) *gomock.Call {
+// Post indicates an expected call of Post.
+func (mr *MockBookMockRecorder) Post(ctx, book interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBook)(nil).Update), id, book)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockBook)(nil).Post), ctx, book)
 }
 
-// Delete mocks base method
-func (m *MockBook) Delete(id string) (int, error) {
+// Restore mocks base method.
+func (m *MockBook) Restore(ctx context.Context, id string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Delete indicates an expected call of Delete
-func (mr *MockBookMockRecorder) Delete(id interface{}) *gomock.Call {
+// Restore indicates an expected call of Restore.
+func (mr *MockBookMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockBook)(nil).Restore), ctx, id)
+}
+
+// Revert mocks base method.
+func (m *MockBook) Revert(ctx context.Context, id, rev string) (models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revert", ctx, id, rev)
+	ret0, _ := ret[0].(models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revert indicates an expected call of Revert.
+func (mr *MockBookMockRecorder) Revert(ctx, id, rev interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockBook)(nil).Revert), ctx, id, rev)
+}
+
+// Update mocks base method.
+func (m *MockBook) Update(ctx context.Context, id string, book *models.Book) (models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, book)
+	ret0, _ := ret[0].(models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBookMockRecorder) Update(ctx, id, book interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBook)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBook)(nil).Update), ctx, id, book)
 }
 
-// MockAuthor is a mock of Author interface
+// Validate mocks base method.
+func (m *MockBook) Validate(ctx context.Context, book *models.Book) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", ctx, book)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockBookMockRecorder) Validate(ctx, book interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockBook)(nil).Validate), ctx, book)
+}
+
+// MockAuthor is a mock of Author interface.
 type MockAuthor struct {
 	ctrl     *gomock.Controller
 	recorder *MockAuthorMockRecorder
 }
 
-// MockAuthorMockRecorder is the mock recorder for MockAuthor
+// MockAuthorMockRecorder is the mock recorder for MockAuthor.
 type MockAuthorMockRecorder struct {
 	mock *MockAuthor
 }
 
-// NewMockAuthor creates a new mock instance
+// NewMockAuthor creates a new mock instance.
 func NewMockAuthor(ctrl *gomock.Controller) *MockAuthor {
 	mock := &MockAuthor{ctrl: ctrl}
 	mock.recorder = &MockAuthorMockRecorder{mock}
 	return mock
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
+// EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockAuthor) EXPECT() *MockAuthorMockRecorder {
 	return m.recorder
 }
 
-// Post mocks base method
-func (m *MockAuthor) Post(arg0 models.Author) (models.Author, error) {
+// Delete mocks base method.
+func (m *MockAuthor) Delete(ctx context.Context, id string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAuthorMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuthor)(nil).Delete), ctx, id)
+}
+
+// GetRevisions mocks base method.
+func (m *MockAuthor) GetRevisions(ctx context.Context, id string) ([]models.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", ctx, id)
+	ret0, _ := ret[0].([]models.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockAuthorMockRecorder) GetRevisions(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockAuthor)(nil).GetRevisions), ctx, id)
+}
+
+// Getbyid mocks base method.
+func (m *MockAuthor) Getbyid(ctx context.Context, id string) (models.Author, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Post", arg0)
+	ret := m.ctrl.Call(m, "Getbyid", ctx, id)
 	ret0, _ := ret[0].(models.Author)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Post indicates an expected call of Post
-func (mr *MockAuthorMockRecorder) Post(arg0 interface{}) *gomock.Call {
+// Getbyid indicates an expected call of Getbyid.
+func (mr *MockAuthorMockRecorder) Getbyid(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockAuthor)(nil).Post), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Getbyid", reflect.TypeOf((*MockAuthor)(nil).Getbyid), ctx, id)
 }
 
-// Update mocks base method
-func (m *MockAuthor) Update(id string, author models.Author) (models.Author, error) {
+// Post mocks base method.
+func (m *MockAuthor) Post(ctx context.Context, author models.Author) (models.Author, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", id, author)
+	ret := m.ctrl.Call(m, "Post", ctx, author)
 	ret0, _ := ret[0].(models.Author)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Update indicates an expected call of Update
-func (mr *MockAuthorMockRecorder) Update(id, author interface{}) *gomock.Call {
+// Post indicates an expected call of Post.
+func (mr *MockAuthorMockRecorder) Post(ctx, author interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuthor)(nil).Update), id, author)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockAuthor)(nil).Post), ctx, author)
 }
 
-// Delete mocks base method
-func (m *MockAuthor) Delete(id string) (int, error) {
+// Restore mocks base method.
+func (m *MockAuthor) Restore(ctx context.Context, id string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Delete indicates an expected call of Delete
-func (mr *MockAuthorMockRecorder) Delete(id interface{}) *gomock.Call {
+// Restore indicates an expected call of Restore.
+func (mr *MockAuthorMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockAuthor)(nil).Restore), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockAuthor) Update(ctx context.Context, id string, author models.Author) (models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, author)
+	ret0, _ := ret[0].(models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAuthorMockRecorder) Update(ctx, id, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuthor)(nil).Update), ctx, id, author)
+}
+
+// Validate mocks base method.
+func (m *MockAuthor) Validate(ctx context.Context, author models.Author) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", ctx, author)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockAuthorMockRecorder) Validate(ctx, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockAuthor)(nil).Validate), ctx, author)
+}
+
+// MockImport is a mock of Import interface.
+type MockImport struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportMockRecorder
+}
+
+// MockImportMockRecorder is the mock recorder for MockImport.
+type MockImportMockRecorder struct {
+	mock *MockImport
+}
+
+// NewMockImport creates a new mock instance.
+func NewMockImport(ctrl *gomock.Controller) *MockImport {
+	mock := &MockImport{ctrl: ctrl}
+	mock.recorder = &MockImportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImport) EXPECT() *MockImportMockRecorder {
+	return m.recorder
+}
+
+// Import mocks base method.
+func (m *MockImport) Import(ctx context.Context, r io.Reader, opts models.ImportOptions) (models.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, r, opts)
+	ret0, _ := ret[0].(models.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockImportMockRecorder) Import(ctx, r, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockImport)(nil).Import), ctx, r, opts)
+}
+
+// MockExport is a mock of Export interface.
+type MockExport struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportMockRecorder
+}
+
+// MockExportMockRecorder is the mock recorder for MockExport.
+type MockExportMockRecorder struct {
+	mock *MockExport
+}
+
+// NewMockExport creates a new mock instance.
+func NewMockExport(ctrl *gomock.Controller) *MockExport {
+	mock := &MockExport{ctrl: ctrl}
+	mock.recorder = &MockExportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExport) EXPECT() *MockExportMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockExport) Export(ctx context.Context, w io.Writer, opts models.ExportOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, w, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockExportMockRecorder) Export(ctx, w, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockExport)(nil).Export), ctx, w, opts)
+}
+
+// MockOAI is a mock of OAI interface.
+type MockOAI struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAIMockRecorder
+}
+
+// MockOAIMockRecorder is the mock recorder for MockOAI.
+type MockOAIMockRecorder struct {
+	mock *MockOAI
+}
+
+// NewMockOAI creates a new mock instance.
+func NewMockOAI(ctrl *gomock.Controller) *MockOAI {
+	mock := &MockOAI{ctrl: ctrl}
+	mock.recorder = &MockOAIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAI) EXPECT() *MockOAIMockRecorder {
+	return m.recorder
+}
+
+// GetRecord mocks base method.
+func (m *MockOAI) GetRecord(ctx context.Context, identifier, prefix string) (models.OAIRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecord", ctx, identifier, prefix)
+	ret0, _ := ret[0].(models.OAIRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecord indicates an expected call of GetRecord.
+func (mr *MockOAIMockRecorder) GetRecord(ctx, identifier, prefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecord", reflect.TypeOf((*MockOAI)(nil).GetRecord), ctx, identifier, prefix)
+}
+
+// Identifier mocks base method.
+func (m *MockOAI) Identifier(bookID int) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Identifier", bookID)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Identifier indicates an expected call of Identifier.
+func (mr *MockOAIMockRecorder) Identifier(bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Identifier", reflect.TypeOf((*MockOAI)(nil).Identifier), bookID)
+}
+
+// Identify mocks base method.
+func (m *MockOAI) Identify(ctx context.Context) (models.OAIRepository, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Identify", ctx)
+	ret0, _ := ret[0].(models.OAIRepository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Identify indicates an expected call of Identify.
+func (mr *MockOAIMockRecorder) Identify(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Identify", reflect.TypeOf((*MockOAI)(nil).Identify), ctx)
+}
+
+// List mocks base method.
+func (m *MockOAI) List(ctx context.Context, list models.OAIList) (models.OAIPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, list)
+	ret0, _ := ret[0].(models.OAIPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockOAIMockRecorder) List(ctx, list interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOAI)(nil).List), ctx, list)
+}
+
+// MetadataFormats mocks base method.
+func (m *MockOAI) MetadataFormats(ctx context.Context, identifier string) ([]models.OAIMetadataFormat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MetadataFormats", ctx, identifier)
+	ret0, _ := ret[0].([]models.OAIMetadataFormat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MetadataFormats indicates an expected call of MetadataFormats.
+func (mr *MockOAIMockRecorder) MetadataFormats(ctx, identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MetadataFormats", reflect.TypeOf((*MockOAI)(nil).MetadataFormats), ctx, identifier)
+}
+
+// Sets mocks base method.
+func (m *MockOAI) Sets(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sets", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Sets indicates an expected call of Sets.
+func (mr *MockOAIMockRecorder) Sets(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sets", reflect.TypeOf((*MockOAI)(nil).Sets), ctx)
+}
+
+// MockSRU is a mock of SRU interface.
+type MockSRU struct {
+	ctrl     *gomock.Controller
+	recorder *MockSRUMockRecorder
+}
+
+// MockSRUMockRecorder is the mock recorder for MockSRU.
+type MockSRUMockRecorder struct {
+	mock *MockSRU
+}
+
+// NewMockSRU creates a new mock instance.
+func NewMockSRU(ctrl *gomock.Controller) *MockSRU {
+	mock := &MockSRU{ctrl: ctrl}
+	mock.recorder = &MockSRUMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSRU) EXPECT() *MockSRUMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *MockSRU) Search(ctx context.Context, query string, start, maximum int) (models.SRUResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, start, maximum)
+	ret0, _ := ret[0].(models.SRUResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSRUMockRecorder) Search(ctx, query, start, maximum interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSRU)(nil).Search), ctx, query, start, maximum)
+}
+
+// MockGraphQL is a mock of GraphQL interface.
+type MockGraphQL struct {
+	ctrl     *gomock.Controller
+	recorder *MockGraphQLMockRecorder
+}
+
+// MockGraphQLMockRecorder is the mock recorder for MockGraphQL.
+type MockGraphQLMockRecorder struct {
+	mock *MockGraphQL
+}
+
+// NewMockGraphQL creates a new mock instance.
+func NewMockGraphQL(ctrl *gomock.Controller) *MockGraphQL {
+	mock := &MockGraphQL{ctrl: ctrl}
+	mock.recorder = &MockGraphQLMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGraphQL) EXPECT() *MockGraphQLMockRecorder {
+	return m.recorder
+}
+
+// Authors mocks base method.
+func (m *MockGraphQL) Authors(ctx context.Context, ids []int) (map[int]models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authors", ctx, ids)
+	ret0, _ := ret[0].(map[int]models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authors indicates an expected call of Authors.
+func (mr *MockGraphQLMockRecorder) Authors(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authors", reflect.TypeOf((*MockGraphQL)(nil).Authors), ctx, ids)
+}
+
+// Books mocks base method.
+func (m *MockGraphQL) Books(ctx context.Context) ([]models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Books", ctx)
+	ret0, _ := ret[0].([]models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Books indicates an expected call of Books.
+func (mr *MockGraphQLMockRecorder) Books(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Books", reflect.TypeOf((*MockGraphQL)(nil).Books), ctx)
+}
+
+// BooksByAuthor mocks base method.
+func (m *MockGraphQL) BooksByAuthor(ctx context.Context, authorIDs []int) (map[int][]models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BooksByAuthor", ctx, authorIDs)
+	ret0, _ := ret[0].(map[int][]models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BooksByAuthor indicates an expected call of BooksByAuthor.
+func (mr *MockGraphQLMockRecorder) BooksByAuthor(ctx, authorIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BooksByAuthor", reflect.TypeOf((*MockGraphQL)(nil).BooksByAuthor), ctx, authorIDs)
+}
+
+// BooksByID mocks base method.
+func (m *MockGraphQL) BooksByID(ctx context.Context, ids []int) (map[int]models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BooksByID", ctx, ids)
+	ret0, _ := ret[0].(map[int]models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BooksByID indicates an expected call of BooksByID.
+func (mr *MockGraphQLMockRecorder) BooksByID(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BooksByID", reflect.TypeOf((*MockGraphQL)(nil).BooksByID), ctx, ids)
+}
+
+// MockLoan is a mock of Loan interface.
+type MockLoan struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoanMockRecorder
+}
+
+// MockLoanMockRecorder is the mock recorder for MockLoan.
+type MockLoanMockRecorder struct {
+	mock *MockLoan
+}
+
+// NewMockLoan creates a new mock instance.
+func NewMockLoan(ctrl *gomock.Controller) *MockLoan {
+	mock := &MockLoan{ctrl: ctrl}
+	mock.recorder = &MockLoanMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoan) EXPECT() *MockLoanMockRecorder {
+	return m.recorder
+}
+
+// Checkin mocks base method.
+func (m *MockLoan) Checkin(ctx context.Context, bookID int) (models.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkin", ctx, bookID)
+	ret0, _ := ret[0].(models.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Checkin indicates an expected call of Checkin.
+func (mr *MockLoanMockRecorder) Checkin(ctx, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkin", reflect.TypeOf((*MockLoan)(nil).Checkin), ctx, bookID)
+}
+
+// Checkout mocks base method.
+func (m *MockLoan) Checkout(ctx context.Context, bookID, userID int) (models.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkout", ctx, bookID, userID)
+	ret0, _ := ret[0].(models.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Checkout indicates an expected call of Checkout.
+func (mr *MockLoanMockRecorder) Checkout(ctx, bookID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkout", reflect.TypeOf((*MockLoan)(nil).Checkout), ctx, bookID, userID)
+}
+
+// Current mocks base method.
+func (m *MockLoan) Current(ctx context.Context, bookID int) (models.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Current", ctx, bookID)
+	ret0, _ := ret[0].(models.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Current indicates an expected call of Current.
+func (mr *MockLoanMockRecorder) Current(ctx, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Current", reflect.TypeOf((*MockLoan)(nil).Current), ctx, bookID)
+}
+
+// Loans mocks base method.
+func (m *MockLoan) Loans(ctx context.Context, userID int) ([]models.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Loans", ctx, userID)
+	ret0, _ := ret[0].([]models.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Loans indicates an expected call of Loans.
+func (mr *MockLoanMockRecorder) Loans(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Loans", reflect.TypeOf((*MockLoan)(nil).Loans), ctx, userID)
+}
+
+// Policy mocks base method.
+func (m *MockLoan) Policy() models.LoanPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Policy")
+	ret0, _ := ret[0].(models.LoanPolicy)
+	return ret0
+}
+
+// Policy indicates an expected call of Policy.
+func (mr *MockLoanMockRecorder) Policy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Policy", reflect.TypeOf((*MockLoan)(nil).Policy))
+}
+
+// Renew mocks base method.
+func (m *MockLoan) Renew(ctx context.Context, bookID, userID int) (models.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", ctx, bookID, userID)
+	ret0, _ := ret[0].(models.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MockLoanMockRecorder) Renew(ctx, bookID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockLoan)(nil).Renew), ctx, bookID, userID)
+}
+
+// MockAPIKey is a mock of APIKey interface.
+type MockAPIKey struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyMockRecorder
+}
+
+// MockAPIKeyMockRecorder is the mock recorder for MockAPIKey.
+type MockAPIKeyMockRecorder struct {
+	mock *MockAPIKey
+}
+
+// NewMockAPIKey creates a new mock instance.
+func NewMockAPIKey(ctrl *gomock.Controller) *MockAPIKey {
+	mock := &MockAPIKey{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKey) EXPECT() *MockAPIKeyMockRecorder {
+	return m.recorder
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockAPIKey) CreateAPIKey(ctx context.Context, name, principal string) (string, models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, name, principal)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(models.APIKey)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockAPIKeyMockRecorder) CreateAPIKey(ctx, name, principal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockAPIKey)(nil).CreateAPIKey), ctx, name, principal)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockAPIKey) RevokeAPIKey(ctx context.Context, id string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockAPIKeyMockRecorder) RevokeAPIKey(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockAPIKey)(nil).RevokeAPIKey), ctx, id)
+}
+
+// MockConsistency is a mock of Consistency interface.
+type MockConsistency struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsistencyMockRecorder
+}
+
+// MockConsistencyMockRecorder is the mock recorder for MockConsistency.
+type MockConsistencyMockRecorder struct {
+	mock *MockConsistency
+}
+
+// NewMockConsistency creates a new mock instance.
+func NewMockConsistency(ctrl *gomock.Controller) *MockConsistency {
+	mock := &MockConsistency{ctrl: ctrl}
+	mock.recorder = &MockConsistencyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsistency) EXPECT() *MockConsistencyMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockConsistency) Check(ctx context.Context) ([]models.Inconsistency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx)
+	ret0, _ := ret[0].([]models.Inconsistency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockConsistencyMockRecorder) Check(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockConsistency)(nil).Check), ctx)
+}
+
+// MockTrash is a mock of Trash interface.
+type MockTrash struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashMockRecorder
+}
+
+// MockTrashMockRecorder is the mock recorder for MockTrash.
+type MockTrashMockRecorder struct {
+	mock *MockTrash
+}
+
+// NewMockTrash creates a new mock instance.
+func NewMockTrash(ctrl *gomock.Controller) *MockTrash {
+	mock := &MockTrash{ctrl: ctrl}
+	mock.recorder = &MockTrashMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrash) EXPECT() *MockTrashMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockTrash) GetAll(ctx context.Context) (models.Trash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].(models.Trash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTrashMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTrash)(nil).GetAll), ctx)
+}
+
+// Purge mocks base method.
+func (m *MockTrash) Purge(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTrashMockRecorder) Purge(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTrash)(nil).Purge), ctx)
+}
+
+// MockAudit is a mock of Audit interface.
+type MockAudit struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditMockRecorder
+}
+
+// MockAuditMockRecorder is the mock recorder for MockAudit.
+type MockAuditMockRecorder struct {
+	mock *MockAudit
+}
+
+// NewMockAudit creates a new mock instance.
+func NewMockAudit(ctrl *gomock.Controller) *MockAudit {
+	mock := &MockAudit{ctrl: ctrl}
+	mock.recorder = &MockAuditMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAudit) EXPECT() *MockAuditMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockAudit) Get(ctx context.Context, filter models.AuditFilter) ([]models.Audit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, filter)
+	ret0, _ := ret[0].([]models.Audit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAuditMockRecorder) Get(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAudit)(nil).Get), ctx, filter)
+}
+
+// MockRevision is a mock of Revision interface.
+type MockRevision struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevisionMockRecorder
+}
+
+// MockRevisionMockRecorder is the mock recorder for MockRevision.
+type MockRevisionMockRecorder struct {
+	mock *MockRevision
+}
+
+// NewMockRevision creates a new mock instance.
+func NewMockRevision(ctrl *gomock.Controller) *MockRevision {
+	mock := &MockRevision{ctrl: ctrl}
+	mock.recorder = &MockRevisionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevision) EXPECT() *MockRevisionMockRecorder {
+	return m.recorder
+}
+
+// AsOf mocks base method.
+func (m *MockRevision) AsOf(ctx context.Context, entity string, entityID int, at time.Time) (models.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AsOf", ctx, entity, entityID, at)
+	ret0, _ := ret[0].(models.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AsOf indicates an expected call of AsOf.
+func (mr *MockRevisionMockRecorder) AsOf(ctx, entity, entityID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AsOf", reflect.TypeOf((*MockRevision)(nil).AsOf), ctx, entity, entityID, at)
+}
+
+// Diff mocks base method.
+func (m *MockRevision) Diff(ctx context.Context, entity string, entityID, from, to int) (models.RevisionDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Diff", ctx, entity, entityID, from, to)
+	ret0, _ := ret[0].(models.RevisionDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Diff indicates an expected call of Diff.
+func (mr *MockRevisionMockRecorder) Diff(ctx, entity, entityID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diff", reflect.TypeOf((*MockRevision)(nil).Diff), ctx, entity, entityID, from, to)
+}
+
+// Get mocks base method.
+func (m *MockRevision) Get(ctx context.Context, entity string, entityID, rev int) (models.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, entity, entityID, rev)
+	ret0, _ := ret[0].(models.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRevisionMockRecorder) Get(ctx, entity, entityID, rev interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRevision)(nil).Get), ctx, entity, entityID, rev)
+}
+
+// GetAll mocks base method.
+func (m *MockRevision) GetAll(ctx context.Context, entity string, entityID int) ([]models.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, entity, entityID)
+	ret0, _ := ret[0].([]models.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockRevisionMockRecorder) GetAll(ctx, entity, entityID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRevision)(nil).GetAll), ctx, entity, entityID)
+}
+
+// MockAuth is a mock of Auth interface.
+type MockAuth struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthMockRecorder
+}
+
+// MockAuthMockRecorder is the mock recorder for MockAuth.
+type MockAuthMockRecorder struct {
+	mock *MockAuth
+}
+
+// NewMockAuth creates a new mock instance.
+func NewMockAuth(ctrl *gomock.Controller) *MockAuth {
+	mock := &MockAuth{ctrl: ctrl}
+	mock.recorder = &MockAuthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuth) EXPECT() *MockAuthMockRecorder {
+	return m.recorder
+}
+
+// AuthenticateAPIKey mocks base method.
+func (m *MockAuth) AuthenticateAPIKey(ctx context.Context, key string) (models.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateAPIKey", ctx, key)
+	ret0, _ := ret[0].(models.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateAPIKey indicates an expected call of AuthenticateAPIKey.
+func (mr *MockAuthMockRecorder) AuthenticateAPIKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateAPIKey", reflect.TypeOf((*MockAuth)(nil).AuthenticateAPIKey), ctx, key)
+}
+
+// AuthenticateSession mocks base method.
+func (m *MockAuth) AuthenticateSession(ctx context.Context, token string) (models.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateSession", ctx, token)
+	ret0, _ := ret[0].(models.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateSession indicates an expected call of AuthenticateSession.
+func (mr *MockAuthMockRecorder) AuthenticateSession(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateSession", reflect.TypeOf((*MockAuth)(nil).AuthenticateSession), ctx, token)
+}
+
+// AuthenticateToken mocks base method.
+func (m *MockAuth) AuthenticateToken(ctx context.Context, token string) (models.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateToken", ctx, token)
+	ret0, _ := ret[0].(models.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateToken indicates an expected call of AuthenticateToken.
+func (mr *MockAuthMockRecorder) AuthenticateToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateToken", reflect.TypeOf((*MockAuth)(nil).AuthenticateToken), ctx, token)
+}
+
+// MockRBAC is a mock of RBAC interface.
+type MockRBAC struct {
+	ctrl     *gomock.Controller
+	recorder *MockRBACMockRecorder
+}
+
+// MockRBACMockRecorder is the mock recorder for MockRBAC.
+type MockRBACMockRecorder struct {
+	mock *MockRBAC
+}
+
+// NewMockRBAC creates a new mock instance.
+func NewMockRBAC(ctrl *gomock.Controller) *MockRBAC {
+	mock := &MockRBAC{ctrl: ctrl}
+	mock.recorder = &MockRBACMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRBAC) EXPECT() *MockRBACMockRecorder {
+	return m.recorder
+}
+
+// Assign mocks base method.
+func (m *MockRBAC) Assign(ctx context.Context, principal, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Assign", ctx, principal, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Assign indicates an expected call of Assign.
+func (mr *MockRBACMockRecorder) Assign(ctx, principal, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Assign", reflect.TypeOf((*MockRBAC)(nil).Assign), ctx, principal, role)
+}
+
+// Authorize mocks base method.
+func (m *MockRBAC) Authorize(ctx context.Context, permission string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, permission)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockRBACMockRecorder) Authorize(ctx, permission interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockRBAC)(nil).Authorize), ctx, permission)
+}
+
+// GetAssignments mocks base method.
+func (m *MockRBAC) GetAssignments(ctx context.Context, principal string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignments", ctx, principal)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignments indicates an expected call of GetAssignments.
+func (mr *MockRBACMockRecorder) GetAssignments(ctx, principal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignments", reflect.TypeOf((*MockRBAC)(nil).GetAssignments), ctx, principal)
+}
+
+// GetRoles mocks base method.
+func (m *MockRBAC) GetRoles(ctx context.Context) ([]models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoles", ctx)
+	ret0, _ := ret[0].([]models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoles indicates an expected call of GetRoles.
+func (mr *MockRBACMockRecorder) GetRoles(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoles", reflect.TypeOf((*MockRBAC)(nil).GetRoles), ctx)
+}
+
+// Unassign mocks base method.
+func (m *MockRBAC) Unassign(ctx context.Context, principal, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unassign", ctx, principal, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unassign indicates an expected call of Unassign.
+func (mr *MockRBACMockRecorder) Unassign(ctx, principal, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unassign", reflect.TypeOf((*MockRBAC)(nil).Unassign), ctx, principal, role)
+}
+
+// MockUser is a mock of User interface.
+type MockUser struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserMockRecorder
+}
+
+// MockUserMockRecorder is the mock recorder for MockUser.
+type MockUserMockRecorder struct {
+	mock *MockUser
+}
+
+// NewMockUser creates a new mock instance.
+func NewMockUser(ctrl *gomock.Controller) *MockUser {
+	mock := &MockUser{ctrl: ctrl}
+	mock.recorder = &MockUserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUser) EXPECT() *MockUserMockRecorder {
+	return m.recorder
+}
+
+// Current mocks base method.
+func (m *MockUser) Current(ctx context.Context) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Current", ctx)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Current indicates an expected call of Current.
+func (mr *MockUserMockRecorder) Current(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Current", reflect.TypeOf((*MockUser)(nil).Current), ctx)
+}
+
+// GetByUsername mocks base method.
+func (m *MockUser) GetByUsername(ctx context.Context, username string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUsername", ctx, username)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUsername indicates an expected call of GetByUsername.
+func (mr *MockUserMockRecorder) GetByUsername(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUsername", reflect.TypeOf((*MockUser)(nil).GetByUsername), ctx, username)
+}
+
+// Login mocks base method.
+func (m *MockUser) Login(ctx context.Context, username, password string) (string, models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, username, password)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(models.Session)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockUserMockRecorder) Login(ctx, username, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUser)(nil).Login), ctx, username, password)
+}
+
+// Logout mocks base method.
+func (m *MockUser) Logout(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUserMockRecorder) Logout(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUser)(nil).Logout), ctx, token)
+}
+
+// Register mocks base method.
+func (m *MockUser) Register(ctx context.Context, username, email, password string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", ctx, username, email, password)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockUserMockRecorder) Register(ctx, username, email, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUser)(nil).Register), ctx, username, email, password)
+}
+
+// RequestPasswordReset mocks base method.
+func (m *MockUser) RequestPasswordReset(ctx context.Context, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestPasswordReset", ctx, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestPasswordReset indicates an expected call of RequestPasswordReset.
+func (mr *MockUserMockRecorder) RequestPasswordReset(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPasswordReset", reflect.TypeOf((*MockUser)(nil).RequestPasswordReset), ctx, username)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUser) ResetPassword(ctx context.Context, token, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, token, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserMockRecorder) ResetPassword(ctx, token, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUser)(nil).ResetPassword), ctx, token, password)
+}
+
+// RevokeSessions mocks base method.
+func (m *MockUser) RevokeSessions(ctx context.Context, userID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessions", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSessions indicates an expected call of RevokeSessions.
+func (mr *MockUserMockRecorder) RevokeSessions(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessions", reflect.TypeOf((*MockUser)(nil).RevokeSessions), ctx, userID)
+}
+
+// Verify mocks base method.
+func (m *MockUser) Verify(ctx context.Context, username, password string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, username, password)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockUserMockRecorder) Verify(ctx, username, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockUser)(nil).Verify), ctx, username, password)
+}
+
+// MockSession is a mock of Session interface.
+type MockSession struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionMockRecorder
+}
+
+// MockSessionMockRecorder is the mock recorder for MockSession.
+type MockSessionMockRecorder struct {
+	mock *MockSession
+}
+
+// NewMockSession creates a new mock instance.
+func NewMockSession(ctrl *gomock.Controller) *MockSession {
+	mock := &MockSession{ctrl: ctrl}
+	mock.recorder = &MockSessionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSession) EXPECT() *MockSessionMockRecorder {
+	return m.recorder
+}
+
+// AuthenticateSession mocks base method.
+func (m *MockSession) AuthenticateSession(ctx context.Context, token string) (models.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateSession", ctx, token)
+	ret0, _ := ret[0].(models.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateSession indicates an expected call of AuthenticateSession.
+func (mr *MockSessionMockRecorder) AuthenticateSession(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateSession", reflect.TypeOf((*MockSession)(nil).AuthenticateSession), ctx, token)
+}
+
+// MockPublisher is a mock of Publisher interface.
+type MockPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublisherMockRecorder
+}
+
+// MockPublisherMockRecorder is the mock recorder for MockPublisher.
+type MockPublisherMockRecorder struct {
+	mock *MockPublisher
+}
+
+// NewMockPublisher creates a new mock instance.
+func NewMockPublisher(ctrl *gomock.Controller) *MockPublisher {
+	mock := &MockPublisher{ctrl: ctrl}
+	mock.recorder = &MockPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublisher) EXPECT() *MockPublisherMockRecorder {
+	return m.recorder
+}
+
+// PublishEvent mocks base method.
+func (m *MockPublisher) PublishEvent(ctx context.Context, event models.WebhookEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishEvent indicates an expected call of PublishEvent.
+func (mr *MockPublisherMockRecorder) PublishEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishEvent", reflect.TypeOf((*MockPublisher)(nil).PublishEvent), ctx, event)
+}
+
+// MockSink is a mock of Sink interface.
+type MockSink struct {
+	ctrl     *gomock.Controller
+	recorder *MockSinkMockRecorder
+}
+
+// MockSinkMockRecorder is the mock recorder for MockSink.
+type MockSinkMockRecorder struct {
+	mock *MockSink
+}
+
+// NewMockSink creates a new mock instance.
+func NewMockSink(ctrl *gomock.Controller) *MockSink {
+	mock := &MockSink{ctrl: ctrl}
+	mock.recorder = &MockSinkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSink) EXPECT() *MockSinkMockRecorder {
+	return m.recorder
+}
+
+// Name mocks base method.
+func (m *MockSink) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockSinkMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockSink)(nil).Name))
+}
+
+// Send mocks base method.
+func (m *MockSink) Send(ctx context.Context, event models.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockSinkMockRecorder) Send(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockSink)(nil).Send), ctx, event)
+}
+
+// MockBroker is a mock of Broker interface.
+type MockBroker struct {
+	ctrl     *gomock.Controller
+	recorder *MockBrokerMockRecorder
+}
+
+// MockBrokerMockRecorder is the mock recorder for MockBroker.
+type MockBrokerMockRecorder struct {
+	mock *MockBroker
+}
+
+// NewMockBroker creates a new mock instance.
+func NewMockBroker(ctrl *gomock.Controller) *MockBroker {
+	mock := &MockBroker{ctrl: ctrl}
+	mock.recorder = &MockBrokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBroker) EXPECT() *MockBrokerMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockBroker) Subscribe(after int64, buffer int, match func(string) bool) (<-chan models.OutboxEvent, func(), bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", after, buffer, match)
+	ret0, _ := ret[0].(<-chan models.OutboxEvent)
+	ret1, _ := ret[1].(func())
+	ret2, _ := ret[2].(bool)
+	return ret0, ret1, ret2
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockBrokerMockRecorder) Subscribe(after, buffer, match interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockBroker)(nil).Subscribe), after, buffer, match)
+}
+
+// MockEvents is a mock of Events interface.
+type MockEvents struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventsMockRecorder
+}
+
+// MockEventsMockRecorder is the mock recorder for MockEvents.
+type MockEventsMockRecorder struct {
+	mock *MockEvents
+}
+
+// NewMockEvents creates a new mock instance.
+func NewMockEvents(ctrl *gomock.Controller) *MockEvents {
+	mock := &MockEvents{ctrl: ctrl}
+	mock.recorder = &MockEventsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEvents) EXPECT() *MockEventsMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockEvents) Subscribe(ctx context.Context, topics []string, lastID string) (models.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, topics, lastID)
+	ret0, _ := ret[0].(models.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockEventsMockRecorder) Subscribe(ctx, topics, lastID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockEvents)(nil).Subscribe), ctx, topics, lastID)
+}
+
+// MockWebhook is a mock of Webhook interface.
+type MockWebhook struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookMockRecorder
+}
+
+// MockWebhookMockRecorder is the mock recorder for MockWebhook.
+type MockWebhookMockRecorder struct {
+	mock *MockWebhook
+}
+
+// NewMockWebhook creates a new mock instance.
+func NewMockWebhook(ctrl *gomock.Controller) *MockWebhook {
+	mock := &MockWebhook{ctrl: ctrl}
+	mock.recorder = &MockWebhookMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhook) EXPECT() *MockWebhookMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhook) Create(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, webhook)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookMockRecorder) Create(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhook)(nil).Create), ctx, webhook)
+}
+
+// Delete mocks base method.
+func (m *MockWebhook) Delete(ctx context.Context, id string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhook)(nil).Delete), ctx, id)
+}
+
+// Deliveries mocks base method.
+func (m *MockWebhook) Deliveries(ctx context.Context, id string) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deliveries", ctx, id)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Deliveries indicates an expected call of Deliveries.
+func (mr *MockWebhookMockRecorder) Deliveries(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deliveries", reflect.TypeOf((*MockWebhook)(nil).Deliveries), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockWebhook) Get(ctx context.Context, id string) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWebhookMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWebhook)(nil).Get), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockWebhook) GetAll(ctx context.Context) ([]models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockWebhookMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWebhook)(nil).GetAll), ctx)
+}
+
+// Redeliver mocks base method.
+func (m *MockWebhook) Redeliver(ctx context.Context, id, delivery string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, id, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockWebhookMockRecorder) Redeliver(ctx, id, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockWebhook)(nil).Redeliver), ctx, id, delivery)
+}
+
+// Update mocks base method.
+func (m *MockWebhook) Update(ctx context.Context, id string, webhook models.Webhook) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, webhook)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWebhookMockRecorder) Update(ctx, id, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhook)(nil).Update), ctx, id, webhook)
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifier) Notify(ctx context.Context, to, subject, body string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, to, subject, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotifierMockRecorder) Notify(ctx, to, subject, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), ctx, to, subject, body)
+}
+
+// MockLimiter is a mock of Limiter interface.
+type MockLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockLimiterMockRecorder
+}
+
+// MockLimiterMockRecorder is the mock recorder for MockLimiter.
+type MockLimiterMockRecorder struct {
+	mock *MockLimiter
+}
+
+// NewMockLimiter creates a new mock instance.
+func NewMockLimiter(ctrl *gomock.Controller) *MockLimiter {
+	mock := &MockLimiter{ctrl: ctrl}
+	mock.recorder = &MockLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLimiter) EXPECT() *MockLimiterMockRecorder {
+	return m.recorder
+}
+
+// Allow mocks base method.
+func (m *MockLimiter) Allow(ctx context.Context, key string, limit models.RateLimit) (models.RateLimitResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allow", ctx, key, limit)
+	ret0, _ := ret[0].(models.RateLimitResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Allow indicates an expected call of Allow.
+func (mr *MockLimiterMockRecorder) Allow(ctx, key, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allow", reflect.TypeOf((*MockLimiter)(nil).Allow), ctx, key, limit)
+}
+
+// MockHealth is a mock of Health interface.
+type MockHealth struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthMockRecorder
+}
+
+// MockHealthMockRecorder is the mock recorder for MockHealth.
+type MockHealthMockRecorder struct {
+	mock *MockHealth
+}
+
+// NewMockHealth creates a new mock instance.
+func NewMockHealth(ctrl *gomock.Controller) *MockHealth {
+	mock := &MockHealth{ctrl: ctrl}
+	mock.recorder = &MockHealthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealth) EXPECT() *MockHealthMockRecorder {
+	return m.recorder
+}
+
+// Ready mocks base method.
+func (m *MockHealth) Ready(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ready indicates an expected call of Ready.
+func (mr *MockHealthMockRecorder) Ready(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuthor)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockHealth)(nil).Ready), ctx)
 }
@@ -0,0 +1,252 @@
+package oai
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"Three-Layer-Architecture/citation"
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+// Granularities of datestamps in requests, responses use seconds
+const (
+	dayGranularity     = "2006-01-02"
+	secondsGranularity = "2006-01-02T15:04:05Z"
+)
+
+// PrefixDC is the only metadata format, simple Dublin Core every repository has to support
+const PrefixDC = "oai_dc"
+
+var dc = models.OAIMetadataFormat{Prefix: PrefixDC, Schema: "http://www.openarchives.org/OAI/2.0/oai_dc.xsd",
+	Namespace: citation.NamespaceOAIDC}
+
+// Config describes the repository to harvesters
+type Config struct {
+	Name       string
+	BaseURL    string
+	AdminEmail string
+	// RepositoryID is the domain in record identifiers like oai:library.example.org:book/1
+	RepositoryID string
+	PageSize     int
+}
+
+type Service struct {
+	datastore datastore.OAI
+	config    Config
+}
+
+func New(oai datastore.OAI, config Config) Service {
+	return Service{datastore: oai, config: config}
+}
+
+// token is the state of a list between pages, it is kept by the harvester
+type token struct {
+	After  int       `json:"a"`
+	From   time.Time `json:"f,omitempty"`
+	Before time.Time `json:"b,omitempty"`
+	Cursor int       `json:"c"`
+}
+
+// Identify method is to describe the repository
+func (s Service) Identify(ctx context.Context) (models.OAIRepository, error) {
+	earliest, err := s.datastore.Earliest(ctx)
+	if err != nil {
+		return models.OAIRepository{}, err
+	}
+
+	return models.OAIRepository{Name: s.config.Name, BaseURL: s.config.BaseURL, AdminEmail: s.config.AdminEmail,
+		EarliestDatestamp: earliest}, nil
+}
+
+// MetadataFormats method is to list formats of the repository, or of one record when identifier is given
+func (s Service) MetadataFormats(ctx context.Context, identifier string) ([]models.OAIMetadataFormat, error) {
+	if identifier != "" {
+		if _, err := s.get(ctx, identifier); err != nil {
+			return nil, err
+		}
+	}
+
+	return []models.OAIMetadataFormat{dc}, nil
+}
+
+// Sets method is to list sets, books are not grouped in sets
+func (s Service) Sets(ctx context.Context) error {
+	return models.OAIError{Code: models.OAINoSetHierarchy, Message: "sets are not supported"}
+}
+
+// GetRecord method is to get one record in a metadata format
+func (s Service) GetRecord(ctx context.Context, identifier, prefix string) (models.OAIRecord, error) {
+	if prefix != PrefixDC {
+		return models.OAIRecord{}, cannotDisseminate(prefix)
+	}
+
+	return s.get(ctx, identifier)
+}
+
+// List method is to get a page of records, selected by datestamp, for ListIdentifiers and ListRecords
+func (s Service) List(ctx context.Context, list models.OAIList) (models.OAIPage, error) {
+	var (
+		state token
+		err   error
+	)
+
+	if list.Token != "" {
+		state, err = decodeToken(list.Token)
+	} else {
+		state, err = s.first(list)
+	}
+
+	if err != nil {
+		return models.OAIPage{}, err
+	}
+
+	limit := s.config.PageSize
+
+	// one more record tells if there is a next page
+	records, err := s.datastore.List(ctx, models.OAIFilter{From: state.From, Before: state.Before, After: state.After,
+		Limit: limit + 1})
+	if err != nil {
+		return models.OAIPage{}, err
+	}
+
+	page := models.OAIPage{Records: records, Cursor: state.Cursor, Resumed: list.Token != ""}
+
+	if len(records) == 0 && !page.Resumed {
+		return models.OAIPage{}, models.OAIError{Code: models.OAINoRecordsMatch, Message: "no books match the arguments"}
+	}
+
+	if len(records) > limit {
+		page.Records = records[:limit]
+
+		next := state
+		next.After = page.Records[limit-1].Book.BookID
+		next.Cursor += limit
+
+		if page.Token, err = encodeToken(next); err != nil {
+			return models.OAIPage{}, err
+		}
+	}
+
+	return page, nil
+}
+
+// Identifier gives the OAI identifier of a book
+func (s Service) Identifier(bookID int) string {
+	return "oai:" + s.config.RepositoryID + ":book/" + strconv.Itoa(bookID)
+}
+
+func (s Service) first(list models.OAIList) (token, error) {
+	if list.MetadataPrefix == "" {
+		return token{}, models.OAIError{Code: models.OAIBadArgument, Message: "metadataPrefix is required"}
+	}
+
+	if list.MetadataPrefix != PrefixDC {
+		return token{}, cannotDisseminate(list.MetadataPrefix)
+	}
+
+	if list.Set != "" {
+		return token{}, models.OAIError{Code: models.OAINoSetHierarchy, Message: "sets are not supported"}
+	}
+
+	var (
+		state             token
+		fromDay, untilDay bool
+		err               error
+	)
+
+	if list.From != "" {
+		if state.From, fromDay, err = parseDatestamp(list.From); err != nil {
+			return token{}, err
+		}
+	}
+
+	if list.Until != "" {
+		var until time.Time
+		if until, untilDay, err = parseDatestamp(list.Until); err != nil {
+			return token{}, err
+		}
+
+		// until is inclusive, at day granularity it covers the whole day
+		state.Before = until.Add(time.Second)
+		if untilDay {
+			state.Before = until.AddDate(0, 0, 1)
+		}
+	}
+
+	if list.From != "" && list.Until != "" {
+		if fromDay != untilDay {
+			return token{}, models.OAIError{Code: models.OAIBadArgument, Message: "from and until have different granularity"}
+		}
+
+		if !state.From.Before(state.Before) {
+			return token{}, models.OAIError{Code: models.OAIBadArgument, Message: "from is later than until"}
+		}
+	}
+
+	return state, nil
+}
+
+func (s Service) get(ctx context.Context, identifier string) (models.OAIRecord, error) {
+	notFound := models.OAIError{Code: models.OAIIDDoesNotExist, Message: "unknown identifier " + identifier}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(identifier, "oai:"+s.config.RepositoryID+":book/"))
+	if err != nil || !strings.HasPrefix(identifier, "oai:") {
+		return models.OAIRecord{}, notFound
+	}
+
+	rec, err := s.datastore.Get(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.OAIRecord{}, notFound
+	}
+
+	return rec, err
+}
+
+func cannotDisseminate(prefix string) error {
+	return models.OAIError{Code: models.OAICannotDisseminateFormat, Message: "unsupported metadataPrefix " + prefix}
+}
+
+// parseDatestamp accepts day and seconds granularity, day tells which one was used
+func parseDatestamp(s string) (time.Time, bool, error) {
+	if t, err := time.Parse(dayGranularity, s); err == nil {
+		return t, true, nil
+	}
+
+	if t, err := time.Parse(secondsGranularity, s); err == nil {
+		return t, false, nil
+	}
+
+	return time.Time{}, false, models.OAIError{Code: models.OAIBadArgument, Message: "invalid datestamp " + s}
+}
+
+func encodeToken(t token) (string, error) {
+	body, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(body), nil
+}
+
+func decodeToken(s string) (token, error) {
+	bad := models.OAIError{Code: models.OAIBadResumptionToken, Message: "invalid resumptionToken"}
+
+	body, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return token{}, bad
+	}
+
+	var t token
+	if err = json.Unmarshal(body, &t); err != nil || t.After <= 0 || t.Cursor <= 0 {
+		return token{}, bad
+	}
+
+	return t, nil
+}
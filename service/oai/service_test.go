@@ -0,0 +1,136 @@
+package oai
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+var config = Config{Name: "Library", BaseURL: "http://localhost:8000/oai", AdminEmail: "admin@localhost",
+	RepositoryID: "library.example.org", PageSize: 2}
+
+func records(ids ...int) []models.OAIRecord {
+	out := make([]models.OAIRecord, len(ids))
+	for i, id := range ids {
+		out[i] = models.OAIRecord{Book: models.Book{BookID: id}}
+	}
+
+	return out
+}
+
+// TestList function is to test paging with resumption tokens
+func TestList(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockOAI := datastore.NewMockOAI(ctr)
+	svc := New(mockOAI, config)
+	ctx := context.Background()
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	mockOAI.EXPECT().List(gomock.Any(), models.OAIFilter{From: from, Before: before, Limit: 3}).Return(records(1, 2, 3), nil)
+
+	page, err := svc.List(ctx, models.OAIList{MetadataPrefix: PrefixDC, From: "2024-01-01", Until: "2024-01-31"})
+	if err != nil || !reflect.DeepEqual(page.Records, records(1, 2)) || page.Token == "" || page.Resumed {
+		t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "first page", 1, page, err, records(1, 2))
+	}
+
+	// filters are carried by the token
+	mockOAI.EXPECT().List(gomock.Any(), models.OAIFilter{From: from, Before: before, After: 2, Limit: 3}).
+		Return(records(3), nil)
+
+	page, err = svc.List(ctx, models.OAIList{Token: page.Token})
+	if err != nil || !reflect.DeepEqual(page.Records, records(3)) || page.Token != "" || !page.Resumed || page.Cursor != 2 {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "last page", 2, page, err, records(3))
+	}
+}
+
+// TestList_Errors function is to test bad arguments are reported with their OAI error code
+func TestList_Errors(t *testing.T) {
+	testcases := []struct {
+		desc  string
+		list  models.OAIList
+		empty bool
+		code  string
+	}{
+		{desc: "missing prefix", list: models.OAIList{}, code: models.OAIBadArgument},
+		{desc: "unknown prefix", list: models.OAIList{MetadataPrefix: "marc21"}, code: models.OAICannotDisseminateFormat},
+		{desc: "set", list: models.OAIList{MetadataPrefix: PrefixDC, Set: "fiction"}, code: models.OAINoSetHierarchy},
+		{desc: "bad datestamp", list: models.OAIList{MetadataPrefix: PrefixDC, From: "01/01/2024"}, code: models.OAIBadArgument},
+		{desc: "mixed granularity", list: models.OAIList{MetadataPrefix: PrefixDC, From: "2024-01-01",
+			Until: "2024-02-01T00:00:00Z"}, code: models.OAIBadArgument},
+		{desc: "from after until", list: models.OAIList{MetadataPrefix: PrefixDC, From: "2024-02-01", Until: "2024-01-01"},
+			code: models.OAIBadArgument},
+		{desc: "bad token", list: models.OAIList{Token: "not a token"}, code: models.OAIBadResumptionToken},
+		{desc: "no records", list: models.OAIList{MetadataPrefix: PrefixDC}, empty: true, code: models.OAINoRecordsMatch},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockOAI := datastore.NewMockOAI(ctr)
+
+		if v.empty {
+			mockOAI.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil)
+		}
+
+		_, err := New(mockOAI, config).List(context.Background(), v.list)
+
+		var oaiErr models.OAIError
+		if !errors.As(err, &oaiErr) || oaiErr.Code != v.code {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.code)
+		}
+
+		ctr.Finish()
+	}
+}
+
+// TestGetRecord function is to test identifiers are resolved to books
+func TestGetRecord(t *testing.T) {
+	testcases := []struct {
+		desc       string
+		identifier string
+		prefix     string
+		id         int
+		err        error
+		code       string
+	}{
+		{desc: "found", identifier: "oai:library.example.org:book/1", prefix: PrefixDC, id: 1},
+		{desc: "missing book", identifier: "oai:library.example.org:book/9", prefix: PrefixDC, id: 9, err: sql.ErrNoRows,
+			code: models.OAIIDDoesNotExist},
+		{desc: "other repository", identifier: "oai:other.org:book/1", prefix: PrefixDC, code: models.OAIIDDoesNotExist},
+		{desc: "unknown prefix", identifier: "oai:library.example.org:book/1", prefix: "marc21",
+			code: models.OAICannotDisseminateFormat},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockOAI := datastore.NewMockOAI(ctr)
+
+		if v.id != 0 {
+			mockOAI.EXPECT().Get(gomock.Any(), v.id).Return(records(v.id)[0], v.err)
+		}
+
+		svc := New(mockOAI, config)
+
+		rec, err := svc.GetRecord(context.Background(), v.identifier, v.prefix)
+
+		var oaiErr models.OAIError
+		if v.code != "" && (!errors.As(err, &oaiErr) || oaiErr.Code != v.code) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.code)
+		}
+
+		if v.code == "" && (err != nil || svc.Identifier(rec.Book.BookID) != v.identifier) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", v.desc, i+1, rec, err, v.identifier)
+		}
+
+		ctr.Finish()
+	}
+}
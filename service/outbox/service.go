@@ -0,0 +1,202 @@
+// Package outbox sends the events stored with book and author changes to sinks. Durable sinks mark each event sent in
+// the database and get every event at least once, local sinks get the events stored while this process runs.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+const (
+	// purgeInterval is how often sent events older than retention are removed
+	purgeInterval = time.Hour
+	// settle is how long an id may wait for a lower one committed later, a local sink moves past it afterwards
+	settle = time.Minute
+)
+
+// Config holds batch size of dispatch and how long sent events are kept
+type Config struct {
+	BatchSize int
+	Retention time.Duration
+}
+
+type Service struct {
+	datastore datastore.Outbox
+	sinks     []service.Sink
+	local     []*local
+	config    Config
+	now       func() time.Time
+}
+
+// local is a sink whose position is kept in memory, it starts after the latest event. Events after floor which were
+// sent are remembered with the time they were sent until floor moves past them.
+type local struct {
+	sink    service.Sink
+	mu      sync.Mutex
+	floor   int64
+	sent    map[int64]time.Time
+	started bool
+}
+
+func New(outbox datastore.Outbox, config Config, sinks, locals []service.Sink) Service {
+	s := Service{datastore: outbox, sinks: sinks, config: config, now: time.Now}
+
+	for _, sink := range locals {
+		s.local = append(s.local, &local{sink: sink})
+	}
+
+	return s
+}
+
+// Dispatch method is to send the next batch of events to every sink, it returns how many events were sent.
+// A sink which fails gets the failed event again in the next dispatch, the other sinks are not held back.
+func (s Service) Dispatch(ctx context.Context) (int, error) {
+	var (
+		sent int
+		errs []error
+	)
+
+	for _, sink := range s.sinks {
+		err := s.datastore.Advance(ctx, sink.Name(), s.config.BatchSize,
+			func(events []models.OutboxEvent) (int64, error) {
+				n, last, err := send(ctx, sink, events)
+				sent += n
+
+				return last, err
+			})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s : %w", sink.Name(), err))
+		}
+	}
+
+	for _, l := range s.local {
+		n, err := s.dispatchLocal(ctx, l)
+		sent += n
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s : %w", l.sink.Name(), err))
+		}
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// Purge method is to remove events older than retention which every durable sink has sent
+func (s Service) Purge(ctx context.Context) (int, error) {
+	names := make([]string, 0, len(s.sinks))
+
+	for _, sink := range s.sinks {
+		names = append(names, sink.Name())
+	}
+
+	return s.datastore.Purge(ctx, names, s.now().Add(-s.config.Retention))
+}
+
+// Schedule method runs Dispatch every interval and Purge every hour until ctx is done
+func (s Service) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	purge := time.NewTicker(purgeInterval)
+	defer purge.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-purge.C:
+			purged, err := s.Purge(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "purging outbox failed", "error", err)
+
+				continue
+			}
+
+			slog.InfoContext(ctx, "purged outbox", "rows", purged)
+		case <-ticker.C:
+			// a full batch may have left more behind
+			for {
+				sent, err := s.Dispatch(ctx)
+				if err != nil {
+					slog.ErrorContext(ctx, "dispatching outbox failed", "error", err)
+				}
+
+				if err != nil || sent < s.config.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (s Service) dispatchLocal(ctx context.Context, l *local) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.started {
+		last, err := s.datastore.Last(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		l.floor, l.sent, l.started = last, make(map[int64]time.Time), true
+
+		return 0, nil
+	}
+
+	events, err := s.datastore.After(ctx, l.floor, s.config.BatchSize+len(l.sent))
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+
+	for _, event := range events {
+		if _, ok := l.sent[event.ID]; ok {
+			continue
+		}
+
+		if err = l.sink.Send(ctx, event); err != nil {
+			break
+		}
+
+		l.sent[event.ID] = s.now()
+		n++
+	}
+
+	// ids are not committed in order, floor only moves past events sent long enough ago for lower ones to show up
+	for _, event := range events {
+		at, ok := l.sent[event.ID]
+		if !ok || s.now().Sub(at) < settle {
+			break
+		}
+
+		l.floor = event.ID
+		delete(l.sent, event.ID)
+	}
+
+	return n, err
+}
+
+// send gives events to sink in order until one fails, it returns how many were sent and the id of the last one
+func send(ctx context.Context, sink service.Sink, events []models.OutboxEvent) (int, int64, error) {
+	var last int64
+
+	for i, event := range events {
+		if err := sink.Send(ctx, event); err != nil {
+			return i, last, err
+		}
+
+		last = event.ID
+	}
+
+	return len(events), last, nil
+}
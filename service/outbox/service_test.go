@@ -0,0 +1,120 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+var events = []models.OutboxEvent{
+	{ID: 4, Event: models.EventBookCreated, Entity: models.EntityBook, EntityID: 1},
+	{ID: 5, Event: models.EventBookUpdated, Entity: models.EntityBook, EntityID: 1},
+}
+
+// advance calls fn with events as the datastore would and checks the position it is moved to
+func advance(t *testing.T, expected int64) func(context.Context, string, int, func([]models.OutboxEvent) (int64, error)) error {
+	return func(_ context.Context, _ string, _ int, fn func([]models.OutboxEvent) (int64, error)) error {
+		last, err := fn(events)
+		if last != expected {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "position", 0, last, expected)
+		}
+
+		return err
+	}
+}
+
+// TestDispatch function is to test a failing sink does not hold back the others
+func TestDispatch(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockOutbox := datastore.NewMockOutbox(ctr)
+	webhook := service.NewMockSink(ctr)
+	file := service.NewMockSink(ctr)
+
+	webhook.EXPECT().Name().Return("webhook").AnyTimes()
+	file.EXPECT().Name().Return("file:events.jsonl").AnyTimes()
+
+	full := errors.New("disk full")
+
+	mockOutbox.EXPECT().Advance(gomock.Any(), "webhook", 10, gomock.Any()).DoAndReturn(advance(t, 5))
+	webhook.EXPECT().Send(gomock.Any(), events[0]).Return(nil)
+	webhook.EXPECT().Send(gomock.Any(), events[1]).Return(nil)
+
+	// file keeps the first event and gets the second again next time
+	mockOutbox.EXPECT().Advance(gomock.Any(), "file:events.jsonl", 10, gomock.Any()).DoAndReturn(advance(t, 4))
+	file.EXPECT().Send(gomock.Any(), events[0]).Return(nil)
+	file.EXPECT().Send(gomock.Any(), events[1]).Return(full)
+
+	s := New(mockOutbox, Config{BatchSize: 10}, []service.Sink{webhook, file}, nil)
+
+	sent, err := s.Dispatch(context.Background())
+	if sent != 3 {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "sent", 1, sent, 3)
+	}
+
+	if !errors.Is(err, full) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "error", 2, err, full)
+	}
+}
+
+// TestDispatchLocal function is to test local sinks start after the latest event and get events committed late
+func TestDispatchLocal(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockOutbox := datastore.NewMockOutbox(ctr)
+	bus := service.NewMockSink(ctr)
+
+	gomock.InOrder(
+		mockOutbox.EXPECT().Last(gomock.Any()).Return(int64(3), nil),
+		// 4 is not committed yet
+		mockOutbox.EXPECT().After(gomock.Any(), int64(3), 10).Return(events[1:], nil),
+		bus.EXPECT().Send(gomock.Any(), events[1]).Return(nil),
+		mockOutbox.EXPECT().After(gomock.Any(), int64(3), 11).Return(events, nil),
+		bus.EXPECT().Send(gomock.Any(), events[0]).Return(nil),
+		// both settled, nothing is sent again
+		mockOutbox.EXPECT().After(gomock.Any(), int64(3), 12).Return(events, nil),
+		mockOutbox.EXPECT().After(gomock.Any(), int64(5), 10).Return(nil, nil),
+	)
+
+	now := time.Date(2024, 6, 8, 10, 0, 0, 0, time.UTC)
+
+	s := New(mockOutbox, Config{BatchSize: 10}, nil, []service.Sink{bus})
+	s.now = func() time.Time { return now }
+
+	for i, expected := range []int{0, 1, 1, 0, 0} {
+		if i == 3 {
+			now = now.Add(settle)
+		}
+
+		sent, err := s.Dispatch(context.Background())
+		if err != nil || sent != expected {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "local dispatch", i+1, sent, expected)
+		}
+	}
+}
+
+// TestPurge function is to test events are kept until every durable sink has sent them
+func TestPurge(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockOutbox := datastore.NewMockOutbox(ctr)
+	webhook := service.NewMockSink(ctr)
+	bus := service.NewMockSink(ctr)
+
+	webhook.EXPECT().Name().Return("webhook")
+
+	now := time.Date(2024, 6, 8, 10, 0, 0, 0, time.UTC)
+	mockOutbox.EXPECT().Purge(gomock.Any(), []string{"webhook"}, now.Add(-7*24*time.Hour)).Return(12, nil)
+
+	s := New(mockOutbox, Config{BatchSize: 10, Retention: 7 * 24 * time.Hour}, []service.Sink{webhook}, []service.Sink{bus})
+	s.now = func() time.Time { return now }
+
+	purged, err := s.Purge(context.Background())
+	if err != nil || purged != 12 {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "purged", 1, purged, 12)
+	}
+}
@@ -0,0 +1,121 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/principal"
+)
+
+// ErrForbidden is returned when the caller lacks a permission
+var ErrForbidden = errors.New("forbidden")
+
+type Service struct {
+	datastore datastore.RBAC
+}
+
+func New(rbac datastore.RBAC) Service {
+	return Service{datastore: rbac}
+}
+
+// Authorize method is to check the caller in ctx has permission, roles are assigned to ids which start with the kind
+// of principal, so a user and a token subject of the same name do not share roles
+func (s Service) Authorize(ctx context.Context, permission string) error {
+	p := principal.FromContext(ctx)
+
+	granted, err := s.datastore.GetPermissions(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, g := range granted {
+		if matches(g, permission) {
+			return nil
+		}
+	}
+
+	return ErrForbidden
+}
+
+// GetRoles method is to list roles with permissions
+func (s Service) GetRoles(ctx context.Context) ([]models.Role, error) {
+	return s.datastore.GetRoles(ctx)
+}
+
+// GetAssignments method is to list roles of a principal
+func (s Service) GetAssignments(ctx context.Context, principalID string) ([]string, error) {
+	if principalID == "" {
+		return nil, errors.New("missing principal")
+	}
+
+	return s.datastore.GetAssignments(ctx, principalID)
+}
+
+// Assign method is to give an existing role to a principal, principalID is kind:id like user:asha
+func (s Service) Assign(ctx context.Context, principalID, role string) error {
+	if principalID == "" {
+		return errors.New("missing principal")
+	}
+
+	if !models.IsPrincipalID(principalID) {
+		return errors.New("invalid principal")
+	}
+
+	if err := s.roleExists(ctx, role); err != nil {
+		return err
+	}
+
+	return s.datastore.Assign(ctx, principalID, role)
+}
+
+// Unassign method is to take a role away from a principal
+func (s Service) Unassign(ctx context.Context, principalID, role string) error {
+	if principalID == "" {
+		return errors.New("missing principal")
+	}
+
+	// nobody may lock themselves out of role management
+	if principal.FromContext(ctx).ID == principalID {
+		return errors.New("cannot unassign own role")
+	}
+
+	removed, err := s.datastore.Unassign(ctx, principalID, role)
+	if err != nil {
+		return err
+	}
+
+	if removed == 0 {
+		return errors.New("role not assigned")
+	}
+
+	return nil
+}
+
+func (s Service) roleExists(ctx context.Context, role string) error {
+	roles, err := s.datastore.GetRoles(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range roles {
+		if r.Name == role {
+			return nil
+		}
+	}
+
+	return errors.New("invalid role")
+}
+
+// matches tells if granted permission covers wanted, granted may be * or resource:*
+func matches(granted, wanted string) bool {
+	if granted == "*" || granted == wanted {
+		return true
+	}
+
+	resource, action, ok := strings.Cut(granted, ":")
+
+	return ok && action == "*" && strings.HasPrefix(wanted, resource+":")
+}
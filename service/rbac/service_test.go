@@ -0,0 +1,108 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/principal"
+)
+
+// TestRBAC_Authorize function is to test permissions and wildcards of caller roles
+func TestRBAC_Authorize(t *testing.T) {
+	testcases := []struct {
+		desc       string
+		granted    []string
+		permission string
+		err        error
+	}{
+		{desc: "exact", granted: []string{"book:read", "book:update"}, permission: models.PermBookUpdate},
+		{desc: "resource wildcard", granted: []string{"book:*"}, permission: models.PermBookDelete},
+		{desc: "admin", granted: []string{"*"}, permission: models.PermRoleManage},
+		{desc: "missing", granted: []string{"book:read", "author:*"}, permission: models.PermBookDelete, err: ErrForbidden},
+		{desc: "wildcard of other resource", granted: []string{"bookmark:*"}, permission: models.PermBookRead, err: ErrForbidden},
+		{desc: "no roles", permission: models.PermBookRead, err: ErrForbidden},
+	}
+
+	ctx := principal.NewContext(context.TODO(), models.Principal{ID: "jwt:u-1", Kind: models.PrincipalJWT})
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockRBAC := datastore.NewMockRBAC(ctr)
+		service := New(mockRBAC)
+
+		mockRBAC.EXPECT().GetPermissions(gomock.Any(), "jwt:u-1").Return(v.granted, nil)
+
+		err := service.Authorize(ctx, v.permission)
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestRBAC_Assign function is to test role assignment validation
+func TestRBAC_Assign(t *testing.T) {
+	testcases := []struct {
+		desc      string
+		principal string
+		role      string
+		err       error
+	}{
+		{desc: "valid", principal: "user:u-2", role: "cataloguer"},
+		{desc: "unknown role", principal: "user:u-2", role: "janitor", err: errors.New("invalid role")},
+		{desc: "missing principal", role: "cataloguer", err: errors.New("missing principal")},
+		{desc: "principal without kind", principal: "u-2", role: "cataloguer", err: errors.New("invalid principal")},
+		{desc: "unknown kind", principal: "group:u-2", role: "cataloguer", err: errors.New("invalid principal")},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockRBAC := datastore.NewMockRBAC(ctr)
+		service := New(mockRBAC)
+
+		mockRBAC.EXPECT().GetRoles(gomock.Any()).Return([]models.Role{{Name: "cataloguer"}, {Name: "patron"}}, nil).AnyTimes()
+		mockRBAC.EXPECT().Assign(gomock.Any(), v.principal, v.role).Return(nil).AnyTimes()
+
+		err := service.Assign(context.TODO(), v.principal, v.role)
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestRBAC_Unassign function is to test removing roles
+func TestRBAC_Unassign(t *testing.T) {
+	testcases := []struct {
+		desc      string
+		principal string
+		removed   int
+		err       error
+	}{
+		{desc: "valid", principal: "user:u-2", removed: 1},
+		{desc: "not assigned", principal: "user:u-2", removed: 0, err: errors.New("role not assigned")},
+		{desc: "self", principal: "user:admin-1", err: errors.New("cannot unassign own role")},
+		// a token subject of the same name is someone else
+		{desc: "same name of other kind", principal: "jwt:admin-1", removed: 1},
+	}
+
+	ctx := principal.NewContext(context.TODO(), models.Principal{ID: "user:admin-1", Name: "admin-1",
+		Kind: models.PrincipalUser})
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockRBAC := datastore.NewMockRBAC(ctr)
+		service := New(mockRBAC)
+
+		mockRBAC.EXPECT().Unassign(gomock.Any(), v.principal, "librarian").Return(v.removed, nil).AnyTimes()
+
+		err := service.Unassign(ctx, v.principal, "librarian")
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package revision
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"time"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+type Service struct {
+	datastore datastore.Revision
+}
+
+func New(revision datastore.Revision) Service {
+	return Service{datastore: revision}
+}
+
+// GetAll method is to get history of a record, oldest first
+func (s Service) GetAll(ctx context.Context, entity string, entityID int) ([]models.Revision, error) {
+	if entityID <= 0 {
+		return nil, errors.New("invalid id")
+	}
+
+	return s.datastore.GetAll(ctx, entity, entityID)
+}
+
+// Get method is to get one revision of a record
+func (s Service) Get(ctx context.Context, entity string, entityID, rev int) (models.Revision, error) {
+	if entityID <= 0 {
+		return models.Revision{}, errors.New("invalid id")
+	}
+
+	if rev <= 0 {
+		return models.Revision{}, errors.New("invalid revision")
+	}
+
+	return s.datastore.Get(ctx, entity, entityID, rev)
+}
+
+// AsOf method is to get the revision of a record which was current at given time
+func (s Service) AsOf(ctx context.Context, entity string, entityID int, at time.Time) (models.Revision, error) {
+	if entityID <= 0 {
+		return models.Revision{}, errors.New("invalid id")
+	}
+
+	return s.datastore.GetAsOf(ctx, entity, entityID, at)
+}
+
+// Diff method is to compare two revisions of a record field by field
+func (s Service) Diff(ctx context.Context, entity string, entityID, from, to int) (models.RevisionDiff, error) {
+	older, err := s.Get(ctx, entity, entityID, from)
+	if err != nil {
+		return models.RevisionDiff{}, err
+	}
+
+	newer, err := s.Get(ctx, entity, entityID, to)
+	if err != nil {
+		return models.RevisionDiff{}, err
+	}
+
+	changes, err := diff(older.Data, newer.Data)
+	if err != nil {
+		return models.RevisionDiff{}, err
+	}
+
+	return models.RevisionDiff{From: from, To: to, Changes: changes}, nil
+}
+
+// diff returns changed fields of two JSON objects sorted by field name
+func diff(older, newer json.RawMessage) ([]models.FieldChange, error) {
+	before, after := map[string]any{}, map[string]any{}
+
+	if err := json.Unmarshal(older, &before); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(newer, &after); err != nil {
+		return nil, err
+	}
+
+	flatBefore, flatAfter := map[string]any{}, map[string]any{}
+	flatten("", before, flatBefore)
+	flatten("", after, flatAfter)
+
+	fields := map[string]bool{}
+	for field := range flatBefore {
+		fields[field] = true
+	}
+
+	for field := range flatAfter {
+		fields[field] = true
+	}
+
+	changes := []models.FieldChange{}
+
+	for field := range fields {
+		if !reflect.DeepEqual(flatBefore[field], flatAfter[field]) {
+			changes = append(changes, models.FieldChange{Field: field, From: flatBefore[field], To: flatAfter[field]})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+
+	return changes, nil
+}
+
+func flatten(prefix string, value map[string]any, out map[string]any) {
+	for key, v := range value {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if nested, ok := v.(map[string]any); ok {
+			flatten(key, nested, out)
+
+			continue
+		}
+
+		out[key] = v
+	}
+}
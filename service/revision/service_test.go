@@ -0,0 +1,59 @@
+package revision
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+// TestRevision_Diff function is to test field by field comparison of two revisions
+func TestRevision_Diff(t *testing.T) {
+	testcases := []struct {
+		desc  string
+		from  int
+		to    int
+		older string
+		newer string
+		resp  models.RevisionDiff
+		err   error
+	}{
+		{desc: "changed fields", from: 1, to: 2,
+			older: `{"bookID":1,"title":"Two States","auth":{"firstName":"Chetan","lastName":"B"}}`,
+			newer: `{"bookID":1,"title":"2 States","auth":{"firstName":"Chetan","lastName":"Bhagat"}}`,
+			resp: models.RevisionDiff{From: 1, To: 2, Changes: []models.FieldChange{
+				{Field: "auth.lastName", From: "B", To: "Bhagat"},
+				{Field: "title", From: "Two States", To: "2 States"},
+			}}},
+		{desc: "same", from: 1, to: 1, older: `{"bookID":1}`, newer: `{"bookID":1}`,
+			resp: models.RevisionDiff{From: 1, To: 1, Changes: []models.FieldChange{}}},
+		{desc: "invalid revision", from: 0, to: 1, err: errors.New("invalid revision")},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockRevision := datastore.NewMockRevision(ctr)
+		service := New(mockRevision)
+
+		mockRevision.EXPECT().Get(gomock.Any(), models.EntityBook, 1, v.from).
+			Return(models.Revision{Rev: v.from, Data: json.RawMessage(v.older)}, nil).AnyTimes()
+		mockRevision.EXPECT().Get(gomock.Any(), models.EntityBook, 1, v.to).
+			Return(models.Revision{Rev: v.to, Data: json.RawMessage(v.newer)}, nil).AnyTimes()
+
+		resp, err := service.Diff(context.TODO(), models.EntityBook, 1, v.from, v.to)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package sru
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"Three-Layer-Architecture/cql"
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+// indexes maps CQL indexes, without context set prefix, to book fields
+var indexes = map[string]string{
+	"title":     models.SearchTitle,
+	"author":    models.SearchAuthor,
+	"creator":   models.SearchAuthor,
+	"publisher": models.SearchPublisher,
+	"date":      models.SearchDate,
+}
+
+// serverChoice are the fields searched by terms without an index
+var serverChoice = []string{models.SearchTitle, models.SearchAuthor, models.SearchPublisher}
+
+// dateTerm is a year or yyyy-mm-dd
+var dateTerm = regexp.MustCompile(`^\d{4}(-\d{2}-\d{2})?$`)
+
+type Service struct {
+	datastore  datastore.SRU
+	maxRecords int
+}
+
+// New returns the search service, pages are cut to maxRecords books
+func New(sru datastore.SRU, maxRecords int) Service {
+	return Service{datastore: sru, maxRecords: maxRecords}
+}
+
+// Search method is to get maximum books matching a CQL query from the 1-based position start.
+// Queries that can not be run are reported as models.SRUDiagnostic.
+func (s Service) Search(ctx context.Context, query string, start, maximum int) (models.SRUResult, error) {
+	if start < 1 {
+		return models.SRUResult{}, models.SRUDiagnostic{Code: models.SRUUnsupportedParameterValue, Details: "startRecord"}
+	}
+
+	if maximum < 0 {
+		return models.SRUResult{}, models.SRUDiagnostic{Code: models.SRUUnsupportedParameterValue,
+			Details: "maximumRecords"}
+	}
+
+	if maximum > s.maxRecords {
+		maximum = s.maxRecords
+	}
+
+	node, err := cql.Parse(query)
+	if err != nil {
+		var syntax cql.SyntaxError
+		if errors.As(err, &syntax) {
+			return models.SRUResult{}, models.SRUDiagnostic{Code: models.SRUQuerySyntax, Details: syntax.Error()}
+		}
+
+		return models.SRUResult{}, err
+	}
+
+	q, err := translate(node)
+	if err != nil {
+		return models.SRUResult{}, err
+	}
+
+	books, total, err := s.datastore.Search(ctx, q, start-1, maximum)
+	if err != nil {
+		return models.SRUResult{}, err
+	}
+
+	// positions past the last record are only an error when there are records
+	if total > 0 && start > total {
+		return models.SRUResult{}, models.SRUDiagnostic{Code: models.SRUFirstRecordOutOfRange,
+			Details: "startRecord"}
+	}
+
+	result := models.SRUResult{Books: books, Total: total}
+
+	if next := start + len(books); len(books) > 0 && next <= total {
+		result.Next = next
+	}
+
+	return result, nil
+}
+
+func translate(node cql.Node) (*models.BookQuery, error) {
+	switch n := node.(type) {
+	case cql.Boolean:
+		return boolean(n)
+	case cql.Clause:
+		return clause(n)
+	default:
+		return nil, models.SRUDiagnostic{Code: models.SRUQuerySyntax}
+	}
+}
+
+func boolean(b cql.Boolean) (*models.BookQuery, error) {
+	if len(b.Modifiers) > 0 {
+		return nil, models.SRUDiagnostic{Code: models.SRUUnsupportedBooleanModifier, Details: b.Modifiers[0].Name}
+	}
+
+	var op string
+
+	switch b.Op {
+	case "and":
+		op = models.OpAnd
+	case "or":
+		op = models.OpOr
+	case "not":
+		op = models.OpNot
+	case "prox":
+		return nil, models.SRUDiagnostic{Code: models.SRUProximity, Details: b.Op}
+	default:
+		return nil, models.SRUDiagnostic{Code: models.SRUUnsupportedBoolean, Details: b.Op}
+	}
+
+	left, err := translate(b.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := translate(b.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BookQuery{Op: op, Left: left, Right: right}, nil
+}
+
+func clause(c cql.Clause) (*models.BookQuery, error) {
+	if len(c.Modifiers) > 0 {
+		return nil, models.SRUDiagnostic{Code: models.SRUUnsupportedRelationModifier, Details: c.Modifiers[0].Name}
+	}
+
+	set, name, found := strings.Cut(strings.ToLower(c.Index), ".")
+	if !found {
+		set, name = "", set
+	}
+
+	switch {
+	case set == "cql" && name == "allrecords":
+		return &models.BookQuery{Field: models.SearchAll}, nil
+	case set == "cql" && (name == "serverchoice" || name == "anywhere"):
+		return text(serverChoice, c)
+	case set != "" && set != "dc":
+		return nil, models.SRUDiagnostic{Code: models.SRUUnsupportedIndex, Details: c.Index}
+	}
+
+	field, ok := indexes[name]
+	if !ok {
+		return nil, models.SRUDiagnostic{Code: models.SRUUnsupportedIndex, Details: c.Index}
+	}
+
+	if field == models.SearchDate {
+		return date(c)
+	}
+
+	return text([]string{field}, c)
+}
+
+// text matches a term in any of fields, = and adj look for the phrase, all and any for its words and == for the whole value
+func text(fields []string, c cql.Clause) (*models.BookQuery, error) {
+	if strings.TrimSpace(c.Term) == "" {
+		return nil, models.SRUDiagnostic{Code: models.SRUEmptyTerm, Details: c.Index}
+	}
+
+	switch c.Relation {
+	case "=", "adj":
+		return anyField(fields, "%"+pattern(c.Term)+"%"), nil
+	case "==", "exact":
+		return anyField(fields, pattern(c.Term)), nil
+	case "<>":
+		return &models.BookQuery{Op: models.OpNot, Right: anyField(fields, pattern(c.Term))}, nil
+	case "all", "any":
+		op := models.OpAnd
+		if c.Relation == "any" {
+			op = models.OpOr
+		}
+
+		var q *models.BookQuery
+
+		for _, word := range strings.Fields(c.Term) {
+			next := anyField(fields, "%"+pattern(word)+"%")
+			if q != nil {
+				next = &models.BookQuery{Op: op, Left: q, Right: next}
+			}
+
+			q = next
+		}
+
+		return q, nil
+	default:
+		return nil, models.SRUDiagnostic{Code: models.SRUUnsupportedRelation, Details: c.Relation}
+	}
+}
+
+// anyField joins conditions on each field with or
+func anyField(fields []string, like string) *models.BookQuery {
+	var q *models.BookQuery
+
+	for _, f := range fields {
+		next := &models.BookQuery{Field: f, Relation: models.RelationLike, Term: like}
+		if q != nil {
+			next = &models.BookQuery{Op: models.OpOr, Left: q, Right: next}
+		}
+
+		q = next
+	}
+
+	return q
+}
+
+// pattern turns a CQL term into a LIKE pattern, * and ? are masking characters unless escaped with \
+func pattern(term string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(term); i++ {
+		c := term[i]
+
+		switch c {
+		case '\\':
+			if i+1 < len(term) {
+				i++
+				c = term[i]
+			}
+
+			if c == '%' || c == '_' || c == '\\' {
+				b.WriteByte('\\')
+			}
+
+			b.WriteByte(c)
+		case '*':
+			b.WriteByte('%')
+		case '?':
+			b.WriteByte('_')
+		case '%', '_':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// date compares publishing dates with a year or yyyy-mm-dd
+func date(c cql.Clause) (*models.BookQuery, error) {
+	if c.Term == "" {
+		return nil, models.SRUDiagnostic{Code: models.SRUEmptyTerm, Details: c.Index}
+	}
+
+	if !dateTerm.MatchString(c.Term) {
+		return nil, models.SRUDiagnostic{Code: models.SRUInvalidTerm, Details: c.Term}
+	}
+
+	q := &models.BookQuery{Field: models.SearchDate, Term: c.Term}
+
+	switch c.Relation {
+	case "=", "==", "exact":
+		q.Relation = models.RelationEqual
+	case "<>":
+		return &models.BookQuery{Op: models.OpNot,
+			Right: &models.BookQuery{Field: models.SearchDate, Relation: models.RelationEqual, Term: c.Term}}, nil
+	case "<", "<=", ">", ">=":
+		q.Relation = c.Relation
+	default:
+		return nil, models.SRUDiagnostic{Code: models.SRUUnsupportedRelation, Details: c.Relation}
+	}
+
+	return q, nil
+}
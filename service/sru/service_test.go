@@ -0,0 +1,151 @@
+package sru
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+func like(field, term string) *models.BookQuery {
+	return &models.BookQuery{Field: field, Relation: models.RelationLike, Term: term}
+}
+
+func join(op string, left, right *models.BookQuery) *models.BookQuery {
+	return &models.BookQuery{Op: op, Left: left, Right: right}
+}
+
+// TestSearch function is to test CQL queries are translated into book queries
+func TestSearch(t *testing.T) {
+	testcases := []struct {
+		desc  string
+		query string
+		resp  *models.BookQuery
+	}{
+		{desc: "phrase", query: `title = "indian girl"`, resp: like(models.SearchTitle, "%indian girl%")},
+		{desc: "context set and masking", query: `dc.creator == "Bha?at*"`, resp: like(models.SearchAuthor, "Bha_at%")},
+		{desc: "escaped masking", query: `dc.publisher exact "100\% \*"`, resp: like(models.SearchPublisher, `100\% *`)},
+		{desc: "all words", query: "title all \"indian girl\"",
+			resp: join(models.OpAnd, like(models.SearchTitle, "%indian%"), like(models.SearchTitle, "%girl%"))},
+		{desc: "any word", query: "publisher any \"penguin scholastic\"",
+			resp: join(models.OpOr, like(models.SearchPublisher, "%penguin%"), like(models.SearchPublisher, "%scholastic%"))},
+		{desc: "not equal", query: "title <> girl",
+			resp: &models.BookQuery{Op: models.OpNot, Right: like(models.SearchTitle, "girl")}},
+		{desc: "server choice", query: "bhagat",
+			resp: join(models.OpOr, join(models.OpOr, like(models.SearchTitle, "%bhagat%"),
+				like(models.SearchAuthor, "%bhagat%")), like(models.SearchPublisher, "%bhagat%"))},
+		{desc: "booleans and dates", query: "(author = lee or date < 1970) not date = 1960-07-11",
+			resp: join(models.OpNot, join(models.OpOr, like(models.SearchAuthor, "%lee%"),
+				&models.BookQuery{Field: models.SearchDate, Relation: models.RelationLess, Term: "1970"}),
+				&models.BookQuery{Field: models.SearchDate, Relation: models.RelationEqual, Term: "1960-07-11"})},
+		{desc: "all records", query: "cql.allRecords = 1", resp: &models.BookQuery{Field: models.SearchAll}},
+	}
+
+	ctr := gomock.NewController(t)
+	mockSRU := datastore.NewMockSRU(ctr)
+	svc := New(mockSRU, 100)
+
+	for i, v := range testcases {
+		mockSRU.EXPECT().Search(gomock.Any(), v.resp, 0, 10).Return(nil, 0, nil)
+
+		if _, err := svc.Search(context.Background(), v.query, 1, 10); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, nil)
+		}
+	}
+}
+
+// TestSearch_Paging function is to test pages are cut to the maximum and point at the next record
+func TestSearch_Paging(t *testing.T) {
+	books := []models.Book{{BookID: 1}, {BookID: 2}}
+	q := like(models.SearchTitle, "%a%")
+
+	testcases := []struct {
+		desc    string
+		start   int
+		maximum int
+		limit   int
+		books   []models.Book
+		total   int
+		resp    models.SRUResult
+		err     error
+	}{
+		{desc: "first page", start: 1, maximum: 2, limit: 2, books: books, total: 5,
+			resp: models.SRUResult{Books: books, Total: 5, Next: 3}},
+		{desc: "last page", start: 4, maximum: 2, limit: 2, books: books, total: 5,
+			resp: models.SRUResult{Books: books, Total: 5}},
+		{desc: "cut to maximum", start: 1, maximum: 500, limit: 3, books: books, total: 2,
+			resp: models.SRUResult{Books: books, Total: 2}},
+		{desc: "count only", start: 1, limit: 0, total: 5, resp: models.SRUResult{Total: 5}},
+		{desc: "no matches", start: 3, maximum: 2, limit: 2, resp: models.SRUResult{}},
+		{desc: "past the end", start: 6, maximum: 2, limit: 2, total: 5,
+			err: models.SRUDiagnostic{Code: models.SRUFirstRecordOutOfRange, Details: "startRecord"}},
+	}
+
+	ctr := gomock.NewController(t)
+	mockSRU := datastore.NewMockSRU(ctr)
+	svc := New(mockSRU, 3)
+
+	for i, v := range testcases {
+		mockSRU.EXPECT().Search(gomock.Any(), q, v.start-1, v.limit).Return(v.books, v.total, nil)
+
+		resp, err := svc.Search(context.Background(), "title = a", v.start, v.maximum)
+
+		if !reflect.DeepEqual(resp, v.resp) || !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", v.desc, i+1, resp, err, v.resp, v.err)
+		}
+	}
+}
+
+// TestSearch_Diagnostics function is to test queries that can not be run are reported with their diagnostic
+func TestSearch_Diagnostics(t *testing.T) {
+	testcases := []struct {
+		desc  string
+		query string
+		start int
+		max   int
+		code  int
+	}{
+		{desc: "start before first record", query: "a", start: 0, code: models.SRUUnsupportedParameterValue},
+		{desc: "negative maximum", query: "a", start: 1, max: -1, code: models.SRUUnsupportedParameterValue},
+		{desc: "syntax", query: "title = ", start: 1, code: models.SRUQuerySyntax},
+		{desc: "unknown index", query: "isbn = 123", start: 1, code: models.SRUUnsupportedIndex},
+		{desc: "unknown context set", query: "bath.title = a", start: 1, code: models.SRUUnsupportedIndex},
+		{desc: "text comparison", query: "title < a", start: 1, code: models.SRUUnsupportedRelation},
+		{desc: "date relation", query: "date any 2016", start: 1, code: models.SRUUnsupportedRelation},
+		{desc: "relation modifier", query: "title =/stem girl", start: 1, code: models.SRUUnsupportedRelationModifier},
+		{desc: "empty term", query: `title = ""`, start: 1, code: models.SRUEmptyTerm},
+		{desc: "date format", query: `date = "16/03/2016"`, start: 1, code: models.SRUInvalidTerm},
+		{desc: "proximity", query: "a prox b", start: 1, code: models.SRUProximity},
+		{desc: "boolean modifier", query: "a and/rel.combine=sum b", start: 1, code: models.SRUUnsupportedBooleanModifier},
+	}
+
+	ctr := gomock.NewController(t)
+	svc := New(datastore.NewMockSRU(ctr), 100)
+
+	for i, v := range testcases {
+		var diag models.SRUDiagnostic
+
+		_, err := svc.Search(context.Background(), v.query, v.start, v.max)
+		if !errors.As(err, &diag) || diag.Code != v.code {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.code)
+		}
+	}
+}
+
+// TestSearch_Error function is to test datastore errors are returned as they are
+func TestSearch_Error(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockSRU := datastore.NewMockSRU(ctr)
+	lost := errors.New("connection lost")
+
+	mockSRU.EXPECT().Search(gomock.Any(), gomock.Any(), 0, 10).Return(nil, 0, lost)
+
+	if _, err := New(mockSRU, 100).Search(context.Background(), "a", 1, 10); !errors.Is(err, lost) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "datastore error", 1, err, lost)
+	}
+}
@@ -0,0 +1,81 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+type Service struct {
+	book      datastore.Book
+	author    datastore.Author
+	retention time.Duration
+}
+
+// New returns trash service, deleted rows older than retention are purged
+func New(book datastore.Book, author datastore.Author, retention time.Duration) Service {
+	return Service{book: book, author: author, retention: retention}
+}
+
+// GetAll method is to list deleted Books and Authors
+func (s Service) GetAll(ctx context.Context) (models.Trash, error) {
+	books, err := s.book.GetDeleted(ctx)
+	if err != nil {
+		return models.Trash{}, err
+	}
+
+	authors, err := s.author.GetDeleted(ctx)
+	if err != nil {
+		return models.Trash{}, err
+	}
+
+	return models.Trash{Books: books, Authors: authors}, nil
+}
+
+// Purge method is to permanently remove everything deleted before the retention period
+func (s Service) Purge(ctx context.Context) (int, error) {
+	if s.retention <= 0 {
+		return 0, errors.New("invalid retention")
+	}
+
+	before := time.Now().Add(-s.retention)
+
+	// books first, authors can't be removed while a book refers them
+	books, err := s.book.Purge(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+
+	authors, err := s.author.Purge(ctx, before)
+	if err != nil {
+		return books, err
+	}
+
+	return books + authors, nil
+}
+
+// Schedule method runs Purge every interval until ctx is done
+func (s Service) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.Purge(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "purging trash failed", "error", err)
+
+				continue
+			}
+
+			slog.InfoContext(ctx, "purged trash", "rows", purged)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+// TestTrash_GetAll function is to test listing of trash
+func TestTrash_GetAll(t *testing.T) {
+	testcases := []struct {
+		desc      string
+		books     []models.Book
+		authors   []models.Author
+		bookErr   error
+		authorErr error
+		resp      models.Trash
+		err       error
+	}{
+		{desc: "valid", books: []models.Book{{BookID: 1, Title: "Journey"}}, authors: []models.Author{{AuthID: 2}},
+			resp: models.Trash{Books: []models.Book{{BookID: 1, Title: "Journey"}}, Authors: []models.Author{{AuthID: 2}}}},
+		{desc: "book error", bookErr: errors.New("connection refused"), err: errors.New("connection refused")},
+		{desc: "author error", authorErr: errors.New("connection refused"), err: errors.New("connection refused")},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockBook := datastore.NewMockBook(ctr)
+		mockAuthor := datastore.NewMockAuthor(ctr)
+		service := New(mockBook, mockAuthor, time.Hour)
+
+		mockBook.EXPECT().GetDeleted(gomock.Any()).Return(v.books, v.bookErr)
+		mockAuthor.EXPECT().GetDeleted(gomock.Any()).Return(v.authors, v.authorErr).AnyTimes()
+
+		resp, err := service.GetAll(context.TODO())
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestTrash_Purge function is to test purging rows older than retention
+func TestTrash_Purge(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockBook := datastore.NewMockBook(ctr)
+	mockAuthor := datastore.NewMockAuthor(ctr)
+	service := New(mockBook, mockAuthor, 24*time.Hour)
+
+	// cutoff must be roughly a day back from now
+	cutoff := gomock.AssignableToTypeOf(time.Time{})
+
+	gomock.InOrder(
+		mockBook.EXPECT().Purge(gomock.Any(), cutoff).DoAndReturn(func(_ context.Context, before time.Time) (int, error) {
+			if time.Since(before) < 24*time.Hour {
+				t.Errorf("[TEST1]Failed. Got cutoff %v\tExpected older than a day", before)
+			}
+
+			return 2, nil
+		}),
+		mockAuthor.EXPECT().Purge(gomock.Any(), cutoff).Return(1, nil),
+	)
+
+	resp, err := service.Purge(context.TODO())
+	if resp != 3 || err != nil {
+		t.Errorf("[TEST1]Failed. Got %v, %v\tExpected 3, <nil>\n", resp, err)
+	}
+
+	// retention must be positive else everything would go
+	_, err = New(mockBook, mockAuthor, 0).Purge(context.TODO())
+	if !reflect.DeepEqual(err, errors.New("invalid retention")) {
+		t.Errorf("[TEST2]Failed. Got %v\tExpected invalid retention\n", err)
+	}
+}
@@ -0,0 +1,288 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/principal"
+	"Three-Layer-Architecture/service"
+)
+
+const minPasswordLength = 12
+
+var (
+	// ErrInvalidCredentials does not tell if the username or the password was wrong
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrLocked is returned while the account is locked after repeated failures
+	ErrLocked = errors.New("account locked")
+)
+
+// Config holds session and lockout policy
+type Config struct {
+	SessionTTL  time.Duration
+	ResetTTL    time.Duration
+	MaxFailures int
+	LockoutFor  time.Duration
+	// ResetURL is prefixed to the reset token in the notification
+	ResetURL string
+}
+
+type Service struct {
+	user     datastore.User
+	session  datastore.Session
+	notifier service.Notifier
+	config   Config
+	now      func() time.Time
+}
+
+func New(user datastore.User, session datastore.Session, notifier service.Notifier, config Config) Service {
+	return Service{user: user, session: session, notifier: notifier, config: config, now: time.Now}
+}
+
+// Register method is to create an account with a password
+func (s Service) Register(ctx context.Context, username, email, password string) (models.User, error) {
+	if username == "" || email == "" {
+		return models.User{}, errors.New("missing fields")
+	}
+
+	if !strings.Contains(email, "@") {
+		return models.User{}, errors.New("invalid email")
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return s.user.Post(ctx, models.User{Username: username, Email: email, PasswordHash: hash, CreatedAt: s.now().UTC()})
+}
+
+// Login method is to check the password and start a session, the session token is returned only here
+func (s Service) Login(ctx context.Context, username, password string) (string, models.Session, error) {
+	user, err := s.Verify(ctx, username, password)
+	if err != nil {
+		return "", models.Session{}, err
+	}
+
+	now := s.now()
+
+	token, err := randomToken()
+	if err != nil {
+		return "", models.Session{}, err
+	}
+
+	session, err := s.session.Post(ctx, models.Session{
+		TokenHash: hashToken(token),
+		UserID:    user.ID,
+		CreatedAt: now.UTC(),
+		ExpiresAt: now.Add(s.config.SessionTTL).UTC(),
+	})
+	if err != nil {
+		return "", models.Session{}, err
+	}
+
+	return token, session, nil
+}
+
+// Verify method is to check the password of a user without starting a session, failures count towards lockout
+func (s Service) Verify(ctx context.Context, username, password string) (models.User, error) {
+	user, err := s.user.GetByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		// comparing anyway so unknown users take as long as wrong passwords
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+
+		return models.User{}, ErrInvalidCredentials
+	}
+
+	if err != nil {
+		return models.User{}, err
+	}
+
+	now := s.now()
+
+	if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+		return models.User{}, ErrLocked
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		failures, err := s.user.RecordFailedLogin(ctx, user.ID, now)
+		// a concurrent failure locked the account since it was read
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, ErrInvalidCredentials
+		}
+
+		if err != nil {
+			return models.User{}, err
+		}
+
+		if failures >= s.config.MaxFailures {
+			if err = s.user.Lock(ctx, user.ID, now.Add(s.config.LockoutFor)); err != nil {
+				return models.User{}, err
+			}
+		}
+
+		return models.User{}, ErrInvalidCredentials
+	}
+
+	if err = s.user.ResetFailedLogins(ctx, user.ID); err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// GetByUsername method is to find an account, like a patron at a kiosk
+func (s Service) GetByUsername(ctx context.Context, username string) (models.User, error) {
+	return s.user.GetByUsername(ctx, username)
+}
+
+// Logout method is to end the session of token
+func (s Service) Logout(ctx context.Context, token string) error {
+	if token == "" {
+		return errors.New("missing session")
+	}
+
+	_, err := s.session.Revoke(ctx, hashToken(token))
+
+	return err
+}
+
+// AuthenticateSession method is to find the user of an active session token
+func (s Service) AuthenticateSession(ctx context.Context, token string) (models.Principal, error) {
+	session, err := s.session.GetActive(ctx, hashToken(token), s.now())
+	if err != nil {
+		return models.Principal{}, ErrInvalidCredentials
+	}
+
+	user, err := s.user.Getbyid(ctx, session.UserID)
+	if err != nil {
+		return models.Principal{}, ErrInvalidCredentials
+	}
+
+	return models.Principal{ID: models.PrincipalID(models.PrincipalUser, user.Username), Name: user.Username,
+		Kind: models.PrincipalUser}, nil
+}
+
+// RevokeSessions method is to end every session of a user
+func (s Service) RevokeSessions(ctx context.Context, userID int) (int, error) {
+	if userID <= 0 {
+		return 0, errors.New("invalid id")
+	}
+
+	return s.session.RevokeAll(ctx, userID)
+}
+
+// RequestPasswordReset method is to send a reset token to the user, unknown users are not reported
+func (s Service) RequestPasswordReset(ctx context.Context, username string) error {
+	user, err := s.user.GetByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+
+	err = s.user.PostReset(ctx, models.PasswordReset{
+		TokenHash: hashToken(token),
+		UserID:    user.ID,
+		ExpiresAt: s.now().Add(s.config.ResetTTL).UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	body := "Use this link to choose a new password, it expires in " + s.config.ResetTTL.String() + ".\n\n" +
+		s.config.ResetURL + token
+
+	if err = s.notifier.Notify(ctx, user.Email, "Password reset", body); err != nil {
+		slog.ErrorContext(ctx, "could not send password reset", "user", user.ID, "error", err)
+
+		return err
+	}
+
+	return nil
+}
+
+// ResetPassword method is to set a new password with a reset token, every session of the user is ended
+func (s Service) ResetPassword(ctx context.Context, token, password string) error {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	reset, err := s.user.UseReset(ctx, hashToken(token), s.now())
+	if err != nil {
+		return errors.New("invalid or expired token")
+	}
+
+	if err = s.user.UpdatePassword(ctx, reset.UserID, hash); err != nil {
+		return err
+	}
+
+	_, err = s.session.RevokeAll(ctx, reset.UserID)
+
+	return err
+}
+
+// Current method is to get the account of the caller in ctx
+func (s Service) Current(ctx context.Context) (models.User, error) {
+	p := principal.FromContext(ctx)
+	if p.Kind != models.PrincipalUser {
+		return models.User{}, errors.New("caller is not a user")
+	}
+
+	return s.user.GetByUsername(ctx, p.Name)
+}
+
+// dummyHash is compared against for unknown users
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("unknown user password"), bcrypt.DefaultCost)
+
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", errors.New("password too short")
+	}
+
+	// bcrypt ignores everything after 72 bytes
+	if len(password) > 72 {
+		return "", errors.New("password too long")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,214 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+var config = Config{SessionTTL: time.Hour, ResetTTL: time.Hour, MaxFailures: 5, LockoutFor: 15 * time.Minute,
+	ResetURL: "https://library.test/reset?token="}
+
+// TestUser_Login function is to test password check, lockout and session creation
+func TestUser_Login(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	hash, _ := bcrypt.GenerateFromPassword([]byte("correct horse battery"), bcrypt.MinCost)
+	locked := now.Add(time.Minute)
+	expired := now.Add(-time.Minute)
+	lockUntil := now.Add(config.LockoutFor)
+
+	testcases := []struct {
+		desc      string
+		password  string
+		user      models.User
+		found     error
+		failures  int
+		record    error
+		lockUntil *time.Time
+		err       error
+	}{
+		{desc: "valid", password: "correct horse battery", user: models.User{ID: 1, PasswordHash: string(hash), FailedLogins: 2}},
+		{desc: "unknown user", password: "correct horse battery", found: sql.ErrNoRows, err: ErrInvalidCredentials},
+		{desc: "wrong password", password: "wrong", user: models.User{ID: 1, PasswordHash: string(hash), FailedLogins: 1},
+			failures: 2, err: ErrInvalidCredentials},
+		{desc: "fifth failure locks", password: "wrong", user: models.User{ID: 1, PasswordHash: string(hash), FailedLogins: 4},
+			failures: 5, lockUntil: &lockUntil, err: ErrInvalidCredentials},
+		{desc: "locked", password: "correct horse battery", user: models.User{ID: 1, PasswordHash: string(hash), FailedLogins: 5,
+			LockedUntil: &locked}, err: ErrLocked},
+		{desc: "failure after lock expired", password: "wrong", user: models.User{ID: 1, PasswordHash: string(hash),
+			FailedLogins: 5, LockedUntil: &expired}, failures: 1, err: ErrInvalidCredentials},
+		{desc: "locked by a concurrent failure", password: "wrong", user: models.User{ID: 1, PasswordHash: string(hash),
+			FailedLogins: 4}, record: sql.ErrNoRows, err: ErrInvalidCredentials},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockUser := datastore.NewMockUser(ctr)
+		mockSession := datastore.NewMockSession(ctr)
+		service := New(mockUser, mockSession, service.NewMockNotifier(ctr), config)
+		service.now = func() time.Time { return now }
+
+		mockUser.EXPECT().GetByUsername(gomock.Any(), "alice").Return(v.user, v.found)
+
+		if v.failures > 0 || v.record != nil {
+			mockUser.EXPECT().RecordFailedLogin(gomock.Any(), 1, now).Return(v.failures, v.record)
+		}
+
+		if v.lockUntil != nil {
+			mockUser.EXPECT().Lock(gomock.Any(), 1, *v.lockUntil).Return(nil)
+		}
+
+		if v.err == nil {
+			mockUser.EXPECT().ResetFailedLogins(gomock.Any(), 1).Return(nil)
+			mockSession.EXPECT().Post(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, s models.Session) (models.Session, error) {
+				if s.UserID != 1 || !s.ExpiresAt.Equal(now.Add(time.Hour)) || len(s.TokenHash) != 64 {
+					t.Errorf("desc : %v ,[TEST%d]Failed. Got session %v\n", v.desc, i+1, s)
+				}
+
+				return s, nil
+			})
+		}
+
+		token, session, err := service.Login(context.Background(), "alice", v.password)
+
+		if !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		if v.err == nil && (len(token) != 64 || session.TokenHash != hashToken(token)) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got token %v for session %v\n", v.desc, i+1, token, session)
+		}
+
+		ctr.Finish()
+	}
+}
+
+// TestUser_Verify function is to test a checked password starts no session
+func TestUser_Verify(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("correct horse battery"), bcrypt.MinCost)
+	user := models.User{ID: 1, Username: "alice", PasswordHash: string(hash)}
+
+	ctr := gomock.NewController(t)
+	mockUser := datastore.NewMockUser(ctr)
+	service := New(mockUser, datastore.NewMockSession(ctr), service.NewMockNotifier(ctr), config)
+
+	mockUser.EXPECT().GetByUsername(gomock.Any(), "alice").Return(user, nil)
+	mockUser.EXPECT().ResetFailedLogins(gomock.Any(), 1).Return(nil)
+
+	if got, err := service.Verify(context.Background(), "alice", "correct horse battery"); err != nil ||
+		!reflect.DeepEqual(got, user) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "verified", 1, got, err, user)
+	}
+}
+
+// TestUser_RequestPasswordReset function is to test reset token is sent only to known users
+func TestUser_RequestPasswordReset(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockUser := datastore.NewMockUser(ctr)
+	mockNotifier := service.NewMockNotifier(ctr)
+	service := New(mockUser, datastore.NewMockSession(ctr), mockNotifier, config)
+
+	var sent string
+
+	mockUser.EXPECT().GetByUsername(gomock.Any(), "alice").Return(models.User{ID: 1, Email: "alice@library.test"}, nil)
+	mockUser.EXPECT().PostReset(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r models.PasswordReset) error {
+		sent = r.TokenHash
+
+		return nil
+	})
+	mockNotifier.EXPECT().Notify(gomock.Any(), "alice@library.test", "Password reset", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, body string) error {
+			token := body[strings.Index(body, config.ResetURL)+len(config.ResetURL):]
+			if hashToken(token) != sent {
+				t.Errorf("[TEST1]Failed. Got token %v which is not the stored one\n", token)
+			}
+
+			return nil
+		})
+
+	if err := service.RequestPasswordReset(context.Background(), "alice"); err != nil {
+		t.Errorf("[TEST1]Failed. Got %v\tExpected <nil>\n", err)
+	}
+
+	mockUser.EXPECT().GetByUsername(gomock.Any(), "nobody").Return(models.User{}, sql.ErrNoRows)
+
+	if err := service.RequestPasswordReset(context.Background(), "nobody"); err != nil {
+		t.Errorf("[TEST2]Failed. Got %v\tExpected <nil>\n", err)
+	}
+}
+
+// TestUser_ResetPassword function is to test new password is stored and sessions are ended
+func TestUser_ResetPassword(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		password string
+		used     error
+		err      error
+	}{
+		{desc: "valid", password: "a much better password"},
+		{desc: "short password", password: "short", err: errors.New("password too short")},
+		{desc: "used token", password: "a much better password", used: sql.ErrNoRows, err: errors.New("invalid or expired token")},
+	}
+
+	for i, v := range testcases {
+		ctr := gomock.NewController(t)
+		mockUser := datastore.NewMockUser(ctr)
+		mockSession := datastore.NewMockSession(ctr)
+		service := New(mockUser, mockSession, service.NewMockNotifier(ctr), config)
+
+		if v.password != "short" {
+			mockUser.EXPECT().UseReset(gomock.Any(), hashToken("tok"), gomock.Any()).Return(models.PasswordReset{UserID: 1}, v.used)
+		}
+
+		if v.err == nil {
+			mockUser.EXPECT().UpdatePassword(gomock.Any(), 1, gomock.Any()).DoAndReturn(func(_ context.Context, _ int, hash string) error {
+				if bcrypt.CompareHashAndPassword([]byte(hash), []byte(v.password)) != nil {
+					t.Errorf("desc : %v ,[TEST%d]Failed. Stored hash does not match password\n", v.desc, i+1)
+				}
+
+				return nil
+			})
+			mockSession.EXPECT().RevokeAll(gomock.Any(), 1).Return(2, nil)
+		}
+
+		err := service.ResetPassword(context.Background(), "tok", v.password)
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		ctr.Finish()
+	}
+}
+
+// TestUser_AuthenticateSession function is to test session token gives the user as principal
+func TestUser_AuthenticateSession(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockUser := datastore.NewMockUser(ctr)
+	mockSession := datastore.NewMockSession(ctr)
+	service := New(mockUser, mockSession, service.NewMockNotifier(ctr), config)
+
+	mockSession.EXPECT().GetActive(gomock.Any(), hashToken("good"), gomock.Any()).Return(models.Session{UserID: 1}, nil)
+	mockSession.EXPECT().GetActive(gomock.Any(), hashToken("bad"), gomock.Any()).Return(models.Session{}, sql.ErrNoRows)
+	mockUser.EXPECT().Getbyid(gomock.Any(), 1).Return(models.User{ID: 1, Username: "alice"}, nil)
+
+	p, err := service.AuthenticateSession(context.Background(), "good")
+	if err != nil || !reflect.DeepEqual(p, models.Principal{ID: "user:alice", Name: "alice", Kind: models.PrincipalUser}) {
+		t.Errorf("[TEST1]Failed. Got %v, %v\n", p, err)
+	}
+
+	if _, err = service.AuthenticateSession(context.Background(), "bad"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("[TEST2]Failed. Got %v\tExpected %v\n", err, ErrInvalidCredentials)
+	}
+}
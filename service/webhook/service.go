@@ -0,0 +1,489 @@
+// Package webhook sends catalogue and circulation events to subscribed URLs. Events are queued as deliveries when the
+// outbox relay reads them after the change is committed and POSTed by the dispatcher, failed attempts are retried
+// with exponential backoff.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/metrics"
+	"Three-Layer-Architecture/models"
+)
+
+const (
+	// deliveryLog is the number of latest deliveries listed for a webhook
+	deliveryLog = 100
+	// claimLease keeps a delivery from other dispatchers while it is sent, it must be longer than the client timeout
+	claimLease = 5 * time.Minute
+	// maxError is the size of the error column
+	maxError = 255
+)
+
+// Config holds retry policy of deliveries
+type Config struct {
+	MaxAttempts int
+	// Backoff is the delay after the first failed attempt, it doubles after every other one up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// BatchSize is the number of due deliveries sent in one dispatch
+	BatchSize int
+}
+
+type Service struct {
+	datastore datastore.Webhook
+	client    *http.Client
+	config    Config
+	now       func() time.Time
+	wake      chan struct{}
+}
+
+func New(webhook datastore.Webhook, client *http.Client, config Config) Service {
+	return Service{datastore: webhook, client: client, config: config, now: time.Now, wake: make(chan struct{}, 1)}
+}
+
+// Create method is to subscribe an URL to events, the secret is generated and returned only here
+func (s Service) Create(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	if err := validate(webhook); err != nil {
+		return models.Webhook{}, err
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	webhook.ID = 0
+	webhook.Secret = secret
+	webhook.Active = true
+	webhook.CreatedAt = s.now().UTC()
+
+	return s.datastore.Post(ctx, webhook)
+}
+
+// GetAll method is to get every webhook without its secret
+func (s Service) GetAll(ctx context.Context) ([]models.Webhook, error) {
+	webhooks, err := s.datastore.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+// Get method is to get a webhook without its secret
+func (s Service) Get(ctx context.Context, id string) (models.Webhook, error) {
+	iD, err := parseID(id)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	webhook, err := s.datastore.Get(ctx, iD)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	webhook.Secret = ""
+
+	return webhook, nil
+}
+
+// Update method is to change url, events and active of a webhook, its secret is kept
+func (s Service) Update(ctx context.Context, id string, webhook models.Webhook) (models.Webhook, error) {
+	iD, err := parseID(id)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	if err = validate(webhook); err != nil {
+		return models.Webhook{}, err
+	}
+
+	existing, err := s.datastore.Get(ctx, iD)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	existing.URL = webhook.URL
+	existing.Events = webhook.Events
+	existing.Active = webhook.Active
+
+	if err = s.datastore.Update(ctx, existing); err != nil {
+		return models.Webhook{}, err
+	}
+
+	existing.Secret = ""
+
+	return existing, nil
+}
+
+// Delete method is to remove a webhook, its queued deliveries are dropped with it
+func (s Service) Delete(ctx context.Context, id string) (int, error) {
+	iD, err := parseID(id)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.datastore.Delete(ctx, iD)
+}
+
+// Deliveries method is to get the latest deliveries of a webhook, newest first
+func (s Service) Deliveries(ctx context.Context, id string) ([]models.WebhookDelivery, error) {
+	iD, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.datastore.Get(ctx, iD); err != nil {
+		return nil, err
+	}
+
+	return s.datastore.GetDeliveries(ctx, iD, deliveryLog)
+}
+
+// Redeliver method is to queue the payload of a delivery again, the old delivery stays in the log as it was
+func (s Service) Redeliver(ctx context.Context, id, delivery string) error {
+	iD, err := parseID(id)
+	if err != nil {
+		return err
+	}
+
+	deliveryID, err := parseID(delivery)
+	if err != nil {
+		return err
+	}
+
+	old, err := s.datastore.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return err
+	}
+
+	// delivery of another webhook is not found under this one
+	if old.WebhookID != iD {
+		return sql.ErrNoRows
+	}
+
+	now := s.now().UTC()
+
+	err = s.datastore.PostDeliveries(ctx, []models.WebhookDelivery{{WebhookID: iD, EventID: old.EventID, Event: old.Event,
+		Payload: old.Payload, Status: models.DeliveryPending, NextAttemptAt: &now, CreatedAt: now}})
+	if err != nil {
+		return err
+	}
+
+	s.notify()
+
+	return nil
+}
+
+// PublishEvent method is to queue an event with its own id for every active webhook subscribed to it
+func (s Service) PublishEvent(ctx context.Context, event models.WebhookEvent) error {
+	webhooks, err := s.datastore.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	var subscribed []models.Webhook
+
+	for _, webhook := range webhooks {
+		if webhook.Active && webhook.Matches(event.Type) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+
+	if len(subscribed) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	now := s.now().UTC()
+	deliveries := make([]models.WebhookDelivery, 0, len(subscribed))
+
+	for _, webhook := range subscribed {
+		deliveries = append(deliveries, models.WebhookDelivery{WebhookID: webhook.ID, EventID: event.ID, Event: event.Type,
+			Payload: payload, Status: models.DeliveryPending, NextAttemptAt: &now, CreatedAt: now})
+	}
+
+	if err = s.datastore.PostDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+
+	s.notify()
+
+	return nil
+}
+
+// Dispatch method is to send the deliveries which are due, it returns how many were attempted
+func (s Service) Dispatch(ctx context.Context) (int, error) {
+	now := s.now().UTC()
+
+	due, err := s.datastore.Due(ctx, now, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	webhooks := make(map[int]models.Webhook)
+	attempted := 0
+
+	for _, delivery := range due {
+		// another replica may be sending it already, the lease starts now as earlier attempts of the batch took time
+		claimed, err := s.datastore.Claim(ctx, delivery, s.now().UTC().Add(claimLease))
+		if err != nil {
+			return attempted, err
+		}
+
+		if !claimed {
+			continue
+		}
+
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			webhook, err = s.datastore.Get(ctx, delivery.WebhookID)
+			// the webhook was deleted since the batch was read, its deliveries are deleted with it
+			if errors.Is(err, sql.ErrNoRows) {
+				slog.InfoContext(ctx, "skipping delivery of deleted webhook", "webhook", delivery.WebhookID,
+					"delivery", delivery.ID)
+
+				continue
+			}
+
+			if err != nil {
+				return attempted, err
+			}
+
+			webhooks[webhook.ID] = webhook
+		}
+
+		if err = s.datastore.UpdateDelivery(ctx, s.attempt(ctx, webhook, delivery)); err != nil {
+			return attempted, err
+		}
+
+		attempted++
+	}
+
+	return attempted, nil
+}
+
+// Schedule method runs Dispatch every interval and after events are queued until ctx is done
+func (s Service) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+
+		attempted, err := s.Dispatch(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "dispatching webhooks failed", "error", err)
+
+			continue
+		}
+
+		// a full batch may have left more behind
+		if attempted == s.config.BatchSize {
+			s.notify()
+		}
+	}
+}
+
+// attempt sends a delivery once and returns it with the result and the time of its next attempt
+func (s Service) attempt(ctx context.Context, webhook models.Webhook, delivery models.WebhookDelivery) models.WebhookDelivery {
+	delivery.Attempts++
+	delivery.ResponseCode = 0
+	delivery.Error = ""
+
+	code, err := s.send(ctx, webhook, delivery)
+
+	now := s.now().UTC()
+
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliverySucceeded
+		delivery.ResponseCode = code
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+
+		metrics.WebhookDelivery(delivery.Event, models.DeliverySucceeded)
+
+		return delivery
+	case code != 0:
+		delivery.ResponseCode = code
+	}
+
+	delivery.Error = truncate(err.Error(), maxError)
+
+	if delivery.Attempts >= s.config.MaxAttempts {
+		delivery.Status = models.DeliveryFailed
+		delivery.NextAttemptAt = nil
+
+		slog.WarnContext(ctx, "webhook delivery failed", "webhook", webhook.ID, "delivery", delivery.ID,
+			"event", delivery.Event, "attempts", delivery.Attempts, "error", delivery.Error)
+		metrics.WebhookDelivery(delivery.Event, models.DeliveryFailed)
+
+		return delivery
+	}
+
+	next := now.Add(s.backoff(delivery.Attempts))
+	delivery.NextAttemptAt = &next
+
+	metrics.WebhookDelivery(delivery.Event, "retried")
+
+	return delivery
+}
+
+// send POSTs the signed payload, any status other than 2xx is an error
+func (s Service) send(ctx context.Context, webhook models.Webhook, delivery models.WebhookDelivery) (int, error) {
+	if !webhook.Active {
+		return 0, errors.New("webhook is inactive")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-ID", delivery.EventID)
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	// the body is read so the connection is reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, errors.New(resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff is the delay after given failed attempt
+func (s Service) backoff(attempts int) time.Duration {
+	delay := s.config.Backoff
+
+	for i := 1; i < attempts && delay < s.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > s.config.MaxBackoff {
+		return s.config.MaxBackoff
+	}
+
+	return delay
+}
+
+// notify wakes the scheduler without blocking, one pending wake is enough for any number of events
+func (s Service) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Sign returns the signature header of a payload, receivers compute it the same way to check the sender and compare
+// the timestamp with their clock to reject replays
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func validate(webhook models.Webhook) error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return invalid("invalid url")
+	}
+
+	if len(webhook.Events) == 0 {
+		return invalid("missing events")
+	}
+
+	for _, event := range webhook.Events {
+		if !models.IsEventFilter(event) {
+			return invalid("invalid event")
+		}
+	}
+
+	return nil
+}
+
+func parseID(id string) (int, error) {
+	if id == "" {
+		return 0, invalid("missing id")
+	}
+
+	iD, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, err
+	}
+
+	if iD <= 0 {
+		return 0, invalid("invalid id")
+	}
+
+	return iD, nil
+}
+
+func randomHex(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func truncate(s string, size int) string {
+	if len(s) <= size {
+		return s
+	}
+
+	return s[:size]
+}
+
+// invalid counts the validation failure and returns reason as error
+func invalid(reason string) error {
+	metrics.ValidationFailure("webhook", reason)
+
+	return errors.New(reason)
+}
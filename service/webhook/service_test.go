@@ -0,0 +1,268 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/datastore"
+	"Three-Layer-Architecture/models"
+)
+
+var config = Config{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: 90 * time.Second, BatchSize: 10}
+
+var now = time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+func setup(t *testing.T) (*datastore.MockWebhook, Service) {
+	ctr := gomock.NewController(t)
+	mockWebhook := datastore.NewMockWebhook(ctr)
+	svc := New(mockWebhook, http.DefaultClient, config)
+	svc.now = func() time.Time { return now }
+
+	return mockWebhook, svc
+}
+
+// TestCreate function is to test webhooks are checked and get a secret
+func TestCreate(t *testing.T) {
+	testcases := []struct {
+		desc    string
+		webhook models.Webhook
+		err     error
+	}{
+		{desc: "valid", webhook: models.Webhook{URL: "https://example.com/hook", Events: []string{"book.*", "loan.renewed"}}},
+		{desc: "every event", webhook: models.Webhook{URL: "http://example.com/hook", Events: []string{"*"}}},
+		{desc: "not http", webhook: models.Webhook{URL: "ftp://example.com", Events: []string{"*"}},
+			err: errors.New("invalid url")},
+		{desc: "relative url", webhook: models.Webhook{URL: "/hook", Events: []string{"*"}}, err: errors.New("invalid url")},
+		{desc: "missing events", webhook: models.Webhook{URL: "https://example.com/hook"}, err: errors.New("missing events")},
+		{desc: "unknown event", webhook: models.Webhook{URL: "https://example.com/hook", Events: []string{"user.*"}},
+			err: errors.New("invalid event")},
+	}
+
+	for i, v := range testcases {
+		mockWebhook, svc := setup(t)
+
+		mockWebhook.EXPECT().Post(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, webhook models.Webhook) (models.Webhook, error) {
+				webhook.ID = 1
+
+				return webhook, nil
+			}).AnyTimes()
+
+		resp, err := svc.Create(context.Background(), v.webhook)
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		if v.err == nil && (len(resp.Secret) != 64 || !resp.Active || !resp.CreatedAt.Equal(now)) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected active webhook with secret\n", v.desc, i+1, resp)
+		}
+	}
+}
+
+// TestPublish function is to test events are queued only for active webhooks subscribed to them
+func TestPublish(t *testing.T) {
+	mockWebhook, svc := setup(t)
+
+	mockWebhook.EXPECT().GetAll(gomock.Any()).Return([]models.Webhook{
+		{ID: 1, Events: []string{"book.*"}, Active: true},
+		{ID: 2, Events: []string{"loan.*"}, Active: true},
+		{ID: 3, Events: []string{"*"}},
+		{ID: 4, Events: []string{"book.created"}, Active: true},
+	}, nil).Times(2)
+
+	var queued []models.WebhookDelivery
+
+	mockWebhook.EXPECT().PostDeliveries(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, deliveries []models.WebhookDelivery) error {
+			queued = deliveries
+
+			return nil
+		})
+
+	event := models.WebhookEvent{ID: "12", Type: models.EventBookCreated, OccurredAt: now,
+		Data: json.RawMessage(`{"bookID":1,"title":"2 States"}`)}
+
+	if err := svc.PublishEvent(context.Background(), event); err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "subscribed", 1, err, nil)
+	}
+
+	if len(queued) != 2 || queued[0].WebhookID != 1 || queued[1].WebhookID != 4 || queued[0].EventID != "12" ||
+		queued[1].EventID != "12" {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected deliveries to webhooks 1 and 4\n", "subscribed", 2, queued)
+	}
+
+	var sent models.WebhookEvent
+
+	if err := json.Unmarshal(queued[0].Payload, &sent); err != nil || sent.ID != "12" || sent.Type != models.EventBookCreated ||
+		string(sent.Data) != `{"bookID":1,"title":"2 States"}` {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %s %v\tExpected book.created event\n", "payload", 3, queued[0].Payload, err)
+	}
+
+	// nobody is subscribed to authors, nothing is queued
+	event.Type = models.EventAuthorUpdated
+
+	if err := svc.PublishEvent(context.Background(), event); err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "not subscribed", 4, err, nil)
+	}
+}
+
+// TestDispatch function is to test deliveries are signed, retried with backoff and failed after max attempts
+func TestDispatch(t *testing.T) {
+	payload := []byte(`{"id":"e1"}`)
+	retry := now.Add(time.Minute)
+	capped := now.Add(90 * time.Second)
+
+	testcases := []struct {
+		desc     string
+		status   int
+		attempts int
+		resp     models.WebhookDelivery
+	}{
+		{desc: "delivered", status: http.StatusNoContent, resp: models.WebhookDelivery{Status: models.DeliverySucceeded,
+			Attempts: 1, ResponseCode: http.StatusNoContent, DeliveredAt: &now}},
+		{desc: "first failure", status: http.StatusInternalServerError, resp: models.WebhookDelivery{
+			Status: models.DeliveryPending, Attempts: 1, ResponseCode: http.StatusInternalServerError,
+			Error: "500 Internal Server Error", NextAttemptAt: &retry}},
+		{desc: "backoff capped", status: http.StatusBadGateway, attempts: 1, resp: models.WebhookDelivery{
+			Status: models.DeliveryPending, Attempts: 2, ResponseCode: http.StatusBadGateway, Error: "502 Bad Gateway",
+			NextAttemptAt: &capped}},
+		{desc: "max attempts", status: http.StatusGone, attempts: 2, resp: models.WebhookDelivery{
+			Status: models.DeliveryFailed, Attempts: 3, ResponseCode: http.StatusGone, Error: "410 Gone"}},
+	}
+
+	for i, v := range testcases {
+		var signature, event string
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			signature = r.Header.Get("X-Webhook-Signature")
+			event = r.Header.Get("X-Webhook-Event")
+
+			if signature != Sign("secret", r.Header.Get("X-Webhook-Timestamp"), body) {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			w.WriteHeader(v.status)
+		}))
+
+		mockWebhook, svc := setup(t)
+
+		due := models.WebhookDelivery{ID: 5, WebhookID: 1, EventID: "e1", Event: models.EventBookCreated, Payload: payload,
+			Status: models.DeliveryPending, Attempts: v.attempts, NextAttemptAt: &now}
+		expected := v.resp
+		expected.ID, expected.WebhookID, expected.EventID, expected.Event, expected.Payload = 5, 1, "e1",
+			models.EventBookCreated, payload
+
+		mockWebhook.EXPECT().Due(gomock.Any(), now, 10).Return([]models.WebhookDelivery{due}, nil)
+		mockWebhook.EXPECT().Claim(gomock.Any(), due, now.Add(claimLease)).Return(true, nil)
+		mockWebhook.EXPECT().Get(gomock.Any(), 1).Return(models.Webhook{ID: 1, URL: server.URL, Secret: "secret",
+			Active: true}, nil)
+		mockWebhook.EXPECT().UpdateDelivery(gomock.Any(), expected).Return(nil)
+
+		attempted, err := svc.Dispatch(context.Background())
+		if attempted != 1 || err != nil || event != models.EventBookCreated || signature == "" {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v %v\tExpected %v\n", v.desc, i+1, attempted, event, err, 1)
+		}
+
+		server.Close()
+	}
+}
+
+// TestDispatch_Claimed function is to test deliveries claimed by another replica are skipped
+func TestDispatch_Claimed(t *testing.T) {
+	mockWebhook, svc := setup(t)
+
+	due := models.WebhookDelivery{ID: 5, WebhookID: 1, Status: models.DeliveryPending, NextAttemptAt: &now}
+
+	mockWebhook.EXPECT().Due(gomock.Any(), now, 10).Return([]models.WebhookDelivery{due}, nil)
+	mockWebhook.EXPECT().Claim(gomock.Any(), due, now.Add(claimLease)).Return(false, nil)
+
+	if attempted, err := svc.Dispatch(context.Background()); attempted != 0 || err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "claimed", 1, attempted, err, 0)
+	}
+}
+
+// TestDispatch_Deleted function is to test a delivery of a webhook deleted during the batch is skipped and the
+// others are sent, each claim is leased from the time it is made
+func TestDispatch_Deleted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	mockWebhook, svc := setup(t)
+
+	// every reading of the clock is a minute later
+	clock := now
+	svc.now = func() time.Time {
+		clock = clock.Add(time.Minute)
+
+		return clock
+	}
+
+	deleted := models.WebhookDelivery{ID: 5, WebhookID: 1, Status: models.DeliveryPending, NextAttemptAt: &now}
+	due := models.WebhookDelivery{ID: 6, WebhookID: 2, Status: models.DeliveryPending, NextAttemptAt: &now}
+
+	mockWebhook.EXPECT().Due(gomock.Any(), now.Add(time.Minute), 10).Return([]models.WebhookDelivery{deleted, due}, nil)
+	mockWebhook.EXPECT().Claim(gomock.Any(), deleted, now.Add(2*time.Minute+claimLease)).Return(true, nil)
+	mockWebhook.EXPECT().Get(gomock.Any(), 1).Return(models.Webhook{}, sql.ErrNoRows)
+	mockWebhook.EXPECT().Claim(gomock.Any(), due, now.Add(3*time.Minute+claimLease)).Return(true, nil)
+	mockWebhook.EXPECT().Get(gomock.Any(), 2).Return(models.Webhook{ID: 2, URL: server.URL, Secret: "secret",
+		Active: true}, nil)
+	mockWebhook.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).Return(nil)
+
+	if attempted, err := svc.Dispatch(context.Background()); attempted != 1 || err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "deleted webhook", 1, attempted, err, 1)
+	}
+}
+
+// TestRedeliver function is to test only deliveries of the webhook are queued again
+func TestRedeliver(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		delivery models.WebhookDelivery
+		err      error
+	}{
+		{desc: "queued", delivery: models.WebhookDelivery{ID: 7, WebhookID: 1, EventID: "e1", Event: "book.created",
+			Status: models.DeliveryFailed}},
+		{desc: "another webhook", delivery: models.WebhookDelivery{ID: 7, WebhookID: 2}, err: sql.ErrNoRows},
+	}
+
+	for i, v := range testcases {
+		mockWebhook, svc := setup(t)
+
+		mockWebhook.EXPECT().GetDelivery(gomock.Any(), 7).Return(v.delivery, nil)
+
+		if v.err == nil {
+			mockWebhook.EXPECT().PostDeliveries(gomock.Any(), []models.WebhookDelivery{{WebhookID: 1, EventID: "e1",
+				Event: "book.created", Status: models.DeliveryPending, NextAttemptAt: &now, CreatedAt: now}}).Return(nil)
+		}
+
+		if err := svc.Redeliver(context.Background(), "1", "7"); !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestSign function is to test signature is HMAC-SHA256 of timestamp and body
+func TestSign(t *testing.T) {
+	// echo -n '1717236000.{}' | openssl dgst -sha256 -hmac secret
+	expected := "sha256=2c624cfb01bffc3ceb529250590312203ea5ede732d49750680107cf4fd27ea1"
+
+	if got := Sign("secret", "1717236000", []byte("{}")); got != expected {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "signed", 1, got, expected)
+	}
+}
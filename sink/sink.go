@@ -0,0 +1,229 @@
+// Package sink has the destinations of outbox events, webhooks, a local file, subscribers and the cache of this process
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+// Webhook queues events for webhooks, the outbox id is the webhook event id so a resent event keeps its id
+type Webhook struct {
+	publisher service.Publisher
+}
+
+func NewWebhook(publisher service.Publisher) Webhook {
+	return Webhook{publisher: publisher}
+}
+
+// Name is the cursor of webhooks
+func (Webhook) Name() string {
+	return "webhook"
+}
+
+// Send queues the event for subscribed webhooks
+func (w Webhook) Send(ctx context.Context, event models.OutboxEvent) error {
+	return w.publisher.PublishEvent(ctx, models.WebhookEvent{ID: strconv.FormatInt(event.ID, 10), Type: event.Event,
+		OccurredAt: event.CreatedAt, Data: event.Data})
+}
+
+// File appends events to a file as JSON lines, an event is sent once it is synced to disk
+type File struct {
+	path string
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFile opens path for appending, it is created when missing
+func NewFile(path string) (*File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{path: path, file: file}, nil
+}
+
+// Name is the cursor of the file, each path has its own
+func (f *File) Name() string {
+	return "file:" + f.path
+}
+
+// Send appends the event as one line
+func (f *File) Send(_ context.Context, event models.OutboxEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err = f.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	return f.file.Sync()
+}
+
+// Close closes the file
+func (f *File) Close() error {
+	return f.file.Close()
+}
+
+// invalidator drops the cached entries of a book or an author, see book.Cached and author.Cached
+type invalidator interface {
+	Invalidate(ctx context.Context, id string)
+}
+
+// Cache drops cached books and authors changed by another process, like another replica or the librarian command
+type Cache struct {
+	book   invalidator
+	author invalidator
+}
+
+func NewCache(book, author invalidator) Cache {
+	return Cache{book: book, author: author}
+}
+
+// Name is the name of the cache in logs, its position is not stored
+func (Cache) Name() string {
+	return "cache"
+}
+
+// Send drops the entries of the book or author of the event, other events are ignored
+func (c Cache) Send(ctx context.Context, event models.OutboxEvent) error {
+	switch event.Entity {
+	case models.EntityBook:
+		c.book.Invalidate(ctx, strconv.Itoa(event.EntityID))
+	case models.EntityAuthor:
+		c.author.Invalidate(ctx, strconv.Itoa(event.EntityID))
+	}
+
+	return nil
+}
+
+// Bus hands events to in-process subscribers and keeps the latest ones to replay to subscribers which resume
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[chan models.OutboxEvent]func(event string) bool
+	replay      []models.OutboxEvent
+	size        int
+	// floor is the id after which every event is in replay, -1 until the first event
+	floor  int64
+	closed bool
+}
+
+// NewBus returns a bus replaying up to size latest events
+func NewBus(size int) *Bus {
+	return &Bus{subscribers: make(map[chan models.OutboxEvent]func(string) bool), size: size, floor: -1}
+}
+
+// Name is the name of the bus in logs, its position is not stored
+func (b *Bus) Name() string {
+	return "bus"
+}
+
+// Subscribe returns a channel of events after id which match, replayed ones first, and a function which
+// unsubscribes. Event ids start at 1 so 0 subscribes to new events only. It reports false when events after id
+// were sent before the bus started or are not kept any more.
+func (b *Bus) Subscribe(after int64, buffer int, match func(event string) bool) (<-chan models.OutboxEvent, func(), bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []models.OutboxEvent
+
+	complete := after <= 0 || (b.floor >= 0 && after >= b.floor)
+
+	if after > 0 {
+		for _, event := range b.replay {
+			if event.ID > after && match(event.Event) {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	ch := make(chan models.OutboxEvent, buffer+len(replay))
+
+	for _, event := range replay {
+		ch <- event
+	}
+
+	if b.closed {
+		close(ch)
+
+		return ch, func() {}, complete
+	}
+
+	b.subscribers[ch] = match
+
+	return ch, func() { b.unsubscribe(ch) }, complete
+}
+
+// Send keeps the event for replay and hands it to every subscriber it matches. It never waits, a subscriber whose
+// buffer is full is closed and resumes by subscribing again.
+func (b *Bus) Send(ctx context.Context, event models.OutboxEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.floor < 0 {
+		b.floor = event.ID - 1
+	}
+
+	if b.size > 0 {
+		if len(b.replay) == b.size {
+			b.floor = b.replay[0].ID
+			b.replay = append(b.replay[:0], b.replay[1:]...)
+		}
+
+		b.replay = append(b.replay, event)
+	} else {
+		b.floor = event.ID
+	}
+
+	for ch, match := range b.subscribers {
+		if !match(event.Event) {
+			continue
+		}
+
+		select {
+		case ch <- event:
+		default:
+			slog.WarnContext(ctx, "dropping slow event subscriber", "event", event.ID)
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return nil
+}
+
+// Close ends every subscription, later ones get replayed events only
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+func (b *Bus) unsubscribe(ch chan models.OutboxEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// a dropped subscriber is closed already
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
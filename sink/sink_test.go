@@ -0,0 +1,198 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"Three-Layer-Architecture/models"
+	"Three-Layer-Architecture/service"
+)
+
+var event = models.OutboxEvent{ID: 7, Event: models.EventBookCreated, Entity: models.EntityBook, EntityID: 1,
+	Data: json.RawMessage(`{"bookID":1}`), CreatedAt: time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)}
+
+// TestWebhook function is to test the outbox id is kept as the webhook event id
+func TestWebhook(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockPublisher := service.NewMockPublisher(ctr)
+
+	expected := models.WebhookEvent{ID: "7", Type: models.EventBookCreated, OccurredAt: event.CreatedAt, Data: event.Data}
+	mockPublisher.EXPECT().PublishEvent(gomock.Any(), expected).Return(nil)
+
+	if err := NewWebhook(mockPublisher).Send(context.Background(), event); err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "queued", 1, err, nil)
+	}
+}
+
+// invalidated records the ids a Cache drops
+type invalidated []string
+
+func (i *invalidated) Invalidate(_ context.Context, id string) {
+	*i = append(*i, id)
+}
+
+// TestCache function is to test books and authors of events are dropped from the cache and loans are not
+func TestCache(t *testing.T) {
+	var books, authors invalidated
+
+	c := NewCache(&books, &authors)
+
+	for _, e := range []models.OutboxEvent{event, {ID: 8, Event: models.EventAuthorUpdated, Entity: models.EntityAuthor,
+		EntityID: 3}, {ID: 9, Event: models.EventLoanCheckedOut, Entity: models.EntityLoan, EntityID: 4}} {
+		if err := c.Send(context.Background(), e); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "send", 1, err, nil)
+		}
+	}
+
+	if !reflect.DeepEqual(books, invalidated{"1"}) || !reflect.DeepEqual(authors, invalidated{"3"}) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", "dropped", 2, books, authors, []string{"1"},
+			[]string{"3"})
+	}
+}
+
+// TestFile function is to test events are appended as JSON lines
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	file, err := NewFile(path)
+	if err != nil {
+		t.Fatalf("could not open file : %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err = file.Send(context.Background(), event); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "appended", i+1, err, nil)
+		}
+	}
+
+	file.Close()
+
+	body, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+
+	var got models.OutboxEvent
+
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[1]), &got) != nil || !reflect.DeepEqual(got, event) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "lines", 3, lines, event)
+	}
+
+	if file.Name() != "file:"+path {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "name", 4, file.Name(), "file:"+path)
+	}
+}
+
+// TestBus function is to test subscribers get events they match and slow ones are dropped without blocking
+func TestBus(t *testing.T) {
+	bus := NewBus(10)
+
+	fast, unsubscribe := subscribe(bus, 0, 2, "book.*")
+	defer unsubscribe()
+
+	authors, unsubscribeAuthors := subscribe(bus, 0, 1, "author.*")
+	defer unsubscribeAuthors()
+
+	slow, unsubscribeSlow := subscribe(bus, 0, 0, "*")
+
+	if err := bus.Send(context.Background(), event); err != nil {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "sent", 1, err, nil)
+	}
+
+	if got := <-fast; !reflect.DeepEqual(got, event) {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "received", 2, got, event)
+	}
+
+	if _, ok := <-slow; ok {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got open channel\tExpected closed\n", "dropped", 3)
+	}
+
+	if len(authors) != 0 {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "filtered", 4, len(authors), 0)
+	}
+
+	// unsubscribing a dropped subscriber is harmless
+	unsubscribeSlow()
+}
+
+// TestBusReplay function is to test resuming subscribers get the events they missed while the buffer has them
+func TestBusReplay(t *testing.T) {
+	bus := NewBus(2)
+
+	if _, _, complete := bus.Subscribe(3, 1, all); complete {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "nothing sent yet", 1, complete, false)
+	}
+
+	for _, id := range []int64{7, 8, 10} {
+		e := event
+		e.ID = id
+
+		_ = bus.Send(context.Background(), e)
+	}
+
+	tcs := []struct {
+		desc     string
+		after    int64
+		ids      []int64
+		complete bool
+	}{
+		{"new events only", 0, nil, true},
+		{"missed one", 8, []int64{10}, true},
+		{"missed two", 7, []int64{8, 10}, true},
+		{"evicted", 6, []int64{8, 10}, false},
+		{"up to date", 10, nil, true},
+	}
+
+	for i, tc := range tcs {
+		ch, unsubscribe, complete := bus.Subscribe(tc.after, 1, all)
+		unsubscribe()
+
+		var ids []int64
+
+		for e := range ch {
+			ids = append(ids, e.ID)
+		}
+
+		if !reflect.DeepEqual(ids, tc.ids) || complete != tc.complete {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", tc.desc, i+2, ids, complete, tc.ids, tc.complete)
+		}
+	}
+}
+
+// TestBusClose function is to test closing ends subscriptions
+func TestBusClose(t *testing.T) {
+	bus := NewBus(1)
+
+	ch, unsubscribe := subscribe(bus, 0, 1, "*")
+	defer unsubscribe()
+
+	bus.Close()
+
+	if _, ok := <-ch; ok {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got open channel\tExpected closed\n", "closed", 1)
+	}
+
+	late, _ := subscribe(bus, 0, 1, "*")
+
+	if _, ok := <-late; ok {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got open channel\tExpected closed\n", "subscribed after close", 2)
+	}
+}
+
+func all(string) bool {
+	return true
+}
+
+func subscribe(bus *Bus, after int64, buffer int, filter string) (<-chan models.OutboxEvent, func()) {
+	ch, unsubscribe, _ := bus.Subscribe(after, buffer, func(event string) bool {
+		return models.MatchEvent([]string{filter}, event)
+	})
+
+	return ch, unsubscribe
+}
@@ -0,0 +1,83 @@
+package sip2
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestParse function is to test fixed and variable fields, sequence numbers and checksums are read
+func TestParse(t *testing.T) {
+	login := "9300CNkiosk|COsecret|CPMAIN|AY3AZ"
+	login += Checksum(login)
+
+	testcases := []struct {
+		desc string
+		line string
+		resp Message
+		err  error
+	}{
+		{desc: "with error detection", line: login + "\r",
+			resp: Message{Code: Login, Fixed: "00", Seq: 3,
+				Fields: []Field{{ID: "CN", Value: "kiosk"}, {ID: "CO", Value: "secret"}, {ID: "CP", Value: "MAIN"}}}},
+		{desc: "without error detection", line: "1720240601   Z100000AOlib|AB12|AC|",
+			resp: Message{Code: ItemInformation, Fixed: "20240601   Z100000", Seq: -1,
+				Fields: []Field{{ID: "AO", Value: "lib"}, {ID: "AB", Value: "12"}, {ID: "AC", Value: ""}}}},
+		{desc: "lower case checksum", line: "97AY1AZ" + lower(Checksum("97AY1AZ")),
+			resp: Message{Code: RequestACSResend, Fixed: "", Seq: 1}},
+		{desc: "wrong checksum", line: "9300CNkiosk|AY3AZ0000", err: ErrChecksum},
+		{desc: "short fixed fields", line: "2300120240601", err: ErrInvalidMessage},
+		{desc: "too short", line: "9", err: ErrInvalidMessage},
+	}
+
+	for i, v := range testcases {
+		resp, err := Parse(v.line)
+
+		if !reflect.DeepEqual(resp, v.resp) || !errors.Is(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v %v\n", v.desc, i+1, resp, err, v.resp, v.err)
+		}
+	}
+}
+
+func lower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'F' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+
+	return string(b)
+}
+
+// TestEncode function is to test messages are written with separators and a checksum that reads back
+func TestEncode(t *testing.T) {
+	m := Message{Code: CheckoutResponse, Fixed: "1NNY" + Time(time.Date(2024, 6, 1, 10, 0, 0, 0, time.FixedZone("IST", 19800))),
+		Fields: []Field{{ID: "AO", Value: "lib"}, {ID: "AJ", Value: "A|B"}}, Seq: 12}
+
+	line := m.Encode()
+	if line[:len(line)-5] != "121NNY20240601   Z043000AOlib|AJA B|AY2AZ" || line[len(line)-1] != '\r' {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %q\n", "encoded", 1, line)
+	}
+
+	// every character and the checksum add up to zero
+	sum, _ := strconv.ParseUint(line[len(line)-5:len(line)-1], 16, 16)
+	for i := 0; i < len(line)-5; i++ {
+		sum += uint64(line[i])
+	}
+
+	if uint16(sum) != 0 {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", "checksum", 2, uint16(sum), 0)
+	}
+
+	back, err := Parse(line)
+	if err != nil || back.Seq != 2 || back.Get("AJ") != "A B" {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %v %v\tExpected %v\n", "read back", 3, back, err, m)
+	}
+
+	if plain := (Message{Code: RequestSCResend, Seq: -1}).Encode(); plain != "96\r" {
+		t.Errorf("desc : %v ,[TEST%d]Failed. Got %q\tExpected %q\n", "no error detection", 4, plain, "96\r")
+	}
+}
@@ -0,0 +1,32 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+)
+
+// TestSetup function is to test exporters are chosen by name
+func TestSetup(t *testing.T) {
+	testcases := []struct {
+		exporter string
+		fails    bool
+	}{
+		{exporter: ""},
+		{exporter: "none"},
+		{exporter: "stdout"},
+		{exporter: "zipkin", fails: true},
+	}
+
+	for i, v := range testcases {
+		shutdown, err := Setup(context.Background(), v.exporter)
+		if (err != nil) != v.fails {
+			t.Errorf("[TEST%d]Failed. Got %v for %q\n", i+1, err, v.exporter)
+		}
+
+		if err == nil {
+			if err = shutdown(context.Background()); err != nil {
+				t.Errorf("[TEST%d]Failed. Got %v on shutdown\n", i+1, err)
+			}
+		}
+	}
+}